@@ -0,0 +1,197 @@
+package sqltk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// tableAlias returns the name a FROM or JOIN table is referenced by: its alias, or the
+// unqualified table name. It returns "" for tables without one, such as unaliased subqueries
+// and raw SQL.
+func tableAlias(table interface{}) string {
+	switch t := table.(type) {
+	case string:
+		fields := strings.Fields(t)
+		if len(fields) == 0 {
+			return ""
+		}
+		name := fields[len(fields)-1]
+		return name[strings.LastIndex(name, ".")+1:]
+	case Identifier:
+		return t.Name()
+	case AliasExpr:
+		return t.Alias
+	default:
+		return ""
+	}
+}
+
+// columnAlias returns the explicit alias of a SELECT column, or "" if it has none.
+func columnAlias(col interface{}) string {
+	switch c := col.(type) {
+	case string:
+		if _, alias, ok := splitAlias(c); ok {
+			return unquoteAlias(alias)
+		}
+	case AliasExpr:
+		return unquoteAlias(c.Alias)
+	}
+	return ""
+}
+
+// ResultColumns returns the name each SELECT column is reported under: its alias, or the
+// unqualified name of a plain column. It is "" for a column without a known name, such as an
+// unaliased expression or *.
+//
+// Example usage:
+//
+//	Select("u.id", "COUNT(*) AS n", "MAX(age)").ResultColumns() // ["id", "n", ""]
+func (b *SelectBuilder) ResultColumns() []string {
+	names := make([]string, len(b.columns))
+	for i, col := range b.columns {
+		if alias := columnAlias(col); alias != "" {
+			names[i] = alias
+			continue
+		}
+		if c, ok := col.(string); ok && isIdentPath(c) {
+			names[i] = strings.TrimSpace(c[strings.LastIndex(c, ".")+1:])
+		}
+	}
+	return names
+}
+
+// splitAlias splits a SELECT column such as "COUNT(*) AS n" into its expression and alias.
+// AS is matched case-insensitively, and only outside parentheses and quotes, so the AS of
+// CAST(x AS int) is not an alias. The alias must be an identifier, quoted or not.
+func splitAlias(col string) (expr, alias string, ok bool) {
+	at := -1
+	depth := 0
+	toks := sqllex.For(sqldialect.GetDialect()).Tokens(col)
+	for i, t := range toks {
+		switch {
+		case t.IsPunct("("):
+			depth++
+		case t.IsPunct(")"):
+			depth--
+		case depth == 0 && t.Is("AS") && t.Space && i+1 < len(toks) && toks[i+1].Space:
+			at = t.Pos
+		}
+	}
+	if at < 0 {
+		return "", "", false
+	}
+	expr = strings.TrimSpace(col[:at])
+	alias = strings.TrimSpace(col[at+2:])
+	if expr == "" || !isAliasIdent(alias) {
+		return "", "", false
+	}
+	return expr, alias, true
+}
+
+// isAliasIdent reports whether s is a single identifier, or a single quoted identifier.
+func isAliasIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	if s[0] == '"' || s[0] == '`' {
+		toks := sqllex.For(sqldialect.GetDialect()).Tokens(s)
+		return len(toks) == 1 && toks[0].Kind == sqllex.Quoted && !toks[0].Unterminated
+	}
+	for i := 0; i < len(s); i++ {
+		if !isIdentByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentPath reports whether s is a possibly table-qualified column name, e.g. "u.name".
+func isIdentPath(s string) bool {
+	for _, part := range strings.Split(s, ".") {
+		part = strings.TrimSpace(part)
+		if part == "" || part[0] == '"' || part[0] == '`' || !isAliasIdent(part) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// unquoteAlias strips the quotes of a quoted alias.
+func unquoteAlias(alias string) string {
+	if len(alias) > 1 && (alias[0] == '"' || alias[0] == '`') && alias[len(alias)-1] == alias[0] {
+		return alias[1 : len(alias)-1]
+	}
+	return alias
+}
+
+// RawAliases turns off quoting of SELECT column aliases, which are then written as given.
+// Use it for aliases that rely on the database's case folding, or that are already escaped.
+//
+// Example usage:
+//
+//	Select("COUNT(*) AS total").From("users").RawAliases() // SELECT COUNT(*) AS total ...
+func (b *SelectBuilder) RawAliases() *SelectBuilder {
+	b.rawAliases = true
+	return b
+}
+
+// quoteAlias quotes a SELECT column alias for the dialect, unless it is already quoted or
+// RawAliases is set.
+func (b *SelectBuilder) quoteAlias(dialect sqldialect.Dialect, alias string) string {
+	if b.rawAliases || alias == "" || alias[0] == '"' || alias[0] == '`' {
+		return alias
+	}
+	return dialect.QuoteIdent(alias)
+}
+
+// checkAliases returns an error if two tables in FROM and JOIN, or two SELECT columns,
+// share an alias. Aliases are compared case-insensitively.
+func (b *SelectBuilder) checkAliases(dialect sqldialect.Dialect) error {
+	tables := []string{tableAlias(b.tableClauseInterface.table)}
+	for _, t := range b.fromTables {
+		tables = append(tables, tableAlias(t))
+	}
+	for _, j := range b.joins {
+		tables = append(tables, tableAlias(j.table))
+	}
+	for _, f := range b.filterJoins {
+		if f.usesLeftJoin(dialect) {
+			tables = append(tables, tableAlias(f.table))
+		}
+	}
+	if alias := firstDuplicate(tables); alias != "" {
+		return fmt.Errorf("Select: duplicate table alias %q", alias)
+	}
+
+	columns := make([]string, len(b.columns))
+	for i, col := range b.columns {
+		columns[i] = columnAlias(col)
+	}
+	if alias := firstDuplicate(columns); alias != "" {
+		return fmt.Errorf("Select: duplicate column alias %q", alias)
+	}
+	return nil
+}
+
+// firstDuplicate returns the first non-empty name that occurs twice, ignoring case.
+func firstDuplicate(names []string) string {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			return name
+		}
+		seen[key] = true
+	}
+	return ""
+}
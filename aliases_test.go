@@ -0,0 +1,159 @@
+package sqltk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectAliasCollisions(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		wantErr string
+	}{
+		{
+			name: "distinct aliases",
+			builder: Select("u.id", Alias("o.total", "total"), "COUNT(*) AS n").From(Alias("users", "u")).
+				Join(Alias("orders", "o")).On("o.user_id", "u.id").
+				LeftJoin("refunds r").On("r.order_id", "o.id"),
+		},
+		{
+			name: "join reuses from alias",
+			builder: Select("u.id").From(Alias("users", "u")).
+				Join(Alias("orders", "u")).On("u.user_id", "u.id"),
+			wantErr: `duplicate table alias "u"`,
+		},
+		{
+			name: "alias comparison ignores case",
+			builder: Select("o.id").From("orders o").
+				Join(Alias("order_items", "O")).On("O.order_id", "o.id"),
+			wantErr: `duplicate table alias "O"`,
+		},
+		{
+			name: "same table joined twice without alias",
+			builder: Select("users.id").From("users").
+				Join("orders").On("orders.user_id", "users.id").
+				LeftJoin("orders").On("orders.parent_id", "users.id"),
+			wantErr: `duplicate table alias "orders"`,
+		},
+		{
+			name: "self join with aliases",
+			builder: Select("e.id").From(Alias("employees", "e")).
+				LeftJoin(Alias("employees", "m")).On("m.id", "e.manager_id"),
+		},
+		{
+			name: "unaliased subqueries and raw tables are not checked",
+			builder: Select("id").From(raw.Raw("users u")).
+				CrossJoin(Select("id").From("orders")).
+				CrossJoin(Select("id").From("orders")),
+		},
+		{
+			name:    "duplicate column alias",
+			builder: Select(Alias("a.name", "name"), "b.title AS name").From(Alias("authors", "a")).CrossJoin(Alias("books", "b")),
+			wantErr: `duplicate column alias "name"`,
+		},
+		{
+			name:    "duplicate lowercase as alias",
+			builder: Select("COUNT(*) as total", Alias(raw.Raw("SUM(x)"), "Total")).From("t"),
+			wantErr: `duplicate column alias "Total"`,
+		},
+		{
+			name:    "anti join table on mysql",
+			builder: Select("o.id").From(Alias("orders", "o")).AntiJoin(Alias("refunds", "o"), JoinKey{Left: "o.id", Right: "o.order_id"}),
+			wantErr: `duplicate table alias "o"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.WithDialect(sqldialect.MySQL()).Build()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectColumnAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:    "string aliases in any case",
+			builder: Select("u.name as author", "COUNT(*) AS posts", "id As key").From("posts"),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `u`.`name` AS `author`, COUNT(*) AS `posts`, `id` AS `key` FROM `posts`",
+		},
+		{
+			name:    "as inside parentheses and quotes",
+			builder: Select("CAST(price AS numeric) AS price", "'a AS b' AS label", "CAST(x AS int)").From("items"),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT CAST(price AS numeric) AS "price", 'a AS b' AS "label", CAST(x AS int) FROM "items"`,
+		},
+		{
+			name:    "qualified expression is not split on dots",
+			builder: Select("COUNT(u.id) AS n").From("users u"),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT COUNT(u.id) AS "n" FROM "users u"`,
+		},
+		{
+			name:    "quoted alias is kept",
+			builder: Select(`SUM(total) AS "Grand Total"`, Alias(raw.Raw("1"), `"One"`)).From("orders"),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT SUM(total) AS "Grand Total", 1 AS "One" FROM "orders"`,
+		},
+		{
+			name:     "alias expr",
+			builder:  Select(Alias(Expr("COALESCE(nick, ?)", "anon"), "nick"), Alias("u.email", "email")).From("users u"),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT COALESCE(nick, $1) AS "nick", "u"."email" AS "email" FROM "users u"`,
+			wantArgs: []interface{}{"anon"},
+		},
+		{
+			name:    "raw aliases",
+			builder: Select("COUNT(*) AS total", Alias(raw.Raw("MAX(id)"), "last_id")).From("users").RawAliases(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT COUNT(*) AS total, MAX(id) AS last_id FROM `users`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if tt.wantArgs == nil {
+				tt.wantArgs = []interface{}{}
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectResultColumns(t *testing.T) {
+	q := Select("u.id", "COUNT(*) AS n", Alias(raw.Raw("MAX(age)"), "oldest"), "MAX(age)", "*", `name AS "Full Name"`)
+	want := []string{"id", "n", "oldest", "", "", "Full Name"}
+	if got := q.ResultColumns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,565 @@
+package sqltk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+// ASTVersion is the version of the AST format written by SelectBuilder.AST. FromAST rejects
+// ASTs of other versions.
+const ASTVersion = 1
+
+// Kinds of ExprAST.
+const (
+	ExprIdent = "ident" // column or table name, quoted for the dialect, e.g. "u.email" or "users u"
+	ExprRaw   = "raw"   // raw.Raw, written as-is
+	ExprFunc  = "func"  // sqlfunc.SqlFunc, written as-is
+	ExprSQL   = "expr"  // Expr, SQL with ? placeholders and args
+	ExprQuery = "query" // subquery
+)
+
+// QueryAST is the structured form of a SELECT, as returned by SelectBuilder.AST. It marshals
+// to JSON and back without loss, so queries can be stored as data and rebuilt with FromAST.
+// Conditions are kept as the SQL they were built to, with ? placeholders and typed args.
+type QueryAST struct {
+	Version     int        `json:"version"`
+	Distinct    bool       `json:"distinct,omitempty"`
+	Hints       []string   `json:"hints,omitempty"`
+	Columns     []ExprAST  `json:"columns,omitempty"`
+	From        []ExprAST  `json:"from,omitempty"`
+	Joins       []JoinAST  `json:"joins,omitempty"`
+	Where       *CondAST   `json:"where,omitempty"`
+	GroupBy     []string   `json:"group_by,omitempty"`
+	GroupByRaw  []string   `json:"group_by_raw,omitempty"`
+	GroupByArgs []ArgAST   `json:"group_by_args,omitempty"`
+	Having      *CondAST   `json:"having,omitempty"`
+	OrderBy     []OrderAST `json:"order_by,omitempty"`
+	Limit       *int       `json:"limit,omitempty"`
+	Offset      *int       `json:"offset,omitempty"`
+	Lock        string     `json:"lock,omitempty"`
+	LockOption  string     `json:"lock_option,omitempty"`
+	RawAliases  bool       `json:"raw_aliases,omitempty"`
+	ReadOnly    bool       `json:"read_only,omitempty"`
+	MaxExecMS   int64      `json:"max_execution_ms,omitempty"`
+	ReuseArgs   bool       `json:"reuse_args,omitempty"`
+	Unlimited   bool       `json:"unlimited,omitempty"`
+	// Deterministic and UniqueColumns are set by DeterministicOrder.
+	Deterministic bool     `json:"deterministic,omitempty"`
+	UniqueColumns []string `json:"unique_columns,omitempty"`
+}
+
+// ExprAST is a column, table, or expression of a QueryAST.
+type ExprAST struct {
+	Kind  string    `json:"kind"` // one of ExprIdent, ExprRaw, ExprFunc, ExprSQL, ExprQuery
+	SQL   string    `json:"sql,omitempty"`
+	Args  []ArgAST  `json:"args,omitempty"`  // args of an ExprSQL
+	Query *QueryAST `json:"query,omitempty"` // subquery of an ExprQuery
+	Alias string    `json:"alias,omitempty"` // set for Alias expressions
+}
+
+// JoinAST is a JOIN of a QueryAST. Suffix is the ON clause as built, and Using the unquoted
+// columns of a USING clause. ASTs written before Using was added may hold a USING clause,
+// quoted for the dialect it was built with, in Suffix.
+type JoinAST struct {
+	Type   string   `json:"type"`
+	Table  ExprAST  `json:"table"`
+	Suffix string   `json:"suffix,omitempty"`
+	Args   []ArgAST `json:"args,omitempty"`
+	Using  []string `json:"using,omitempty"`
+}
+
+// CondAST holds the WHERE or HAVING conditions of a QueryAST, which are ANDed. If Or is set,
+// the first condition is an OR of earlier ones and is parenthesized. Subqueries are the
+// queries embedded in the conditions, kept for GetTables.
+type CondAST struct {
+	Conditions []string    `json:"conditions"`
+	Args       []ArgAST    `json:"args,omitempty"`
+	Or         bool        `json:"or,omitempty"`
+	Subqueries []*QueryAST `json:"subqueries,omitempty"`
+}
+
+// OrderAST is an ORDER BY term of a QueryAST.
+type OrderAST struct {
+	Expr      string   `json:"expr"`
+	Raw       bool     `json:"raw,omitempty"` // Expr is SQL rather than a column name
+	Direction string   `json:"direction,omitempty"`
+	Nulls     string   `json:"nulls,omitempty"` // "first" or "last"
+	Args      []ArgAST `json:"args,omitempty"`
+}
+
+// ArgAST is a typed query argument, so that values keep their Go type through JSON.
+// Type is "null", "bool", "int", "float", "string", "bytes" (base64), or "time" (RFC 3339).
+// Integers above 2^53 need a JSON decoder with UseNumber to be read back exactly.
+type ArgAST struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// AST returns the structured form of the query, for storing it as data. Features that have no
+// structured form, such as window functions, custom clauses, keyset pagination, semi- and
+// anti-joins, index hints, and Binder arguments other than Expr, return an error. The dialect
+// and schema of the builder are not part of the AST.
+//
+// Example usage:
+//
+//	node, err := q.AST()
+//	data, err := json.Marshal(node)
+//	// later
+//	var node sqltk.QueryAST
+//	err = json.Unmarshal(data, &node)
+//	q, err := sqltk.FromAST(&node)
+func (b *SelectBuilder) AST() (*QueryAST, error) {
+	if b == nil {
+		return nil, errors.New("AST: builder is nil")
+	}
+	if b.tableClauseInterface.err != nil {
+		return nil, b.tableClauseInterface.err
+	}
+	if b.whereClause.err != nil {
+		return nil, b.whereClause.err
+	}
+	switch {
+	case len(b.clauses) > 0:
+		return nil, errors.New("AST: custom clauses are not supported")
+	case b.seek != nil:
+		return nil, errors.New("AST: keyset pagination is not supported")
+	case len(b.filterJoins) > 0:
+		return nil, errors.New("AST: semi- and anti-joins are not supported")
+	case len(b.indexHints) > 0:
+		return nil, errors.New("AST: index hints are not supported")
+	}
+
+	node := &QueryAST{
+		Version:    ASTVersion,
+		Distinct:   b.distinct,
+		Hints:      slices.Clone(b.hints),
+		GroupBy:    slices.Clone(b.groupBy),
+		GroupByRaw: slices.Clone(b.groupByRaw),
+		Lock:       b.lock,
+		LockOption: b.lockOption,
+		RawAliases: b.rawAliases,
+		ReadOnly:   b.readOnly,
+		MaxExecMS:  b.maxExecTime.Milliseconds(),
+		ReuseArgs:  b.reuseArgs,
+		Unlimited:  b.unlimited,
+
+		Deterministic: b.deterministic,
+		UniqueColumns: slices.Clone(b.uniqueColumns),
+	}
+	var err error
+	for _, col := range b.columns {
+		e, err := exprAST(col)
+		if err != nil {
+			return nil, err
+		}
+		node.Columns = append(node.Columns, e)
+	}
+	if b.tableClauseInterface.table != nil {
+		for _, t := range append([]interface{}{b.tableClauseInterface.table}, b.fromTables...) {
+			e, err := exprAST(t)
+			if err != nil {
+				return nil, err
+			}
+			node.From = append(node.From, e)
+		}
+	}
+	for _, j := range b.joins {
+		table, err := exprAST(j.table)
+		if err != nil {
+			return nil, err
+		}
+		on, onArgs, err := bindArgs(b.GetDialect(), j.on, j.onArgs)
+		if err != nil {
+			return nil, err
+		}
+		args, err := argsAST(onArgs)
+		if err != nil {
+			return nil, err
+		}
+		join := JoinAST{Type: j.joinType, Table: table, Args: args, Using: slices.Clone(j.using)}
+		if on != "" {
+			join.Suffix = " ON " + on
+		}
+		node.Joins = append(node.Joins, join)
+	}
+	if node.Where, err = condAST(b.GetDialect(), slices.Concat(b.whereParam, b.whereRaw), b.whereArgs, b.whereOr, b.whereClause.subqueries); err != nil {
+		return nil, err
+	}
+	if node.GroupByArgs, err = argsAST(b.groupByArgs); err != nil {
+		return nil, err
+	}
+	if node.Having, err = condAST(b.GetDialect(), slices.Concat(b.havingParam, b.havingRaw), b.havingArgs, b.havingOr, b.havingSubqueries); err != nil {
+		return nil, err
+	}
+	for _, t := range b.orderBy {
+		if t.binder != nil {
+			return nil, fmt.Errorf("AST: ORDER BY %T is not supported", t.binder)
+		}
+		args, err := argsAST(t.args)
+		if err != nil {
+			return nil, err
+		}
+		o := OrderAST{Expr: t.expr, Raw: t.raw, Direction: t.suffix, Args: args}
+		switch t.nulls {
+		case NullsFirst:
+			o.Nulls = "first"
+		case NullsLast:
+			o.Nulls = "last"
+		}
+		node.OrderBy = append(node.OrderBy, o)
+	}
+	if b.limitSet {
+		limit := b.limit
+		node.Limit = &limit
+	}
+	if b.offsetSet {
+		offset := b.offset
+		node.Offset = &offset
+	}
+	return node, nil
+}
+
+// exprAST converts a column or table of a SelectBuilder.
+func exprAST(expr interface{}) (ExprAST, error) {
+	switch e := expr.(type) {
+	case string:
+		return ExprAST{Kind: ExprIdent, SQL: e}, nil
+	case raw.Raw:
+		return ExprAST{Kind: ExprRaw, SQL: string(e)}, nil
+	case sqlfunc.SqlFunc:
+		return ExprAST{Kind: ExprFunc, SQL: string(e)}, e.Err()
+	case SQLExpr:
+		args, err := argsAST(e.Args)
+		return ExprAST{Kind: ExprSQL, SQL: e.SQL, Args: args}, err
+	case *SelectBuilder:
+		query, err := e.AST()
+		return ExprAST{Kind: ExprQuery, Query: query}, err
+	case AliasExpr:
+		if _, ok := e.Expr.(AliasExpr); ok {
+			return ExprAST{}, errors.New("AST: nested aliases are not supported")
+		}
+		inner, err := exprAST(e.Expr)
+		inner.Alias = e.Alias
+		return inner, err
+	default:
+		return ExprAST{}, fmt.Errorf("AST: %T is not supported", expr)
+	}
+}
+
+// condAST converts WHERE or HAVING conditions, returning nil if there are none.
+func condAST(dialect sqldialect.Dialect, conditions []string, args []interface{}, or bool, subqueries []*SelectBuilder) (*CondAST, error) {
+	if len(conditions) == 0 && len(args) == 0 {
+		return nil, nil
+	}
+	conditions, args, err := bindConditions(dialect, conditions, args)
+	if err != nil {
+		return nil, err
+	}
+	c := &CondAST{Conditions: conditions, Or: or}
+	if c.Args, err = argsAST(args); err != nil {
+		return nil, err
+	}
+	for _, sub := range subqueries {
+		query, err := sub.AST()
+		if err != nil {
+			return nil, err
+		}
+		c.Subqueries = append(c.Subqueries, query)
+	}
+	return c, nil
+}
+
+// bindConditions expands the Binder args of conditions, such as the subqueries of
+// ConditionBuilder.Exists, into the conditions they belong to.
+func bindConditions(dialect sqldialect.Dialect, conditions []string, args []interface{}) ([]string, []interface{}, error) {
+	out := make([]string, len(conditions))
+	var outArgs []interface{}
+	next := 0
+	lx := sqllex.For(dialect)
+	for i, cond := range conditions {
+		end := min(next+countPlaceholders(lx, cond), len(args))
+		var condArgs []interface{}
+		var err error
+		if out[i], condArgs, err = bindArgs(dialect, cond, args[next:end]); err != nil {
+			return nil, nil, err
+		}
+		outArgs = append(outArgs, condArgs...)
+		next = end
+	}
+	return out, append(outArgs, args[next:]...), nil
+}
+
+// argsAST converts query args to typed args.
+func argsAST(args []interface{}) ([]ArgAST, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	out := make([]ArgAST, len(args))
+	for i, arg := range args {
+		a, err := argAST(arg)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = a
+	}
+	return out, nil
+}
+
+func argAST(arg interface{}) (ArgAST, error) {
+	switch v := arg.(type) {
+	case nil:
+		return ArgAST{Type: "null"}, nil
+	case time.Time:
+		return ArgAST{Type: "time", Value: v.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return ArgAST{Type: "bytes", Value: base64.StdEncoding.EncodeToString(v)}, nil
+	}
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Bool:
+		return ArgAST{Type: "bool", Value: v.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ArgAST{Type: "int", Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return ArgAST{}, fmt.Errorf("AST: argument %d overflows int64", v.Uint())
+		}
+		return ArgAST{Type: "int", Value: int64(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return ArgAST{Type: "float", Value: v.Float()}, nil
+	case reflect.String:
+		return ArgAST{Type: "string", Value: v.String()}, nil
+	default:
+		return ArgAST{}, fmt.Errorf("AST: argument of type %T is not supported", arg)
+	}
+}
+
+// FromAST rebuilds a SelectBuilder from its structured form, as returned by
+// SelectBuilder.AST. The rebuilt builder produces the same SQL and args as the original.
+func FromAST(node *QueryAST) (*SelectBuilder, error) {
+	if node == nil {
+		return nil, errors.New("FromAST: node is nil")
+	}
+	if node.Version != ASTVersion {
+		return nil, fmt.Errorf("FromAST: unsupported version %d", node.Version)
+	}
+
+	b := &SelectBuilder{
+		distinct:    node.Distinct,
+		hints:       slices.Clone(node.Hints),
+		groupBy:     slices.Clone(node.GroupBy),
+		groupByRaw:  slices.Clone(node.GroupByRaw),
+		lock:        node.Lock,
+		lockOption:  node.LockOption,
+		rawAliases:  node.RawAliases,
+		readOnly:    node.ReadOnly,
+		maxExecTime: time.Duration(node.MaxExecMS) * time.Millisecond,
+		reuseArgs:   node.ReuseArgs,
+		unlimited:   node.Unlimited,
+
+		deterministic: node.Deterministic,
+		uniqueColumns: slices.Clone(node.UniqueColumns),
+	}
+	var err error
+	for _, e := range node.Columns {
+		col, err := exprFromAST(e)
+		if err != nil {
+			return nil, err
+		}
+		b.columns = append(b.columns, col)
+	}
+	for i, e := range node.From {
+		table, err := exprFromAST(e)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			b.tableClauseInterface.table = table
+		} else {
+			b.fromTables = append(b.fromTables, table)
+		}
+	}
+	for _, j := range node.Joins {
+		table, err := exprFromAST(j.Table)
+		if err != nil {
+			return nil, err
+		}
+		args, err := argsFromAST(j.Args)
+		if err != nil {
+			return nil, err
+		}
+		join := joinClause{joinType: j.Type, table: table, onArgs: args, using: slices.Clone(j.Using)}
+		switch {
+		case strings.HasPrefix(j.Suffix, " ON "):
+			join.on = strings.TrimPrefix(j.Suffix, " ON ")
+		case strings.HasPrefix(j.Suffix, " USING (") && strings.HasSuffix(j.Suffix, ")"):
+			for _, col := range strings.Split(j.Suffix[len(" USING ("):len(j.Suffix)-1], ",") {
+				join.using = append(join.using, strings.Trim(strings.TrimSpace(col), "`\""))
+			}
+		case j.Suffix != "":
+			return nil, fmt.Errorf("FromAST: invalid join suffix %q", j.Suffix)
+		}
+		b.joins = append(b.joins, join)
+	}
+	if node.Where != nil {
+		if b.whereParam, b.whereArgs, b.whereClause.subqueries, err = condFromAST(node.Where); err != nil {
+			return nil, err
+		}
+		b.whereOr = node.Where.Or
+	}
+	if b.groupByArgs, err = argsFromAST(node.GroupByArgs); err != nil {
+		return nil, err
+	}
+	if node.Having != nil {
+		if b.havingParam, b.havingArgs, b.havingSubqueries, err = condFromAST(node.Having); err != nil {
+			return nil, err
+		}
+		b.havingOr = node.Having.Or
+	}
+	for _, o := range node.OrderBy {
+		args, err := argsFromAST(o.Args)
+		if err != nil {
+			return nil, err
+		}
+		t := orderTerm{expr: o.Expr, raw: o.Raw, suffix: o.Direction, args: args}
+		switch o.Nulls {
+		case "":
+		case "first":
+			t.nulls = NullsFirst
+		case "last":
+			t.nulls = NullsLast
+		default:
+			return nil, fmt.Errorf("FromAST: unknown nulls order %q", o.Nulls)
+		}
+		b.orderBy = append(b.orderBy, t)
+	}
+	if node.Limit != nil {
+		b.limitSet, b.limit = true, *node.Limit
+	}
+	if node.Offset != nil {
+		b.offsetSet, b.offset = true, *node.Offset
+	}
+	return b, nil
+}
+
+// exprFromAST rebuilds a column or table.
+func exprFromAST(e ExprAST) (interface{}, error) {
+	var expr interface{}
+	switch e.Kind {
+	case ExprIdent:
+		expr = e.SQL
+	case ExprRaw:
+		expr = raw.Raw(e.SQL)
+	case ExprFunc:
+		expr = sqlfunc.SqlFunc(e.SQL)
+	case ExprSQL:
+		args, err := argsFromAST(e.Args)
+		if err != nil {
+			return nil, err
+		}
+		expr = SQLExpr{SQL: e.SQL, Args: args}
+	case ExprQuery:
+		query, err := FromAST(e.Query)
+		if err != nil {
+			return nil, err
+		}
+		expr = query
+	default:
+		return nil, fmt.Errorf("FromAST: unknown expression kind %q", e.Kind)
+	}
+	if e.Alias != "" {
+		return AliasExpr{Expr: expr, Alias: e.Alias}, nil
+	}
+	return expr, nil
+}
+
+// condFromAST rebuilds WHERE or HAVING conditions.
+func condFromAST(c *CondAST) ([]string, []interface{}, []*SelectBuilder, error) {
+	args, err := argsFromAST(c.Args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var subqueries []*SelectBuilder
+	for _, node := range c.Subqueries {
+		sub, err := FromAST(node)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		subqueries = append(subqueries, sub)
+	}
+	return slices.Clone(c.Conditions), args, subqueries, nil
+}
+
+// argsFromAST converts typed args back to query args.
+func argsFromAST(args []ArgAST) ([]interface{}, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	out := make([]interface{}, len(args))
+	for i, a := range args {
+		v, err := a.value()
+		if err != nil {
+			return nil, fmt.Errorf("FromAST: argument %d: %w", i+1, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// value returns the Go value of the arg. Value may hold what encoding/json decoded it to, or
+// the value AST stored.
+func (a ArgAST) value() (interface{}, error) {
+	switch a.Type {
+	case "null":
+		return nil, nil
+	case "bool":
+		if v, ok := a.Value.(bool); ok {
+			return v, nil
+		}
+	case "int":
+		switch v := a.Value.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+				return int64(v), nil
+			}
+		case json.Number:
+			return v.Int64()
+		}
+	case "float":
+		switch v := a.Value.(type) {
+		case float64:
+			return v, nil
+		case json.Number:
+			return v.Float64()
+		}
+	case "string":
+		if v, ok := a.Value.(string); ok {
+			return v, nil
+		}
+	case "bytes":
+		if v, ok := a.Value.(string); ok {
+			return base64.StdEncoding.DecodeString(v)
+		}
+	case "time":
+		if v, ok := a.Value.(string); ok {
+			return time.Parse(time.RFC3339Nano, v)
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q", a.Type)
+	}
+	return nil, fmt.Errorf("invalid %s value %v", a.Type, a.Value)
+}
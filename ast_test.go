@@ -0,0 +1,143 @@
+package sqltk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+func TestSelectAST(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	paid := func() *SelectBuilder {
+		return Select("user_id").From("orders").WhereEqual("status", "paid")
+	}
+
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+	}{
+		{
+			name: "report",
+			builder: Select("u.id", "u.email AS contact", raw.Raw("COUNT(o.id)"), Alias(sqlfunc.SqlFunc("MAX(o.total)"), "max_total")).
+				Distinct().
+				From("users u").
+				LeftJoin(Alias(paid(), "o")).OnCond(NewStringCondition("o.user_id = u.id AND o.kind = ?", "web")).
+				Where(NewCond().GreaterThan("u.created_at", since).In("u.role", "admin", "staff")).
+				WhereIn("u.id", Select("user_id").From("admins")).
+				OrWhere(NewStringCondition("u.vip = ?", true)).
+				GroupBy("u.id", Expr("date_trunc(?, u.created_at)", "day")).
+				Having(NewStringCondition("COUNT(o.id) > ?", 2)).
+				OrderByExpr(Expr("u.score * ?", 1.5), Desc, NullsLast).
+				OrderBy("u.id").
+				Limit(10).
+				Offset(20),
+		},
+		{
+			name:    "join using",
+			builder: Select("id").From("users").Join("accounts").Using("tenant_id", "user_id").WhereEqual("active", true),
+		},
+		{
+			name:    "several tables and locking",
+			builder: Select(Expr("? AS tag", []byte("x")), "a.id").From(Alias("accounts", "a"), raw.Raw("generate_series(1, 3) AS n")).WhereEqual("a.id", nil).ForUpdate().ReadOnly(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dialect := range []sqldialect.Dialect{sqldialect.MySQL(), sqldialect.Postgres()} {
+				wantSQL, wantArgs, err := tt.builder.WithDialect(dialect).Build()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				node, err := tt.builder.AST()
+				if err != nil {
+					t.Fatalf("unexpected AST error: %v", err)
+				}
+				data, err := json.Marshal(node)
+				if err != nil {
+					t.Fatalf("unexpected marshal error: %v", err)
+				}
+				var decoded QueryAST
+				if err := json.Unmarshal(data, &decoded); err != nil {
+					t.Fatalf("unexpected unmarshal error: %v", err)
+				}
+				rebuilt, err := FromAST(&decoded)
+				if err != nil {
+					t.Fatalf("unexpected FromAST error: %v", err)
+				}
+
+				sql, args, err := rebuilt.WithDialect(dialect).Build()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if sql != wantSQL {
+					t.Errorf("got SQL %q, want %q", sql, wantSQL)
+				}
+				if fmt.Sprint(args) != fmt.Sprint(wantArgs) {
+					t.Errorf("got args %v, want %v", args, wantArgs)
+				}
+				if !reflect.DeepEqual(rebuilt.GetTables(), tt.builder.GetTables()) {
+					t.Errorf("got tables %q, want %q", rebuilt.GetTables(), tt.builder.GetTables())
+				}
+			}
+		})
+	}
+
+	t.Run("argument types", func(t *testing.T) {
+		q := Select("id").From("events").
+			Where(NewStringCondition("a = ? AND b = ? AND c = ? AND d = ? AND e = ?", uint8(7), 2.5, since, []byte{0, 1}, "s"))
+		node, err := q.AST()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, _ := json.Marshal(node)
+		var decoded QueryAST
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rebuilt, err := FromAST(&decoded)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, args, _ := rebuilt.Build()
+		want := []interface{}{int64(7), 2.5, since, []byte{0, 1}, "s"}
+		if !reflect.DeepEqual(args, want) {
+			t.Errorf("got args %#v, want %#v", args, want)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		for name, q := range map[string]*SelectBuilder{
+			"window function": Select(sqlfunc.RowNumber().Over().OrderBy("id")).From("users"),
+			"semi-join":       Select("id").From("users").SemiJoin("orders", JoinKey{Left: "users.id", Right: "orders.user_id"}),
+			"argument type":   Select("id").From("users").Where(NewStringCondition("tags = ?", []string{"a"})),
+			"builder error":   Select("id").From("users").Where(nil),
+		} {
+			if _, err := q.AST(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("bad AST", func(t *testing.T) {
+		for name, node := range map[string]*QueryAST{
+			"nil":          nil,
+			"version":      {Version: 99},
+			"kind":         {Version: ASTVersion, Columns: []ExprAST{{Kind: "column"}}},
+			"argument":     {Version: ASTVersion, Where: &CondAST{Conditions: []string{"a = ?"}, Args: []ArgAST{{Type: "int", Value: "seven"}}}},
+			"nulls order":  {Version: ASTVersion, OrderBy: []OrderAST{{Expr: "id", Nulls: "middle"}}},
+			"nested query": {Version: ASTVersion, From: []ExprAST{{Kind: ExprQuery}}},
+		} {
+			if _, err := FromAST(node); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// foreignKey is a foreign key between two tables of a Schema.
+type foreignKey struct {
+	table      string
+	columns    []string
+	refTable   string
+	refColumns []string
+}
+
+// AddForeignKey records that columns of table reference refColumns of refTable, for AutoJoin.
+// With ddl definitions, the foreign keys come from CreateTableBuilder.GetRelations.
+func (s *Schema) AddForeignKey(table string, columns []string, refTable string, refColumns []string) *Schema {
+	s.foreignKeys = append(s.foreignKeys, foreignKey{
+		table:      strings.ToLower(table),
+		columns:    append([]string(nil), columns...),
+		refTable:   strings.ToLower(refTable),
+		refColumns: append([]string(nil), refColumns...),
+	})
+	return s
+}
+
+// References returns the tables that table references through its foreign keys, in the order
+// they were added, without duplicates. Table names are lowercase.
+func (s *Schema) References(table string) []string {
+	table = strings.ToLower(table)
+	var refs []string
+	for _, fk := range s.foreignKeys {
+		if fk.table == table && !slices.Contains(refs, fk.refTable) {
+			refs = append(refs, fk.refTable)
+		}
+	}
+	return refs
+}
+
+// joinCondition returns the column pairs joining table to other through their foreign key.
+// The first column of each pair belongs to table.
+func (s *Schema) joinCondition(table, other string) ([][2]string, error) {
+	table, other = strings.ToLower(table), strings.ToLower(other)
+	var found *foreignKey
+	flipped := false
+	for i := range s.foreignKeys {
+		fk := &s.foreignKeys[i]
+		var match, flip bool
+		switch {
+		case fk.table == table && fk.refTable == other:
+			match = true
+		case fk.table == other && fk.refTable == table:
+			match, flip = true, true
+		}
+		if !match {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("schema: more than one foreign key between %q and %q", table, other)
+		}
+		found, flipped = fk, flip
+	}
+	if found == nil {
+		return nil, fmt.Errorf("schema: no foreign key between %q and %q", table, other)
+	}
+	if len(found.columns) == 0 || len(found.columns) != len(found.refColumns) {
+		return nil, fmt.Errorf("schema: foreign key of %q has %d columns but references %d", found.table, len(found.columns), len(found.refColumns))
+	}
+
+	pairs := make([][2]string, len(found.columns))
+	for i := range found.columns {
+		if flipped {
+			pairs[i] = [2]string{found.refColumns[i], found.columns[i]}
+		} else {
+			pairs[i] = [2]string{found.columns[i], found.refColumns[i]}
+		}
+	}
+	return pairs, nil
+}
+
+// AutoJoin joins table to the query, with an ON condition taken from the foreign key between
+// table and to in the schema set with WithSchema. Either table may hold the foreign key. Both
+// are table names, optionally followed by an alias (e.g. "orders o"), and to must already be
+// part of the query. It is an error if the tables have no foreign key or more than one.
+//
+// Example usage:
+//
+//	schema := sqltk.NewSchema().AddForeignKey("orders", []string{"user_id"}, "users", []string{"id"})
+//	q := sqltk.Select("u.id", "o.total").From("users u").WithSchema(schema).AutoJoin("orders o", "users u")
+//	// ... JOIN `orders o` ON o.user_id = u.id
+func (b *SelectBuilder) AutoJoin(table, to string) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	if b.schema == nil {
+		b.whereClause.err = errors.New("AutoJoin: a schema is required, see WithSchema")
+		return b
+	}
+	tableFields, toFields := strings.Fields(table), strings.Fields(to)
+	if len(tableFields) == 0 || len(toFields) == 0 {
+		b.whereClause.err = errors.New("AutoJoin: tables must be set")
+		return b
+	}
+	pairs, err := b.schema.joinCondition(tableFields[0], toFields[0])
+	if err != nil {
+		b.whereClause.err = fmt.Errorf("AutoJoin: %w", err)
+		return b
+	}
+
+	jb := b.Join(table)
+	for _, p := range pairs {
+		jb.OnEqual(tableAlias(table)+"."+p[0], tableAlias(to)+"."+p[1])
+	}
+	return jb.End()
+}
@@ -0,0 +1,85 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectAutoJoin(t *testing.T) {
+	col := func(name string) SchemaColumn { return SchemaColumn{Name: name} }
+	schema := NewSchema().
+		AddTable("users", col("id")).
+		AddTable("orders", col("id"), col("tenant_id"), col("user_id"), col("total")).
+		AddTable("order_items", col("id"), col("tenant_id"), col("order_id")).
+		AddTable("accounts", col("id")).
+		AddTable("transfers", col("id"), col("from_account"), col("to_account")).
+		AddForeignKey("orders", []string{"user_id"}, "users", []string{"id"}).
+		AddForeignKey("order_items", []string{"tenant_id", "order_id"}, "orders", []string{"tenant_id", "id"}).
+		AddForeignKey("transfers", []string{"from_account"}, "accounts", []string{"id"}).
+		AddForeignKey("transfers", []string{"to_account"}, "accounts", []string{"id"})
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:    "joined table holds the foreign key",
+			builder: Select("u.id", "o.total").From("users u").WithSchema(schema).AutoJoin("orders o", "users u"),
+			wantSQL: "SELECT u.id, o.total FROM users u JOIN orders o ON o.user_id = u.id",
+		},
+		{
+			name:    "query table holds the foreign key",
+			builder: Select("orders.id").From("orders").WithSchema(schema).AutoJoin("users", "orders"),
+			wantSQL: "SELECT orders.id FROM orders JOIN users ON users.id = orders.user_id",
+		},
+		{
+			name:    "composite key",
+			builder: Select("i.id").From("order_items i").WithSchema(schema).AutoJoin("orders o", "order_items i"),
+			wantSQL: "SELECT i.id FROM order_items i JOIN orders o ON o.tenant_id = i.tenant_id AND o.id = i.order_id",
+		},
+		{
+			name:    "no foreign key",
+			builder: Select("id").From("users").WithSchema(schema).AutoJoin("accounts", "users"),
+			wantErr: true,
+		},
+		{
+			name:    "ambiguous foreign key",
+			builder: Select("id").From("transfers").WithSchema(schema).AutoJoin("accounts", "transfers"),
+			wantErr: true,
+		},
+		{
+			name:    "no schema",
+			builder: Select("id").From("users").AutoJoin("orders", "users"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(sqldialect.NoQuoteIdent()).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if tt.wantArgs == nil {
+				tt.wantArgs = []interface{}{}
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// Statement is a built SQL statement and its arguments.
+type Statement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Batch holds builders that are built, and usually executed, in order.
+type Batch struct {
+	builders []Builder
+	err      error
+}
+
+// NewBatch creates a new Batch with the given builders.
+func NewBatch(builders ...Builder) *Batch {
+	return (&Batch{}).Add(builders...)
+}
+
+// Add appends builders to the batch.
+func (b *Batch) Add(builders ...Builder) *Batch {
+	b.builders = append(b.builders, builders...)
+	return b
+}
+
+// Len returns the number of builders in the batch.
+func (b *Batch) Len() int {
+	return len(b.builders)
+}
+
+// Statements builds every builder in the batch and returns the statements, or the first build error.
+func (b *Batch) Statements() ([]*Statement, error) {
+	stmts := make([]*Statement, 0, len(b.builders))
+	for stmt := range b.All() {
+		stmts = append(stmts, stmt)
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	return stmts, nil
+}
+
+// All returns an iterator that builds each statement as it is requested.
+// Iteration stops at the first build error, which is then reported by Err.
+//
+// Example usage:
+//
+//	for stmt := range batch.All() {
+//		db.Exec(stmt.SQL, stmt.Args...)
+//	}
+//	if err := batch.Err(); err != nil { ... }
+func (b *Batch) All() iter.Seq[*Statement] {
+	return func(yield func(*Statement) bool) {
+		b.err = nil
+		for i, builder := range b.builders {
+			if builder == nil {
+				b.err = fmt.Errorf("batch: statement %d: %w", i+1, errors.New("builder is nil"))
+				return
+			}
+			sql, args, err := builder.Build()
+			if err != nil {
+				b.err = fmt.Errorf("batch: statement %d: %w", i+1, err)
+				return
+			}
+			if !yield(&Statement{SQL: sql, Args: args}) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the build error that stopped the last iteration over All, if any.
+func (b *Batch) Err() error {
+	return b.err
+}
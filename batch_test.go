@@ -0,0 +1,67 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	t.Run("all yields statements in order", func(t *testing.T) {
+		batch := NewBatch(
+			Insert("users").Columns("name").Values("Alice"),
+			Update("users").Set("active", true).WhereEqual("name", "Alice"),
+		).Add(Delete("sessions").WhereEqual("user", "Alice"))
+
+		var got []Statement
+		for stmt := range batch.All() {
+			got = append(got, *stmt)
+		}
+		want := []Statement{
+			{SQL: "INSERT INTO users (name) VALUES (?)", Args: []interface{}{"Alice"}},
+			{SQL: "UPDATE users SET active = ? WHERE name = ?", Args: []interface{}{true, "Alice"}},
+			{SQL: "DELETE FROM sessions WHERE user = ?", Args: []interface{}{"Alice"}},
+		}
+		if err := batch.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got statements %v, want %v", got, want)
+		}
+		if batch.Len() != 3 {
+			t.Errorf("got len %d, want 3", batch.Len())
+		}
+	})
+
+	t.Run("break stops building", func(t *testing.T) {
+		batch := NewBatch(Delete("a"), Delete("b"))
+		n := 0
+		for range batch.All() {
+			n++
+			break
+		}
+		if n != 1 {
+			t.Errorf("got %d statements, want 1", n)
+		}
+	})
+
+	t.Run("build error stops iteration", func(t *testing.T) {
+		batch := NewBatch(Delete("a"), Insert("b"), Delete("c"))
+		stmts, err := batch.Statements()
+		if err == nil {
+			t.Fatal("expected error for invalid insert")
+		}
+		if stmts != nil {
+			t.Errorf("got statements %v, want nil", stmts)
+		}
+	})
+
+	t.Run("statements", func(t *testing.T) {
+		stmts, err := NewBatch(Delete("a")).Statements()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stmts) != 1 || stmts[0].SQL != "DELETE FROM a" {
+			t.Errorf("got statements %v", stmts)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package sqltk
+
+import (
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Binder is implemented by values that control how they are rendered and bound,
+// such as money amounts, enums, or encrypted strings. When a builder encounters a
+// Binder as an argument, it replaces the argument's placeholder with the SQL returned
+// by BindSQL and binds the returned args in its place. A Binder can also be used as a
+// SELECT column, for expressions that need bound parameters.
+//
+// The returned SQL must use ? for each of its args; builders renumber placeholders
+// for the dialect. For example, a type could return ("LOWER(?)", []interface{}{v}).
+type Binder interface {
+	BindSQL(dialect sqldialect.Dialect) (placeholderSQL string, args []interface{})
+}
+
+// bindChecker is implemented by Binders that cannot be bound for every dialect, such as the
+// condition of Regexp. bindExpr and bindArgs return the error of checkBind instead of binding.
+type bindChecker interface {
+	checkBind(dialect sqldialect.Dialect) error
+}
+
+// checkBinders returns the error of the first of args that cannot be bound for dialect.
+func checkBinders(dialect sqldialect.Dialect, args []interface{}) error {
+	for _, arg := range args {
+		if c, ok := arg.(bindChecker); ok {
+			if err := c.checkBind(dialect); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindExpr renders a Binder used directly as an expression.
+func bindExpr(dialect sqldialect.Dialect, binder Binder) (string, []interface{}, error) {
+	dialect = baseDialect(dialect)
+	if c, ok := binder.(bindChecker); ok {
+		if err := c.checkBind(dialect); err != nil {
+			return "", nil, err
+		}
+	}
+	sql, args := binder.BindSQL(dialect)
+	return sql, args, nil
+}
+
+// bindArgs expands Binder args in a SQL fragment that uses ? placeholders.
+// Args without a matching placeholder are kept as-is at the end. Like numberPlaceholders,
+// it ignores ? in quoted text and comments, and the ?? escape. It returns an error if an arg
+// cannot be bound for dialect.
+func bindArgs(dialect sqldialect.Dialect, sql string, args []interface{}) (string, []interface{}, error) {
+	hasBinder := false
+	for _, arg := range args {
+		if _, ok := arg.(Binder); ok {
+			hasBinder = true
+			break
+		}
+	}
+	if !hasBinder {
+		return sql, args, nil
+	}
+	dialect = baseDialect(dialect)
+	if err := checkBinders(dialect, args); err != nil {
+		return "", nil, err
+	}
+	lx := sqllex.For(dialect)
+
+	var sb strings.Builder
+	bound := make([]interface{}, 0, len(args))
+	argIdx := 0
+	for i := 0; i < len(sql); i++ {
+		if end := lx.Skip(sql, i); end > i {
+			sb.WriteString(sql[i:end])
+			i = end - 1
+			continue
+		}
+		if sql[i] == '?' && i+1 < len(sql) && sql[i+1] == '?' {
+			sb.WriteString("??")
+			i++
+			continue
+		}
+		if sql[i] != '?' || argIdx >= len(args) {
+			sb.WriteByte(sql[i])
+			continue
+		}
+		arg := args[argIdx]
+		argIdx++
+		if binder, ok := arg.(Binder); ok {
+			bindSQL, bindArgs := binder.BindSQL(dialect)
+			sb.WriteString(bindSQL)
+			bound = append(bound, bindArgs...)
+			continue
+		}
+		sb.WriteByte('?')
+		bound = append(bound, arg)
+	}
+	bound = append(bound, args[argIdx:]...)
+	return sb.String(), bound, nil
+}
@@ -0,0 +1,114 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// lowerBinder binds a value through LOWER(?).
+type lowerBinder string
+
+func (l lowerBinder) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	return "LOWER(?)", []interface{}{string(l)}
+}
+
+// moneyBinder binds an amount and currency, rendering differently per dialect.
+type moneyBinder struct {
+	cents    int
+	currency string
+}
+
+func (m moneyBinder) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	if dialect == sqldialect.Postgres() {
+		return "ROW(?, ?)::money_t", []interface{}{m.cents, m.currency}
+	}
+	return "?", []interface{}{m.cents}
+}
+
+func TestBinder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "where equal",
+			builder:  Select("id").From("users").WhereEqual("email", lowerBinder("A@B.C")).WithDialect(sqldialect.NoQuoteIdent()),
+			wantSQL:  "SELECT id FROM users WHERE email = LOWER(?)",
+			wantArgs: []interface{}{"A@B.C"},
+		},
+		{
+			name: "condition builder with postgres numbering",
+			builder: Select("id").From("orders").
+				Where(NewCond().Equal("price", moneyBinder{100, "EUR"}).Equal("status", "paid")).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "orders" WHERE price = ROW($1, $2)::money_t AND status = $3`,
+			wantArgs: []interface{}{100, "EUR", "paid"},
+		},
+		{
+			name:     "in list",
+			builder:  Delete("users").WhereIn("email", "x", lowerBinder("Y")).WithDialect(sqldialect.NoQuoteIdent()),
+			wantSQL:  "DELETE FROM users WHERE email IN (?, LOWER(?))",
+			wantArgs: []interface{}{"x", "Y"},
+		},
+		{
+			name:     "update set",
+			builder:  Update("users").Set("email", lowerBinder("A")).WhereEqual("id", 1).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `UPDATE "users" SET email = LOWER($1) WHERE id = $2`,
+			wantArgs: []interface{}{"A", 1},
+		},
+		{
+			name:     "insert values",
+			builder:  Insert("prices").Columns("id", "amount").Values(1, moneyBinder{250, "USD"}).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `INSERT INTO "prices" ("id", "amount") VALUES ($1, ROW($2, $3)::money_t)`,
+			wantArgs: []interface{}{1, 250, "USD"},
+		},
+		{
+			name: "having",
+			builder: Select("team").From("scores").GroupBy("team").
+				Having(NewStringCondition("MAX(name) = ?", lowerBinder("Z"))).WithDialect(sqldialect.NoQuoteIdent()),
+			wantSQL:  "SELECT team FROM scores GROUP BY team HAVING MAX(name) = LOWER(?)",
+			wantArgs: []interface{}{"Z"},
+		},
+		{
+			name: "join condition",
+			builder: Select("u.id").From("users u").
+				Join("emails e").OnEqual("e.user_id", "u.id").And(NewStringCondition("e.address = ?", lowerBinder("Q"))).End().
+				WithDialect(sqldialect.NoQuoteIdent()),
+			wantSQL:  "SELECT u.id FROM users u JOIN emails e ON e.user_id = u.id AND e.address = LOWER(?)",
+			wantArgs: []interface{}{"Q"},
+		},
+		{
+			name: "compound parts see the real dialect",
+			builder: Select("id").From("a").Where(NewStringCondition("p = ?", moneyBinder{1, "EUR"})).
+				Union(Select("id").From("b")).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "a" WHERE p = ROW($1, $2)::money_t UNION SELECT "id" FROM "b"`,
+			wantArgs: []interface{}{1, "EUR"},
+		},
+		{
+			name: "select column with alias",
+			builder: Select("id", Alias(moneyBinder{5, "EUR"}, "price")).From("items").WhereEqual("id", 7).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id", ROW($1, $2)::money_t AS "price" FROM "items" WHERE id = $3`,
+			wantArgs: []interface{}{5, "EUR", 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
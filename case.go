@@ -0,0 +1,174 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+// CaseExpr is a CASE expression built with Case. It is a Binder, so it can be used as a
+// SELECT column, in Alias, in OrderBy, and as a value in Set.
+type CaseExpr struct {
+	whens   []caseWhen
+	elseVal interface{}
+	hasElse bool
+	columns bool // see ColumnResults
+	err     error
+}
+
+// caseWhen is a WHEN clause of a CaseExpr.
+type caseWhen struct {
+	cond     string // with ? placeholders
+	condArgs []interface{}
+	result   interface{}
+}
+
+// Case starts a CASE expression. THEN and ELSE results are bound as args, unless they are
+// raw.Raw, sqlfunc.SqlFunc, or a Binder such as Expr, or ColumnResults is set.
+//
+// Example usage:
+//
+//	sqltk.Select("id", sqltk.Alias(sqltk.Case().
+//		When(sqltk.NewCond().GreaterThan("total", 1000), "large").
+//		When(sqltk.NewCond().GreaterThan("total", 100), "medium").
+//		Else("small"), "size")).From("orders")
+//	// SELECT id, CASE WHEN total > ? THEN ? WHEN total > ? THEN ? ELSE ? END AS size FROM orders
+func Case() *CaseExpr {
+	return &CaseExpr{}
+}
+
+// When adds a WHEN clause. The condition is a Condition, such as a *ConditionBuilder, or raw
+// SQL as a raw.Raw or string.
+func (e *CaseExpr) When(condition interface{}, result interface{}) *CaseExpr {
+	if e.err != nil {
+		return e
+	}
+	var w caseWhen
+	switch c := condition.(type) {
+	case Condition:
+		sql, args, err := c.BuildCondition()
+		if err != nil {
+			e.err = fmt.Errorf("Case: when condition error: %w", err)
+			return e
+		}
+		w.cond, w.condArgs = sql, args
+	case raw.Raw:
+		w.cond = string(c)
+	case string:
+		w.cond = c
+	default:
+		e.err = fmt.Errorf("Case: when condition must be a Condition, raw.Raw, or string (got %T)", condition)
+		return e
+	}
+	if w.cond == "" {
+		e.err = errors.New("Case: when condition must not be empty")
+		return e
+	}
+	w.result = result
+	e.whens = append(e.whens, w)
+	return e
+}
+
+// Else sets the ELSE result. Without it, the expression is NULL when no WHEN matches.
+func (e *CaseExpr) Else(value interface{}) *CaseExpr {
+	e.elseVal = value
+	e.hasElse = true
+	return e
+}
+
+// ColumnResults renders string THEN and ELSE results as column references, quoted for the
+// dialect, instead of binding them, as in CASE WHEN ... THEN price_eur ELSE price_usd END.
+func (e *CaseExpr) ColumnResults() *CaseExpr {
+	e.columns = true
+	return e
+}
+
+// Err returns the error of the expression, if any. The builders it is used in report it.
+func (e *CaseExpr) Err() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.whens) == 0 {
+		return errors.New("Case: at least one WHEN clause must be set")
+	}
+	for _, w := range e.whens {
+		if err := exprErr(w.result); err != nil {
+			return fmt.Errorf("Case: %w", err)
+		}
+	}
+	if err := exprErr(e.elseVal); err != nil {
+		return fmt.Errorf("Case: %w", err)
+	}
+	return nil
+}
+
+// checkBind reports conditions and results of the expression that cannot be bound for
+// dialect, such as a Regexp condition.
+func (e *CaseExpr) checkBind(dialect sqldialect.Dialect) error {
+	values := []interface{}{e.elseVal}
+	for _, w := range e.whens {
+		if err := checkBinders(dialect, w.condArgs); err != nil {
+			return fmt.Errorf("Case: %w", err)
+		}
+		values = append(values, w.result)
+	}
+	if err := checkBinders(dialect, values); err != nil {
+		return fmt.Errorf("Case: %w", err)
+	}
+	return nil
+}
+
+// BindSQL implements Binder.
+func (e *CaseExpr) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	var sb strings.Builder
+	var args []interface{}
+	sb.WriteString("CASE")
+	for _, w := range e.whens {
+		cond, condArgs, _ := bindArgs(dialect, w.cond, w.condArgs) // checked by checkBind
+		result, resultArgs := e.result(dialect, w.result)
+		sb.WriteString(" WHEN " + cond + " THEN " + result)
+		args = append(args, condArgs...)
+		args = append(args, resultArgs...)
+	}
+	if e.hasElse {
+		result, resultArgs := e.result(dialect, e.elseVal)
+		sb.WriteString(" ELSE " + result)
+		args = append(args, resultArgs...)
+	}
+	sb.WriteString(" END")
+	return sb.String(), args
+}
+
+// result renders a THEN or ELSE result.
+func (e *CaseExpr) result(dialect sqldialect.Dialect, value interface{}) (string, []interface{}) {
+	if col, ok := value.(string); ok && e.columns {
+		return quoteQualifiedIdent(dialect, col), nil
+	}
+	sql, args, _ := buildValue(dialect, value)
+	return sql, args
+}
+
+// exprErr returns the error recorded by an expression, such as a CaseExpr or sqlfunc.SqlFunc.
+func exprErr(expr interface{}) error {
+	if e, ok := expr.(interface{ Err() error }); ok {
+		return e.Err()
+	}
+	return nil
+}
+
+// argsErr returns the error recorded by a sqlfunc.SqlFunc bound as an arg, such as a value of
+// Where or Values, so that the SqlFunc of sqlfunc.Invalid is rejected wherever it is passed.
+func argsErr(args []interface{}) error {
+	for _, arg := range args {
+		if fn, ok := arg.(sqlfunc.SqlFunc); ok {
+			if err := fn.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
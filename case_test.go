@@ -0,0 +1,102 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCase(t *testing.T) {
+	size := func() *CaseExpr {
+		return Case().
+			When(NewCond().GreaterThan("total", 1000), "large").
+			When(NewCond().GreaterThan("total", 100), "medium").
+			Else("small")
+	}
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "column",
+			builder:  Select("id", Alias(size(), "size")).From("orders"),
+			wantSQL:  "SELECT id, CASE WHEN total > ? THEN ? WHEN total > ? THEN ? ELSE ? END AS size FROM orders",
+			wantArgs: []interface{}{1000, "large", 100, "medium", "small"},
+		},
+		{
+			name:     "postgres numbering",
+			builder:  Select("id", size()).From("orders").WhereEqual("status", "paid").WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id", CASE WHEN total > $1 THEN $2 WHEN total > $3 THEN $4 ELSE $5 END FROM "orders" WHERE status = $6`,
+			wantArgs: []interface{}{1000, "large", 100, "medium", "small", "paid"},
+		},
+		{
+			name: "column results",
+			builder: Select("id", Alias(Case().ColumnResults().
+				When(NewStringCondition("currency = ?", "EUR"), "prices.eur").
+				Else("prices.usd"), "price")).From("prices").WithDialect(sqldialect.MySQL()),
+			wantSQL:  "SELECT `id`, CASE WHEN currency = ? THEN `prices`.`eur` ELSE `prices`.`usd` END AS `price` FROM `prices`",
+			wantArgs: []interface{}{"EUR"},
+		},
+		{
+			name:     "raw results and no else",
+			builder:  Select("id", Case().When(raw.Raw("a IS NULL"), raw.Raw("b")).When("a > 0", Expr("a * ?", 2))).From("t"),
+			wantSQL:  "SELECT id, CASE WHEN a IS NULL THEN b WHEN a > 0 THEN a * ? END FROM t",
+			wantArgs: []interface{}{2},
+		},
+		{
+			name: "order by",
+			builder: Select("id").From("tickets").WhereEqual("open", true).
+				OrderBy(Case().When(NewCond().Equal("priority", "urgent"), 0).Else(1)).OrderByAsc("id"),
+			wantSQL:  "SELECT id FROM tickets WHERE open = ? ORDER BY CASE WHEN priority = ? THEN ? ELSE ? END, id ASC",
+			wantArgs: []interface{}{true, "urgent", 0, 1},
+		},
+		{
+			name: "order by expr",
+			builder: Select("id").From("tickets").
+				OrderByExpr(Case().When(NewCond().IsNull("due"), 1).Else(0), Desc, NullsDefault).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "tickets" ORDER BY CASE WHEN due IS NULL THEN $1 ELSE $2 END DESC`,
+			wantArgs: []interface{}{1, 0},
+		},
+		{
+			name: "set",
+			builder: Update("orders").
+				Set("tier", Case().When(NewCond().GreaterThan("total", 1000), "gold").Else(raw.Raw("tier"))).
+				WhereEqual("id", 7),
+			wantSQL:  "UPDATE orders SET tier = CASE WHEN total > ? THEN ? ELSE tier END WHERE id = ?",
+			wantArgs: []interface{}{1000, "gold", 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for name, b := range map[string]Builder{
+			"no when":        Select("id", Case().Else(1)).From("t"),
+			"bad condition":  Select("id", Alias(Case().When(NewCond().In("a"), 1), "x")).From("t"),
+			"condition type": Select("id").From("t").OrderBy(Case().When(42, 1)),
+			"empty when":     Update("t").Set("a", Case().When("", 1)),
+		} {
+			if _, _, err := b.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
@@ -13,25 +13,145 @@ type whereClause struct {
 	whereParam []string
 	whereRaw   []string
 	whereArgs  []interface{}
+	// whereOr reports whether whereParam[0] is an OR, of earlier conditions or of a
+	// ConditionBuilder, which must be parenthesized when further conditions are ANDed to it.
+	// Later such conditions are parenthesized when they are added.
+	whereOr    bool
+	subqueries []*SelectBuilder // subqueries of the conditions, see GetTables
+	callSites  []string         // see SetTraceCallSites
 	err        error
 }
 
 func (w *whereClause) Where(cond Condition, args ...interface{}) {
-	if w.err != nil {
+	if cond == nil {
+		w.addErr(errors.New("Where: condition must not be nil"))
 		return
 	}
 
 	sql, condArgs, err := cond.BuildCondition()
 	if err != nil {
-		w.err = fmt.Errorf("Where: condition error: %w", err)
+		w.addErr(fmt.Errorf("Where: condition error: %w", err))
 		return
 	}
 	if sql != "" {
+		if condNeedsGroup(cond) {
+			if len(w.whereParam) == 0 {
+				w.whereOr = true
+			} else {
+				sql = "(" + sql + ")"
+			}
+		}
 		w.whereParam = append(w.whereParam, sql)
 		w.whereArgs = append(w.whereArgs, condArgs...)
+		w.subqueries = append(w.subqueries, condSubqueries(cond)...)
+		w.recordCall()
 	}
 }
 
+// condNeedsGroup reports whether the SQL of cond must be parenthesized when it is ANDed with
+// other conditions, as that of a ConditionBuilder whose conditions are ORed.
+func condNeedsGroup(cond Condition) bool {
+	switch c := cond.(type) {
+	case *ConditionBuilder:
+		return c.err == nil && c.expr != nil && c.expr.needsGroup()
+	case fragmentCondition:
+		if resolved, err := c.resolve(); err == nil {
+			return condNeedsGroup(resolved)
+		}
+	}
+	return false
+}
+
+// OrWhere combines the conditions added so far with cond using OR.
+func (w *whereClause) OrWhere(cond Condition) {
+	if cond == nil {
+		w.addErr(errors.New("OrWhere: condition must not be nil"))
+		return
+	}
+
+	sql, condArgs, err := cond.BuildCondition()
+	if err != nil {
+		w.addErr(fmt.Errorf("OrWhere: condition error: %w", err))
+		return
+	}
+	if sql == "" {
+		return
+	}
+	w.recordCall()
+	w.subqueries = append(w.subqueries, condSubqueries(cond)...)
+	if len(w.whereParam) == 0 {
+		w.whereParam = append(w.whereParam, sql)
+		w.whereArgs = append(w.whereArgs, condArgs...)
+		return
+	}
+
+	left := w.joinWheres()
+	if len(w.whereParam) > 1 || !w.whereOr {
+		left = "(" + left + ")"
+	}
+	w.whereParam = []string{left + " OR (" + sql + ")"}
+	w.whereArgs = append(w.whereArgs, condArgs...)
+	w.whereOr = true
+}
+
+// WhereGroup adds the conditions built by fn as a single parenthesized condition.
+// The ConditionBuilder passed to fn uses dialect if it is not nil.
+func (w *whereClause) WhereGroup(dialect sqldialect.Dialect, fn func(*ConditionBuilder)) {
+	if fn == nil {
+		w.addErr(errors.New("WhereGroup: fn must not be nil"))
+		return
+	}
+
+	cond := NewCond()
+	if dialect != nil {
+		cond.WithDialect(dialect)
+	}
+	fn(cond)
+	sql, condArgs, err := cond.BuildCondition()
+	if err != nil {
+		w.addErr(fmt.Errorf("WhereGroup: condition error: %w", err))
+		return
+	}
+	if sql == "" {
+		return
+	}
+	w.whereParam = append(w.whereParam, "("+sql+")")
+	w.whereArgs = append(w.whereArgs, condArgs...)
+	w.subqueries = append(w.subqueries, cond.subqueries...)
+	w.recordCall()
+}
+
+// addErr records err in addition to the errors recorded before, so that Build reports every
+// problem of a builder at once.
+func (w *whereClause) addErr(err error) {
+	w.err = errors.Join(w.err, err)
+}
+
+// joinWheres joins the WHERE conditions with AND, parenthesizing a leading OR.
+func (w *whereClause) joinWheres() string {
+	if w.whereOr && len(w.whereParam) > 1 {
+		return "(" + w.whereParam[0] + ") AND " + strings.Join(w.whereParam[1:], " AND ")
+	}
+	return strings.Join(w.whereParam, " AND ")
+}
+
+// merge ANDs the conditions of other onto w.
+func (w *whereClause) merge(other whereClause) {
+	if other.err != nil {
+		w.addErr(other.err)
+		return
+	}
+	params := other.whereParam
+	if other.whereOr {
+		params = append([]string{"(" + params[0] + ")"}, params[1:]...)
+	}
+	w.whereParam = append(w.whereParam, params...)
+	w.whereRaw = append(w.whereRaw, other.whereRaw...)
+	w.whereArgs = append(w.whereArgs, other.whereArgs...)
+	w.subqueries = append(w.subqueries, other.subqueries...)
+	w.callSites = append(w.callSites, other.callSites...)
+}
+
 func (w *whereClause) WhereEqual(column string, value interface{}) {
 	if value == nil {
 		w.Where(NewStringCondition(column + " IS NULL"))
@@ -48,7 +168,7 @@ func (w *whereClause) WhereNotEqual(column string, value interface{}) {
 	w.Where(NewStringCondition(column+" != ?", value))
 }
 
-func (w *whereClause) buildWhereSQL(dialect sqldialect.Dialect, placeholderIdx *int) (string, []interface{}) {
+func (w *whereClause) buildWhereSQL(dialect sqldialect.Dialect) (string, []interface{}, error) {
 	var wheres []string
 	if len(w.whereParam) > 0 {
 		wheres = append(wheres, w.whereParam...)
@@ -58,14 +178,12 @@ func (w *whereClause) buildWhereSQL(dialect sqldialect.Dialect, placeholderIdx *
 	}
 	if len(wheres) == 0 {
 		// Even if there's no WHERE clause, return any stored args (from subqueries)
-		return "", w.whereArgs
+		return "", w.whereArgs, nil
 	}
-	whereSQL := strings.Join(wheres, " AND ")
-	for strings.Contains(whereSQL, "?") && dialect.Placeholder(0) != "?" {
-		whereSQL = strings.Replace(whereSQL, "?", dialect.Placeholder(*placeholderIdx), 1)
-		*placeholderIdx++
+	if w.whereOr && len(wheres) > 1 {
+		wheres[0] = "(" + wheres[0] + ")"
 	}
-	return whereSQL, w.whereArgs
+	return bindArgs(dialect, strings.Join(wheres, " AND "), w.whereArgs)
 }
 
 // tableClauseString holds shared table and error logic for builders with string table names.
@@ -0,0 +1,189 @@
+package sqltk
+
+import "slices"
+
+// Clone returns a deep copy of the builder, so a base query can be reused and branched.
+// Changes to the copy, including appending conditions, columns, or joins, do not affect the
+// original, and vice versa. Subqueries are cloned too; argument values are shared.
+func (b *SelectBuilder) Clone() *SelectBuilder {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.tableClauseInterface.table = cloneExpr(b.tableClauseInterface.table)
+	c.columns = slices.Clone(b.columns)
+	for i, col := range c.columns {
+		c.columns[i] = cloneExpr(col)
+	}
+	c.fromTables = slices.Clone(b.fromTables)
+	for i, t := range c.fromTables {
+		c.fromTables[i] = cloneExpr(t)
+	}
+	c.joins = slices.Clone(b.joins)
+	for i := range c.joins {
+		c.joins[i].table = cloneExpr(c.joins[i].table)
+		c.joins[i].onArgs = slices.Clone(c.joins[i].onArgs)
+		c.joins[i].using = slices.Clone(c.joins[i].using)
+	}
+	c.whereClause = b.whereClause.clone()
+	c.groupBy = slices.Clone(b.groupBy)
+	c.groupByRaw = slices.Clone(b.groupByRaw)
+	c.groupByArgs = slices.Clone(b.groupByArgs)
+	c.havingParam = slices.Clone(b.havingParam)
+	c.havingRaw = slices.Clone(b.havingRaw)
+	c.havingArgs = slices.Clone(b.havingArgs)
+	c.havingSubqueries = slices.Clone(b.havingSubqueries)
+	c.orderBy = slices.Clone(b.orderBy)
+	c.clauses = slices.Clone(b.clauses)
+	c.filterJoins = slices.Clone(b.filterJoins)
+	c.hints = slices.Clone(b.hints)
+	c.indexHints = slices.Clone(b.indexHints)
+	if b.callSites != nil {
+		c.callSites = make(map[string][]string, len(b.callSites))
+		for clause, sites := range b.callSites {
+			c.callSites[clause] = slices.Clone(sites)
+		}
+	}
+	c.uniqueColumns = slices.Clone(b.uniqueColumns)
+	if b.seek != nil {
+		c.seek = &seekSpec{keys: slices.Clone(b.seek.keys), values: slices.Clone(b.seek.values)}
+	}
+	return &c
+}
+
+// cloneExpr clones the subqueries in a column or table expression.
+func cloneExpr(expr interface{}) interface{} {
+	switch e := expr.(type) {
+	case *SelectBuilder:
+		return e.Clone()
+	case AliasExpr:
+		return AliasExpr{Expr: cloneExpr(e.Expr), Alias: e.Alias}
+	case derivedTable:
+		return derivedTable{query: e.query.Clone(), alias: e.alias}
+	case existsExpr:
+		return existsExpr{query: e.query.Clone()}
+	case topNTable:
+		e.query = e.query.Clone()
+		e.partition = slices.Clone(e.partition)
+		return e
+	default:
+		return expr
+	}
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (c *CompoundBuilder) Clone() *CompoundBuilder {
+	if c == nil {
+		return nil
+	}
+	n := *c
+	n.parts = make([]compoundPart, len(c.parts))
+	for i, part := range c.parts {
+		n.parts[i] = compoundPart{op: part.op, query: part.query.Clone()}
+	}
+	n.orderBy = slices.Clone(c.orderBy)
+	return &n
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *UpdateBuilder) Clone() *UpdateBuilder {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.sets = slices.Clone(b.sets)
+	c.setArgs = slices.Clone(b.setArgs)
+	c.whereClause = b.whereClause.clone()
+	c.returning = slices.Clone(b.returning)
+	c.joins = slices.Clone(b.joins)
+	c.from = slices.Clone(b.from)
+	c.with.ctes = slices.Clone(b.with.ctes)
+	return &c
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *PostgresUpdateBuilder) Clone() *PostgresUpdateBuilder {
+	if b == nil {
+		return nil
+	}
+	return &PostgresUpdateBuilder{UpdateBuilder: b.UpdateBuilder.Clone(), returning: slices.Clone(b.returning)}
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *DeleteBuilder) Clone() *DeleteBuilder {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.whereClause = b.whereClause.clone()
+	c.returning = slices.Clone(b.returning)
+	c.with.ctes = slices.Clone(b.with.ctes)
+	return &c
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *PostgresDeleteBuilder) Clone() *PostgresDeleteBuilder {
+	if b == nil {
+		return nil
+	}
+	return &PostgresDeleteBuilder{DeleteBuilder: b.DeleteBuilder.Clone(), returning: slices.Clone(b.returning)}
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *InsertBuilder) Clone() *InsertBuilder {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	c.columns = slices.Clone(b.columns)
+	c.returning = slices.Clone(b.returning)
+	if b.values != nil {
+		c.values = make([][]interface{}, len(b.values))
+		for i, row := range b.values {
+			c.values[i] = slices.Clone(row)
+		}
+	}
+	if b.conflict != nil {
+		conflict := *b.conflict
+		conflict.targets = slices.Clone(b.conflict.targets)
+		conflict.whereArgs = slices.Clone(b.conflict.whereArgs)
+		conflict.updates = slices.Clone(b.conflict.updates)
+		c.conflict = &conflict
+	}
+	c.query = b.query.Clone()
+	c.with.ctes = slices.Clone(b.with.ctes)
+	return &c
+}
+
+// Clone returns a deep copy of the builder. See SelectBuilder.Clone.
+func (b *PostgresInsertBuilder) Clone() *PostgresInsertBuilder {
+	if b == nil {
+		return nil
+	}
+	return &PostgresInsertBuilder{InsertBuilder: b.InsertBuilder.Clone(), returning: slices.Clone(b.returning)}
+}
+
+// Clone returns a deep copy of the condition builder, so a shared condition can be extended
+// in different ways.
+func (c *ConditionBuilder) Clone() *ConditionBuilder {
+	if c == nil {
+		return nil
+	}
+	n := *c
+	n.expr = c.expr.clone()
+	n.subqueries = slices.Clone(c.subqueries)
+	return &n
+}
+
+// clone returns a copy of the WHERE clause that does not share slices with w.
+func (w whereClause) clone() whereClause {
+	return whereClause{
+		whereParam: slices.Clone(w.whereParam),
+		whereRaw:   slices.Clone(w.whereRaw),
+		whereArgs:  slices.Clone(w.whereArgs),
+		whereOr:    w.whereOr,
+		subqueries: slices.Clone(w.subqueries),
+		callSites:  slices.Clone(w.callSites),
+		err:        w.err,
+	}
+}
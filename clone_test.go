@@ -0,0 +1,115 @@
+package sqltk
+
+import (
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestClone(t *testing.T) {
+	d := sqldialect.NoQuoteIdent()
+
+	t.Run("select", func(t *testing.T) {
+		sub := Select("COUNT(*)").From("orders").WhereEqual("paid", true).WithDialect(d)
+		base := Select("id", Alias(sub, "orders")).From("users").Where(NewStringCondition("a = ?", 1)).Where(NewStringCondition("b = ?", 2)).
+			Where(NewStringCondition("e = ?", 5)).OrderByAsc("id").WithDialect(d)
+		wantBase, _, _ := base.Build()
+
+		clone := base.Clone()
+		clone.WhereEqual("c", 3).AddField("name").OrderByDesc("name").Join("teams t").On("t.id", "users.team_id")
+		sub.WhereEqual("refunded", false)
+
+		gotBase, _, _ := base.Build()
+		if gotBase == wantBase {
+			t.Fatalf("expected the original to see its own subquery change")
+		}
+
+		cloneSQL, cloneArgs, err := clone.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantClone := "SELECT id, (SELECT COUNT(*) FROM orders WHERE paid = ?) AS orders, name FROM users JOIN teams t ON t.id = users.team_id WHERE a = ? AND b = ? AND e = ? AND c = ? ORDER BY id ASC, name DESC"
+		if cloneSQL != wantClone {
+			t.Errorf("got SQL %q, want %q", cloneSQL, wantClone)
+		}
+		if len(cloneArgs) != 5 {
+			t.Errorf("got args %v, want 5", cloneArgs)
+		}
+
+		base.WhereEqual("d", 4)
+		if again, _, _ := clone.Build(); again != wantClone {
+			t.Errorf("clone changed after original was modified: %q", again)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		base := Update("users").Set("a", 1).WhereEqual("id", 1).WithDialect(d)
+		clone := base.Clone().Set("b", 2)
+		base.Set("c", 3)
+		sql, args, err := clone.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "UPDATE users SET a = ?, b = ? WHERE id = ?"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if len(args) != 3 {
+			t.Errorf("got args %v, want 3", args)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		base := Delete("users").WhereEqual("a", 1).WithDialect(d)
+		clone := base.Clone().WhereEqual("b", 2)
+		if sql, _, _ := base.Build(); sql != "DELETE FROM users WHERE a = ?" {
+			t.Errorf("original changed: %q", sql)
+		}
+		if sql, _, _ := clone.Build(); sql != "DELETE FROM users WHERE a = ? AND b = ?" {
+			t.Errorf("got SQL %q", sql)
+		}
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		base := Insert("users").Columns("a").Values(1).WithDialect(d)
+		clone := base.Clone().Values(2)
+		base.values[0][0] = 9
+		sql, args, err := clone.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "INSERT INTO users (a) VALUES (?), (?)"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if args[0] != 1 {
+			t.Errorf("got args %v, want first arg 1", args)
+		}
+	})
+
+	t.Run("condition", func(t *testing.T) {
+		base := NewCond().Equal("a", 1)
+		clone := base.Clone().Equal("b", 2)
+		base.Equal("c", 3)
+		sql, args, err := clone.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != "a = ? AND b = ?" || len(args) != 2 {
+			t.Errorf("got %q %v", sql, args)
+		}
+	})
+
+	t.Run("postgres variants keep returning", func(t *testing.T) {
+		base := NewPostgresDelete("users").Returning("id")
+		clone := base.Clone().Returning("name")
+		if len(base.returning) != 1 || len(clone.returning) != 2 {
+			t.Errorf("got %v and %v", base.returning, clone.returning)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var b *SelectBuilder
+		if b.Clone() != nil {
+			t.Error("expected nil")
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words written in upper case in Go names, as in UserID.
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TLS": true, "TTL": true,
+	"UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// generate returns the formatted Go source declaring the names of tables in package pkg.
+func generate(pkg string, tables []table) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by sqltkgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+
+	names := make(map[string]bool)
+	for _, t := range tables {
+		name := uniqueName(goName(t.name), names)
+		names[name] = true
+
+		fields := map[string]bool{"Table": true}
+		columns := make([]string, len(t.columns))
+		for i, col := range t.columns {
+			field := goName(col)
+			if field == "Table" {
+				field = "TableColumn"
+			}
+			columns[i] = uniqueName(field, fields)
+			fields[columns[i]] = true
+		}
+
+		fmt.Fprintf(&buf, "\n// %s holds the names of table %s and its columns.\n", name, t.name)
+		fmt.Fprintf(&buf, "var %s = struct {\n\tTable string\n", name)
+		for _, field := range columns {
+			fmt.Fprintf(&buf, "\t%s string\n", field)
+		}
+		fmt.Fprintf(&buf, "}{\n\tTable: %s,\n", strconv.Quote(t.name))
+		for i, field := range columns {
+			fmt.Fprintf(&buf, "\t%s: %s,\n", field, strconv.Quote(t.columns[i]))
+		}
+		buf.WriteString("}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// goName returns the exported Go name for a table or column name, e.g. UserID for user_id.
+func goName(name string) string {
+	var sb strings.Builder
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			sb.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		sb.WriteRune(unicode.ToUpper(runes[0]))
+		sb.WriteString(string(runes[1:]))
+	}
+	s := sb.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
+
+// uniqueName returns name, or name with a number appended if it is taken.
+func uniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/ddl"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestParseDDL(t *testing.T) {
+	users, _, err := ddl.CreateTable("users").
+		AddColumns(
+			ddl.Column("id").Type("INT").AutoIncrement().NotNull(),
+			ddl.Column("email").Type("VARCHAR").Size(255).NotNull(),
+			ddl.Column("created_at").Type("DATETIME").Default("2024-01-01"),
+		).
+		PrimaryKey("id").
+		WithDialect(sqldialect.MySQL()).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := users + `;
+-- orders; of users
+CREATE TABLE IF NOT EXISTS "public"."orders" (
+	"id" BIGINT,
+	user_id INT REFERENCES users (id),
+	total NUMERIC(10, 2) CHECK (total > 0), /* a comment, with a comma */
+	CONSTRAINT orders_pk PRIMARY KEY (id),
+	UNIQUE (user_id, total)
+);
+CREATE INDEX idx_orders_user ON orders (user_id);
+CREATE TABLE order_copy AS SELECT * FROM orders`
+
+	tables, err := parseDDL(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []table{
+		{name: "users", columns: []string{"id", "email", "created_at"}},
+		{name: "orders", columns: []string{"id", "user_id", "total"}},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("got %+v, want %+v", tables, want)
+	}
+
+	if _, err := parseDDL("CREATE TABLE t (a INT"); err == nil {
+		t.Error("expected error for unbalanced parentheses")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("tables", []table{
+		{name: "users", columns: []string{"id", "email", "api_url", "table", "Email"}},
+		{name: "order-items", columns: []string{"2fa"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `// Code generated by sqltkgen. DO NOT EDIT.
+
+package tables
+
+// Users holds the names of table users and its columns.
+var Users = struct {
+	Table       string
+	ID          string
+	Email       string
+	APIURL      string
+	TableColumn string
+	Email2      string
+}{
+	Table:       "users",
+	ID:          "id",
+	Email:       "email",
+	APIURL:      "api_url",
+	TableColumn: "table",
+	Email2:      "Email",
+}
+
+// OrderItems holds the names of table order-items and its columns.
+var OrderItems = struct {
+	Table string
+	X2fa  string
+}{
+	Table: "order-items",
+	X2fa:  "2fa",
+}
+`
+	if string(src) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", src, want)
+	}
+
+	if _, err := generate("my-tables", nil); err == nil {
+		t.Error("expected error for an invalid package name")
+	}
+}
@@ -0,0 +1,105 @@
+// Command sqltkgen generates Go names for the tables and columns of a database schema, so that
+// queries refer to columns through identifiers the compiler checks:
+//
+//	q := sqltk.Select(tables.Users.ID, tables.Users.Email).From(tables.Users.Table).
+//		WhereEqual(tables.Users.Active, true)
+//
+// A misspelled column is then a compile error rather than a failing query. The schema is read
+// from a live database or from a file of CREATE TABLE statements, such as those written by the
+// ddl builders:
+//
+//	sqltkgen -driver postgres -dsn "postgres://localhost/app?sslmode=disable" -pkg tables -out tables/tables.go
+//	sqltkgen -ddl schema.sql -pkg tables -out tables/tables.go
+//
+// For each table, the generated file has a variable named after the table whose Table field is
+// the table name and whose other fields are the column names.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "sqltkgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line, reads the schema, and writes the generated file.
+func run(args []string) error {
+	fs := flag.NewFlagSet("sqltkgen", flag.ContinueOnError)
+	driver := fs.String("driver", "", `database driver of -dsn, "postgres" or "mysql"`)
+	dsn := fs.String("dsn", "", "data source name of the database to read the schema from")
+	schema := fs.String("schema", "", `database schema to read (default "public" on postgres, the current database on mysql)`)
+	ddlFile := fs.String("ddl", "", "file of CREATE TABLE statements to read the schema from, instead of a database")
+	pkg := fs.String("pkg", "tables", "package name of the generated file")
+	out := fs.String("out", "", "file to write (default standard output)")
+	only := fs.String("tables", "", "comma-separated tables to generate (default all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var tables []table
+	switch {
+	case *ddlFile != "" && *dsn != "":
+		return errors.New("-ddl and -dsn are mutually exclusive")
+	case *ddlFile != "":
+		data, err := os.ReadFile(*ddlFile)
+		if err != nil {
+			return err
+		}
+		if tables, err = parseDDL(string(data)); err != nil {
+			return fmt.Errorf("%s: %w", *ddlFile, err)
+		}
+	case *dsn != "":
+		db, err := sql.Open(*driver, *dsn)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		if tables, err = loadSchema(db, *driver, *schema); err != nil {
+			return err
+		}
+	default:
+		return errors.New("one of -ddl or -dsn is required")
+	}
+
+	if *only != "" {
+		tables = filterTables(tables, strings.Split(*only, ","))
+	}
+	if len(tables) == 0 {
+		return errors.New("no tables found")
+	}
+	src, err := generate(*pkg, tables)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+// filterTables returns the tables whose names are in names, compared case-insensitively.
+func filterTables(tables []table, names []string) []table {
+	var kept []table
+	for _, t := range tables {
+		for _, name := range names {
+			if strings.EqualFold(t.name, strings.TrimSpace(name)) {
+				kept = append(kept, t)
+				break
+			}
+		}
+	}
+	return kept
+}
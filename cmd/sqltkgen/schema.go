@@ -0,0 +1,221 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// table is a table of the schema with its columns, in the order they are defined.
+type table struct {
+	name    string
+	columns []string
+}
+
+// loadSchema reads the tables of a database schema from information_schema.
+func loadSchema(db *sql.DB, driver, schema string) ([]table, error) {
+	q := sqltk.Select("table_name", "column_name").From("information_schema.columns").
+		OrderBy("table_name").OrderBy("ordinal_position")
+	switch driver {
+	case "postgres":
+		if schema == "" {
+			schema = "public"
+		}
+		q.WhereEqual("table_schema", schema).WithDialect(sqldialect.Postgres())
+	case "mysql":
+		if schema == "" {
+			q.Where(raw.Cond("table_schema = DATABASE()"))
+		} else {
+			q.WhereEqual("table_schema", schema)
+		}
+		q.WithDialect(sqldialect.MySQL())
+	default:
+		return nil, fmt.Errorf("unsupported driver %q", driver)
+	}
+	query, args, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []table
+	for rows.Next() {
+		var tableName, column string
+		if err := rows.Scan(&tableName, &column); err != nil {
+			return nil, err
+		}
+		if len(tables) == 0 || tables[len(tables)-1].name != tableName {
+			tables = append(tables, table{name: tableName})
+		}
+		t := &tables[len(tables)-1]
+		t.columns = append(t.columns, column)
+	}
+	return tables, rows.Err()
+}
+
+// parseDDL reads the tables of the CREATE TABLE statements in ddl. Other statements, and
+// CREATE TABLE ... AS SELECT, are skipped; table constraints and indexes are not columns.
+func parseDDL(ddl string) ([]table, error) {
+	var tables []table
+	for _, stmt := range splitTopLevel(stripComments(ddl), ';') {
+		words := strings.Fields(stmt)
+		i := 0
+		if i < len(words) && strings.EqualFold(words[i], "CREATE") {
+			i++
+		} else {
+			continue
+		}
+		for i < len(words) && isOneOf(words[i], "TEMP", "TEMPORARY", "UNLOGGED", "GLOBAL", "LOCAL", "OR", "REPLACE") {
+			i++
+		}
+		if i >= len(words) || !strings.EqualFold(words[i], "TABLE") {
+			continue
+		}
+
+		rest := strings.TrimSpace(stmt[strings.Index(strings.ToUpper(stmt), "TABLE")+len("TABLE"):])
+		if upper := strings.ToUpper(rest); strings.HasPrefix(upper, "IF NOT EXISTS") {
+			rest = strings.TrimSpace(rest[len("IF NOT EXISTS"):])
+		}
+		open := strings.IndexByte(rest, '(')
+		if open < 0 {
+			continue // CREATE TABLE ... AS SELECT
+		}
+		name := unquoteName(strings.TrimSpace(rest[:open]))
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			continue
+		}
+		body, ok := parenthesized(rest[open:])
+		if !ok {
+			return nil, fmt.Errorf("table %s: unbalanced parentheses", name)
+		}
+
+		t := table{name: name}
+		for _, def := range splitTopLevel(body, ',') {
+			fields := strings.Fields(def)
+			if len(fields) == 0 || isOneOf(fields[0], "CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK",
+				"INDEX", "KEY", "FULLTEXT", "SPATIAL", "EXCLUDE", "LIKE") {
+				continue
+			}
+			t.columns = append(t.columns, unquoteName(fields[0]))
+		}
+		if len(t.columns) == 0 {
+			return nil, fmt.Errorf("table %s: no columns", name)
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+// stripComments removes the -- and /* */ comments outside quotes from sql.
+func stripComments(sql string) string {
+	var sb strings.Builder
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := len(sql)
+			if idx := strings.IndexByte(sql[i+1:], c); idx >= 0 {
+				end = i + idx + 2
+			}
+			sb.WriteString(sql[i:end])
+			i = end - 1
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return sb.String()
+			}
+			i += end - 1
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			sb.WriteByte(' ')
+			i += end + 3
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// splitTopLevel splits s at the separators outside parentheses and quotes.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			if end := strings.IndexByte(s[i+1:], c); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(s)
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// parenthesized returns the text inside the parentheses s starts with.
+func parenthesized(s string) (string, bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return "", false
+			}
+			i += end + 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// unquoteName removes identifier quotes from a name, and the schema of a qualified name.
+func unquoteName(name string) string {
+	if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if len(name) >= 2 {
+		switch first, last := name[0], name[len(name)-1]; {
+		case first == '"' && last == '"', first == '`' && last == '`', first == '[' && last == ']':
+			name = name[1 : len(name)-1]
+		}
+	}
+	return name
+}
+
+// isOneOf reports whether word is one of the keywords, compared case-insensitively.
+func isOneOf(word string, keywords ...string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(word, k) {
+			return true
+		}
+	}
+	return false
+}
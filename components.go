@@ -0,0 +1,138 @@
+package sqltk
+
+import (
+	"errors"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// WhereClause is the WHERE handling of the builders of this package, for embedding in
+// builders of other packages. Conditions are added as with SelectBuilder.Where, and
+// BuildWhere renders them. The zero value is an empty clause.
+//
+// Example usage:
+//
+//	type ReplaceBuilder struct {
+//		sqltk.TableClause
+//		sqltk.WhereClause
+//		dialect sqldialect.Dialect
+//	}
+//
+//	func (b *ReplaceBuilder) Build() (string, []interface{}, error) {
+//		dialect := sqltk.ResolveDialect(b.dialect)
+//		table, args, err := b.BuildTable(dialect)
+//		if err != nil {
+//			return "", nil, err
+//		}
+//		where, whereArgs, err := b.BuildWhere(dialect)
+//		if err != nil {
+//			return "", nil, err
+//		}
+//		return sqltk.Rebind(dialect, "REPLACE INTO "+table+" SELECT * FROM staging"+where), append(args, whereArgs...), nil
+//	}
+type WhereClause struct {
+	w whereClause
+}
+
+// Where adds a condition, ANDed with the conditions added before.
+func (c *WhereClause) Where(cond Condition) {
+	c.w.Where(cond)
+}
+
+// OrWhere combines the conditions added so far with cond using OR.
+func (c *WhereClause) OrWhere(cond Condition) {
+	c.w.OrWhere(cond)
+}
+
+// WhereGroup adds the conditions built by fn as a single parenthesized condition. The
+// ConditionBuilder passed to fn uses dialect if it is not nil.
+func (c *WhereClause) WhereGroup(dialect sqldialect.Dialect, fn func(*ConditionBuilder)) {
+	c.w.WhereGroup(dialect, fn)
+}
+
+// WhereEqual adds column = value, or column IS NULL if value is nil.
+func (c *WhereClause) WhereEqual(column string, value interface{}) {
+	c.w.WhereEqual(column, value)
+}
+
+// WhereNotEqual adds column != value, or column IS NOT NULL if value is nil.
+func (c *WhereClause) WhereNotEqual(column string, value interface{}) {
+	c.w.WhereNotEqual(column, value)
+}
+
+// HasConditions reports whether any condition was added.
+func (c *WhereClause) HasConditions() bool {
+	return len(c.w.whereParam) > 0 || len(c.w.whereRaw) > 0
+}
+
+// WhereErr returns the first error from adding a condition, if any.
+func (c *WhereClause) WhereErr() error {
+	return c.w.err
+}
+
+// Subqueries returns the subqueries of the conditions, for reporting the tables a builder
+// references as GetTables does.
+func (c *WhereClause) Subqueries() []*SelectBuilder {
+	return c.w.subqueries
+}
+
+// BuildWhere renders the clause as " WHERE ..." with ? placeholders, or "" if it has no
+// conditions, together with its args. Number the placeholders with Rebind once the statement
+// is complete, so they are numbered across all of it. A nil dialect is the global dialect.
+func (c *WhereClause) BuildWhere(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if c.w.err != nil {
+		return "", nil, c.w.err
+	}
+	sql, args, err := c.w.buildWhereSQL(renderDialect(ResolveDialect(dialect)))
+	if err != nil {
+		return "", nil, err
+	}
+	if sql == "" {
+		return "", args, nil
+	}
+	return " WHERE " + sql, args, nil
+}
+
+// TableClause is the table handling of the builders of this package, for embedding in
+// builders of other packages. The table may be anything From accepts: a table name, which is
+// quoted for the dialect, raw.Raw, a *SelectBuilder, or an Alias of one of these.
+type TableClause struct {
+	t tableClauseInterface
+}
+
+// SetTable sets the table.
+func (c *TableClause) SetTable(table interface{}) {
+	c.t = tableClauseInterface{}
+	c.t.SetTable(table)
+}
+
+// Table returns the table, or nil if none is set.
+func (c *TableClause) Table() interface{} {
+	return c.t.table
+}
+
+// TableErr returns the error from setting the table, if any.
+func (c *TableClause) TableErr() error {
+	return c.t.err
+}
+
+// BuildTable renders the table with ? placeholders, together with the args of a subquery.
+// A nil dialect is the global dialect.
+func (c *TableClause) BuildTable(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if c.t.err != nil {
+		return "", nil, c.t.err
+	}
+	if c.t.table == nil {
+		return "", nil, errors.New("table must be set")
+	}
+	return buildFromTable(renderDialect(ResolveDialect(dialect)), c.t.table)
+}
+
+// ResolveDialect returns dialect, or the global dialect if dialect is nil, as builders do
+// for the dialect set with WithDialect.
+func ResolveDialect(dialect sqldialect.Dialect) sqldialect.Dialect {
+	if dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return dialect
+}
@@ -0,0 +1,83 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// copyBuilder is a builder defined outside the core builders, from the exported components.
+type copyBuilder struct {
+	TableClause
+	WhereClause
+	source  string
+	dialect sqldialect.Dialect
+}
+
+func (b *copyBuilder) Build() (string, []interface{}, error) {
+	dialect := ResolveDialect(b.dialect)
+	table, args, err := b.BuildTable(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	where, whereArgs, err := b.BuildWhere(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "REPLACE INTO " + table + " SELECT * FROM " + dialect.QuoteIdent(b.source) + where
+	return Rebind(dialect, sql), append(args, whereArgs...), nil
+}
+
+func TestExportedComponents(t *testing.T) {
+	t.Run("where and table", func(t *testing.T) {
+		b := &copyBuilder{source: "staging", dialect: sqldialect.Postgres()}
+		b.SetTable("users")
+		b.Where(NewCond().GreaterThan("id", 10))
+		b.WhereEqual("deleted_at", nil)
+		b.OrWhere(NewCond().InQuery("id", Select("user_id").From("admins").WhereEqual("active", true)))
+		b.WhereGroup(sqldialect.Postgres(), func(c *ConditionBuilder) { c.Equal("a", 1).Equal("b", 2) })
+
+		sql, args, err := b.Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `REPLACE INTO "users" SELECT * FROM "staging" WHERE ((id > $1 AND deleted_at IS NULL) OR (id IN (SELECT "user_id" FROM "admins" WHERE active = $2))) AND ("a" = $3 AND "b" = $4)`
+		if sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if wantArgs := []interface{}{10, true, 1, 2}; !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+		if !b.HasConditions() || len(b.Subqueries()) != 1 {
+			t.Errorf("got HasConditions %v and %d subqueries, want true and 1", b.HasConditions(), len(b.Subqueries()))
+		}
+	})
+
+	t.Run("no conditions", func(t *testing.T) {
+		var w WhereClause
+		sql, args, err := w.BuildWhere(nil)
+		if err != nil || sql != "" || len(args) != 0 {
+			t.Errorf("got %q, %v, %v, want an empty clause", sql, args, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		b := &copyBuilder{source: "staging"}
+		if _, _, err := b.Build(); err == nil {
+			t.Error("expected error for missing table")
+		}
+		b.SetTable("")
+		if b.TableErr() == nil {
+			t.Error("expected error for empty table")
+		}
+		b.SetTable("users")
+		b.Where(nil)
+		if b.WhereErr() == nil {
+			t.Error("expected error for nil condition")
+		}
+		if _, _, err := b.Build(); err == nil {
+			t.Error("expected build error")
+		}
+	})
+}
@@ -0,0 +1,312 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldebug"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// SetOperator is a SQL set operator used to combine SELECT queries.
+type SetOperator string
+
+const (
+	UnionOp     SetOperator = "UNION"
+	UnionAllOp  SetOperator = "UNION ALL"
+	IntersectOp SetOperator = "INTERSECT"
+	ExceptOp    SetOperator = "EXCEPT"
+)
+
+// compoundPart is a SELECT query and the operator joining it to the previous part.
+type compoundPart struct {
+	op    SetOperator
+	query *SelectBuilder
+}
+
+// CompoundBuilder builds SELECT queries combined with UNION, UNION ALL, INTERSECT, or EXCEPT.
+// ORDER BY, LIMIT, and OFFSET set on a CompoundBuilder apply to the combined result.
+type CompoundBuilder struct {
+	parts     []compoundPart
+	orderBy   []orderTerm
+	limitSet  bool
+	limit     int
+	offsetSet bool
+	offset    int
+	err       error
+	dialect   sqldialect.Dialect // per-builder dialect, if set
+	reuseArgs bool               // see ReuseArgs
+	unlimited bool               // see Unlimited
+}
+
+// Union combines this query with others using UNION.
+//
+// Example usage:
+//
+//	q := Select("id").From("users").Union(Select("id").From("admins")).OrderBy("id").Limit(10)
+func (b *SelectBuilder) Union(others ...*SelectBuilder) *CompoundBuilder {
+	return newCompound(b).add(UnionOp, others)
+}
+
+// UnionAll combines this query with others using UNION ALL.
+func (b *SelectBuilder) UnionAll(others ...*SelectBuilder) *CompoundBuilder {
+	return newCompound(b).add(UnionAllOp, others)
+}
+
+// Intersect combines this query with others using INTERSECT.
+func (b *SelectBuilder) Intersect(others ...*SelectBuilder) *CompoundBuilder {
+	return newCompound(b).add(IntersectOp, others)
+}
+
+// Except combines this query with others using EXCEPT.
+func (b *SelectBuilder) Except(others ...*SelectBuilder) *CompoundBuilder {
+	return newCompound(b).add(ExceptOp, others)
+}
+
+func newCompound(first *SelectBuilder) *CompoundBuilder {
+	c := &CompoundBuilder{}
+	if first == nil {
+		c.err = errors.New("compound: first query must not be nil")
+		return c
+	}
+	c.parts = append(c.parts, compoundPart{query: first})
+	return c
+}
+
+func (c *CompoundBuilder) add(op SetOperator, others []*SelectBuilder) *CompoundBuilder {
+	if c.err != nil {
+		return c
+	}
+	if len(others) == 0 {
+		c.err = fmt.Errorf("%s: at least one query is required", op)
+		return c
+	}
+	for _, q := range others {
+		if q == nil {
+			c.err = fmt.Errorf("%s: query must not be nil", op)
+			return c
+		}
+		c.parts = append(c.parts, compoundPart{op: op, query: q})
+	}
+	return c
+}
+
+// Union appends more queries using UNION.
+func (c *CompoundBuilder) Union(others ...*SelectBuilder) *CompoundBuilder {
+	return c.add(UnionOp, others)
+}
+
+// UnionAll appends more queries using UNION ALL.
+func (c *CompoundBuilder) UnionAll(others ...*SelectBuilder) *CompoundBuilder {
+	return c.add(UnionAllOp, others)
+}
+
+// Intersect appends more queries using INTERSECT.
+func (c *CompoundBuilder) Intersect(others ...*SelectBuilder) *CompoundBuilder {
+	return c.add(IntersectOp, others)
+}
+
+// Except appends more queries using EXCEPT.
+func (c *CompoundBuilder) Except(others ...*SelectBuilder) *CompoundBuilder {
+	return c.add(ExceptOp, others)
+}
+
+// ApplyIf calls fn with the builder only if cond is true.
+func (c *CompoundBuilder) ApplyIf(cond bool, fn func(*CompoundBuilder) *CompoundBuilder) *CompoundBuilder {
+	if !cond || fn == nil {
+		return c
+	}
+	if next := fn(c); next != nil {
+		return next
+	}
+	return c
+}
+
+// OrderBy adds an ORDER BY clause to the combined query. Accepts a column string, Raw, or Expr.
+func (c *CompoundBuilder) OrderBy(expr interface{}) *CompoundBuilder {
+	if c.err != nil {
+		return c
+	}
+	term, err := newOrderTerm(expr)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.orderBy = append(c.orderBy, term)
+	return c
+}
+
+// OrderByAsc adds an ascending ORDER BY term for a column of the combined query.
+func (c *CompoundBuilder) OrderByAsc(column string) *CompoundBuilder {
+	return c.OrderByExpr(column, Asc, NullsDefault)
+}
+
+// OrderByDesc adds a descending ORDER BY term for a column of the combined query.
+func (c *CompoundBuilder) OrderByDesc(column string) *CompoundBuilder {
+	return c.OrderByExpr(column, Desc, NullsDefault)
+}
+
+// OrderByExpr adds an ORDER BY term with a direction and NULLS ordering to the combined query.
+func (c *CompoundBuilder) OrderByExpr(expr interface{}, dir OrderDirection, nulls NullsOrder) *CompoundBuilder {
+	if c.err != nil {
+		return c
+	}
+	term, err := newOrderExprTerm(expr, dir, nulls)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.orderBy = append(c.orderBy, term)
+	return c
+}
+
+// Limit sets a LIMIT clause on the combined query.
+func (c *CompoundBuilder) Limit(n int) *CompoundBuilder {
+	c.limitSet = true
+	c.limit = n
+	return c
+}
+
+// Offset sets an OFFSET clause on the combined query.
+func (c *CompoundBuilder) Offset(n int) *CompoundBuilder {
+	c.offsetSet = true
+	c.offset = n
+	return c
+}
+
+// HasLimit reports whether a LIMIT is set on the combined query.
+func (c *CompoundBuilder) HasLimit() bool {
+	return c.limitSet
+}
+
+// Unlimited marks the combined query as meant to read every row, see SelectBuilder.Unlimited.
+func (c *CompoundBuilder) Unlimited() *CompoundBuilder {
+	c.unlimited = true
+	return c
+}
+
+// IsUnlimited reports whether the combined query was marked with Unlimited.
+func (c *CompoundBuilder) IsUnlimited() bool {
+	return c.unlimited
+}
+
+// WithDialect sets the dialect for this builder instance. It is also used to build every combined query.
+func (c *CompoundBuilder) WithDialect(d sqldialect.Dialect) *CompoundBuilder {
+	c.dialect = d
+	return c
+}
+
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (c *CompoundBuilder) GetDialect() sqldialect.Dialect {
+	if c.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return c.dialect
+}
+
+// Build builds the combined SQL query and returns the query string, arguments, and error if any.
+// Arguments are returned in the order the queries were combined, and placeholders are
+// numbered across the whole statement.
+func (c *CompoundBuilder) Build() (string, []interface{}, error) {
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	if len(c.parts) < 2 {
+		return "", nil, errors.New("compound: at least two queries are required")
+	}
+
+	dialect := c.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	out := dialect
+	dialect = renderDialect(dialect)
+
+	var sb strings.Builder
+	var args []interface{}
+
+	for i, part := range c.parts {
+		if i > 0 {
+			sb.WriteString(" ")
+			sb.WriteString(string(part.op))
+			sb.WriteString(" ")
+		}
+		// Build each query with "?" placeholders so they can be numbered once combined.
+		q := *part.query
+		q.dialect = positional(dialect)
+		partSQL, partArgs, err := q.Build()
+		if err != nil {
+			return "", nil, fmt.Errorf("compound: query %d error: %w", i+1, err)
+		}
+		if q.limitSet || q.offsetSet || len(q.orderBy) > 0 {
+			partSQL = "(" + partSQL + ")"
+		}
+		sb.WriteString(partSQL)
+		args = append(args, partArgs...)
+	}
+
+	orderBys, orderArgs, err := buildOrderBys(dialect, c.orderBy)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(orderBys) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(orderBys, ", "))
+		args = append(args, orderArgs...)
+	}
+	limitSQL, offsetSQL, err := buildLimit(dialect, c.limitSet, c.limit, c.offsetSet, c.offset, len(orderBys) > 0)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(limitSQL)
+	sb.WriteString(offsetSQL)
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, c.reuseArgs)
+	placeholderIdx := 1
+	return numberPlaceholders(out, sb.String(), &placeholderIdx, numbers), args, nil
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
+// DO NOT use the result for execution (not safe against SQL injection).
+func (c *CompoundBuilder) DebugSQL() string {
+	sql, args, _ := c.Build()
+	return sqldebug.InterpolateSQLDialect(c.dialect, sql, args).String()
+}
+
+// BuildTo builds the query and writes its SQL to w, returning the args. See BuildTo.
+func (c *CompoundBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, c)
+}
+
+// positionalDialect wraps a dialect but always renders "?" placeholders, so that
+// fragments built separately can be numbered once they are combined.
+type positionalDialect struct {
+	sqldialect.Dialect
+}
+
+func (positionalDialect) Placeholder(n int) string { return "?" }
+
+func (p positionalDialect) Unwrap() sqldialect.Dialect { return p.Dialect }
+
+// positional returns d if it is a positionalDialect, or a positionalDialect wrapping it.
+func positional(d sqldialect.Dialect) sqldialect.Dialect {
+	if _, ok := d.(positionalDialect); ok {
+		return d
+	}
+	return positionalDialect{d}
+}
+
+// baseDialect returns the dialect d wraps, such as with a positionalDialect or
+// sqldialect.Strict, or d itself, for telling which database d is for.
+func baseDialect(d sqldialect.Dialect) sqldialect.Dialect {
+	return sqldialect.Unwrap(d)
+}
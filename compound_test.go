@@ -0,0 +1,133 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCompoundBuilder(t *testing.T) {
+	t.Run("union", func(t *testing.T) {
+		q := Select("id").From("users").WhereEqual("active", true).
+			Union(Select("id").From("admins").WhereEqual("level", 2))
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT id FROM users WHERE active = ? UNION SELECT id FROM admins WHERE level = ?"
+		wantArgs := []interface{}{true, 2}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("union all with trailing order by and limit", func(t *testing.T) {
+		q := Select("id", "name").From("users").
+			UnionAll(Select("id", "name").From("archived_users"), Select("id", "name").From("guests")).
+			OrderBy("name DESC").
+			OrderBy(raw.Raw("id")).
+			Limit(10).
+			Offset(20)
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT id, name FROM users UNION ALL SELECT id, name FROM archived_users UNION ALL SELECT id, name FROM guests ORDER BY name DESC, id LIMIT 10 OFFSET 20"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if len(args) != 0 {
+			t.Errorf("got args %v, want none", args)
+		}
+	})
+
+	t.Run("structured order by on mysql", func(t *testing.T) {
+		q := Select("id", "name").From("users").
+			Union(Select("id", "name").From("guests")).
+			OrderByExpr("name", Asc, NullsLast).
+			OrderByDesc("id")
+		sql, _, err := q.WithDialect(sqldialect.MySQL()).Build()
+		wantSQL := "SELECT `id`, `name` FROM `users` UNION SELECT `id`, `name` FROM `guests` ORDER BY `name` IS NULL, `name` ASC, `id` DESC"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("intersect and except chained", func(t *testing.T) {
+		q := Select("id").From("a").
+			Intersect(Select("id").From("b")).
+			Except(Select("id").From("c"))
+		sql, _, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT id FROM a INTERSECT SELECT id FROM b EXCEPT SELECT id FROM c"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("parts with limit are parenthesized", func(t *testing.T) {
+		q := Select("id").From("a").OrderBy("id").Limit(5).
+			Union(Select("id").From("b"))
+		sql, _, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "(SELECT id FROM a ORDER BY id LIMIT 5) UNION SELECT id FROM b"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("postgres placeholders are numbered across queries", func(t *testing.T) {
+		q := Select("id").From("users").WhereEqual("active", true).
+			Union(Select("id").From("admins").Where(NewCond().Equal("level", 2)))
+		sql, args, err := q.WithDialect(sqldialect.Postgres()).Build()
+		wantSQL := `SELECT "id" FROM "users" WHERE active = $1 UNION SELECT "id" FROM "admins" WHERE level = $2`
+		wantArgs := []interface{}{true, 2}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("combined query does not modify its parts", func(t *testing.T) {
+		first := Select("id").From("users")
+		_, _, err := first.Union(Select("id").From("admins")).WithDialect(sqldialect.Postgres()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.dialect != nil {
+			t.Errorf("expected first query dialect to be untouched, got %v", first.dialect)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, _, err := Select("id").From("a").Union().Build(); err == nil {
+			t.Error("expected error for union without queries")
+		}
+		if _, _, err := Select("id").From("a").Union(nil).Build(); err == nil {
+			t.Error("expected error for nil query")
+		}
+		if _, _, err := Select("id").From("a").Union(Select("id")).Build(); err == nil {
+			t.Error("expected error propagated from invalid query")
+		}
+		if _, _, err := Select("id").From("a").Union(Select("id").From("b")).OrderBy(1).Build(); err == nil {
+			t.Error("expected error for invalid order by")
+		}
+	})
+}
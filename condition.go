@@ -2,7 +2,10 @@ package sqltk
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sprylic/sqltk/raw"
 	"github.com/sprylic/sqltk/sqldebug"
@@ -31,20 +34,35 @@ func NewStringCondition(sql string, args ...interface{}) *StringCondition {
 
 // BuildCondition implements the Condition interface.
 func (sc *StringCondition) BuildCondition() (string, []interface{}, error) {
+	if sc == nil {
+		return "", nil, fmt.Errorf("string condition is nil")
+	}
 	return sc.SQL, sc.Args, nil
 }
 
 // ConditionBuilder provides a fluent API for building SQL conditions.
 type ConditionBuilder struct {
-	parts   []string
-	args    []interface{}
-	err     error
-	dialect sqldialect.Dialect
+	expr          *condNode        // nil if no condition was added
+	subqueries    []*SelectBuilder // see GetTables
+	nullSafeNotIn bool             // see NullSafeNotIn
+	err           error
+	dialect       sqldialect.Dialect
 }
 
-// BuildCondition implements the Condition interface.
+// BuildCondition implements the Condition interface. Unlike Build, it leaves the subqueries
+// of Exists and NotExists to be built by the statement the condition is added to.
 func (c *ConditionBuilder) BuildCondition() (string, []interface{}, error) {
-	return c.Build()
+	if c == nil {
+		return "", nil, fmt.Errorf("condition builder is nil")
+	}
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	if c.expr == nil {
+		return "", nil, nil
+	}
+	sql, args := c.expr.build()
+	return sql, args, nil
 }
 
 // NewCond creates a new ConditionBuilder.
@@ -73,34 +91,38 @@ func (c *ConditionBuilder) Where(column string, operator string, value interface
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
-		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
-	}
+	quotedCol := quoteQualifiedIdent(dialect, column)
 
 	if value == nil {
 		switch operator {
 		case "=":
-			c.parts = append(c.parts, quotedCol+" IS NULL")
+			c.add(quotedCol + " IS NULL")
 		case "!=", "<>":
-			c.parts = append(c.parts, quotedCol+" IS NOT NULL")
+			c.add(quotedCol + " IS NOT NULL")
 		default:
 			c.err = fmt.Errorf("invalid operator %q for NULL value", operator)
 		}
 		return c
 	}
 
-	c.parts = append(c.parts, quotedCol+" "+operator+" ?")
-	c.args = append(c.args, value)
+	c.add(quotedCol+" "+operator+" ?", value)
+	return c
+}
+
+// ApplyIf calls fn with the condition builder only if cond is true, for optional conditions.
+//
+// Example usage:
+//
+//	sqltk.NewCond().Equal("active", true).ApplyIf(minAge > 0, func(c *sqltk.ConditionBuilder) *sqltk.ConditionBuilder {
+//		return c.GreaterThan("age", minAge)
+//	})
+func (c *ConditionBuilder) ApplyIf(cond bool, fn func(*ConditionBuilder) *ConditionBuilder) *ConditionBuilder {
+	if !cond || fn == nil {
+		return c
+	}
+	if next := fn(c); next != nil {
+		return next
+	}
 	return c
 }
 
@@ -134,6 +156,24 @@ func (c *ConditionBuilder) LessThanOrEqual(column string, value interface{}) *Co
 	return c.Where(column, "<=", value)
 }
 
+// BitAnd adds a condition that the bits of column selected by mask equal expected
+// ((column & mask) = expected), as for flags stored in an integer column.
+//
+// Example usage:
+//
+//	NewCond().BitAnd("flags", 4, 4) // (flags & ?) = ?
+func (c *ConditionBuilder) BitAnd(column string, mask, expected interface{}) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	if mask == nil || expected == nil {
+		c.err = fmt.Errorf("BitAnd: mask and expected value must not be nil")
+		return c
+	}
+	c.add("("+quoteQualifiedIdent(c.getDialect(), column)+" & ?) = ?", mask, expected)
+	return c
+}
+
 // Like adds a LIKE condition (column LIKE pattern).
 func (c *ConditionBuilder) Like(column string, pattern string) *ConditionBuilder {
 	return c.Where(column, "LIKE", pattern)
@@ -144,7 +184,79 @@ func (c *ConditionBuilder) NotLike(column string, pattern string) *ConditionBuil
 	return c.Where(column, "NOT LIKE", pattern)
 }
 
-// In adds an IN condition (column IN (values...)).
+// Regexp adds a regular expression match condition, with the pattern bound as an arg. Like the
+// subqueries of Exists, it is built with the dialect of the statement it is added to:
+// column ~ ? on Postgres, regexp_matches(column, ?) on DuckDB, REGEXP_CONTAINS(column, ?) on
+// BigQuery, REGEXP_LIKE(column, ?) on Oracle, match(column, ?) on ClickHouse, and
+// column REGEXP ? on MySQL and NoQuoteIdent. Build returns an error for other dialects. The
+// syntax of the pattern is that of the database.
+func (c *ConditionBuilder) Regexp(column string, pattern string) *ConditionBuilder {
+	return c.regexp(column, pattern, false)
+}
+
+// NotRegexp adds the negation of Regexp: column !~ ? on Postgres, column NOT REGEXP ? on MySQL,
+// and NOT ... otherwise.
+func (c *ConditionBuilder) NotRegexp(column string, pattern string) *ConditionBuilder {
+	return c.regexp(column, pattern, true)
+}
+
+func (c *ConditionBuilder) regexp(column string, pattern string, not bool) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	c.add("?", regexpArg{column: column, pattern: pattern, not: not})
+	return c
+}
+
+// regexpArg is the match of Regexp, bound in place of a ? of a condition like a subqueryArg.
+type regexpArg struct {
+	column  string
+	pattern string
+	not     bool
+}
+
+// checkBind implements bindChecker.
+func (a regexpArg) checkBind(dialect sqldialect.Dialect) error {
+	switch dialect {
+	case sqldialect.Postgres(), sqldialect.DuckDB(), sqldialect.BigQuery(), sqldialect.Oracle(),
+		sqldialect.ClickHouse(), sqldialect.MySQL(), sqldialect.NoQuoteIdent():
+		return nil
+	}
+	return fmt.Errorf("Regexp: regular expression match is not supported by this dialect")
+}
+
+// BindSQL implements Binder.
+func (a regexpArg) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	col := quoteQualifiedIdent(dialect, a.column)
+	var sql string
+	switch dialect {
+	case sqldialect.Postgres():
+		if a.not {
+			return col + " !~ ?", []interface{}{a.pattern}
+		}
+		return col + " ~ ?", []interface{}{a.pattern}
+	case sqldialect.DuckDB():
+		sql = "regexp_matches(" + col + ", ?)"
+	case sqldialect.BigQuery():
+		sql = "REGEXP_CONTAINS(" + col + ", ?)"
+	case sqldialect.Oracle():
+		sql = "REGEXP_LIKE(" + col + ", ?)"
+	case sqldialect.ClickHouse():
+		sql = "match(" + col + ", ?)"
+	default:
+		if a.not {
+			return col + " NOT REGEXP ?", []interface{}{a.pattern}
+		}
+		return col + " REGEXP ?", []interface{}{a.pattern}
+	}
+	if a.not {
+		sql = "NOT " + sql
+	}
+	return sql, []interface{}{a.pattern}
+}
+
+// In adds an IN condition (column IN (values...)). A single *SelectBuilder value is rendered
+// as a subquery, as with InQuery.
 func (c *ConditionBuilder) In(column string, values ...interface{}) *ConditionBuilder {
 	if c.err != nil {
 		return c
@@ -156,64 +268,301 @@ func (c *ConditionBuilder) In(column string, values ...interface{}) *ConditionBu
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
+	quotedCol := quoteQualifiedIdent(dialect, column)
+
+	if len(values) == 1 {
+		if subquery, ok := values[0].(*SelectBuilder); ok {
+			return c.inQuery("IN", column, subquery)
 		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
 	}
-
-	// Check if any value is a subquery
-	hasSubquery := false
 	for _, value := range values {
 		if _, ok := value.(*SelectBuilder); ok {
-			hasSubquery = true
-			break
-		}
-	}
-
-	if hasSubquery {
-		// Handle subqueries in IN clause
-		if len(values) != 1 {
 			c.err = fmt.Errorf("IN with subquery can only have one subquery value")
 			return c
 		}
+	}
 
-		subquery, ok := values[0].(*SelectBuilder)
-		if !ok {
-			c.err = fmt.Errorf("IN with subquery must be *SelectBuilder")
-			return c
+	// Handle regular values
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = "?"
+	}
+
+	c.add(quotedCol+" IN ("+strings.Join(placeholders, ", ")+")", values...)
+	return c
+}
+
+// InSlice adds an IN condition with one placeholder per element of slice, which may be a slice
+// or array of any element type, e.g. []int64 or []string, so typed slices need no conversion
+// to []interface{}. A []byte is a single value, not a slice of bytes.
+//
+// Example usage:
+//
+//	NewCond().InSlice("id", []int64{1, 2, 3}) // id IN (?, ?, ?)
+func (c *ConditionBuilder) InSlice(column string, slice interface{}) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	values, err := expandSlice(slice)
+	if err != nil {
+		c.err = fmt.Errorf("IN condition: %w", err)
+		return c
+	}
+	return c.In(column, values...)
+}
+
+// NotInSlice adds a NOT IN condition with one placeholder per element of slice. See InSlice.
+func (c *ConditionBuilder) NotInSlice(column string, slice interface{}) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	values, err := expandSlice(slice)
+	if err != nil {
+		c.err = fmt.Errorf("NOT IN condition: %w", err)
+		return c
+	}
+	return c.NotIn(column, values...)
+}
+
+// InEnum adds an IN condition for the requested values, after checking each of them against
+// allowed. It is an error if a requested value is not allowed, so that filters taken from
+// request parameters cannot send arbitrary values to the database. Repeated values are
+// bound once.
+//
+// Example usage:
+//
+//	NewCond().InEnum("status", []string{"active", "pending", "closed"}, r.URL.Query()["status"])
+func (c *ConditionBuilder) InEnum(column string, allowed []string, requested []string) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	valid := make(map[string]bool, len(allowed))
+	for _, v := range allowed {
+		valid[v] = true
+	}
+	seen := make(map[string]bool, len(requested))
+	var values []interface{}
+	var unknown []string
+	for _, v := range requested {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if !valid[v] {
+			unknown = append(unknown, strconv.Quote(v))
+			continue
 		}
+		values = append(values, v)
+	}
+	if len(unknown) > 0 {
+		c.err = fmt.Errorf("IN condition: %s not allowed for %s", strings.Join(unknown, ", "), column)
+		return c
+	}
+	return c.In(column, values...)
+}
 
-		sql, args, err := subquery.Build()
+// expandSlice returns the elements of a slice or array as []interface{}.
+func expandSlice(slice interface{}) ([]interface{}, error) {
+	if values, ok := slice.([]interface{}); ok {
+		return values, nil
+	}
+	v := reflect.ValueOf(slice)
+	if _, ok := slice.([]byte); ok || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("expected a slice, got %T", slice)
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// InQuery adds an IN condition against a subquery (column IN (SELECT ...)). In does the same
+// when given a single *SelectBuilder. Like with Exists, the subquery is built with the dialect
+// of the statement the condition is added to unless it has its own, and its placeholders are
+// numbered together with those of the statement.
+//
+// Example usage:
+//
+//	NewCond().InQuery("id", Select("user_id").From("orders").WhereEqual("status", "paid"))
+func (c *ConditionBuilder) InQuery(column string, subquery *SelectBuilder) *ConditionBuilder {
+	return c.inQuery("IN", column, subquery)
+}
+
+// NotInQuery adds a NOT IN condition against a subquery (column NOT IN (SELECT ...)). NotIn
+// does the same when given a single *SelectBuilder. NOT IN matches no rows at all if the
+// subquery returns a NULL; see NullSafeNotIn.
+func (c *ConditionBuilder) NotInQuery(column string, subquery *SelectBuilder) *ConditionBuilder {
+	return c.inQuery("NOT IN", column, subquery)
+}
+
+// NullSafeNotIn makes the NOT IN conditions against subqueries added after it, by NotIn,
+// NotInQuery, and RowNotIn, render as NOT EXISTS (SELECT 1 FROM (subquery) AS not_in WHERE
+// not_in.x = column), which keeps the rows that match no non-NULL value of the subquery when it
+// also returns NULLs. The columns of the subquery must be column names or have aliases, and the
+// columns compared with them must be qualified with their table or alias, since inside the
+// NOT EXISTS an unqualified name could refer to a column of the subquery.
+//
+// Example usage:
+//
+//	NewCond().NullSafeNotIn().NotInQuery("e.id", Select("manager_id").From("employees"))
+//	// NOT EXISTS (SELECT 1 FROM (SELECT manager_id FROM employees) AS not_in WHERE not_in.manager_id = e.id)
+func (c *ConditionBuilder) NullSafeNotIn() *ConditionBuilder {
+	c.nullSafeNotIn = true
+	return c
+}
+
+func (c *ConditionBuilder) inQuery(op, column string, subquery *SelectBuilder) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	if subquery == nil {
+		c.err = fmt.Errorf("%s condition requires a subquery", op)
+		return c
+	}
+	if _, _, err := subquery.Build(); err != nil {
+		c.err = fmt.Errorf("%s subquery error: %w", op, err)
+		return c
+	}
+	sq := subquery.Clone()
+	if op == "NOT IN" && c.nullSafeNotIn {
+		arg, err := notInAsNotExists([]string{column}, sq)
 		if err != nil {
-			c.err = fmt.Errorf("IN subquery error: %w", err)
+			c.err = err
 			return c
 		}
+		c.add("?", arg)
+	} else {
+		c.add(quoteQualifiedIdent(c.getDialect(), column)+" "+op+" (?)", subqueryArg{query: sq})
+	}
+	c.subqueries = append(c.subqueries, sq)
+	return c
+}
+
+// RowIn adds a row-constructor IN condition against a subquery ((a, b) IN (SELECT x, y FROM ...)),
+// for matching composite keys. The subquery is built as with InQuery.
+//
+// Example usage:
+//
+//	NewCond().RowIn([]string{"tenant_id", "user_id"}, Select("tenant_id", "user_id").From("banned_users"))
+func (c *ConditionBuilder) RowIn(columns []string, subquery *SelectBuilder) *ConditionBuilder {
+	return c.rowIn("IN", columns, subquery)
+}
+
+// RowNotIn adds a row-constructor NOT IN condition against a subquery ((a, b) NOT IN (SELECT x, y FROM ...)).
+func (c *ConditionBuilder) RowNotIn(columns []string, subquery *SelectBuilder) *ConditionBuilder {
+	return c.rowIn("NOT IN", columns, subquery)
+}
 
-		c.parts = append(c.parts, quotedCol+" IN (("+sql+"))")
-		c.args = append(c.args, args...)
+func (c *ConditionBuilder) rowIn(op string, columns []string, subquery *SelectBuilder) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	if len(columns) == 0 {
+		c.err = fmt.Errorf("row %s condition requires at least one column", op)
+		return c
+	}
+	if subquery == nil {
+		c.err = fmt.Errorf("row %s condition requires a subquery", op)
+		return c
+	}
+	if n := len(subquery.columns); n > 0 && n != len(columns) {
+		c.err = fmt.Errorf("row %s condition has %d columns but the subquery selects %d", op, len(columns), n)
 		return c
 	}
 
-	// Handle regular values
-	placeholders := make([]string, len(values))
-	for i := range values {
-		placeholders[i] = "?"
+	if _, _, err := subquery.Build(); err != nil {
+		c.err = fmt.Errorf("row %s subquery error: %w", op, err)
+		return c
+	}
+	sq := subquery.Clone()
+	if op == "NOT IN" && c.nullSafeNotIn {
+		arg, err := notInAsNotExists(columns, sq)
+		if err != nil {
+			c.err = err
+			return c
+		}
+		c.add("?", arg)
+		c.subqueries = append(c.subqueries, sq)
+		return c
 	}
 
-	c.parts = append(c.parts, quotedCol+" IN ("+strings.Join(placeholders, ", ")+")")
-	c.args = append(c.args, values...)
+	dialect := c.getDialect()
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteQualifiedIdent(dialect, col)
+	}
+	lhs := quoted[0]
+	if len(quoted) > 1 {
+		lhs = "(" + strings.Join(quoted, ", ") + ")"
+	}
+	c.add(lhs+" "+op+" (?)", subqueryArg{query: sq})
+	c.subqueries = append(c.subqueries, sq)
 	return c
 }
 
+// notInAsNotExists returns the NOT IN of columns against subquery rewritten as NOT EXISTS, see
+// NullSafeNotIn. It is bound to the statement, so that it is quoted with its dialect.
+func notInAsNotExists(columns []string, subquery *SelectBuilder) (notExistsArg, error) {
+	if len(subquery.columns) != len(columns) {
+		return notExistsArg{}, fmt.Errorf("NullSafeNotIn: the subquery must select %d columns", len(columns))
+	}
+	for _, col := range columns {
+		// an unqualified column would bind to the subquery's own column of that name
+		if parts, ok := sqldialect.SplitQualified(col); !ok || len(parts) < 2 {
+			return notExistsArg{}, fmt.Errorf("NullSafeNotIn: column %q must be qualified with its table or alias", col)
+		}
+	}
+	names := make([]string, len(columns))
+	for i, col := range subquery.columns {
+		switch c := col.(type) {
+		case string:
+			if isPlainIdent(c) {
+				names[i] = c[strings.LastIndexByte(c, '.')+1:]
+			}
+		case AliasExpr:
+			names[i] = c.Alias
+		}
+		if names[i] == "" {
+			return notExistsArg{}, fmt.Errorf("NullSafeNotIn: subquery column %d must be a column name or have an alias", i+1)
+		}
+	}
+	return notExistsArg{query: subquery, columns: columns, names: names}, nil
+}
+
+// notExistsArg is a NOT IN against a subquery written as NOT EXISTS, bound in place of a ? of
+// a condition like a subqueryArg.
+type notExistsArg struct {
+	query   *SelectBuilder
+	columns []string // the columns of the enclosing query
+	names   []string // the names of the columns the subquery selects
+}
+
+// BindSQL implements Binder.
+func (a notExistsArg) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	sql, args, _ := buildPositional(a.query, dialect) // checked by notInAsNotExists' callers
+	alias := dialect.QuoteIdent("not_in")
+	conds := make([]string, len(a.columns))
+	for i, col := range a.columns {
+		conds[i] = alias + "." + dialect.QuoteIdent(a.names[i]) + " = " + quoteQualifiedIdent(dialect, col)
+	}
+	return "NOT EXISTS (SELECT 1 FROM (" + sql + ")" + asTableAlias(dialect, alias) + " WHERE " + strings.Join(conds, " AND ") + ")", args
+}
+
+// buildPositional builds a subquery with ? placeholders, so they are numbered together with
+// the enclosing query. The subquery uses dialect unless it has its own.
+func buildPositional(subquery *SelectBuilder, dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if subquery == nil {
+		return subquery.Build() // reports the nil builder
+	}
+	q := *subquery
+	if q.dialect == nil {
+		q.dialect = dialect
+	}
+	q.dialect = positional(q.dialect)
+	return q.Build()
+}
+
 // NotIn adds a NOT IN condition (column NOT IN (values...)).
 func (c *ConditionBuilder) NotIn(column string, values ...interface{}) *ConditionBuilder {
 	if c.err != nil {
@@ -226,51 +575,18 @@ func (c *ConditionBuilder) NotIn(column string, values ...interface{}) *Conditio
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
+	quotedCol := quoteQualifiedIdent(dialect, column)
+
+	if len(values) == 1 {
+		if subquery, ok := values[0].(*SelectBuilder); ok {
+			return c.inQuery("NOT IN", column, subquery)
 		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
 	}
-
-	// Check if any value is a subquery
-	hasSubquery := false
 	for _, value := range values {
 		if _, ok := value.(*SelectBuilder); ok {
-			hasSubquery = true
-			break
-		}
-	}
-
-	if hasSubquery {
-		// Handle subqueries in NOT IN clause
-		if len(values) != 1 {
 			c.err = fmt.Errorf("NOT IN with subquery can only have one subquery value")
 			return c
 		}
-
-		subquery, ok := values[0].(*SelectBuilder)
-		if !ok {
-			c.err = fmt.Errorf("NOT IN with subquery must be *SelectBuilder")
-			return c
-		}
-
-		sql, args, err := subquery.Build()
-		if err != nil {
-			c.err = fmt.Errorf("NOT IN subquery error: %w", err)
-			return c
-		}
-
-		c.parts = append(c.parts, quotedCol+" NOT IN (("+sql+"))")
-		c.args = append(c.args, args...)
-		return c
 	}
 
 	// Handle regular values
@@ -279,8 +595,7 @@ func (c *ConditionBuilder) NotIn(column string, values ...interface{}) *Conditio
 		placeholders[i] = "?"
 	}
 
-	c.parts = append(c.parts, quotedCol+" NOT IN ("+strings.Join(placeholders, ", ")+")")
-	c.args = append(c.args, values...)
+	c.add(quotedCol+" NOT IN ("+strings.Join(placeholders, ", ")+")", values...)
 	return c
 }
 
@@ -291,22 +606,9 @@ func (c *ConditionBuilder) Between(column string, min, max interface{}) *Conditi
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
-		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
-	}
+	quotedCol := quoteQualifiedIdent(dialect, column)
 
-	c.parts = append(c.parts, quotedCol+" BETWEEN ? AND ?")
-	c.args = append(c.args, min, max)
+	c.add(quotedCol+" BETWEEN ? AND ?", min, max)
 	return c
 }
 
@@ -317,22 +619,34 @@ func (c *ConditionBuilder) NotBetween(column string, min, max interface{}) *Cond
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
-		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
-	}
+	quotedCol := quoteQualifiedIdent(dialect, column)
+
+	c.add(quotedCol+" NOT BETWEEN ? AND ?", min, max)
+	return c
+}
 
-	c.parts = append(c.parts, quotedCol+" NOT BETWEEN ? AND ?")
-	c.args = append(c.args, min, max)
+// DateRange adds a half-open time range condition for the bounds that are set: column >= from
+// if from is not nil, and column < to if to is not nil, so that consecutive ranges do not
+// overlap. With neither bound set, it adds nothing. It is an error if to is before from.
+//
+// Example usage:
+//
+//	NewCond().DateRange("created_at", &start, nil) // created_at >= ?
+func (c *ConditionBuilder) DateRange(column string, from, to *time.Time) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		c.err = fmt.Errorf("DateRange: to must not be before from")
+		return c
+	}
+	col := quoteQualifiedIdent(c.getDialect(), column)
+	if from != nil {
+		c.add(col+" >= ?", *from)
+	}
+	if to != nil {
+		c.add(col+" < ?", *to)
+	}
 	return c
 }
 
@@ -343,21 +657,9 @@ func (c *ConditionBuilder) IsNull(column string) *ConditionBuilder {
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
-		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
-	}
+	quotedCol := quoteQualifiedIdent(dialect, column)
 
-	c.parts = append(c.parts, quotedCol+" IS NULL")
+	c.add(quotedCol + " IS NULL")
 	return c
 }
 
@@ -368,95 +670,73 @@ func (c *ConditionBuilder) IsNotNull(column string) *ConditionBuilder {
 	}
 
 	dialect := c.getDialect()
-	var quotedCol string
-
-	// Handle table-qualified column names (e.g., "table.column")
-	if strings.Contains(column, ".") {
-		parts := strings.Split(column, ".")
-		quotedParts := make([]string, len(parts))
-		for i, part := range parts {
-			quotedParts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
-		}
-		quotedCol = strings.Join(quotedParts, ".")
-	} else {
-		quotedCol = dialect.QuoteIdent(column)
-	}
+	quotedCol := quoteQualifiedIdent(dialect, column)
 
-	c.parts = append(c.parts, quotedCol+" IS NOT NULL")
+	c.add(quotedCol + " IS NOT NULL")
 	return c
 }
 
-// Exists adds an EXISTS condition (EXISTS (subquery)).
+// Exists adds an EXISTS condition (EXISTS (subquery)). A *SelectBuilder subquery is built
+// when the statement the condition is added to is, with the dialect of the statement unless it
+// has its own, and its placeholders are numbered together with those of the statement.
 func (c *ConditionBuilder) Exists(subquery interface{}) *ConditionBuilder {
-	if c.err != nil {
-		return c
-	}
-
-	var sql string
-	var args []interface{}
-	var err error
-
-	switch sq := subquery.(type) {
-	case *SelectBuilder:
-		sql, args, err = sq.Build()
-		if err != nil {
-			c.err = fmt.Errorf("exists subquery error: %w", err)
-			return c
-		}
-	case raw.Raw:
-		sql = string(sq)
-	default:
-		c.err = fmt.Errorf("exists: subquery must be *SelectBuilder or raw.Raw (got %T)", subquery)
-		return c
-	}
-
-	c.parts = append(c.parts, "EXISTS ("+sql+")")
-	c.args = append(c.args, args...)
-	return c
+	return c.exists("EXISTS", "exists", subquery)
 }
 
-// NotExists adds a NOT EXISTS condition (NOT EXISTS (subquery)).
+// NotExists adds a NOT EXISTS condition (NOT EXISTS (subquery)). See Exists.
 func (c *ConditionBuilder) NotExists(subquery interface{}) *ConditionBuilder {
+	return c.exists("NOT EXISTS", "not exists", subquery)
+}
+
+func (c *ConditionBuilder) exists(op, name string, subquery interface{}) *ConditionBuilder {
 	if c.err != nil {
 		return c
 	}
 
-	var sql string
-	var args []interface{}
-	var err error
-
 	switch sq := subquery.(type) {
 	case *SelectBuilder:
-		sql, args, err = sq.Build()
-		if err != nil {
-			c.err = fmt.Errorf("not exists subquery error: %w", err)
+		if _, _, err := sq.Build(); err != nil {
+			c.err = fmt.Errorf("%s subquery error: %w", name, err)
 			return c
 		}
+		sq = sq.Clone()
+		c.subqueries = append(c.subqueries, sq)
+		c.add(op+" (?)", subqueryArg{query: sq})
 	case raw.Raw:
-		sql = string(sq)
+		c.add(op + " (" + string(sq) + ")")
 	default:
-		c.err = fmt.Errorf("not exists: subquery must be *SelectBuilder or raw.Raw (got %T)", subquery)
-		return c
+		c.err = fmt.Errorf("%s: subquery must be *SelectBuilder or raw.Raw (got %T)", name, subquery)
 	}
-
-	c.parts = append(c.parts, "NOT EXISTS ("+sql+")")
-	c.args = append(c.args, args...)
 	return c
 }
 
+// subqueryArg is a subquery bound in place of a ? of a condition, so that it is built by the
+// statement the condition is added to, with the dialect of the statement.
+type subqueryArg struct {
+	query *SelectBuilder
+}
+
+// BindSQL implements Binder.
+func (a subqueryArg) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	sql, args, _ := buildPositional(a.query, dialect) // checked by exists
+	return sql, args
+}
+
 // Case adds a CASE WHEN condition.
 func (c *ConditionBuilder) Case() *CaseBuilder {
 	return &CaseBuilder{parent: c}
 }
 
-// Raw adds a raw SQL condition.
+// Raw adds a raw SQL condition. It is parenthesized when ANDed with other conditions, since
+// it may contain an OR.
 func (c *ConditionBuilder) Raw(sql string, args ...interface{}) *ConditionBuilder {
 	if c.err != nil {
 		return c
 	}
 
-	c.parts = append(c.parts, sql)
-	c.args = append(c.args, args...)
+	leaf := condLeaf(sql, args...)
+	leaf.raw = true
+	c.expr = condAnd(c.expr, leaf)
 	return c
 }
 
@@ -465,64 +745,141 @@ func (c *ConditionBuilder) And(other *ConditionBuilder) *ConditionBuilder {
 	if c.err != nil {
 		return c
 	}
-	if other.err != nil {
-		c.err = other.err
+	if other == nil {
+		c.err = fmt.Errorf("and: condition must not be nil")
 		return c
 	}
-
-	if len(other.parts) == 0 {
+	if other.err != nil {
+		c.err = other.err
 		return c
 	}
 
-	if len(c.parts) == 0 {
-		c.parts = other.parts
-		c.args = other.args
+	if other.expr == nil {
 		return c
 	}
 
-	// Combine conditions with AND
-	c.parts = append(c.parts, other.parts...)
-	c.args = append(c.args, other.args...)
+	c.subqueries = append(c.subqueries, other.subqueries...)
+	c.expr = condAnd(c.expr, other.expr.clone())
 	return c
 }
 
-// Or combines conditions with OR.
+// Or combines the conditions added so far with other using OR. Conditions added after Or are
+// ANDed with the whole OR, so NewCond().Equal("a", 1).Or(other).Equal("b", 2) renders
+// ((a = ?) OR (...)) AND b = ?.
 func (c *ConditionBuilder) Or(other *ConditionBuilder) *ConditionBuilder {
 	if c.err != nil {
 		return c
 	}
+	if other == nil {
+		c.err = fmt.Errorf("or: condition must not be nil")
+		return c
+	}
 	if other.err != nil {
 		c.err = other.err
 		return c
 	}
 
-	if len(other.parts) == 0 {
+	if other.expr == nil {
 		return c
 	}
 
-	if len(c.parts) == 0 {
-		c.parts = other.parts
-		c.args = other.args
+	c.subqueries = append(c.subqueries, other.subqueries...)
+	c.expr = condOr(c.expr, other.expr.clone())
+	return c
+}
+
+// Not adds the negation of other, as NOT (...), combined with AND. It is an error if other is
+// empty, since there is nothing to negate.
+//
+// Example usage:
+//
+//	sqltk.NewCond().Equal("tenant_id", 7).Not(sqltk.NewCond().Equal("status", "closed").IsNull("owner_id"))
+//	// tenant_id = ? AND NOT (status = ? AND owner_id IS NULL)
+func (c *ConditionBuilder) Not(other *ConditionBuilder) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	if other == nil {
+		c.err = fmt.Errorf("not: condition must not be nil")
+		return c
+	}
+	if other.err != nil {
+		c.err = other.err
+		return c
+	}
+	if other.expr == nil {
+		c.err = fmt.Errorf("not: condition must not be empty")
 		return c
 	}
 
-	// Wrap both sides in parentheses and combine with OR
-	c.parts = []string{"(" + strings.Join(c.parts, " AND ") + ") OR (" + strings.Join(other.parts, " AND ") + ")"}
-	c.args = append(c.args, other.args...)
+	c.expr = condAnd(c.expr, &condNode{op: "NOT", children: []*condNode{other.expr.clone()}})
+	c.subqueries = append(c.subqueries, other.subqueries...)
 	return c
 }
 
-// Build returns the SQL condition string and arguments.
-func (c *ConditionBuilder) Build() (string, []interface{}, error) {
+// Group adds other in parentheses, combined with AND, so that an Or inside it binds before
+// the conditions around it. An empty other adds nothing.
+//
+// Example usage:
+//
+//	sqltk.NewCond().Equal("tenant_id", 7).Group(sqltk.NewCond().Equal("role", "admin").Or(sqltk.NewCond().Equal("role", "owner")))
+//	// tenant_id = ? AND ((role = ?) OR (role = ?))
+func (c *ConditionBuilder) Group(other *ConditionBuilder) *ConditionBuilder {
 	if c.err != nil {
-		return "", nil, c.err
+		return c
+	}
+	if other == nil {
+		c.err = fmt.Errorf("group: condition must not be nil")
+		return c
+	}
+	if other.err != nil {
+		c.err = other.err
+		return c
+	}
+	if other.expr == nil {
+		return c
 	}
 
-	if len(c.parts) == 0 {
-		return "", nil, nil
+	c.expr = condAnd(c.expr, &condNode{op: "GROUP", children: []*condNode{other.expr.clone()}})
+	c.subqueries = append(c.subqueries, other.subqueries...)
+	return c
+}
+
+// Not returns a condition negating cond, as NOT (...). It is shorthand for NewCond().Not(cond).
+func Not(cond *ConditionBuilder) *ConditionBuilder {
+	return NewCond().Not(cond)
+}
+
+// Group returns cond in parentheses. It is shorthand for NewCond().Group(cond).
+func Group(cond *ConditionBuilder) *ConditionBuilder {
+	return NewCond().Group(cond)
+}
+
+// Build returns the SQL condition string and arguments, with the subqueries of Exists and
+// NotExists built with the dialect of the condition builder.
+func (c *ConditionBuilder) Build() (string, []interface{}, error) {
+	sql, args, err := c.BuildCondition()
+	if err == nil {
+		err = sqldialect.CheckIdents(c.getDialect(), sql)
+	}
+	if err == nil {
+		err = argsErr(args)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	for _, arg := range args {
+		switch arg.(type) {
+		case subqueryArg, notExistsArg, regexpArg, jsonContainsArg, jsonPathArg:
+			return bindArgs(c.getDialect(), sql, args)
+		}
 	}
+	return sql, args, nil
+}
 
-	return strings.Join(c.parts, " AND "), c.args, nil
+// add ANDs a single condition to the conditions added so far.
+func (c *ConditionBuilder) add(sql string, args ...interface{}) {
+	c.expr = condAnd(c.expr, condLeaf(sql, args...))
 }
 
 // GetUnsafeString returns the condition as a string (for debugging).
@@ -534,7 +891,7 @@ func (c *ConditionBuilder) GetUnsafeString() string {
 	if len(args) == 0 {
 		return sql
 	}
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(c.getDialect(), sql, args).GetUnsafeString()
 }
 
 // CaseBuilder provides a fluent API for building CASE WHEN expressions.
@@ -610,9 +967,7 @@ func (cb *CaseBuilder) End() *ConditionBuilder {
 	}
 	caseSQL += " END"
 
-	cb.parent.parts = append(cb.parent.parts, caseSQL)
-	cb.parent.args = append(cb.parent.args, cb.whenArgs...)
-	cb.parent.args = append(cb.parent.args, cb.elseArgs...)
+	cb.parent.add(caseSQL, append(append([]interface{}{}, cb.whenArgs...), cb.elseArgs...)...)
 
 	return cb.parent
 }
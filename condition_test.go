@@ -2,7 +2,9 @@ package sqltk
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sprylic/sqltk/raw"
 	"github.com/sprylic/sqltk/sqldialect"
@@ -190,6 +192,91 @@ func TestConditionBuilder_Like(t *testing.T) {
 	})
 }
 
+// customDialect is a dialect of another database, which sqltk has no syntax for.
+type customDialect struct {
+	sqldialect.Dialect
+}
+
+func TestConditionBuilder_Regexp(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqldialect.Dialect
+		not     bool
+		wantSQL string
+	}{
+		{name: "default", wantSQL: "name REGEXP ?"},
+		{name: "default not", not: true, wantSQL: "name NOT REGEXP ?"},
+		{name: "mysql", dialect: sqldialect.MySQL(), wantSQL: "`name` REGEXP ?"},
+		{name: "postgres", dialect: sqldialect.Postgres(), wantSQL: `"name" ~ ?`},
+		{name: "postgres not", dialect: sqldialect.Postgres(), not: true, wantSQL: `"name" !~ ?`},
+		{name: "duckdb not", dialect: sqldialect.DuckDB(), not: true, wantSQL: `NOT regexp_matches("name", ?)`},
+		{name: "bigquery", dialect: sqldialect.BigQuery(), wantSQL: "REGEXP_CONTAINS(`name`, ?)"},
+		{name: "oracle", dialect: sqldialect.Oracle(), wantSQL: `REGEXP_LIKE("name", ?)`},
+		{name: "oracle not", dialect: sqldialect.Oracle(), not: true, wantSQL: `NOT REGEXP_LIKE("name", ?)`},
+		{name: "clickhouse", dialect: sqldialect.ClickHouse(), wantSQL: "match(`name`, ?)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCond().WithDialect(tt.dialect)
+			if tt.not {
+				c.NotRegexp("name", "^a.*z$")
+			} else {
+				c.Regexp("name", "^a.*z$")
+			}
+			sql, args, err := c.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, []interface{}{"^a.*z$"}) {
+				t.Errorf("got args %v, want [^a.*z$]", args)
+			}
+		})
+	}
+
+	t.Run("where", func(t *testing.T) {
+		sql, _, err := Select("id").From("users").
+			Where(NewCond().WithDialect(sqldialect.Postgres()).Regexp("users.email", "@example\\.com$")).
+			WithDialect(sqldialect.Postgres()).Build()
+		want := `SELECT "id" FROM "users" WHERE "users"."email" ~ $1`
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+	})
+
+	t.Run("statement dialect", func(t *testing.T) {
+		tests := []struct {
+			dialect sqldialect.Dialect
+			wantSQL string
+		}{
+			{sqldialect.Postgres(), `SELECT "id" FROM "users" WHERE "name" ~ $1`},
+			{sqldialect.MySQL(), "SELECT `id` FROM `users` WHERE `name` REGEXP ?"},
+			{sqldialect.Oracle(), `SELECT "id" FROM "users" WHERE REGEXP_LIKE("name", :1)`},
+		}
+		for _, tt := range tests {
+			sql, _, err := Select("id").From("users").Where(NewCond().Regexp("name", "^a")).WithDialect(tt.dialect).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		}
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		q := Select("id").From("users").Where(NewCond().NotRegexp("name", "^a")).WithDialect(customDialect{sqldialect.Postgres()})
+		if _, _, err := q.Build(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
 func TestConditionBuilder_In(t *testing.T) {
 	t.Run("in values", func(t *testing.T) {
 		cond := NewCond().In("status", "active", "pending", "approved")
@@ -231,6 +318,393 @@ func TestConditionBuilder_In(t *testing.T) {
 	})
 }
 
+func TestConditionBuilder_InQuery(t *testing.T) {
+	pg := sqldialect.Postgres()
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "in query with merged postgres placeholders",
+			builder: Select("id").From("users").WhereEqual("active", true).
+				Where(NewCond().WithDialect(pg).InQuery("id", Select("user_id").From("orders").WhereEqual("status", "paid"))).
+				WhereEqual("role", "admin").
+				WithDialect(pg),
+			wantSQL:  `SELECT "id" FROM "users" WHERE active = $1 AND "id" IN (SELECT "user_id" FROM "orders" WHERE status = $2) AND role = $3`,
+			wantArgs: []interface{}{true, "paid", "admin"},
+		},
+		{
+			name: "in with a single subquery",
+			builder: Select("id").From("users").
+				Where(NewCond().WithDialect(pg).In("id", Select("user_id").From("orders").WhereEqual("status", "paid"))).
+				WithDialect(pg),
+			wantSQL:  `SELECT "id" FROM "users" WHERE "id" IN (SELECT "user_id" FROM "orders" WHERE status = $1)`,
+			wantArgs: []interface{}{"paid"},
+		},
+		{
+			name: "not in with a single subquery",
+			builder: Select("id").From("users").
+				Where(NewCond().WithDialect(pg).NotIn("id", Select("user_id").From("bans"))).
+				WithDialect(pg),
+			wantSQL:  `SELECT "id" FROM "users" WHERE "id" NOT IN (SELECT "user_id" FROM "bans")`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "subquery mixed with values",
+			builder: Select("id").From("users").Where(NewCond().In("id", 1, Select("user_id").From("orders"))),
+			wantErr: true,
+		},
+		{
+			name:    "nil subquery",
+			builder: Select("id").From("users").Where(NewCond().InQuery("id", nil)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestConditionBuilder_NotInQuery(t *testing.T) {
+	managers := func() *SelectBuilder { return Select("manager_id").From("employees") }
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "not in query",
+			builder:  NewCond().NotInQuery("id", managers()),
+			wantSQL:  "id NOT IN (SELECT manager_id FROM employees)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "null safe",
+			builder:  NewCond().NullSafeNotIn().NotInQuery("e.id", managers()),
+			wantSQL:  "NOT EXISTS (SELECT 1 FROM (SELECT manager_id FROM employees) AS not_in WHERE not_in.manager_id = e.id)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "null safe not in with a subquery",
+			builder:  NewCond().NullSafeNotIn().NotIn("e.id", Select("e2.manager_id").From("employees e2")),
+			wantSQL:  "NOT EXISTS (SELECT 1 FROM (SELECT e2.manager_id FROM employees e2) AS not_in WHERE not_in.manager_id = e.id)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "null safe leaves in alone",
+			builder:  NewCond().NullSafeNotIn().InQuery("id", managers()),
+			wantSQL:  "id IN (SELECT manager_id FROM employees)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "null safe row not in",
+			builder: NewCond().NullSafeNotIn().RowNotIn([]string{"r.a", "r.b"},
+				Select("x", Alias(raw.Raw("y + 1"), "z")).From("t").WhereEqual("k", 1)),
+			wantSQL:  "NOT EXISTS (SELECT 1 FROM (SELECT x, y + 1 AS z FROM t WHERE k = ?) AS not_in WHERE not_in.x = r.a AND not_in.z = r.b)",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "null safe postgres numbering",
+			builder: Select("id").From("users").WhereEqual("active", true).
+				Where(NewCond().WithDialect(sqldialect.Postgres()).NullSafeNotIn().
+					NotInQuery("users.id", Select("user_id").From("bans").WhereEqual("kind", "hard"))).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "users" WHERE active = $1 AND NOT EXISTS (SELECT 1 FROM (SELECT "user_id" FROM "bans" WHERE kind = $2) AS "not_in" WHERE "not_in"."user_id" = "users"."id")`,
+			wantArgs: []interface{}{true, "hard"},
+		},
+		{
+			name: "subqueries follow the statement's dialect",
+			builder: Select("id").From("users").
+				Where(NewCond().InQuery("id", Select("user_id").From("orders")).
+					RowIn([]string{"a", "b"}, Select("x", "y").From("t").WhereEqual("k", 1))).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "users" WHERE id IN (SELECT "user_id" FROM "orders") AND (a, b) IN (SELECT "x", "y" FROM "t" WHERE k = $1)`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "null safe follows the statement's dialect",
+			builder: Select("id").From("users").
+				Where(NewCond().NullSafeNotIn().NotInQuery("users.id", Select("user_id").From("bans"))).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "users" WHERE NOT EXISTS (SELECT 1 FROM (SELECT "user_id" FROM "bans") AS "not_in" WHERE "not_in"."user_id" = "users"."id")`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "null safe same column name",
+			builder:  NewCond().NullSafeNotIn().NotInQuery("users.id", Select("id").From("banned")),
+			wantSQL:  "NOT EXISTS (SELECT 1 FROM (SELECT id FROM banned) AS not_in WHERE not_in.id = users.id)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "null safe unqualified column",
+			builder: NewCond().NullSafeNotIn().NotInQuery("id", Select("id").From("banned")),
+			wantErr: true,
+		},
+		{
+			name:    "null safe unqualified row column",
+			builder: NewCond().NullSafeNotIn().RowNotIn([]string{"r.a", "b"}, Select("a", "b").From("t")),
+			wantErr: true,
+		},
+		{
+			name:    "null safe unnamed column",
+			builder: NewCond().NullSafeNotIn().NotInQuery("e.id", Select(raw.Raw("MAX(id)")).From("t")),
+			wantErr: true,
+		},
+		{
+			name:    "null safe select star",
+			builder: NewCond().NullSafeNotIn().NotInQuery("e.id", Select().From("t")),
+			wantErr: true,
+		},
+		{
+			name:    "nil subquery",
+			builder: NewCond().NotInQuery("id", nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) == 0 && len(tt.wantArgs) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestConditionBuilder_InSlice(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     *ConditionBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "int64 slice",
+			cond:     NewCond().InSlice("id", []int64{1, 2, 3}),
+			wantSQL:  "id IN (?, ?, ?)",
+			wantArgs: []interface{}{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:     "string array",
+			cond:     NewCond().NotInSlice("status", [2]string{"banned", "deleted"}),
+			wantSQL:  "status NOT IN (?, ?)",
+			wantArgs: []interface{}{"banned", "deleted"},
+		},
+		{
+			name:     "interface slice",
+			cond:     NewCond().InSlice("id", []interface{}{1, "a"}),
+			wantSQL:  "id IN (?, ?)",
+			wantArgs: []interface{}{1, "a"},
+		},
+		{
+			name:    "empty slice",
+			cond:    NewCond().InSlice("id", []int{}),
+			wantErr: true,
+		},
+		{
+			name:    "not a slice",
+			cond:    NewCond().InSlice("id", 7),
+			wantErr: true,
+		},
+		{
+			name:    "bytes are not a slice",
+			cond:    NewCond().InSlice("hash", []byte("abc")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.cond.WithDialect(sqldialect.NoQuoteIdent()).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("select with postgres placeholders", func(t *testing.T) {
+		sql, args, err := Select("id").From("users").WhereInSlice("id", []int{4, 5}).WithDialect(sqldialect.Postgres()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantSQL := `SELECT "id" FROM "users" WHERE id IN ($1, $2)`
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if wantArgs := []interface{}{4, 5}; !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %#v, want %#v", args, wantArgs)
+		}
+	})
+}
+
+func TestConditionBuilder_InEnum(t *testing.T) {
+	allowed := []string{"active", "pending", "closed"}
+
+	tests := []struct {
+		name     string
+		cond     *ConditionBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "allowed values",
+			cond:     NewCond().InEnum("status", allowed, []string{"pending", "active"}),
+			wantSQL:  "status IN (?, ?)",
+			wantArgs: []interface{}{"pending", "active"},
+		},
+		{
+			name:     "repeated values are bound once",
+			cond:     NewCond().InEnum("status", allowed, []string{"closed", "closed"}),
+			wantSQL:  "status IN (?)",
+			wantArgs: []interface{}{"closed"},
+		},
+		{
+			name:    "unknown value",
+			cond:    NewCond().InEnum("status", allowed, []string{"active", "1=1; --"}),
+			wantErr: true,
+		},
+		{
+			name:    "nothing requested",
+			cond:    NewCond().InEnum("status", allowed, nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.cond.WithDialect(sqldialect.NoQuoteIdent()).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("select error", func(t *testing.T) {
+		_, _, err := Select("id").From("tickets").WhereInEnum("status", allowed, []string{"archived"}).Build()
+		if err == nil || !strings.Contains(err.Error(), `"archived"`) {
+			t.Errorf("got error %v, want an error naming the unknown value", err)
+		}
+	})
+}
+
+func TestConditionBuilder_RowIn(t *testing.T) {
+	t.Run("row in subquery", func(t *testing.T) {
+		sub := Select("tenant_id", "user_id").From("banned_users").WhereEqual("active", true)
+		cond := NewCond().RowIn([]string{"t.tenant_id", "t.user_id"}, sub)
+		sql, args, err := cond.Build()
+		wantSQL := "(t.tenant_id, t.user_id) IN (SELECT tenant_id, user_id FROM banned_users WHERE active = ?)"
+		wantArgs := []interface{}{true}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("row not in with merged postgres placeholders", func(t *testing.T) {
+		pg := sqldialect.Postgres()
+		sub := Select("tenant_id", "user_id").From("banned_users").WhereEqual("reason", "spam").WithDialect(pg)
+		q := Select("id").From("orders").WhereEqual("status", "open").
+			Where(NewCond().WithDialect(pg).RowNotIn([]string{"tenant_id", "user_id"}, sub)).
+			WithDialect(pg)
+		sql, args, err := q.Build()
+		wantSQL := `SELECT "id" FROM "orders" WHERE status = $1 AND ("tenant_id", "user_id") NOT IN (SELECT "tenant_id", "user_id" FROM "banned_users" WHERE reason = $2)`
+		wantArgs := []interface{}{"open", "spam"}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("column count mismatch", func(t *testing.T) {
+		_, _, err := NewCond().RowIn([]string{"a", "b"}, Select("x").From("t")).Build()
+		if err == nil {
+			t.Errorf("expected error for column count mismatch, got none")
+		}
+	})
+
+	t.Run("nil subquery", func(t *testing.T) {
+		_, _, err := NewCond().RowIn([]string{"a"}, nil).Build()
+		if err == nil {
+			t.Errorf("expected error for nil subquery, got none")
+		}
+	})
+}
+
 func TestConditionBuilder_Between(t *testing.T) {
 	t.Run("between values", func(t *testing.T) {
 		cond := NewCond().Between("age", 18, 65)
@@ -265,6 +739,60 @@ func TestConditionBuilder_Between(t *testing.T) {
 	})
 }
 
+func TestConditionBuilder_DateRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to *time.Time
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{name: "both", from: &from, to: &to, wantSQL: "created_at >= ? AND created_at < ?", wantArgs: []interface{}{from, to}},
+		{name: "from only", from: &from, wantSQL: "created_at >= ?", wantArgs: []interface{}{from}},
+		{name: "to only", to: &to, wantSQL: "created_at < ?", wantArgs: []interface{}{to}},
+		{name: "neither", wantSQL: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := NewCond().DateRange("created_at", tt.from, tt.to).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("where", func(t *testing.T) {
+		sql, _, err := Select("id").From("orders").WhereEqual("status", "paid").WhereDateRange("orders.created_at", nil, &to).Build()
+		want := "SELECT id FROM orders WHERE status = ? AND orders.created_at < ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		sql, _, err = Select("id").From("orders").WhereDateRange("created_at", nil, nil).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != "SELECT id FROM orders" {
+			t.Errorf("got SQL %q with no bounds", sql)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, _, err := NewCond().DateRange("created_at", &to, &from).Build(); err == nil {
+			t.Error("expected error for to before from")
+		}
+	})
+}
+
 func TestConditionBuilder_Null(t *testing.T) {
 	t.Run("is null", func(t *testing.T) {
 		cond := NewCond().IsNull("deleted_at")
@@ -345,6 +873,55 @@ func TestConditionBuilder_Exists(t *testing.T) {
 			t.Errorf("got args %v, want none", args)
 		}
 	})
+
+	t.Run("parent dialect", func(t *testing.T) {
+		paid := Select(raw.Raw("1")).From("orders").WhereEqual("status", "paid")
+		q := Select("id").From("users").
+			WhereEqual("tenant_id", 7).
+			Where(NewCond().Exists(paid).NotExists(Select(raw.Raw("1")).From("bans").WhereEqual("active", true))).
+			WithDialect(sqldialect.Postgres())
+		sql, args, err := q.Build()
+		wantSQL := `SELECT "id" FROM "users" WHERE tenant_id = $1 AND EXISTS (SELECT 1 FROM "orders" WHERE status = $2) AND NOT EXISTS (SELECT 1 FROM "bans" WHERE active = $3)`
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, []interface{}{7, "paid", true}) {
+			t.Errorf("got args %v, want [7 paid true]", args)
+		}
+
+		node, err := q.AST()
+		if err != nil {
+			t.Fatalf("unexpected AST error: %v", err)
+		}
+		rebuilt, err := FromAST(node)
+		if err != nil {
+			t.Fatalf("unexpected FromAST error: %v", err)
+		}
+		if got, _, _ := rebuilt.WithDialect(sqldialect.Postgres()).Build(); got != wantSQL {
+			t.Errorf("got rebuilt SQL %q, want %q", got, wantSQL)
+		}
+	})
+
+	t.Run("subquery dialect", func(t *testing.T) {
+		sub := Select(raw.Raw("1")).From("orders").WhereEqual("status", "paid").WithDialect(sqldialect.MySQL())
+		sql, _, err := Delete("users").Where(NewCond().Exists(sub)).WhereEqual("id", 1).WithDialect(sqldialect.Postgres()).Build()
+		wantSQL := `DELETE FROM "users" WHERE EXISTS (SELECT 1 FROM ` + "`orders`" + ` WHERE status = $1) AND id = $2`
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, _, err := NewCond().Exists(Select("id")).Build(); err == nil {
+			t.Error("expected error for a subquery without FROM")
+		}
+	})
 }
 
 func TestConditionBuilder_Combination(t *testing.T) {
@@ -406,6 +983,274 @@ func TestConditionBuilder_Combination(t *testing.T) {
 	})
 }
 
+func TestConditionBuilder_Precedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     func() *ConditionBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "condition after or",
+			cond: func() *ConditionBuilder {
+				return NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2)).Equal("c", 3)
+			},
+			wantSQL:  "((a = ?) OR (b = ?)) AND c = ?",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name: "and of an or",
+			cond: func() *ConditionBuilder {
+				return NewCond().Equal("a", 1).And(NewCond().Equal("b", 2).Or(NewCond().Equal("c", 3)))
+			},
+			wantSQL:  "a = ? AND ((b = ?) OR (c = ?))",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name: "chained or",
+			cond: func() *ConditionBuilder {
+				return NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2)).Or(NewCond().Equal("c", 3).Equal("d", 4))
+			},
+			wantSQL:  "(a = ?) OR (b = ?) OR (c = ? AND d = ?)",
+			wantArgs: []interface{}{1, 2, 3, 4},
+		},
+		{
+			name: "or after and after or",
+			cond: func() *ConditionBuilder {
+				return NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2)).Equal("c", 3).Or(NewCond().Equal("d", 4))
+			},
+			wantSQL:  "(((a = ?) OR (b = ?)) AND c = ?) OR (d = ?)",
+			wantArgs: []interface{}{1, 2, 3, 4},
+		},
+		{
+			name: "or into empty",
+			cond: func() *ConditionBuilder {
+				return NewCond().Or(NewCond().Equal("a", 1)).Equal("b", 2)
+			},
+			wantSQL:  "a = ? AND b = ?",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name: "other changed after and",
+			cond: func() *ConditionBuilder {
+				other := NewCond().Equal("b", 2)
+				c := NewCond().Equal("a", 1).And(other)
+				other.Equal("z", 26)
+				return c
+			},
+			wantSQL:  "a = ? AND b = ?",
+			wantArgs: []interface{}{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.cond().Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("clone", func(t *testing.T) {
+		base := NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2))
+		extended := base.Clone().Equal("c", 3)
+		if sql, _, _ := base.Build(); sql != "(a = ?) OR (b = ?)" {
+			t.Errorf("got SQL %q for the original", sql)
+		}
+		if sql, _, _ := extended.Build(); sql != "((a = ?) OR (b = ?)) AND c = ?" {
+			t.Errorf("got SQL %q for the clone", sql)
+		}
+	})
+
+	or := func() *ConditionBuilder { return NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2)) }
+
+	t.Run("select where after or", func(t *testing.T) {
+		q := Select("id").From("t").Where(or()).Where(NewCond().Equal("c", 3))
+		sql, args, err := q.Build()
+		wantSQL := "SELECT id FROM t WHERE ((a = ?) OR (b = ?)) AND c = ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, []interface{}{1, 2, 3}) {
+			t.Errorf("got args %v", args)
+		}
+	})
+
+	t.Run("select where or after where", func(t *testing.T) {
+		q := Select("id").From("t").WhereEqual("c", 3).Where(or()).WhereEqual("d", 4)
+		sql, _, err := q.Build()
+		wantSQL := "SELECT id FROM t WHERE c = ? AND ((a = ?) OR (b = ?)) AND d = ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("select where or alone", func(t *testing.T) {
+		sql, _, err := Select("id").From("t").Where(or()).Build()
+		wantSQL := "SELECT id FROM t WHERE (a = ?) OR (b = ?)"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("having after or", func(t *testing.T) {
+		q := Select("a").From("t").GroupBy("a").
+			Having(NewCond().Raw("COUNT(*) > ?", 1).Or(NewCond().Raw("SUM(b) > ?", 2))).
+			Having(NewStringCondition("MAX(b) < ?", 3))
+		sql, _, err := q.Build()
+		wantSQL := "SELECT a FROM t GROUP BY a HAVING ((COUNT(*) > ?) OR (SUM(b) > ?)) AND MAX(b) < ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("update where after or", func(t *testing.T) {
+		q := Update("t").Set("x", 0).Where(or()).Where(NewCond().Equal("c", 3))
+		sql, args, err := q.Build()
+		wantSQL := "UPDATE t SET x = ? WHERE ((a = ?) OR (b = ?)) AND c = ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, []interface{}{0, 1, 2, 3}) {
+			t.Errorf("got args %v", args)
+		}
+	})
+
+	t.Run("delete where after or", func(t *testing.T) {
+		q := Delete("t").Where(or()).WhereEqual("c", 3)
+		sql, _, err := q.Build()
+		wantSQL := "DELETE FROM t WHERE ((a = ?) OR (b = ?)) AND c = ?"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("raw conditions are grouped", func(t *testing.T) {
+		q := Select("id").From("t").
+			Where(NewCond().Raw("x = 1 OR y = 2")).
+			Where(NewCond().Equal("c", 3).Raw("p = 1 OR q = 2").Not(NewCond().Raw("r = 1 OR s = 2")))
+		sql, _, err := q.Build()
+		wantSQL := "SELECT id FROM t WHERE (x = 1 OR y = 2) AND c = ? AND (p = 1 OR q = 2) AND NOT (r = 1 OR s = 2)"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+}
+
+func TestConditionBuilder_NotGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     *ConditionBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "not",
+			cond:     Not(NewCond().Equal("a", 1).Equal("b", 2)),
+			wantSQL:  "NOT (a = ? AND b = ?)",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "not after other conditions",
+			cond:     NewCond().Equal("tenant_id", 7).Not(NewCond().Equal("status", "closed").IsNull("owner_id")),
+			wantSQL:  "tenant_id = ? AND NOT (status = ? AND owner_id IS NULL)",
+			wantArgs: []interface{}{7, "closed"},
+		},
+		{
+			name:     "not of or",
+			cond:     Not(NewCond().Equal("a", 1).Or(NewCond().Equal("b", 2))),
+			wantSQL:  "NOT ((a = ?) OR (b = ?))",
+			wantArgs: []interface{}{1, 2},
+		},
+		{
+			name:     "group",
+			cond:     NewCond().Equal("tenant_id", 7).Group(NewCond().Equal("role", "admin").Or(NewCond().Equal("role", "owner"))).Equal("active", true),
+			wantSQL:  "tenant_id = ? AND ((role = ?) OR (role = ?)) AND active = ?",
+			wantArgs: []interface{}{7, "admin", "owner", true},
+		},
+		{
+			name:     "group in or",
+			cond:     Group(NewCond().Equal("a", 1).Equal("b", 2)).Or(NewCond().Equal("c", 3)),
+			wantSQL:  "((a = ? AND b = ?)) OR (c = ?)",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "empty group",
+			cond:     NewCond().Equal("a", 1).Group(NewCond()),
+			wantSQL:  "a = ?",
+			wantArgs: []interface{}{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.cond.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("where", func(t *testing.T) {
+		sql, args, err := Select("id").From("users").Where(Not(NewCond().Equal("a", 1).Equal("b", 2))).Build()
+		wantSQL := "SELECT id FROM users WHERE NOT (a = ? AND b = ?)"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, []interface{}{1, 2}) {
+			t.Errorf("got args %v, want [1 2]", args)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for name, cond := range map[string]*ConditionBuilder{
+			"nil not":     NewCond().Not(nil),
+			"empty not":   Not(NewCond()),
+			"nil group":   NewCond().Group(nil),
+			"inner error": Not(NewCond().In("a")),
+		} {
+			if _, _, err := cond.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
 func TestConditionBuilder_Case(t *testing.T) {
 	t.Run("simple case", func(t *testing.T) {
 		cond := NewCond().Case().
@@ -657,3 +1502,20 @@ func TestTypeSafeWhere(t *testing.T) {
 		t.Skip("This is now a compile-time error, not a runtime error")
 	})
 }
+
+func TestConditionBuilder_BitAnd(t *testing.T) {
+	sql, args, err := NewCond().WithDialect(sqldialect.MySQL()).BitAnd("u.flags", 6, 2).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "(`u`.`flags` & ?) = ?"; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if want := []interface{}{6, 2}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got args %v, want %v", args, want)
+	}
+
+	if _, _, err := NewCond().BitAnd("flags", nil, 1).Build(); err == nil {
+		t.Error("expected error for nil mask")
+	}
+}
@@ -0,0 +1,91 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestConditionalHelpers(t *testing.T) {
+	d := sqldialect.NoQuoteIdent()
+	name, minAge, sort := "bob", 0, "recent"
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "select",
+			builder: Select("id").From("users").
+				WhereIf(name != "", NewStringCondition("name = ?", name)).
+				WhereIf(minAge > 0, NewStringCondition("age >= ?", minAge)).
+				ApplyIf(sort == "recent", func(q *SelectBuilder) *SelectBuilder {
+					return q.OrderByDesc("created_at").Limit(10)
+				}).
+				ApplyIf(sort == "name", func(q *SelectBuilder) *SelectBuilder {
+					return q.OrderByAsc("name")
+				}).
+				WithDialect(d),
+			wantSQL:  "SELECT id FROM users WHERE name = ? ORDER BY created_at DESC LIMIT 10",
+			wantArgs: []interface{}{"bob"},
+		},
+		{
+			name: "update",
+			builder: Update("users").Set("active", false).
+				WhereIf(true, NewStringCondition("id = ?", 1)).
+				WhereIf(false, nil).
+				ApplyIf(true, func(q *UpdateBuilder) *UpdateBuilder { return q.Set("reason", "x") }).
+				WithDialect(d),
+			wantSQL:  "UPDATE users SET active = ?, reason = ? WHERE id = ?",
+			wantArgs: []interface{}{false, "x", 1},
+		},
+		{
+			name: "delete",
+			builder: Delete("users").WhereIf(false, NewStringCondition("id = ?", 1)).
+				ApplyIf(true, func(q *DeleteBuilder) *DeleteBuilder { return q.WhereEqual("banned", true) }).
+				WithDialect(d),
+			wantSQL:  "DELETE FROM users WHERE banned = ?",
+			wantArgs: []interface{}{true},
+		},
+		{
+			name: "insert",
+			builder: Insert("users").Columns("name").Values("a").
+				ApplyIf(false, func(q *InsertBuilder) *InsertBuilder { return q.Values("b") }).
+				WithDialect(d),
+			wantSQL:  "INSERT INTO users (name) VALUES (?)",
+			wantArgs: []interface{}{"a"},
+		},
+		{
+			name: "condition",
+			builder: Select("id").From("users").Where(NewCond().WithDialect(d).Equal("active", true).
+				ApplyIf(minAge == 0, func(c *ConditionBuilder) *ConditionBuilder { return c.IsNull("age") })).
+				WithDialect(d),
+			wantSQL:  "SELECT id FROM users WHERE active = ? AND age IS NULL",
+			wantArgs: []interface{}{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("nil condition when true", func(t *testing.T) {
+		if _, _, err := Select("id").From("users").WhereIf(true, nil).Build(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
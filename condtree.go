@@ -0,0 +1,115 @@
+package sqltk
+
+import "strings"
+
+// condNode is a node of the expression tree of a ConditionBuilder: a leaf holding a single
+// condition, or the AND, OR, or NOT of its children. Building the tree rather than strings
+// keeps the grouping of And, Or, and the conditions added after them as they were chained.
+type condNode struct {
+	op       string        // "" for a leaf, or "AND", "OR", "NOT", or "GROUP"
+	sql      string        // condition of a leaf, with ? placeholders
+	args     []interface{} // args of a leaf
+	raw      bool          // the leaf was added by Raw, so its SQL may contain an OR
+	children []*condNode
+}
+
+// condLeaf returns a leaf node, copying args.
+func condLeaf(sql string, args ...interface{}) *condNode {
+	return &condNode{sql: sql, args: append([]interface{}(nil), args...)}
+}
+
+// condAnd returns the AND of a and b, flattening nested ANDs. Either may be nil.
+func condAnd(a, b *condNode) *condNode {
+	return condJoin("AND", a, b)
+}
+
+// condOr returns the OR of a and b, flattening nested ORs. Either may be nil.
+func condOr(a, b *condNode) *condNode {
+	return condJoin("OR", a, b)
+}
+
+func condJoin(op string, a, b *condNode) *condNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	n := &condNode{op: op}
+	for _, child := range []*condNode{a, b} {
+		if child.op == op {
+			n.children = append(n.children, child.children...)
+		} else {
+			n.children = append(n.children, child)
+		}
+	}
+	return n
+}
+
+// clone returns a deep copy of n, so that trees taken from another ConditionBuilder do not
+// share nodes with it.
+func (n *condNode) clone() *condNode {
+	if n == nil {
+		return nil
+	}
+	c := &condNode{op: n.op, sql: n.sql, args: n.args, raw: n.raw}
+	if len(n.args) > 0 {
+		c.args = append([]interface{}{}, n.args...)
+	}
+	for _, child := range n.children {
+		c.children = append(c.children, child.clone())
+	}
+	return c
+}
+
+// build renders n with ? placeholders and returns its args in SQL order. The operands of an
+// OR are parenthesized, as are an OR and a Raw condition that are operands of an AND.
+func (n *condNode) build() (string, []interface{}) {
+	var args []interface{}
+	var sb strings.Builder
+	n.write(&sb, &args)
+	return sb.String(), args
+}
+
+func (n *condNode) write(sb *strings.Builder, args *[]interface{}) {
+	switch n.op {
+	case "":
+		sb.WriteString(n.sql)
+		*args = append(*args, n.args...)
+	case "AND":
+		for i, child := range n.children {
+			if i > 0 {
+				sb.WriteString(" AND ")
+			}
+			if child.needsGroup() {
+				sb.WriteString("(")
+				child.write(sb, args)
+				sb.WriteString(")")
+			} else {
+				child.write(sb, args)
+			}
+		}
+	case "OR":
+		for i, child := range n.children {
+			if i > 0 {
+				sb.WriteString(" OR ")
+			}
+			sb.WriteString("(")
+			child.write(sb, args)
+			sb.WriteString(")")
+		}
+	case "NOT", "GROUP":
+		if n.op == "NOT" {
+			sb.WriteString("NOT ")
+		}
+		sb.WriteString("(")
+		n.children[0].write(sb, args)
+		sb.WriteString(")")
+	}
+}
+
+// needsGroup reports whether n must be parenthesized when it is ANDed with other conditions:
+// it is an OR, or a Raw condition, whose SQL is not looked into.
+func (n *condNode) needsGroup() bool {
+	return n.op == "OR" || n.raw
+}
@@ -0,0 +1,76 @@
+package sqltk
+
+import (
+	"maps"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Config is a snapshot of the package-wide settings: the global dialect, call-site tracing,
+// the warning handler, the presets registered with RegisterPreset, and the fragments
+// registered in DefaultFragments. It is taken with SnapshotConfig and put back with
+// RestoreConfig.
+type Config struct {
+	dialect        sqldialect.Dialect
+	traceCallSites bool
+	warningHandler func(warning string)
+	presets        map[string][]interface{}
+	fragments      map[string]interface{}
+}
+
+// SnapshotConfig returns the current package-wide settings, so that code which changes them,
+// such as a test that calls SetDialect, can put them back afterwards with RestoreConfig.
+//
+// Example usage:
+//
+//	func TestPostgresQueries(t *testing.T) {
+//		saved := sqltk.SnapshotConfig()
+//		t.Cleanup(func() { sqltk.RestoreConfig(saved) })
+//		sqltk.SetDialect(sqldialect.Postgres())
+//		...
+//	}
+func SnapshotConfig() Config {
+	warningMu.RLock()
+	handler := warningHandler
+	warningMu.RUnlock()
+
+	presetMu.RLock()
+	columnSets := maps.Clone(presets)
+	presetMu.RUnlock()
+
+	DefaultFragments.mu.RLock()
+	fragments := maps.Clone(DefaultFragments.items)
+	DefaultFragments.mu.RUnlock()
+
+	return Config{
+		dialect:        sqldialect.GetDialect(),
+		traceCallSites: traceCallSites.Load(),
+		warningHandler: handler,
+		presets:        columnSets,
+		fragments:      fragments,
+	}
+}
+
+// RestoreConfig sets the package-wide settings to those of a snapshot taken with
+// SnapshotConfig. Presets and fragments registered after the snapshot are removed.
+func RestoreConfig(c Config) {
+	sqldialect.SetDialect(c.dialect)
+	traceCallSites.Store(c.traceCallSites)
+	SetWarningHandler(c.warningHandler)
+
+	columnSets := maps.Clone(c.presets)
+	if columnSets == nil {
+		columnSets = make(map[string][]interface{})
+	}
+	presetMu.Lock()
+	presets = columnSets
+	presetMu.Unlock()
+
+	fragments := maps.Clone(c.fragments)
+	if fragments == nil {
+		fragments = make(map[string]interface{})
+	}
+	DefaultFragments.mu.Lock()
+	DefaultFragments.items = fragments
+	DefaultFragments.mu.Unlock()
+}
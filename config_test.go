@@ -0,0 +1,42 @@
+package sqltk
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSnapshotConfig(t *testing.T) {
+	saved := SnapshotConfig()
+	t.Cleanup(func() { RestoreConfig(saved) })
+
+	RegisterFragment("config_test_before", NewStringCondition("a = 1"))
+	snapshot := SnapshotConfig()
+
+	var warnings []string
+	SetDialect(sqldialect.Postgres())
+	SetTraceCallSites(true)
+	SetWarningHandler(func(w string) { warnings = append(warnings, w) })
+	RegisterFragment("config_test_after", NewStringCondition("b = 2"))
+
+	RestoreConfig(snapshot)
+
+	if got := sqldialect.GetDialect(); got != sqldialect.NoQuoteIdent() {
+		t.Errorf("got dialect %T, want the dialect of the snapshot", got)
+	}
+	if traceCallSites.Load() {
+		t.Error("call-site tracing was not restored")
+	}
+	warn("restored")
+	if len(warnings) != 0 {
+		t.Errorf("got warnings %v from the replaced handler", warnings)
+	}
+	names := DefaultFragments.Names()
+	if !slices.Contains(names, "config_test_before") || slices.Contains(names, "config_test_after") {
+		t.Errorf("got fragments %v, want those of the snapshot", names)
+	}
+	if _, _, err := Select("id").From("t").Where(Fragment("config_test_before")).Build(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
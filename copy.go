@@ -0,0 +1,255 @@
+package sqltk
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldebug"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// CopyBuilder builds bulk-load statements, which read rows from a file or the client far
+// faster than INSERT: COPY ... FROM on Postgres and DuckDB, and LOAD DATA LOCAL INFILE on MySQL.
+type CopyBuilder struct {
+	table     string
+	columns   []string
+	source    string // file to read, or "" for STDIN
+	csv       bool
+	header    bool
+	delimiter string
+	null      string
+	hasNull   bool
+	err       error
+	dialect   sqldialect.Dialect // per-builder dialect, if set
+}
+
+// Copy creates a new CopyBuilder loading rows into the given table.
+//
+// Example usage:
+//
+//	sqltk.Copy("events").Columns("id", "name").CSV().Header().WithDialect(sqldialect.Postgres())
+//	// COPY "events" ("id", "name") FROM STDIN WITH (FORMAT csv, HEADER)
+//
+//	sqltk.Copy("events").Columns("id", "name").From("/tmp/events.csv").CSV().WithDialect(sqldialect.MySQL())
+//	// LOAD DATA LOCAL INFILE '/tmp/events.csv' INTO TABLE `events`
+//	//   FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' (`id`, `name`)
+func Copy(table string) *CopyBuilder {
+	b := &CopyBuilder{table: table}
+	if table == "" {
+		b.err = errors.New("Copy: table must be set")
+	}
+	return b
+}
+
+// Columns sets the columns of the loaded rows, in the order of the fields of the data. Without
+// columns, the fields are loaded into all columns of the table.
+func (b *CopyBuilder) Columns(cols ...string) *CopyBuilder {
+	b.columns = append([]string{}, cols...)
+	return b
+}
+
+// From reads the rows from a file instead of STDIN. MySQL reads the file on the client, and
+// requires one; with the go-sql-driver/mysql driver, "Reader::name" reads from the io.Reader
+// registered with mysql.RegisterReaderHandler. Postgres and DuckDB read it on the server.
+func (b *CopyBuilder) From(path string) *CopyBuilder {
+	if path == "" {
+		b.err = errors.New("From: path must not be empty")
+		return b
+	}
+	b.source = path
+	return b
+}
+
+// CSV reads the data as CSV, with fields separated by commas and optionally quoted. The
+// default is the text format of the database: tab-separated fields, with NULL as \N.
+func (b *CopyBuilder) CSV() *CopyBuilder {
+	b.csv = true
+	return b
+}
+
+// Header skips the first line of the data, which holds the column names. It requires CSV.
+func (b *CopyBuilder) Header() *CopyBuilder {
+	b.header = true
+	return b
+}
+
+// Delimiter sets the character that separates fields.
+func (b *CopyBuilder) Delimiter(delimiter string) *CopyBuilder {
+	if len(delimiter) != 1 {
+		b.err = errors.New("Delimiter: must be a single character")
+		return b
+	}
+	b.delimiter = delimiter
+	return b
+}
+
+// Null sets the string that stands for NULL, such as "" for empty CSV fields (Postgres and
+// DuckDB). MySQL always reads \N as NULL.
+func (b *CopyBuilder) Null(null string) *CopyBuilder {
+	b.null = null
+	b.hasNull = true
+	return b
+}
+
+// WithDialect sets the dialect for this builder instance.
+func (b *CopyBuilder) WithDialect(d sqldialect.Dialect) *CopyBuilder {
+	b.dialect = d
+	return b
+}
+
+// Build builds the bulk-load statement for the dialect. It has no args. It is an error if the
+// dialect has no bulk-load statement.
+func (b *CopyBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if b.header && !b.csv {
+		return "", nil, errors.New("Copy: Header requires CSV")
+	}
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+
+	var sql string
+	var err error
+	switch baseDialect(dialect) {
+	case sqldialect.MySQL():
+		sql, err = b.buildLoadData(dialect)
+	case sqldialect.Postgres(), sqldialect.DuckDB():
+		sql, err = b.buildCopy(dialect)
+	default:
+		err = errors.New("Copy: the dialect has no bulk-load statement")
+	}
+	if err == nil {
+		err = sqldialect.CheckIdents(dialect, sql)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, nil, nil
+}
+
+// buildCopy builds COPY ... FROM for Postgres and DuckDB.
+func (b *CopyBuilder) buildCopy(dialect sqldialect.Dialect) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("COPY ")
+	sb.WriteString(quoteQualifiedIdent(dialect, b.table))
+	b.writeColumns(&sb, dialect)
+	sb.WriteString(" FROM ")
+	if b.source == "" {
+		if baseDialect(dialect) == sqldialect.DuckDB() {
+			return "", errors.New("Copy: DuckDB requires From")
+		}
+		sb.WriteString("STDIN")
+	} else {
+		sb.WriteString(dialect.QuoteString(b.source))
+	}
+
+	var options []string
+	if b.csv {
+		options = append(options, "FORMAT csv")
+	}
+	if b.delimiter != "" {
+		options = append(options, "DELIMITER "+dialect.QuoteString(b.delimiter))
+	}
+	if b.hasNull {
+		options = append(options, "NULL "+dialect.QuoteString(b.null))
+	}
+	if b.header {
+		options = append(options, "HEADER")
+	}
+	if len(options) > 0 {
+		if baseDialect(dialect) == sqldialect.Postgres() {
+			sb.WriteString(" WITH")
+		}
+		sb.WriteString(" (" + strings.Join(options, ", ") + ")")
+	}
+	return sb.String(), nil
+}
+
+// buildLoadData builds LOAD DATA LOCAL INFILE for MySQL.
+func (b *CopyBuilder) buildLoadData(dialect sqldialect.Dialect) (string, error) {
+	if b.source == "" {
+		return "", errors.New("Copy: MySQL requires From")
+	}
+	if b.hasNull {
+		return "", errors.New("Copy: MySQL does not support Null")
+	}
+	var sb strings.Builder
+	sb.WriteString("LOAD DATA LOCAL INFILE ")
+	sb.WriteString(dialect.QuoteString(b.source))
+	sb.WriteString(" INTO TABLE ")
+	sb.WriteString(quoteQualifiedIdent(dialect, b.table))
+
+	delimiter := b.delimiter
+	if delimiter == "" && b.csv {
+		delimiter = ","
+	}
+	if delimiter != "" || b.csv {
+		sb.WriteString(" FIELDS")
+		if delimiter != "" {
+			sb.WriteString(" TERMINATED BY " + dialect.QuoteString(delimiter))
+		}
+		if b.csv {
+			sb.WriteString(" OPTIONALLY ENCLOSED BY " + dialect.QuoteString(`"`))
+		}
+	}
+	if b.header {
+		sb.WriteString(" IGNORE 1 LINES")
+	}
+	b.writeColumns(&sb, dialect)
+	return sb.String(), nil
+}
+
+// writeColumns writes the parenthesized column list, if any.
+func (b *CopyBuilder) writeColumns(sb *strings.Builder, dialect sqldialect.Dialect) {
+	if len(b.columns) == 0 {
+		return
+	}
+	sb.WriteString(" (")
+	for i, col := range b.columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(dialect.QuoteIdent(col))
+	}
+	sb.WriteString(")")
+}
+
+// BuildCopyIn builds the COPY ... FROM STDIN statement that the lib/pq driver runs as a
+// prepared statement taking one row per Exec, as pq.CopyIn does, for Runner.CopyIn. The driver
+// sends the rows in the text format, so it is an error to set From, CSV, Header, Delimiter or
+// Null, or to use a dialect other than Postgres.
+func (b *CopyBuilder) BuildCopyIn() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.source != "" || b.csv || b.header || b.delimiter != "" || b.hasNull {
+		return "", errors.New("BuildCopyIn: the rows are sent from the client in the text format")
+	}
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	if baseDialect(dialect) != sqldialect.Postgres() {
+		return "", errors.New("BuildCopyIn: requires the Postgres dialect")
+	}
+	sql, _, err := b.Build()
+	return sql, err
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
+// DO NOT use the result for execution (not safe against SQL injection).
+func (b *CopyBuilder) DebugSQL() string {
+	sql, args, _ := b.Build()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CopyBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
+}
@@ -0,0 +1,111 @@
+package sqltk
+
+import (
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCopyBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *CopyBuilder
+		dialect sqldialect.Dialect
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "postgres stdin",
+			builder: Copy("events").Columns("id", "name"),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `COPY "events" ("id", "name") FROM STDIN`,
+		},
+		{
+			name:    "postgres csv options",
+			builder: Copy("events").Columns("id", "name").CSV().Delimiter(";").Null("").Header(),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `COPY "events" ("id", "name") FROM STDIN WITH (FORMAT csv, DELIMITER ';', NULL '', HEADER)`,
+		},
+		{
+			name:    "postgres file",
+			builder: Copy("events").From("/data/it's.csv").CSV(),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `COPY "events" FROM '/data/it''s.csv' WITH (FORMAT csv)`,
+		},
+		{
+			name:    "duckdb file",
+			builder: Copy("events").From("events.csv").CSV().Header(),
+			dialect: sqldialect.DuckDB(),
+			wantSQL: `COPY "events" FROM 'events.csv' (FORMAT csv, HEADER)`,
+		},
+		{
+			name:    "mysql csv",
+			builder: Copy("events").Columns("id", "name").From("Reader::events").CSV().Header(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "LOAD DATA LOCAL INFILE 'Reader::events' INTO TABLE `events` FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' IGNORE 1 LINES (`id`, `name`)",
+		},
+		{
+			name:    "mysql text",
+			builder: Copy("events").From("/tmp/events.tsv"),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "LOAD DATA LOCAL INFILE '/tmp/events.tsv' INTO TABLE `events`",
+		},
+		{
+			name:    "mysql without file",
+			builder: Copy("events"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "mysql null",
+			builder: Copy("events").From("events.csv").Null(""),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "duckdb stdin",
+			builder: Copy("events"),
+			dialect: sqldialect.DuckDB(),
+			wantErr: true,
+		},
+		{
+			name:    "header without csv",
+			builder: Copy("events").Header(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported dialect",
+			builder: Copy("events").From("events.csv"),
+			dialect: sqldialect.BigQuery(),
+			wantErr: true,
+		},
+		{
+			name:    "missing table",
+			builder: Copy(""),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) != 0 {
+				t.Errorf("got args %v, want none", args)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// cte is a common table expression of a WITH clause.
+type cte struct {
+	name string
+	body Builder
+}
+
+// withClause holds the WITH clause of an INSERT, UPDATE, or DELETE, see InsertBuilder.With.
+type withClause struct {
+	ctes []cte
+}
+
+// add adds a common table expression, checking its body.
+func (w *withClause) add(name string, body Builder) error {
+	if name == "" {
+		return errors.New("With: name must not be empty")
+	}
+	switch body.(type) {
+	case *SelectBuilder, *InsertBuilder, *UpdateBuilder, *DeleteBuilder,
+		*PostgresInsertBuilder, *PostgresUpdateBuilder, *PostgresDeleteBuilder:
+	default:
+		return fmt.Errorf("With %s: body must be a SELECT, INSERT, UPDATE, or DELETE builder (got %T)", name, body)
+	}
+	if isNilBuilder(body) {
+		return fmt.Errorf("With %s: body must not be nil", name)
+	}
+	w.ctes = append(w.ctes, cte{name: name, body: body})
+	return nil
+}
+
+// isNilBuilder reports whether b is a nil builder pointer.
+func isNilBuilder(b Builder) bool {
+	switch b := b.(type) {
+	case *SelectBuilder:
+		return b == nil
+	case *InsertBuilder:
+		return b == nil
+	case *UpdateBuilder:
+		return b == nil
+	case *DeleteBuilder:
+		return b == nil
+	case *PostgresInsertBuilder:
+		return b == nil || b.InsertBuilder == nil
+	case *PostgresUpdateBuilder:
+		return b == nil || b.UpdateBuilder == nil
+	case *PostgresDeleteBuilder:
+		return b == nil || b.DeleteBuilder == nil
+	}
+	return false
+}
+
+// build renders the WITH clause with ? placeholders, followed by a space, and the args of the
+// bodies in SQL order.
+func (w withClause) build(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if len(w.ctes) == 0 {
+		return "", nil, nil
+	}
+	var args []interface{}
+	parts := make([]string, len(w.ctes))
+	for i, c := range w.ctes {
+		sql, bodyArgs, err := buildCTEBody(c.body, dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("With %s: %w", c.name, err)
+		}
+		parts[i] = dialect.QuoteIdent(c.name) + " AS (" + sql + ")"
+		args = append(args, bodyArgs...)
+	}
+	return "WITH " + strings.Join(parts, ", ") + " ", args, nil
+}
+
+// buildCTEBody builds the body of a common table expression with ? placeholders, so that its
+// args take their place in the enclosing statement. Data-modifying bodies need Postgres.
+func buildCTEBody(body Builder, dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if q, ok := body.(*SelectBuilder); ok {
+		return buildPositional(q, dialect)
+	}
+	if base := baseDialect(dialect); base != sqldialect.Postgres() && base != sqldialect.NoQuoteIdent() {
+		return "", nil, errors.New("an INSERT, UPDATE, or DELETE in WITH requires Postgres")
+	}
+	bodyDialect := func(d sqldialect.Dialect) sqldialect.Dialect {
+		if d == nil {
+			d = dialect
+		}
+		return positional(d)
+	}
+	switch b := body.(type) {
+	case *InsertBuilder:
+		c := *b
+		c.dialect = bodyDialect(c.dialect)
+		return c.Build()
+	case *UpdateBuilder:
+		c := *b
+		c.dialect = bodyDialect(c.dialect)
+		return c.Build()
+	case *DeleteBuilder:
+		c := *b
+		c.dialect = bodyDialect(c.dialect)
+		return c.Build()
+	case *PostgresInsertBuilder:
+		core := *b.InsertBuilder
+		core.dialect = bodyDialect(core.dialect)
+		c := *b
+		c.InsertBuilder = &core
+		return c.Build()
+	case *PostgresUpdateBuilder:
+		core := *b.UpdateBuilder
+		core.dialect = bodyDialect(core.dialect)
+		c := *b
+		c.UpdateBuilder = &core
+		return c.Build()
+	case *PostgresDeleteBuilder:
+		core := *b.DeleteBuilder
+		core.dialect = bodyDialect(core.dialect)
+		c := *b
+		c.DeleteBuilder = &core
+		return c.Build()
+	}
+	return "", nil, fmt.Errorf("unsupported body %T", body)
+}
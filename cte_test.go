@@ -0,0 +1,120 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestDMLWith(t *testing.T) {
+	moved := func() *DeleteBuilder {
+		return Delete("events").WhereLessThan("created_at", "2024-01-01").Returning("*")
+	}
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "insert from a delete",
+			builder: Insert("events_archive").With("moved", moved()).
+				FromSelect(Select().From("moved").Where(NewStringCondition("kind = ?", "click"))),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `WITH "moved" AS (DELETE FROM "events" WHERE created_at < $1 RETURNING *) INSERT INTO "events_archive" SELECT * FROM "moved" WHERE kind = $2`,
+			wantArgs: []interface{}{"2024-01-01", "click"},
+		},
+		{
+			name:     "insert select with columns",
+			builder:  Insert("totals").Columns("user_id", "total").FromSelect(Select("user_id", "SUM(amount)").From("orders").GroupBy("user_id")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "INSERT INTO totals (user_id, total) SELECT user_id, SUM(amount) FROM orders GROUP BY user_id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "update with a select",
+			builder: Update("users").
+				With("active", Select("user_id").From("sessions").WhereGreaterThan("seen_at", "2024-06-01")).
+				Set("status", "active").
+				Where(NewStringCondition("id IN (SELECT user_id FROM active)")),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `WITH "active" AS (SELECT "user_id" FROM "sessions" WHERE seen_at > $1) UPDATE "users" SET status = $2 WHERE id IN (SELECT user_id FROM active)`,
+			wantArgs: []interface{}{"2024-06-01", "active"},
+		},
+		{
+			name: "delete with an update",
+			builder: Delete("carts").
+				With("closed", Update("orders").Set("state", "closed").WhereEqual("state", "open").Returning("cart_id")).
+				Where(NewStringCondition("id IN (SELECT cart_id FROM closed)")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "WITH closed AS (UPDATE orders SET state = ? WHERE state = ? RETURNING cart_id) DELETE FROM carts WHERE id IN (SELECT cart_id FROM closed)",
+			wantArgs: []interface{}{"closed", "open"},
+		},
+		{
+			name: "mysql insert select",
+			builder: Insert("events_archive").
+				With("old", Select().From("events").WhereLessThan("created_at", "2024-01-01")).
+				FromSelect(Select().From("old")),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "INSERT INTO `events_archive` WITH `old` AS (SELECT * FROM `events` WHERE created_at < ?) SELECT * FROM `old`",
+			wantArgs: []interface{}{"2024-01-01"},
+		},
+		{
+			name:    "mysql data-modifying body",
+			builder: Update("users").With("moved", moved()).Set("status", "x"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "mysql insert values",
+			builder: Insert("t").Columns("a").Values(1).With("x", Select("a").From("s")),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid body",
+			builder: Delete("t").With("x", Copy("s")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "from select and values",
+			builder: Insert("t").Columns("a").Values(1).FromSelect(Select("a").From("s")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switch b := tt.builder.(type) {
+			case *InsertBuilder:
+				b.WithDialect(tt.dialect)
+			case *UpdateBuilder:
+				b.WithDialect(tt.dialect)
+			case *DeleteBuilder:
+				b.WithDialect(tt.dialect)
+			}
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// ClausePosition is where a custom clause is rendered in a SELECT statement.
+type ClausePosition int
+
+const (
+	// AfterFrom renders after FROM and JOINs, before WHERE (e.g. ClickHouse PREWHERE).
+	AfterFrom ClausePosition = iota
+	// AfterWhere renders after WHERE, before GROUP BY.
+	AfterWhere
+	// AfterGroupBy renders after GROUP BY, before HAVING.
+	AfterGroupBy
+	// AfterHaving renders after HAVING, before ORDER BY (e.g. Snowflake QUALIFY, WINDOW).
+	AfterHaving
+	// AfterOrderBy renders after ORDER BY, before LIMIT.
+	AfterOrderBy
+	// AfterLimit renders after LIMIT and OFFSET, before the locking clause.
+	AfterLimit
+	// End renders at the very end of the statement (e.g. ClickHouse SETTINGS, FORMAT).
+	End
+)
+
+// Clause is a custom clause attached to a SelectBuilder with AddClause. It is the extension
+// point for vendor-specific syntax the builder does not know about.
+//
+// BuildClause returns the clause SQL using ? for each argument; the builder renumbers
+// placeholders for the dialect. Binder arguments are expanded like anywhere else.
+type Clause interface {
+	Position() ClausePosition
+	BuildClause(dialect sqldialect.Dialect) (string, []interface{}, error)
+}
+
+// rawClause is a Clause with fixed SQL.
+type rawClause struct {
+	pos  ClausePosition
+	sql  string
+	args []interface{}
+}
+
+// RawClause returns a Clause that renders sql with args at the given position.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id").From("events").
+//		AddClause(sqltk.RawClause(sqltk.AfterHaving, "QUALIFY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY ts DESC) = ?", 1)).
+//		AddClause(sqltk.RawClause(sqltk.End, "SETTINGS max_threads = 8"))
+func RawClause(pos ClausePosition, sql string, args ...interface{}) Clause {
+	return rawClause{pos: pos, sql: sql, args: args}
+}
+
+func (c rawClause) Position() ClausePosition { return c.pos }
+
+func (c rawClause) BuildClause(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	return c.sql, c.args, nil
+}
+
+// AddClause attaches a custom clause, rendered at the clause's position during Build.
+// Clauses at the same position render in the order they were added.
+func (b *SelectBuilder) AddClause(c Clause) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	if c == nil {
+		b.whereClause.err = errors.New("AddClause: clause must not be nil")
+		return b
+	}
+	if pos := c.Position(); pos < AfterFrom || pos > End {
+		b.whereClause.err = fmt.Errorf("AddClause: invalid clause position %d", pos)
+		return b
+	}
+	b.clauses = append(b.clauses, c)
+	b.recordCall("CLAUSE")
+	return b
+}
+
+// buildClauses writes the custom clauses at pos to sb and appends their arguments to args.
+func (b *SelectBuilder) buildClauses(sb *strings.Builder, dialect sqldialect.Dialect, pos ClausePosition, args []interface{}, tr *buildTracer) ([]interface{}, error) {
+	for _, c := range b.clauses {
+		if c.Position() != pos {
+			continue
+		}
+		clauseSQL, clauseArgs, err := c.BuildClause(baseDialect(dialect))
+		if err != nil {
+			return nil, fmt.Errorf("custom clause: %w", err)
+		}
+		if clauseSQL == "" {
+			continue
+		}
+		if clauseSQL, clauseArgs, err = bindArgs(dialect, clauseSQL, clauseArgs); err != nil {
+			return nil, fmt.Errorf("custom clause: %w", err)
+		}
+		tr.mark("CLAUSE", sb.Len())
+		sb.WriteString(" ")
+		sb.WriteString(clauseSQL)
+		args = append(args, clauseArgs...)
+	}
+	return args, nil
+}
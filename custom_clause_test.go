@@ -0,0 +1,117 @@
+package sqltk
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// settingsClause renders a ClickHouse-style SETTINGS tail.
+type settingsClause map[string]int
+
+func (s settingsClause) Position() ClausePosition { return End }
+
+func (s settingsClause) BuildClause(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if len(s) == 0 {
+		return "", nil, nil
+	}
+	if len(s) > 1 {
+		return "", nil, errors.New("only one setting supported in test")
+	}
+	for k, v := range s {
+		return "SETTINGS " + k + " = " + intToString(v), nil, nil
+	}
+	return "", nil, nil
+}
+
+func TestSelectCustomClauses(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "qualify after having",
+			builder: Select("id").From("events").WhereEqual("kind", "click").
+				AddClause(RawClause(AfterHaving, "QUALIFY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY ts DESC) = ?", 1)).
+				OrderBy("id").Limit(10),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "events" WHERE kind = $1 QUALIFY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY ts DESC) = $2 ORDER BY "id" LIMIT 10`,
+			wantArgs: []interface{}{"click", 1},
+		},
+		{
+			name: "placeholders numbered in statement order",
+			builder: Select("id").From("events").
+				AddClause(RawClause(End, "SETTINGS max_threads = ?", 8)).
+				AddClause(RawClause(AfterFrom, "PREWHERE day = ?", "2024-01-01")).
+				WhereEqual("kind", "click"),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "events" PREWHERE day = $1 WHERE kind = $2 SETTINGS max_threads = $3`,
+			wantArgs: []interface{}{"2024-01-01", "click", 8},
+		},
+		{
+			name:     "custom clause type",
+			builder:  Select("id").From("events").ForUpdate().AddClause(settingsClause{"max_threads": 4}),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM events FOR UPDATE SETTINGS max_threads = 4",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "clause error",
+			builder: Select("id").From("events").AddClause(settingsClause{"a": 1, "b": 2}),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "nil clause",
+			builder: Select("id").From("events").AddClause(nil),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid position",
+			builder: Select("id").From("events").AddClause(RawClause(ClausePosition(99), "X")),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("compound parts", func(t *testing.T) {
+		q := Select("id").From("a").AddClause(RawClause(AfterWhere, "WINDOW w AS (ORDER BY ?)", "x")).
+			Union(Select("id").From("b").WhereEqual("y", 2))
+		sql, _, err := q.WithDialect(sqldialect.Postgres()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(sql, "ORDER BY $1)") || !strings.Contains(sql, "y = $2") {
+			t.Errorf("got SQL %q", sql)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package ddl
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -93,7 +94,7 @@ func (b *AlterTableBuilder) AddColumnWithType(name, typ string) *AlterTableBuild
 	b.operations = append(b.operations, AlterOperation{
 		Type:    AddColumnType,
 		Column:  name,
-		NewType: strings.ToUpper(typ),
+		NewType: typ,
 	})
 	return b
 }
@@ -114,7 +115,8 @@ func (b *AlterTableBuilder) DropColumn(columnName string) *AlterTableBuilder {
 	return b
 }
 
-// RenameColumn renames a column in the table.
+// RenameColumn renames a column in the table. Postgres does not accept a rename together with
+// other operations, so rename columns in an AlterTable of their own there.
 func (b *AlterTableBuilder) RenameColumn(oldName, newName string) *AlterTableBuilder {
 	if b.err != nil {
 		return b
@@ -135,8 +137,9 @@ func (b *AlterTableBuilder) RenameColumn(oldName, newName string) *AlterTableBui
 	return b
 }
 
-// RenameTable renames the table.
-func (b *AlterTableBuilder) RenameTable(newName string) *AlterTableBuilder {
+// RenameTo renames the table to newName. Postgres does not accept a rename together with
+// other operations, so rename the table in an AlterTable of its own there.
+func (b *AlterTableBuilder) RenameTo(newName string) *AlterTableBuilder {
 	if b.err != nil {
 		return b
 	}
@@ -151,7 +154,16 @@ func (b *AlterTableBuilder) RenameTable(newName string) *AlterTableBuilder {
 	return b
 }
 
-// ModifyColumn modifies an existing column.
+// RenameTable renames the table. It is the same as RenameTo.
+func (b *AlterTableBuilder) RenameTable(newName string) *AlterTableBuilder {
+	return b.RenameTo(newName)
+}
+
+// ModifyColumn changes the type, nullability, and default of an existing column to those of
+// cb. MySQL and most dialects write MODIFY COLUMN with the whole definition, which replaces
+// the column: a nullability or default left out of cb is reset. Postgres has no MODIFY and
+// gets ALTER COLUMN ... TYPE, followed by SET or DROP NOT NULL and SET DEFAULT for those
+// set in cb; the others are left as they are.
 func (b *AlterTableBuilder) ModifyColumn(cb *ColumnBuilder) *AlterTableBuilder {
 	if b.err != nil {
 		return b
@@ -339,13 +351,20 @@ func (b *AlterTableBuilder) Build() (string, []interface{}, error) {
 	if dialect == nil {
 		dialect = sqldialect.GetDialect() // Use global dialect instead of defaulting to MySQL
 	}
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() && len(b.operations) > 1 {
+		for _, op := range b.operations {
+			if op.Type == RenameColumnType || op.Type == RenameTableType {
+				return "", nil, fmt.Errorf("operation %s: Postgres cannot combine a rename with other operations", op.Type)
+			}
+		}
+	}
 
 	var sb strings.Builder
 	var args []interface{}
 
 	// ALTER TABLE
 	sb.WriteString("ALTER TABLE ")
-	sb.WriteString(dialect.QuoteIdent(b.tableName))
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.tableName))
 
 	// Operations
 	operationSQLs := make([]string, 0, len(b.operations))
@@ -360,6 +379,9 @@ func (b *AlterTableBuilder) Build() (string, []interface{}, error) {
 	sb.WriteString(" ")
 	sb.WriteString(strings.Join(operationSQLs, ", "))
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
@@ -401,6 +423,9 @@ func (b *AlterTableBuilder) buildOperationSQL(op AlterOperation, dialect sqldial
 			Nullable:  op.Nullable,
 			Default:   op.Default,
 		}
+		if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
+			return alterColumnSQL(col, dialect)
+		}
 		colSQL, err := col.buildSQL(dialect)
 		if err != nil {
 			return "", err
@@ -439,9 +464,40 @@ func (b *AlterTableBuilder) buildOperationSQL(op AlterOperation, dialect sqldial
 	}
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// alterColumnSQL returns the ALTER COLUMN actions that change a column to col on Postgres: its
+// type, then its nullability and default if they are set.
+func alterColumnSQL(col ColumnDef, dialect sqldialect.Dialect) (string, error) {
+	if col.Name == "" {
+		return "", errors.New("column name is required")
+	}
+	if col.Type == "" {
+		return "", errors.New("column type is required")
+	}
+	alter := "ALTER COLUMN " + dialect.QuoteIdent(col.Name) + " "
+	actions := []string{alter + "TYPE " + col.typeSQL(dialect)}
+	if col.Nullable != nil {
+		if *col.Nullable {
+			actions = append(actions, alter+"DROP NOT NULL")
+		} else {
+			actions = append(actions, alter+"SET NOT NULL")
+		}
+	}
+	if col.Default != nil {
+		actions = append(actions, alter+"SET DEFAULT "+formatDefaultValue(col.Default, dialect))
+	}
+	return strings.Join(actions, ", "), nil
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *AlterTableBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *AlterTableBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
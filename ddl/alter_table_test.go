@@ -203,7 +203,7 @@ func TestAlterTableBuilder(t *testing.T) {
 			ModifyColumn(Column("age").Type("BIGINT").NotNull()).
 			WithDialect(sqldialect.Postgres()).Build()
 		// Postgres uses ALTER COLUMN ... TYPE ...
-		wantSQL := "ALTER TABLE \"users\" MODIFY COLUMN \"age\" BIGINT NOT NULL"
+		wantSQL := "ALTER TABLE \"users\" ALTER COLUMN \"age\" TYPE BIGINT, ALTER COLUMN \"age\" SET NOT NULL"
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -212,6 +212,70 @@ func TestAlterTableBuilder(t *testing.T) {
 		}
 	})
 
+	t.Run("modify column with default and null (postgres)", func(t *testing.T) {
+		sql, _, err := AlterTable("users").
+			ModifyColumn(Column("status").Type("VARCHAR").Size(20).Nullable().Default("new")).
+			DropColumn("legacy").
+			WithDialect(sqldialect.Postgres()).Build()
+		wantSQL := "ALTER TABLE \"users\" ALTER COLUMN \"status\" TYPE VARCHAR(20), ALTER COLUMN \"status\" DROP NOT NULL, ALTER COLUMN \"status\" SET DEFAULT 'new', DROP COLUMN \"legacy\""
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("modify column with default (mysql)", func(t *testing.T) {
+		sql, _, err := AlterTable("users").
+			ModifyColumn(Column("status").Type("VARCHAR").Size(20).NotNull().Default("new")).
+			WithDialect(sqldialect.MySQL()).Build()
+		wantSQL := "ALTER TABLE `users` MODIFY COLUMN `status` VARCHAR(20) NOT NULL DEFAULT 'new'"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("rename to", func(t *testing.T) {
+		sql, _, err := AlterTable("users").RenameTo("accounts").
+			WithDialect(sqldialect.Postgres()).Build()
+		wantSQL := "ALTER TABLE \"users\" RENAME TO \"accounts\""
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("renames with other operations (mysql)", func(t *testing.T) {
+		sql, _, err := AlterTable("users").
+			RenameColumn("username", "login").
+			DropConstraint("chk_age").
+			RenameTo("accounts").
+			WithDialect(sqldialect.MySQL()).Build()
+		wantSQL := "ALTER TABLE `users` RENAME COLUMN `username` TO `login`, DROP CONSTRAINT `chk_age`, RENAME TO `accounts`"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("renames with other operations (postgres)", func(t *testing.T) {
+		_, _, err := AlterTable("users").
+			RenameColumn("username", "login").
+			DropColumn("legacy").
+			WithDialect(sqldialect.Postgres()).Build()
+		if err == nil {
+			t.Fatal("expected an error for a rename combined with other operations")
+		}
+	})
+
 	t.Run("add constraint (postgres)", func(t *testing.T) {
 		sql, _, err := AlterTable("users").
 			AddConstraint(NewConstraint().Unique("idx_email", "email")).
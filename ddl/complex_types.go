@@ -0,0 +1,65 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// StructField is a named field of a STRUCT column type.
+type StructField struct {
+	Name string
+	Type string
+}
+
+// ArrayType returns the array column type with elements of elemType for the dialect:
+// elemType[] on Postgres and DuckDB, ARRAY<elemType> on BigQuery, and Array(elemType) on
+// ClickHouse.
+//
+// Example usage:
+//
+//	typ, err := ddl.ArrayType(sqldialect.BigQuery(), "STRING") // ARRAY<STRING>
+//	ddl.CreateTable("events").AddColumn(ddl.Column("tags").Type(typ))
+func ArrayType(dialect sqldialect.Dialect, elemType string) (string, error) {
+	if elemType == "" {
+		return "", errors.New("array element type is required")
+	}
+	switch sqldialect.Unwrap(dialect) {
+	case sqldialect.Postgres(), sqldialect.DuckDB():
+		return elemType + "[]", nil
+	case sqldialect.BigQuery():
+		return "ARRAY<" + elemType + ">", nil
+	case sqldialect.ClickHouse():
+		return "Array(" + elemType + ")", nil
+	default:
+		return "", errors.New("array types are not supported by this dialect")
+	}
+}
+
+// StructType returns the struct column type with the given fields for the dialect:
+// STRUCT(name type, ...) on DuckDB, STRUCT<name type, ...> on BigQuery, and the named tuple
+// Tuple(name type, ...) on ClickHouse.
+func StructType(dialect sqldialect.Dialect, fields ...StructField) (string, error) {
+	if len(fields) == 0 {
+		return "", errors.New("struct type requires at least one field")
+	}
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		if f.Name == "" || f.Type == "" {
+			return "", fmt.Errorf("struct field %d: name and type are required", i+1)
+		}
+		parts[i] = dialect.QuoteIdent(f.Name) + " " + f.Type
+	}
+	switch sqldialect.Unwrap(dialect) {
+	case sqldialect.DuckDB():
+		return "STRUCT(" + strings.Join(parts, ", ") + ")", nil
+	case sqldialect.BigQuery():
+		return "STRUCT<" + strings.Join(parts, ", ") + ">", nil
+	case sqldialect.ClickHouse():
+		return "Tuple(" + strings.Join(parts, ", ") + ")", nil
+	default:
+		return "", errors.New("struct types are not supported by this dialect")
+	}
+}
@@ -0,0 +1,78 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestComplexTypes(t *testing.T) {
+	t.Run("array types", func(t *testing.T) {
+		tests := []struct {
+			dialect sqldialect.Dialect
+			want    string
+		}{
+			{sqldialect.Postgres(), "TEXT[]"},
+			{sqldialect.DuckDB(), "TEXT[]"},
+			{sqldialect.BigQuery(), "ARRAY<TEXT>"},
+			{sqldialect.ClickHouse(), "Array(TEXT)"},
+		}
+		for _, tt := range tests {
+			got, err := ArrayType(tt.dialect, "TEXT")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		}
+		if _, err := ArrayType(sqldialect.MySQL(), "TEXT"); err == nil {
+			t.Error("expected error for MySQL")
+		}
+	})
+
+	t.Run("struct types", func(t *testing.T) {
+		fields := []StructField{{"city", "STRING"}, {"zip", "INT64"}}
+		got, err := StructType(sqldialect.BigQuery(), fields...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "STRUCT<`city` STRING, `zip` INT64>"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		got, err = StructType(sqldialect.DuckDB(), StructField{"city", "VARCHAR"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := `STRUCT("city" VARCHAR)`; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		got, err = StructType(sqldialect.ClickHouse(), StructField{"city", "String"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "Tuple(`city` String)"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if _, err := StructType(sqldialect.Postgres(), fields...); err == nil {
+			t.Error("expected error for Postgres")
+		}
+		if _, err := StructType(sqldialect.BigQuery()); err == nil {
+			t.Error("expected error for no fields")
+		}
+	})
+
+	t.Run("in create table", func(t *testing.T) {
+		typ, err := ArrayType(sqldialect.BigQuery(), "STRING")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sql, _, err := CreateTable("events").AddColumn(Column("tags").Type(typ)).WithDialect(sqldialect.BigQuery()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "CREATE TABLE `events` (`tags` ARRAY<STRING>)"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+	})
+}
@@ -202,7 +202,7 @@ func TestCreateDatabaseDebugSQL(t *testing.T) {
 	builder.WithDialect(sqldialect.MySQL())
 
 	debugSQL := builder.DebugSQL()
-	expected := "CREATE DATABASE `testdb` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
+	expected := "/* sqltk debug SQL, do not execute */ CREATE DATABASE `testdb` CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci"
 
 	if debugSQL != expected {
 		t.Errorf("expected debug SQL %q, got %q", expected, debugSQL)
@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -115,7 +116,7 @@ func (b *CreateIndexBuilder) Build() (string, []interface{}, error) {
 	}
 	sb.WriteString(dialect.QuoteIdent(b.indexName))
 	sb.WriteString(" ON ")
-	sb.WriteString(dialect.QuoteIdent(b.tableName))
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.tableName))
 
 	// Columns
 	quotedCols := make([]string, len(b.columns))
@@ -126,12 +127,22 @@ func (b *CreateIndexBuilder) Build() (string, []interface{}, error) {
 	sb.WriteString(strings.Join(quotedCols, ", "))
 	sb.WriteString(")")
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *CreateIndexBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CreateIndexBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
@@ -1,6 +1,7 @@
 package ddl
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sprylic/sqltk/sqldialect"
@@ -210,3 +211,14 @@ func TestCreateIndexBuilder_Postgres(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateIndexBuildTo(t *testing.T) {
+	var sb strings.Builder
+	args, err := CreateIndex("idx_users_email", "users").Columns("email").WithDialect(sqldialect.NoQuoteIdent()).BuildTo(&sb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "CREATE INDEX idx_users_email ON users (email)"; sb.String() != want || len(args) != 0 {
+		t.Errorf("got SQL %q and args %v, want %q", sb.String(), args, want)
+	}
+}
@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -103,12 +104,22 @@ func (b *CreateSchemaBuilder) Build() (string, []interface{}, error) {
 	}
 
 	sql := strings.Join(parts, " ")
+	if err := sqldialect.CheckIdents(dialect, sql); err != nil {
+		return "", nil, err
+	}
 	return sql, nil, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *CreateSchemaBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CreateSchemaBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
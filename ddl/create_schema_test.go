@@ -192,7 +192,7 @@ func TestCreateSchemaDebugSQL(t *testing.T) {
 	builder.WithDialect(sqldialect.MySQL())
 
 	debugSQL := builder.DebugSQL()
-	expected := "CREATE SCHEMA IF NOT EXISTS `testschema` AUTHORIZATION `testuser`"
+	expected := "/* sqltk debug SQL, do not execute */ CREATE SCHEMA IF NOT EXISTS `testschema` AUTHORIZATION `testuser`"
 
 	if debugSQL != expected {
 		t.Errorf("expected debug SQL %q, got %q", expected, debugSQL)
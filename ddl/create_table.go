@@ -3,10 +3,10 @@ package ddl
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
-
 	"github.com/sprylic/sqltk/sqldialect"
 	"github.com/sprylic/sqltk/sqlfunc"
 )
@@ -17,6 +17,9 @@ type CreateTableBuilder struct {
 	columns     []ColumnDef
 	constraints []Constraint
 	options     []TableOption // ENGINE, CHARSET, etc. in order
+	engineSpec  string        // ClickHouse table engine, see EngineSpec
+	orderBy     []string      // ClickHouse sorting key, see OrderByClause
+	partitionBy string        // ClickHouse partition key, see PartitionByClause
 	ifNotExists bool
 	temporary   bool
 	err         error
@@ -52,6 +55,9 @@ func Column(name string) *ColumnBuilder {
 
 // BuildDef returns the built ColumnDef and any error.
 func (cb *ColumnBuilder) BuildDef() (ColumnDef, error) {
+	if cb == nil {
+		return ColumnDef{}, errors.New("column builder is nil")
+	}
 	if cb.err != nil {
 		return ColumnDef{}, cb.err
 	}
@@ -134,7 +140,7 @@ func (b *CreateTableBuilder) AddColumnWithType(name, typ string) *CreateTableBui
 	}
 	col := ColumnDef{
 		Name: name,
-		Type: strings.ToUpper(typ),
+		Type: typ,
 	}
 	b.columns = append(b.columns, col)
 	return b
@@ -149,7 +155,7 @@ func (cb *ColumnBuilder) Type(typ string) *ColumnBuilder {
 		cb.err = errors.New("column type is required")
 		return cb
 	}
-	cb.def.Type = strings.ToUpper(typ)
+	cb.def.Type = typ
 	return cb
 }
 
@@ -209,6 +215,12 @@ func (cb *ColumnBuilder) Default(value interface{}) *ColumnBuilder {
 	if cb.err != nil {
 		return cb
 	}
+	if fn, ok := value.(sqlfunc.SqlFunc); ok {
+		if err := fn.Err(); err != nil {
+			cb.err = fmt.Errorf("Default: %w", err)
+			return cb
+		}
+	}
 	cb.def.Default = value
 	return cb
 }
@@ -277,6 +289,10 @@ func (cb *ColumnBuilder) OnUpdate(action interface{}) *ColumnBuilder {
 	case string:
 		actionStr = v
 	case sqlfunc.SqlFunc:
+		if err := v.Err(); err != nil {
+			cb.err = fmt.Errorf("OnUpdate: %w", err)
+			return cb
+		}
 		actionStr = string(v)
 	default:
 		cb.err = fmt.Errorf("OnUpdate action must be string or sqlfunc.SqlFunc, got %T", action)
@@ -311,6 +327,61 @@ func (b *CreateTableBuilder) Engine(engine string) *CreateTableBuilder {
 	return b
 }
 
+// EngineSpec sets the ClickHouse table engine, written as ENGINE = spec before the other
+// table options, e.g. "MergeTree()" or "ReplacingMergeTree(version)".
+//
+// Example usage:
+//
+//	ddl.CreateTable("events").
+//		AddColumn(ddl.Column("ts").Type("DateTime")).
+//		AddColumn(ddl.Column("user_id").Type("UInt64")).
+//		EngineSpec("MergeTree()").
+//		PartitionByClause("toYYYYMM(ts)").
+//		OrderByClause("user_id", "ts").
+//		WithDialect(sqldialect.ClickHouse())
+//	// CREATE TABLE `events` (`ts` DateTime, `user_id` UInt64) ENGINE = MergeTree() ORDER BY (user_id, ts) PARTITION BY toYYYYMM(ts)
+func (b *CreateTableBuilder) EngineSpec(spec string) *CreateTableBuilder {
+	if b.err != nil {
+		return b
+	}
+	if spec == "" {
+		b.err = errors.New("engine spec is required")
+		return b
+	}
+	b.engineSpec = spec
+	return b
+}
+
+// OrderByClause sets the sorting key of a ClickHouse MergeTree table to the expressions, which
+// are written as-is. With no expressions the table is unsorted, as ORDER BY tuple().
+func (b *CreateTableBuilder) OrderByClause(exprs ...string) *CreateTableBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, expr := range exprs {
+		if expr == "" {
+			b.err = errors.New("order by expression must not be empty")
+			return b
+		}
+	}
+	b.orderBy = append([]string{}, exprs...)
+	return b
+}
+
+// PartitionByClause sets the partition key of a ClickHouse MergeTree table to expr, which is
+// written as-is, e.g. "toYYYYMM(created_at)".
+func (b *CreateTableBuilder) PartitionByClause(expr string) *CreateTableBuilder {
+	if b.err != nil {
+		return b
+	}
+	if expr == "" {
+		b.err = errors.New("partition by expression is required")
+		return b
+	}
+	b.partitionBy = expr
+	return b
+}
+
 func (b *CreateTableBuilder) Charset(charset string) *CreateTableBuilder {
 	if b.err != nil {
 		return b
@@ -562,7 +633,7 @@ func (b *CreateTableBuilder) Build() (string, []interface{}, error) {
 	if b.ifNotExists {
 		sb.WriteString("IF NOT EXISTS ")
 	}
-	sb.WriteString(dialect.QuoteIdent(b.tableName))
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.tableName))
 	sb.WriteString(" (")
 
 	// Columns
@@ -629,6 +700,26 @@ func (b *CreateTableBuilder) Build() (string, []interface{}, error) {
 	sb.WriteString(strings.Join(columnSQLs, ", "))
 	sb.WriteString(")")
 
+	// ClickHouse engine clauses, before the other options
+	if b.engineSpec != "" {
+		sb.WriteString(" ENGINE = " + b.engineSpec)
+		if b.orderBy != nil {
+			switch len(b.orderBy) {
+			case 0:
+				sb.WriteString(" ORDER BY tuple()")
+			case 1:
+				sb.WriteString(" ORDER BY " + b.orderBy[0])
+			default:
+				sb.WriteString(" ORDER BY (" + strings.Join(b.orderBy, ", ") + ")")
+			}
+		}
+		if b.partitionBy != "" {
+			sb.WriteString(" PARTITION BY " + b.partitionBy)
+		}
+	} else if b.orderBy != nil || b.partitionBy != "" {
+		return "", nil, errors.New("order by and partition by clauses require EngineSpec")
+	}
+
 	// Table options in order
 	if len(b.options) > 0 {
 		optionSQLs := make([]string, 0, len(b.options))
@@ -648,7 +739,7 @@ func (b *CreateTableBuilder) Build() (string, []interface{}, error) {
 	}
 
 	// For PostgreSQL, generate triggers for OnUpdate columns
-	if dialect == sqldialect.Postgres() {
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
 		triggerSQL := b.buildPostgresTriggers(dialect)
 		if triggerSQL != "" {
 			sb.WriteString(";\n")
@@ -656,6 +747,9 @@ func (b *CreateTableBuilder) Build() (string, []interface{}, error) {
 		}
 	}
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
@@ -689,7 +783,7 @@ CREATE OR REPLACE TRIGGER %s
     FOR EACH ROW
     EXECUTE FUNCTION %s();`,
 				dialect.QuoteIdent(triggerName),
-				dialect.QuoteIdent(b.tableName),
+				sqldialect.QuoteQualified(dialect, b.tableName),
 				dialect.QuoteIdent(triggerFuncName))
 
 			if b.ifNotExists {
@@ -715,11 +809,18 @@ END$$;`,
 	return strings.Join(triggers, "\n")
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *CreateTableBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CreateTableBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
 
 // Column constraint methods that can be chained after convenience methods
@@ -0,0 +1,140 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldebug"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// CreateTableAsBuilder builds CREATE TABLE ... AS SELECT statements.
+type CreateTableAsBuilder struct {
+	tableName   string
+	query       interface{}
+	temporary   bool
+	ifNotExists bool
+	err         error
+	dialect     sqldialect.Dialect
+}
+
+// CreateTableAs creates a new CREATE TABLE ... AS builder that creates tableName from the
+// result of query. query is a Raw or any value implementing Build() (string, []interface{}, error),
+// such as a SelectBuilder; it is built with its own dialect when Build is called.
+//
+// The query's arguments are returned by Build. PostgreSQL does not accept bind parameters in
+// CREATE TABLE AS, so queries for it should not use arguments.
+//
+// Examples:
+//   - CreateTableAs("archive_users", Raw("SELECT * FROM users WHERE deleted = 1"))
+//   - CreateTableAs("archive_users", Select("*").From("users").WhereEqual("deleted", true))
+func CreateTableAs(tableName string, query interface{}) *CreateTableAsBuilder {
+	if tableName == "" {
+		return &CreateTableAsBuilder{err: errors.New("table name is required")}
+	}
+	if query == nil {
+		return &CreateTableAsBuilder{err: errors.New("query is required")}
+	}
+	return &CreateTableAsBuilder{
+		tableName: tableName,
+		query:     query,
+	}
+}
+
+// Temporary creates a TEMPORARY table.
+func (b *CreateTableAsBuilder) Temporary() *CreateTableAsBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.temporary = true
+	return b
+}
+
+// IfNotExists adds IF NOT EXISTS to the CREATE TABLE statement.
+func (b *CreateTableAsBuilder) IfNotExists() *CreateTableAsBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.ifNotExists = true
+	return b
+}
+
+// WithDialect sets the dialect for this builder instance.
+func (b *CreateTableAsBuilder) WithDialect(d sqldialect.Dialect) *CreateTableAsBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.dialect = d
+	return b
+}
+
+// Build builds the SQL CREATE TABLE ... AS query and returns the query string, arguments, and error if any.
+func (b *CreateTableAsBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+
+	var selectSQL string
+	args := []interface{}{}
+	switch v := b.query.(type) {
+	case raw.Raw:
+		selectSQL = string(v)
+	case *raw.Raw:
+		selectSQL = string(*v)
+	case interface {
+		Build() (string, []interface{}, error)
+	}:
+		sql, queryArgs, err := v.Build()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to build table query: %w", err)
+		}
+		selectSQL = sql
+		args = append(args, queryArgs...)
+	default:
+		return "", nil, errors.New("CreateTableAs() expects a builder with Build() or Raw")
+	}
+	if selectSQL == "" {
+		return "", nil, errors.New("query is required")
+	}
+
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if b.temporary {
+		sb.WriteString("TEMPORARY ")
+	}
+	sb.WriteString("TABLE ")
+	if b.ifNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.tableName))
+	sb.WriteString(" AS ")
+	sb.WriteString(selectSQL)
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	return sb.String(), args, nil
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
+// DO NOT use the result for execution (not safe against SQL injection).
+func (b *CreateTableAsBuilder) DebugSQL() string {
+	sql, args, _ := b.Build()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CreateTableAsBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
+}
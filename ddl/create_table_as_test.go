@@ -0,0 +1,90 @@
+package ddl
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCreateTableAsBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *CreateTableAsBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "raw query",
+			builder:  CreateTableAs("archive_users", raw.Raw("SELECT * FROM users WHERE deleted = 1")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "CREATE TABLE archive_users AS SELECT * FROM users WHERE deleted = 1",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "temporary if not exists",
+			builder: CreateTableAs("report_snapshot", raw.Raw("SELECT id FROM orders")).
+				Temporary().IfNotExists(),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `CREATE TEMPORARY TABLE IF NOT EXISTS "report_snapshot" AS SELECT id FROM orders`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "builder with args",
+			builder: CreateTableAs("archive_users",
+				&mockSelectBuilder{sql: "SELECT * FROM users WHERE deleted = ?", args: []interface{}{true}}),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "CREATE TABLE `archive_users` AS SELECT * FROM users WHERE deleted = ?",
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:    "empty table name",
+			builder: CreateTableAs("", raw.Raw("SELECT 1")),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "nil query",
+			builder: CreateTableAs("archive_users", nil),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported query type",
+			builder: CreateTableAs("archive_users", "SELECT 1"),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "query error",
+			builder: CreateTableAs("archive_users", &mockSelectBuilder{err: errors.New("boom")}),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
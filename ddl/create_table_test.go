@@ -737,6 +737,97 @@ func TestCreateTableBuilder_Postgres(t *testing.T) {
 	})
 }
 
+func TestCreateTableBuilder_Oracle(t *testing.T) {
+	t.Run("create table with auto increment (oracle)", func(t *testing.T) {
+		q := CreateTable("users").
+			AddColumn(Column("id").Type("NUMBER").Precision(19, 0).NotNull().AutoIncrement().PrimaryKey()).
+			AddColumn(Column("status").Type("VARCHAR2").Size(20).NotNull().Default("active"))
+
+		sql, args, err := q.WithDialect(sqldialect.Oracle()).Build()
+		wantSQL := "CREATE TABLE \"users\" (\"id\" NUMBER(19,0) GENERATED BY DEFAULT AS IDENTITY NOT NULL, \"status\" VARCHAR2(20) DEFAULT 'active' NOT NULL, PRIMARY KEY (\"id\"))"
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if len(args) != 0 {
+			t.Errorf("got args %v, want none", args)
+		}
+	})
+
+	t.Run("auto increment with default (oracle)", func(t *testing.T) {
+		q := CreateTable("users").
+			AddColumn(Column("id").Type("NUMBER").AutoIncrement().Default(1))
+
+		if _, _, err := q.WithDialect(sqldialect.Oracle()).Build(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestCreateTableBuilder_ClickHouse(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *CreateTableBuilder
+		wantSQL string
+	}{
+		{
+			name: "merge tree",
+			builder: CreateTable("events").
+				AddColumn(Column("ts").Type("DateTime")).
+				AddColumn(Column("user_id").Type("UInt64")).
+				AddColumn(Column("kind").Type("LowCardinality(String)").Default("view")).
+				EngineSpec("MergeTree()").
+				PartitionByClause("toYYYYMM(ts)").
+				OrderByClause("user_id", "ts").
+				Comment("raw events"),
+			wantSQL: "CREATE TABLE `events` (`ts` DateTime, `user_id` UInt64, `kind` LowCardinality(String) DEFAULT 'view') ENGINE = MergeTree() ORDER BY (user_id, ts) PARTITION BY toYYYYMM(ts) COMMENT 'raw events'",
+		},
+		{
+			name: "single sorting key",
+			builder: CreateTable("users").IfNotExists().
+				AddColumn(Column("id").Type("UInt64")).
+				EngineSpec("ReplacingMergeTree").
+				OrderByClause("id"),
+			wantSQL: "CREATE TABLE IF NOT EXISTS `users` (`id` UInt64) ENGINE = ReplacingMergeTree ORDER BY id",
+		},
+		{
+			name: "unsorted",
+			builder: CreateTable("log").
+				AddColumn(Column("line").Type("String")).
+				EngineSpec("MergeTree").
+				OrderByClause(),
+			wantSQL: "CREATE TABLE `log` (`line` String) ENGINE = MergeTree ORDER BY tuple()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.WithDialect(sqldialect.ClickHouse()).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for name, b := range map[string]*CreateTableBuilder{
+			"order by without engine": CreateTable("t").AddColumn(Column("a").Type("UInt8")).OrderByClause("a"),
+			"empty engine":            CreateTable("t").AddColumn(Column("a").Type("UInt8")).EngineSpec(""),
+			"empty partition":         CreateTable("t").AddColumn(Column("a").Type("UInt8")).EngineSpec("MergeTree").PartitionByClause(""),
+			"auto increment":          CreateTable("t").AddColumn(Column("a").Type("UInt64").AutoIncrement()).EngineSpec("MergeTree"),
+		} {
+			if _, _, err := b.WithDialect(sqldialect.ClickHouse()).Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
 func TestCreateTable_OnUpdateOnDeleteWithSqlFunc(t *testing.T) {
 	t.Run("on update and on delete with string actions", func(t *testing.T) {
 		q := CreateTable("orders").
@@ -936,3 +1027,36 @@ func TestCreateTable_OnUpdate_IfNotExists(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateTable_StrictDialect(t *testing.T) {
+	strict := sqldialect.Strict(sqldialect.Postgres())
+
+	sql, _, err := CreateTable("users").AddColumn(Column("id").Type("SERIAL")).WithDialect(strict).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `CREATE TABLE "users" ("id" SERIAL)`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+
+	_, _, err = CreateTable("users").AddColumn(Column("id\n); DROP TABLE users").Type("INT")).WithDialect(strict).Build()
+	if err == nil || !strings.Contains(err.Error(), "invalid identifier") {
+		t.Errorf("got error %v, want an invalid identifier", err)
+	}
+	if _, _, err := DropTable(`users"`).WithDialect(strict).Build(); err == nil {
+		t.Error("expected error for an invalid identifier")
+	}
+}
+
+func TestCreateTable_QualifiedName(t *testing.T) {
+	sql, _, err := CreateTable(`analytics."daily.events"`).
+		AddColumn(Column("user_id").Type("INT")).
+		AddForeignKey(ForeignKey("fk_user", "user_id").References("crm.users", "id")).
+		WithDialect(sqldialect.Postgres()).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `CREATE TABLE "analytics"."daily.events" ("user_id" INT, CONSTRAINT "fk_user" FOREIGN KEY ("user_id") REFERENCES "crm"."users" ("id"))`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+}
@@ -3,6 +3,7 @@ package ddl
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/raw"
@@ -132,16 +133,26 @@ func (b *CreateViewBuilder) Build() (string, []interface{}, error) {
 		sb.WriteString("MATERIALIZED ")
 	}
 	sb.WriteString("VIEW ")
-	sb.WriteString(dialect.QuoteIdent(b.viewName))
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.viewName))
 	sb.WriteString(" AS ")
 	sb.WriteString(b.selectSQL)
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *CreateViewBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *CreateViewBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
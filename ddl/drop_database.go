@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -74,17 +75,27 @@ func (b *DropDatabaseBuilder) Build() (string, []interface{}, error) {
 	parts = append(parts, dialect.QuoteIdent(b.name))
 
 	// Add CASCADE for PostgreSQL
-	if b.cascade && dialect == sqldialect.Postgres() {
+	if b.cascade && sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
 		parts = append(parts, "CASCADE")
 	}
 
 	sql := strings.Join(parts, " ")
+	if err := sqldialect.CheckIdents(dialect, sql); err != nil {
+		return "", nil, err
+	}
 	return sql, nil, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *DropDatabaseBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *DropDatabaseBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
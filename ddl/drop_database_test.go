@@ -167,7 +167,7 @@ func TestDropDatabaseDebugSQL(t *testing.T) {
 	builder.WithDialect(sqldialect.Postgres())
 
 	debugSQL := builder.DebugSQL()
-	expected := `DROP DATABASE IF EXISTS "testdb" CASCADE`
+	expected := `/* sqltk debug SQL, do not execute */ DROP DATABASE IF EXISTS "testdb" CASCADE`
 
 	if debugSQL != expected {
 		t.Errorf("expected debug SQL %q, got %q", expected, debugSQL)
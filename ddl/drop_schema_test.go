@@ -202,7 +202,7 @@ func TestDropSchemaDebugSQL(t *testing.T) {
 	builder.WithDialect(sqldialect.Postgres())
 
 	debugSQL := builder.DebugSQL()
-	expected := `DROP SCHEMA IF EXISTS "testschema" CASCADE`
+	expected := `/* sqltk debug SQL, do not execute */ DROP SCHEMA IF EXISTS "testschema" CASCADE`
 
 	if debugSQL != expected {
 		t.Errorf("expected debug SQL %q, got %q", expected, debugSQL)
@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -98,7 +99,7 @@ func (b *DropTableBuilder) Build() (string, []interface{}, error) {
 	// Table names
 	quotedNames := make([]string, len(b.tableNames))
 	for i, name := range b.tableNames {
-		quotedNames[i] = dialect.QuoteIdent(name)
+		quotedNames[i] = sqldialect.QuoteQualified(dialect, name)
 	}
 	sb.WriteString(strings.Join(quotedNames, ", "))
 
@@ -109,12 +110,22 @@ func (b *DropTableBuilder) Build() (string, []interface{}, error) {
 		sb.WriteString(" RESTRICT")
 	}
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *DropTableBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *DropTableBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
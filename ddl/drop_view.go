@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -95,7 +96,7 @@ func (b *DropViewBuilder) Build() (string, []interface{}, error) {
 	if b.ifExists {
 		sb.WriteString("IF EXISTS ")
 	}
-	sb.WriteString(dialect.QuoteIdent(b.viewName))
+	sb.WriteString(sqldialect.QuoteQualified(dialect, b.viewName))
 
 	// CASCADE or RESTRICT
 	if b.cascade {
@@ -104,12 +105,22 @@ func (b *DropViewBuilder) Build() (string, []interface{}, error) {
 		sb.WriteString(" RESTRICT")
 	}
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *DropViewBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *DropViewBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
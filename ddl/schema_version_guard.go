@@ -0,0 +1,214 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldebug"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Statement is implemented by every builder in this package.
+type Statement interface {
+	Build() (string, []interface{}, error)
+}
+
+// SchemaVersionGuardBuilder wraps DDL statements in an advisory lock and a
+// check-and-swap of a schema version, so that concurrent deploys running the
+// same migration script apply it at most once.
+type SchemaVersionGuardBuilder struct {
+	versionTable string
+	fromVersion  int64
+	toVersion    int64
+	lockKey      int64
+	lockKeySet   bool
+	statements   []Statement
+	err          error
+	dialect      sqldialect.Dialect
+}
+
+// SchemaVersionGuard creates a new SchemaVersionGuardBuilder. The wrapped statements only run
+// when the highest version recorded in versionTable equals fromVersion (0 for an empty table);
+// toVersion is then recorded in the same transaction. The version table is expected to have a
+// single integer "version" column.
+func SchemaVersionGuard(versionTable string, fromVersion, toVersion int64) *SchemaVersionGuardBuilder {
+	if versionTable == "" {
+		return &SchemaVersionGuardBuilder{err: errors.New("version table name is required")}
+	}
+	if toVersion <= fromVersion {
+		return &SchemaVersionGuardBuilder{err: errors.New("target version must be greater than the current version")}
+	}
+	return &SchemaVersionGuardBuilder{
+		versionTable: versionTable,
+		fromVersion:  fromVersion,
+		toVersion:    toVersion,
+	}
+}
+
+// LockKey sets the advisory lock key. By default the key is derived from the version table name,
+// so every migration guarded by the same table is serialized.
+func (b *SchemaVersionGuardBuilder) LockKey(key int64) *SchemaVersionGuardBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.lockKey = key
+	b.lockKeySet = true
+	return b
+}
+
+// Add adds statements to run when the version check passes. Statements of this package that
+// have no dialect of their own are built with the dialect of the guard. Other statements,
+// such as sqltk builders, are built as they are, and Build fails if their GetDialect reports
+// another dialect than the guard's. CREATE DATABASE and DROP DATABASE are rejected, as
+// Postgres cannot run them inside a transaction.
+func (b *SchemaVersionGuardBuilder) Add(stmts ...Statement) *SchemaVersionGuardBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, stmt := range stmts {
+		switch stmt.(type) {
+		case nil:
+			b.err = errors.New("statement cannot be nil")
+			return b
+		case *CreateDatabaseBuilder, *DropDatabaseBuilder:
+			b.err = errors.New("CREATE DATABASE and DROP DATABASE cannot run inside a transaction")
+			return b
+		}
+	}
+	b.statements = append(b.statements, stmts...)
+	return b
+}
+
+// WithDialect sets the dialect for this builder instance.
+func (b *SchemaVersionGuardBuilder) WithDialect(d sqldialect.Dialect) *SchemaVersionGuardBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.dialect = d
+	return b
+}
+
+// Build builds the guarded migration and returns the query string, arguments, and error if any.
+// Only the Postgres dialect is supported: the statements run inside a DO block holding a
+// transaction-level advisory lock, which is released even if a statement fails.
+func (b *SchemaVersionGuardBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.statements) == 0 {
+		return "", nil, errors.New("at least one statement is required")
+	}
+
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	if sqldialect.Unwrap(dialect) != sqldialect.Postgres() {
+		return "", nil, errors.New("schema version guard is only supported for the Postgres dialect")
+	}
+
+	lockKey := b.lockKey
+	if !b.lockKeySet {
+		h := fnv.New64a()
+		h.Write([]byte(b.versionTable))
+		lockKey = int64(h.Sum64())
+	}
+
+	var body []string
+	for i, stmt := range b.statements {
+		stmt, ours := defaultDialect(stmt, dialect)
+		if d, ok := stmt.(interface{ GetDialect() sqldialect.Dialect }); ok && !ours && sqldialect.Unwrap(d.GetDialect()) != sqldialect.Unwrap(dialect) {
+			return "", nil, fmt.Errorf("statement %d: built for another dialect than the guard", i+1)
+		}
+		sql, args, err := stmt.Build()
+		if err != nil {
+			return "", nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		if len(args) > 0 {
+			return "", nil, fmt.Errorf("statement %d: arguments are not supported inside a guard", i+1)
+		}
+		body = append(body, "        "+strings.TrimSuffix(strings.TrimSpace(sql), ";")+";")
+	}
+
+	table := sqldialect.QuoteQualified(dialect, b.versionTable)
+	version := dialect.QuoteIdent("version")
+
+	var sb strings.Builder
+	sb.WriteString("DO $sqltk_guard$\n")
+	sb.WriteString("BEGIN\n")
+	sb.WriteString(fmt.Sprintf("    PERFORM pg_advisory_xact_lock(%d);\n", lockKey))
+	sb.WriteString(fmt.Sprintf("    IF (SELECT COALESCE(MAX(%s), 0) FROM %s) = %d THEN\n", version, table, b.fromVersion))
+	sb.WriteString(strings.Join(body, "\n"))
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("        INSERT INTO %s (%s) VALUES (%d);\n", table, version, b.toVersion))
+	sb.WriteString("    END IF;\n")
+	sb.WriteString("END\n")
+	sb.WriteString("$sqltk_guard$")
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	return sb.String(), []interface{}{}, nil
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
+// DO NOT use the result for execution (not safe against SQL injection).
+func (b *SchemaVersionGuardBuilder) DebugSQL() string {
+	sql, args, _ := b.Build()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *SchemaVersionGuardBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
+}
+
+// ddlBuilder is a builder of this package, with B its struct type.
+type ddlBuilder[B any] interface {
+	*B
+	Statement
+	WithDialect(d sqldialect.Dialect) *B
+}
+
+// withDefaultDialect returns b, or a copy of b with dialect d if its own dialect is nil.
+func withDefaultDialect[B any, P ddlBuilder[B]](b P, own, d sqldialect.Dialect) Statement {
+	if own != nil {
+		return b
+	}
+	c := *b
+	return P(P(&c).WithDialect(d))
+}
+
+// defaultDialect returns stmt with dialect d if it is a builder of this package without a
+// dialect of its own, so that a guard builds it with its dialect. It reports whether stmt is
+// a builder of this package.
+func defaultDialect(stmt Statement, d sqldialect.Dialect) (Statement, bool) {
+	switch b := stmt.(type) {
+	case *AlterTableBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *CreateIndexBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *CreateSchemaBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *CreateTableBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *CreateTableAsBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *CreateViewBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *DropSchemaBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *DropTableBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *DropViewBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	case *TruncateTableBuilder:
+		return withDefaultDialect(b, b.dialect, d), true
+	}
+	return stmt, false
+}
@@ -0,0 +1,160 @@
+package ddl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSchemaVersionGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SchemaVersionGuardBuilder
+		expected string
+		wantErr  bool
+	}{
+		{
+			name: "guarded create table",
+			builder: SchemaVersionGuard("schema_version", 41, 42).
+				LockKey(918273).
+				Add(CreateTable("users").AddColumn(Column("id").Type("INTEGER").PrimaryKey()).WithDialect(sqldialect.Postgres())),
+			expected: "DO $sqltk_guard$\n" +
+				"BEGIN\n" +
+				"    PERFORM pg_advisory_xact_lock(918273);\n" +
+				"    IF (SELECT COALESCE(MAX(\"version\"), 0) FROM \"schema_version\") = 41 THEN\n" +
+				"        CREATE TABLE \"users\" (\"id\" INTEGER, PRIMARY KEY (\"id\"));\n" +
+				"        INSERT INTO \"schema_version\" (\"version\") VALUES (42);\n" +
+				"    END IF;\n" +
+				"END\n" +
+				"$sqltk_guard$",
+		},
+		{
+			name: "multiple statements",
+			builder: SchemaVersionGuard("migrations", 0, 1).
+				LockKey(7).
+				Add(CreateSchema("app").WithDialect(sqldialect.Postgres()), DropTable("legacy").WithDialect(sqldialect.Postgres())),
+			expected: "DO $sqltk_guard$\n" +
+				"BEGIN\n" +
+				"    PERFORM pg_advisory_xact_lock(7);\n" +
+				"    IF (SELECT COALESCE(MAX(\"version\"), 0) FROM \"migrations\") = 0 THEN\n" +
+				"        CREATE SCHEMA \"app\";\n" +
+				"        DROP TABLE \"legacy\";\n" +
+				"        INSERT INTO \"migrations\" (\"version\") VALUES (1);\n" +
+				"    END IF;\n" +
+				"END\n" +
+				"$sqltk_guard$",
+		},
+		{
+			name:    "empty version table",
+			builder: SchemaVersionGuard("", 1, 2).Add(DropTable("t")),
+			wantErr: true,
+		},
+		{
+			name:    "target version not greater",
+			builder: SchemaVersionGuard("schema_version", 2, 2).Add(DropTable("t")),
+			wantErr: true,
+		},
+		{
+			name:    "no statements",
+			builder: SchemaVersionGuard("schema_version", 1, 2),
+			wantErr: true,
+		},
+		{
+			name:    "create database",
+			builder: SchemaVersionGuard("schema_version", 1, 2).Add(CreateDatabase("app")),
+			wantErr: true,
+		},
+		{
+			name:    "drop database",
+			builder: SchemaVersionGuard("schema_version", 1, 2).Add(DropTable("t"), DropDatabase("app")),
+			wantErr: true,
+		},
+		{
+			name:    "invalid statement",
+			builder: SchemaVersionGuard("schema_version", 1, 2).Add(CreateTable("")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.builder.WithDialect(sqldialect.Postgres())
+			sql, _, err := tt.builder.Build()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if sql != tt.expected {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", tt.expected, sql)
+			}
+		})
+	}
+
+	t.Run("default lock key is stable per table", func(t *testing.T) {
+		a, _, _ := SchemaVersionGuard("schema_version", 1, 2).Add(DropTable("t")).WithDialect(sqldialect.Postgres()).Build()
+		b, _, _ := SchemaVersionGuard("schema_version", 1, 2).Add(DropTable("t")).WithDialect(sqldialect.Postgres()).Build()
+		if a != b {
+			t.Errorf("expected identical SQL, got:\n%s\nand:\n%s", a, b)
+		}
+	})
+
+	t.Run("statements without a dialect use the guard's", func(t *testing.T) {
+		sqldialect.SetDialect(sqldialect.MySQL())
+		defer sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+		alter := AlterTable("users").AddColumnWithType("nickname", "TEXT")
+		sql, _, err := SchemaVersionGuard("schema_version", 41, 42).
+			LockKey(1).
+			Add(alter).
+			WithDialect(sqldialect.Postgres()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "        ALTER TABLE \"users\" ADD COLUMN \"nickname\" TEXT;\n"
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected SQL to contain %q, got:\n%s", want, sql)
+		}
+		if got, _, _ := alter.Build(); got != "ALTER TABLE `users` ADD COLUMN `nickname` TEXT" {
+			t.Errorf("the statement was changed: %s", got)
+		}
+	})
+
+	t.Run("statement for another dialect", func(t *testing.T) {
+		_, _, err := SchemaVersionGuard("schema_version", 1, 2).
+			Add(dialectStatement{sqldialect.MySQL()}).
+			WithDialect(sqldialect.Postgres()).Build()
+		if err == nil {
+			t.Errorf("expected error for a MySQL statement in a Postgres guard")
+		}
+	})
+
+	t.Run("non-postgres dialect", func(t *testing.T) {
+		_, _, err := SchemaVersionGuard("schema_version", 1, 2).Add(DropTable("t")).WithDialect(sqldialect.MySQL()).Build()
+		if err == nil {
+			t.Errorf("expected error for MySQL dialect")
+		}
+	})
+}
+
+// dialectStatement is a Statement built for a dialect, like the builders of sqltk.
+type dialectStatement struct {
+	dialect sqldialect.Dialect
+}
+
+func (s dialectStatement) Build() (string, []interface{}, error) {
+	return "SELECT 1", nil, nil
+}
+
+func (s dialectStatement) GetDialect() sqldialect.Dialect {
+	return s.dialect
+}
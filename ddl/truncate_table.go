@@ -2,6 +2,7 @@ package ddl
 
 import (
 	"errors"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -35,6 +36,17 @@ func TruncateTable(tableNames ...string) *TruncateTableBuilder {
 	}
 }
 
+// Truncate creates a new TruncateTableBuilder for the given table(s). It is short for
+// TruncateTable.
+//
+// Example usage:
+//
+//	ddl.Truncate("events").RestartIdentity().Cascade().WithDialect(sqldialect.Postgres())
+//	// TRUNCATE TABLE "events" RESTART IDENTITY CASCADE
+func Truncate(tableNames ...string) *TruncateTableBuilder {
+	return TruncateTable(tableNames...)
+}
+
 // Cascade adds CASCADE to the TRUNCATE TABLE statement.
 func (b *TruncateTableBuilder) Cascade() *TruncateTableBuilder {
 	if b.err != nil {
@@ -65,6 +77,12 @@ func (b *TruncateTableBuilder) Restart() *TruncateTableBuilder {
 	return b
 }
 
+// RestartIdentity adds RESTART IDENTITY to the TRUNCATE TABLE statement (PostgreSQL), which
+// resets the sequences of the identity columns. It is the same as Restart.
+func (b *TruncateTableBuilder) RestartIdentity() *TruncateTableBuilder {
+	return b.Restart()
+}
+
 // Continue adds CONTINUE IDENTITY to the TRUNCATE TABLE statement (PostgreSQL).
 func (b *TruncateTableBuilder) Continue() *TruncateTableBuilder {
 	if b.err != nil {
@@ -107,12 +125,12 @@ func (b *TruncateTableBuilder) Build() (string, []interface{}, error) {
 	// Table names
 	quotedNames := make([]string, len(b.tableNames))
 	for i, name := range b.tableNames {
-		quotedNames[i] = dialect.QuoteIdent(name)
+		quotedNames[i] = sqldialect.QuoteQualified(dialect, name)
 	}
 	sb.WriteString(strings.Join(quotedNames, ", "))
 
 	// PostgreSQL-specific options
-	if dialect == sqldialect.Postgres() {
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
 		var options []string
 
 		if b.restart {
@@ -130,8 +148,13 @@ func (b *TruncateTableBuilder) Build() (string, []interface{}, error) {
 		if len(options) > 0 {
 			sb.WriteString(" " + strings.Join(options, " "))
 		}
+	} else if sqldialect.Unwrap(dialect) == sqldialect.MySQL() {
+		// MySQL truncates one table, and has no options; AUTO_INCREMENT is always reset.
+		if len(b.tableNames) > 1 {
+			return "", nil, errors.New("MySQL truncates one table per statement")
+		}
 	} else {
-		// MySQL and Standard dialects
+		// Standard dialects
 		if b.cascade {
 			sb.WriteString(" CASCADE")
 		} else if b.restrict {
@@ -139,12 +162,22 @@ func (b *TruncateTableBuilder) Build() (string, []interface{}, error) {
 		}
 	}
 
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
 	return sb.String(), args, nil
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *TruncateTableBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *TruncateTableBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return buildTo(w, b)
 }
@@ -265,4 +265,33 @@ func TestTruncateTableBuilder(t *testing.T) {
 			t.Errorf("got SQL %q, want %q", sql, wantSQL)
 		}
 	})
+
+	t.Run("truncate restart identity cascade", func(t *testing.T) {
+		sql, _, err := Truncate("events").RestartIdentity().Cascade().WithDialect(sqldialect.Postgres()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantSQL := "TRUNCATE TABLE \"events\" RESTART IDENTITY CASCADE"
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("cascade is left out for mysql", func(t *testing.T) {
+		sql, _, err := Truncate("events").RestartIdentity().Cascade().WithDialect(sqldialect.MySQL()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantSQL := "TRUNCATE TABLE `events`"
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("error: multiple tables (mysql)", func(t *testing.T) {
+		_, _, err := Truncate("events", "logs").WithDialect(sqldialect.MySQL()).Build()
+		if err == nil {
+			t.Error("expected error for multiple tables on mysql")
+		}
+	})
 }
@@ -3,6 +3,7 @@ package ddl
 import (
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/sprylic/sqltk/raw"
@@ -12,7 +13,7 @@ import (
 // ColumnDef represents a column definition in a CREATE TABLE statement.
 type ColumnDef struct {
 	Name          string
-	Type          string
+	Type          string // written in upper case, except on ClickHouse, whose types are case-sensitive
 	Size          *int
 	Precision     *int
 	Scale         *int
@@ -61,6 +62,24 @@ type TableOption struct {
 	Value string
 }
 
+// typeSQL returns the type of the column with its size or precision.
+func (c *ColumnDef) typeSQL(dialect sqldialect.Dialect) string {
+	typeSQL := c.Type
+	if sqldialect.Unwrap(dialect) != sqldialect.ClickHouse() {
+		typeSQL = strings.ToUpper(typeSQL)
+	}
+	if c.Size != nil {
+		typeSQL += fmt.Sprintf("(%d)", *c.Size)
+	} else if c.Precision != nil {
+		if c.Scale != nil {
+			typeSQL += fmt.Sprintf("(%d,%d)", *c.Precision, *c.Scale)
+		} else {
+			typeSQL += fmt.Sprintf("(%d)", *c.Precision)
+		}
+	}
+	return typeSQL
+}
+
 // buildColumnSQL builds the SQL for a column definition.
 func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 	if c.Name == "" {
@@ -72,19 +91,7 @@ func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 
 	var parts []string
 	parts = append(parts, dialect.QuoteIdent(c.Name))
-
-	// Type with size/precision
-	typeSQL := c.Type
-	if c.Size != nil {
-		typeSQL += fmt.Sprintf("(%d)", *c.Size)
-	} else if c.Precision != nil {
-		if c.Scale != nil {
-			typeSQL += fmt.Sprintf("(%d,%d)", *c.Precision, *c.Scale)
-		} else {
-			typeSQL += fmt.Sprintf("(%d)", *c.Precision)
-		}
-	}
-	parts = append(parts, typeSQL)
+	parts = append(parts, c.typeSQL(dialect))
 
 	// Charset
 	if c.Charset != "" {
@@ -96,6 +103,17 @@ func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 		parts = append(parts, "COLLATE", c.Collation)
 	}
 
+	// Default and identity, which Oracle only accepts before NULL and NOT NULL
+	oracle := sqldialect.Unwrap(dialect) == sqldialect.Oracle()
+	if oracle && c.AutoIncrement {
+		if c.Default != nil {
+			return "", errors.New("auto increment column cannot have a default")
+		}
+		parts = append(parts, "GENERATED BY DEFAULT AS IDENTITY")
+	} else if c.Default != nil && oracle {
+		parts = append(parts, "DEFAULT", formatDefaultValue(c.Default, dialect))
+	}
+
 	// Nullable
 	if c.Nullable != nil {
 		if *c.Nullable {
@@ -106,13 +124,13 @@ func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 	}
 
 	// Default
-	if c.Default != nil {
+	if c.Default != nil && !oracle {
 		parts = append(parts, "DEFAULT", formatDefaultValue(c.Default, dialect))
 	}
 
 	// Auto increment
 	if c.AutoIncrement {
-		if dialect == sqldialect.Postgres() {
+		if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
 			// For Postgres, change the type to SERIAL based on the original type
 			parts = parts[:1] // Keep only the quoted column name
 			switch strings.ToUpper(c.Type) {
@@ -126,8 +144,10 @@ func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 			if c.Nullable != nil && !*c.Nullable {
 				parts = append(parts, "NOT NULL")
 			}
-		} else {
-			// For MySQL and Standard, use AUTO_INCREMENT
+		} else if sqldialect.Unwrap(dialect) == sqldialect.ClickHouse() {
+			return "", errors.New("auto increment is not supported by ClickHouse")
+		} else if !oracle {
+			// For MySQL and Standard, use AUTO_INCREMENT; Oracle has an identity column, above
 			parts = append(parts, "AUTO_INCREMENT")
 		}
 	}
@@ -139,7 +159,7 @@ func (c *ColumnDef) buildSQL(dialect sqldialect.Dialect) (string, error) {
 
 	// ON UPDATE handling
 	if c.OnUpdate != "" {
-		if dialect == sqldialect.Postgres() {
+		if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
 			// For PostgreSQL, we'll need to create a trigger to handle ON UPDATE
 			// This will be handled in the CreateTableBuilder to generate the trigger
 			// For now, we don't add ON UPDATE to the column definition for PostgreSQL
@@ -216,7 +236,7 @@ func (c *Constraint) buildSQL(dialect sqldialect.Dialect) (string, error) {
 			parts = append(parts, "("+strings.Join(quotedCols, ", ")+")")
 		}
 		if c.Reference != nil {
-			parts = append(parts, "REFERENCES", dialect.QuoteIdent(c.Reference.Table))
+			parts = append(parts, "REFERENCES", sqldialect.QuoteQualified(dialect, c.Reference.Table))
 			if len(c.Reference.Columns) > 0 {
 				quotedRefCols := make([]string, len(c.Reference.Columns))
 				for i, col := range c.Reference.Columns {
@@ -419,3 +439,17 @@ func (cb *ConstraintBuilder) Build() Constraint {
 func (cb *ConstraintBuilder) BuildPtr() *Constraint {
 	return &cb.constraint
 }
+
+// buildTo builds b and writes its SQL to w, for the BuildTo methods of the builders.
+func buildTo(w io.Writer, b interface {
+	Build() (string, []interface{}, error)
+}) ([]interface{}, error) {
+	sql, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, sql); err != nil {
+		return nil, fmt.Errorf("BuildTo: %w", err)
+	}
+	return args, nil
+}
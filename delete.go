@@ -2,7 +2,9 @@ package sqltk
 
 import (
 	"errors"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/sprylic/sqltk/raw"
 	"github.com/sprylic/sqltk/sqldebug"
@@ -13,7 +15,12 @@ import (
 type DeleteBuilder struct {
 	tableClauseString
 	whereClause
-	dialect sqldialect.Dialect // per-builder dialect, if set
+	dialect     sqldialect.Dialect // per-builder dialect, if set
+	schema      *Schema            // schema to check against, see WithSchema
+	unsupported UnsupportedMode    // see OnUnsupported
+	reuseArgs   bool               // see ReuseArgs
+	returning   []interface{}      // see Returning
+	with        withClause         // see With
 }
 
 // Delete creates a new DeleteBuilder for the given table.
@@ -29,6 +36,37 @@ func (b *DeleteBuilder) Where(cond Condition, args ...interface{}) *DeleteBuilde
 	return b
 }
 
+// OrWhere combines the WHERE conditions added so far with cond using OR.
+func (b *DeleteBuilder) OrWhere(cond Condition) *DeleteBuilder {
+	b.whereClause.OrWhere(cond)
+	return b
+}
+
+// WhereGroup adds the conditions built by fn as a single parenthesized WHERE condition.
+func (b *DeleteBuilder) WhereGroup(fn func(*ConditionBuilder)) *DeleteBuilder {
+	b.whereClause.WhereGroup(b.dialect, fn)
+	return b
+}
+
+// WhereIf adds a WHERE clause only if cond is true.
+func (b *DeleteBuilder) WhereIf(cond bool, condition Condition) *DeleteBuilder {
+	if cond {
+		b.whereClause.Where(condition)
+	}
+	return b
+}
+
+// ApplyIf calls fn with the builder only if cond is true.
+func (b *DeleteBuilder) ApplyIf(cond bool, fn func(*DeleteBuilder) *DeleteBuilder) *DeleteBuilder {
+	if !cond || fn == nil {
+		return b
+	}
+	if next := fn(b); next != nil {
+		return next
+	}
+	return b
+}
+
 // WhereEqual adds a WHERE clause for equality (column = value).
 func (b *DeleteBuilder) WhereEqual(column string, value interface{}) *DeleteBuilder {
 	b.whereClause.WhereEqual(column, value)
@@ -101,6 +139,33 @@ func (b *DeleteBuilder) WhereNotIn(column string, values ...interface{}) *Delete
 	return b
 }
 
+// WhereInSlice adds a WHERE clause for IN condition with one placeholder per element of a typed
+// slice, e.g. []int64 or []string.
+func (b *DeleteBuilder) WhereInSlice(column string, slice interface{}) *DeleteBuilder {
+	b.Where(NewCond().InSlice(column, slice))
+	return b
+}
+
+// WhereNotInSlice adds a WHERE clause for NOT IN condition with one placeholder per element of a typed slice.
+func (b *DeleteBuilder) WhereNotInSlice(column string, slice interface{}) *DeleteBuilder {
+	b.Where(NewCond().NotInSlice(column, slice))
+	return b
+}
+
+// WhereInEnum adds a WHERE clause for IN condition with the requested values, which must all be
+// in allowed. See ConditionBuilder.InEnum.
+func (b *DeleteBuilder) WhereInEnum(column string, allowed []string, requested []string) *DeleteBuilder {
+	b.Where(NewCond().InEnum(column, allowed, requested))
+	return b
+}
+
+// WhereDateRange adds a WHERE clause for the half-open time range of the bounds that are set.
+// See ConditionBuilder.DateRange.
+func (b *DeleteBuilder) WhereDateRange(column string, from, to *time.Time) *DeleteBuilder {
+	b.Where(NewCond().DateRange(column, from, to))
+	return b
+}
+
 // WhereBetween adds a WHERE clause for BETWEEN condition (column BETWEEN min AND max).
 func (b *DeleteBuilder) WhereBetween(column string, min, max interface{}) *DeleteBuilder {
 	b.Where(NewCond().Between(column, min, max))
@@ -131,12 +196,42 @@ func (b *DeleteBuilder) WhereColsEqual(column1, column2 string) *DeleteBuilder {
 	return b
 }
 
+// Returning adds columns to a RETURNING clause, which returns them from the deleted rows.
+// A plain column name is quoted for the dialect; other strings, such as "*", and raw.Raw or
+// sqlfunc.SqlFunc expressions are written as-is. Dialects without RETURNING for DELETE, such as
+// MySQL, are handled as set with OnUnsupported; see sqldialect.SupportsReturning.
+func (b *DeleteBuilder) Returning(cols ...interface{}) *DeleteBuilder {
+	if err := checkReturning(cols); err != nil {
+		b.whereClause.addErr(err)
+		return b
+	}
+	b.returning = append(b.returning, cols...)
+	return b
+}
+
+// With adds a common table expression named name, which the statement reads like a table. See
+// InsertBuilder.With.
+func (b *DeleteBuilder) With(name string, body Builder) *DeleteBuilder {
+	if err := b.with.add(name, body); err != nil {
+		b.whereClause.addErr(err)
+	}
+	return b
+}
+
 // WithDialect sets the dialect for this builder instance.
 func (b *DeleteBuilder) WithDialect(d sqldialect.Dialect) *DeleteBuilder {
 	b.dialect = d
 	return b
 }
 
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (b *DeleteBuilder) GetDialect() sqldialect.Dialect {
+	if b.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return b.dialect
+}
+
 // Build builds the SQL DELETE query and returns the query string, arguments, and error if any.
 func (b *DeleteBuilder) Build() (string, []interface{}, error) {
 	if b.tableClauseString.err != nil {
@@ -148,27 +243,55 @@ func (b *DeleteBuilder) Build() (string, []interface{}, error) {
 	if b.tableClauseString.table == "" {
 		return "", nil, errors.New("Delete: table must be set")
 	}
+	if b.schema != nil {
+		if _, err := b.schema.checkTable(b.tableClauseString.table); err != nil {
+			return "", nil, err
+		}
+	}
 
 	dialect := b.dialect
 	if dialect == nil {
 		dialect = sqldialect.GetDialect()
 	}
-	placeholderIdx := 1
+	out := dialect
+	dialect = renderDialect(dialect)
 
+	withSQL, withArgs, err := b.with.build(dialect)
+	if err != nil {
+		return "", nil, err
+	}
 	var sb strings.Builder
-	args := []interface{}{}
+	args := append([]interface{}{}, withArgs...)
 
+	sb.WriteString(withSQL)
 	sb.WriteString("DELETE FROM ")
-	sb.WriteString(dialect.QuoteIdent(b.tableClauseString.table))
+	sb.WriteString(quoteQualifiedIdent(dialect, b.tableClauseString.table))
 
-	whereSQL, whereArgs := b.whereClause.buildWhereSQL(dialect, &placeholderIdx)
+	whereSQL, whereArgs, err := b.whereClause.buildWhereSQL(dialect)
+	if err != nil {
+		return "", nil, err
+	}
 	if whereSQL != "" {
 		sb.WriteString(" WHERE ")
 		sb.WriteString(whereSQL)
 		args = append(args, whereArgs...)
 	}
 
-	return sb.String(), args, nil
+	returning, err := returningClause(dialect, b.unsupported, "DELETE", b.returning)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(returning)
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, b.reuseArgs)
+	placeholderIdx := 1
+	return numberPlaceholders(out, sb.String(), &placeholderIdx, numbers), args, nil
 }
 
 // PostgresDeleteBuilder extends DeleteBuilder with RETURNING support for Postgres.
@@ -190,23 +313,30 @@ func (b *PostgresDeleteBuilder) Returning(cols ...string) *PostgresDeleteBuilder
 
 // Build builds the SQL DELETE query with RETURNING (if set) and returns the query string, arguments, and error if any.
 func (b *PostgresDeleteBuilder) Build() (string, []interface{}, error) {
-	sql, args, err := b.DeleteBuilder.Build()
-	if err != nil {
-		return sql, args, err
-	}
-	if len(b.returning) > 0 {
-		sql += " RETURNING " + strings.Join(b.returning, ", ")
-	}
-	return sql, args, nil
+	c := *b.DeleteBuilder
+	c.returning = append(c.returning[:len(c.returning):len(c.returning)], rawColumns(b.returning)...)
+	return c.Build()
+}
+
+// BuildTo builds the statement with RETURNING (if set) and writes its SQL to w, returning the
+// args. See BuildTo.
+func (b *PostgresDeleteBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
 
 // Example usage:
 //   pq := sq.NewPostgresDelete("users").Where("id = ?", 1).Returning("id")
 //   sql, args, err := pq.Build()
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *DeleteBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. See BuildTo.
+func (b *DeleteBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
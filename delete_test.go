@@ -52,7 +52,7 @@ func TestDeleteBuilder(t *testing.T) {
 
 		q := Delete("users").Where(cond)
 		sql, args, err := q.Build()
-		wantSQL := "DELETE FROM users WHERE (active = ?) OR (deleted_at IS NULL) AND created_at < ?"
+		wantSQL := "DELETE FROM users WHERE ((active = ?) OR (deleted_at IS NULL)) AND created_at < ?"
 		wantArgs := []interface{}{false, "2023-01-01"}
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -0,0 +1,126 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// derivedTable is a query used as the FROM table of a query derived from it.
+type derivedTable struct {
+	query *SelectBuilder
+	alias string
+}
+
+// existsExpr is an EXISTS(query) column of a query derived from it.
+type existsExpr struct {
+	query *SelectBuilder
+}
+
+// ToCount returns a new builder counting the rows of this query:
+// SELECT COUNT(*) FROM (query) AS t. ORDER BY, LIMIT, OFFSET, and locking are dropped from
+// the inner query. The inner query is rendered with the dialect of the new builder, which
+// starts out as this builder's dialect.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id", "title").From("posts").WhereEqual("published", true).OrderByDesc("id").Limit(20)
+//	total := q.ToCount() // SELECT COUNT(*) FROM (SELECT ... WHERE published = ?) AS t
+func (b *SelectBuilder) ToCount() *SelectBuilder {
+	inner := *b
+	inner.orderBy = nil
+	inner.limitSet, inner.limit = false, 0
+	inner.offsetSet, inner.offset = false, 0
+	inner.lock, inner.lockOption = "", ""
+	inner.maxExecTime = 0
+
+	return &SelectBuilder{
+		columns:              []interface{}{raw.Raw("COUNT(*)")},
+		tableClauseInterface: tableClauseInterface{table: derivedTable{query: &inner, alias: "t"}},
+		maxExecTime:          b.maxExecTime,
+		dialect:              b.dialect,
+	}
+}
+
+// ToExists returns a new builder checking whether this query returns any rows:
+// SELECT EXISTS(query). ORDER BY is dropped unless LIMIT or OFFSET is set, and locking is dropped.
+// The inner query is rendered with the dialect of the new builder, which starts out as this
+// builder's dialect. On Oracle, which has no boolean values, it is
+// SELECT CASE WHEN EXISTS(query) THEN 1 ELSE 0 END FROM DUAL.
+func (b *SelectBuilder) ToExists() *SelectBuilder {
+	inner := *b
+	if !inner.limitSet && !inner.offsetSet {
+		inner.orderBy = nil
+	}
+	inner.lock, inner.lockOption = "", ""
+	inner.maxExecTime = 0
+
+	return &SelectBuilder{
+		columns:     []interface{}{existsExpr{query: &inner}},
+		maxExecTime: b.maxExecTime,
+		dialect:     b.dialect,
+	}
+}
+
+// AsExists returns the condition EXISTS (query), for use in Where, Having, or a ConditionBuilder.
+// The query is rendered with its own dialect, and its placeholders are numbered together with
+// the enclosing query.
+//
+// Example usage:
+//
+//	hasOrders := sqltk.Select(raw.Raw("1")).From("orders").Where(raw.Cond("orders.user_id = users.id"))
+//	q := sqltk.Select("id").From("users").Where(hasOrders.AsExists())
+func (b *SelectBuilder) AsExists() Condition {
+	return existsCondition{query: b}
+}
+
+// AsNotExists returns the condition NOT EXISTS (query). See AsExists.
+func (b *SelectBuilder) AsNotExists() Condition {
+	return existsCondition{query: b, not: true}
+}
+
+// existsCondition is the condition returned by AsExists and AsNotExists.
+type existsCondition struct {
+	query *SelectBuilder
+	not   bool
+}
+
+func (c existsCondition) BuildCondition() (string, []interface{}, error) {
+	if c.query == nil {
+		return "", nil, errors.New("exists: subquery must not be nil")
+	}
+	sql, args, err := buildPositional(c.query, c.query.GetDialect())
+	if err != nil {
+		return "", nil, fmt.Errorf("exists subquery error: %w", err)
+	}
+	if c.not {
+		return "NOT EXISTS (" + sql + ")", args, nil
+	}
+	return "EXISTS (" + sql + ")", args, nil
+}
+
+func (c existsCondition) subqueryBuilders() []*SelectBuilder {
+	if c.query == nil {
+		return nil
+	}
+	return []*SelectBuilder{c.query}
+}
+
+// isExistsOnly reports whether columns is the single EXISTS column of ToExists, which needs no FROM.
+func isExistsOnly(columns []interface{}) bool {
+	if len(columns) != 1 {
+		return false
+	}
+	_, ok := columns[0].(existsExpr)
+	return ok
+}
+
+// buildDerived builds a copy of q with the dialect of the enclosing query and ? placeholders,
+// which are numbered with the rest of the enclosing query.
+func buildDerived(q *SelectBuilder, dialect sqldialect.Dialect) (string, []interface{}, error) {
+	inner := *q
+	inner.dialect = positional(dialect)
+	return inner.Build()
+}
@@ -0,0 +1,141 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectDerivations(t *testing.T) {
+	base := func() *SelectBuilder {
+		return Select("id", "title").From("posts").
+			WhereEqual("published", true).
+			OrderByDesc("id").
+			Limit(20).
+			Offset(40)
+	}
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "count strips order, limit and offset",
+			builder:  base().WithDialect(sqldialect.Postgres()).ToCount(),
+			wantSQL:  `SELECT COUNT(*) FROM (SELECT "id", "title" FROM "posts" WHERE published = $1) AS t`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "count of a join",
+			builder:  Select("p.id").From("posts p").Join("users u").On("u.id", "p.user_id").WhereEqual("u.active", 1).ToCount().WithDialect(sqldialect.NoQuoteIdent()),
+			wantSQL:  "SELECT COUNT(*) FROM (SELECT p.id FROM posts p JOIN users u ON u.id = p.user_id WHERE u.active = ?) AS t",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:     "exists keeps limit and offset",
+			builder:  base().WithDialect(sqldialect.Postgres()).ToExists(),
+			wantSQL:  `SELECT EXISTS(SELECT "id", "title" FROM "posts" WHERE published = $1 ORDER BY "id" DESC LIMIT 20 OFFSET 40)`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "exists drops order by",
+			builder:  Select("id").From("posts").WhereEqual("user_id", 7).OrderByAsc("id").ForUpdate().ToExists().WithDialect(sqldialect.MySQL()),
+			wantSQL:  "SELECT EXISTS(SELECT `id` FROM `posts` WHERE user_id = ?)",
+			wantArgs: []interface{}{7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("original is unchanged", func(t *testing.T) {
+		q := base().WithDialect(sqldialect.NoQuoteIdent())
+		before, _, _ := q.Build()
+		q.ToCount()
+		q.ToExists()
+		after, _, _ := q.Build()
+		if before != after {
+			t.Errorf("got %q, want %q", after, before)
+		}
+	})
+
+	t.Run("errors propagate", func(t *testing.T) {
+		if _, _, err := Select("id").ToCount().Build(); err == nil {
+			t.Error("expected error for count of query without table")
+		}
+		if _, _, err := Select("id").ToExists().Build(); err == nil {
+			t.Error("expected error for exists of query without table")
+		}
+	})
+}
+
+func TestSelectAsExists(t *testing.T) {
+	pg := sqldialect.Postgres()
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "exists with merged postgres placeholders",
+			builder: Select("id").From("users").WhereEqual("active", true).
+				Where(Select(raw.Raw("1")).From("orders").Where(NewStringCondition("orders.user_id = users.id AND orders.total > ?", 100)).WithDialect(pg).AsExists()).
+				WhereEqual("role", "admin").
+				WithDialect(pg),
+			wantSQL:  `SELECT "id" FROM "users" WHERE active = $1 AND EXISTS (SELECT 1 FROM "orders" WHERE orders.user_id = users.id AND orders.total > $2) AND role = $3`,
+			wantArgs: []interface{}{true, 100, "admin"},
+		},
+		{
+			name: "not exists",
+			builder: Select("id").From("users").
+				Where(Select(raw.Raw("1")).From("bans").Where(NewStringCondition("bans.user_id = users.id")).WithDialect(sqldialect.MySQL()).AsNotExists()).
+				WithDialect(sqldialect.MySQL()),
+			wantSQL:  "SELECT `id` FROM `users` WHERE NOT EXISTS (SELECT 1 FROM `bans` WHERE bans.user_id = users.id)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "subquery error",
+			builder: Select("id").From("users").Where(Select(raw.Raw("1")).AsExists()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
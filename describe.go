@@ -0,0 +1,167 @@
+package sqltk
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Describe returns a short English summary of the query, for showing users what a saved query
+// or report does, e.g. "select 4 columns from users joined with orders, filtered by 3
+// conditions, ordered by created_at desc, limit 20". Conditions are counted, not described,
+// and the query is not checked; use Build for that.
+func (b *SelectBuilder) Describe() string {
+	var parts []string
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	lx := sqllex.For(dialect)
+
+	head := "select "
+	if b.distinct {
+		head += "distinct "
+	}
+	switch {
+	case len(b.columns) == 0 || len(b.columns) == 1 && b.columns[0] == "*":
+		head += "all columns"
+	default:
+		head += plural(len(b.columns), "column")
+	}
+	var from []string
+	if b.tableClauseInterface.table != nil {
+		from = append(from, describeTable(b.tableClauseInterface.table))
+	}
+	for _, t := range b.fromTables {
+		from = append(from, describeTable(t))
+	}
+	if len(from) > 0 {
+		head += " from " + joinWords(from)
+	}
+	if len(b.joins) > 0 {
+		joined := make([]string, len(b.joins))
+		for i, j := range b.joins {
+			joined[i] = describeTable(j.table)
+		}
+		head += " joined with " + joinWords(joined)
+	}
+	parts = append(parts, head)
+
+	conds := len(b.whereClause.whereRaw) + len(b.filterJoins)
+	for _, cond := range b.whereClause.whereParam {
+		conds += countConditions(lx, cond)
+	}
+	if b.seek != nil {
+		conds++
+	}
+	if conds > 0 {
+		parts = append(parts, "filtered by "+plural(conds, "condition"))
+	}
+
+	if len(b.groupBy) > 0 || len(b.groupByRaw) > 0 {
+		groups := append(append([]string(nil), b.groupBy...), b.groupByRaw...)
+		parts = append(parts, "grouped by "+strings.Join(groups, ", "))
+	}
+	havings := 0
+	for _, cond := range append(append([]string(nil), b.havingParam...), b.havingRaw...) {
+		havings += countConditions(lx, cond)
+	}
+	if havings > 0 {
+		parts = append(parts, "having "+plural(havings, "condition"))
+	}
+
+	if len(b.orderBy) > 0 {
+		terms := make([]string, len(b.orderBy))
+		for i, t := range b.orderBy {
+			term := t.expr
+			if t.binder != nil {
+				term = "an expression"
+			}
+			if t.suffix != "" {
+				term += " " + strings.ToLower(t.suffix)
+			}
+			switch t.nulls {
+			case NullsFirst:
+				term += " nulls first"
+			case NullsLast:
+				term += " nulls last"
+			}
+			terms[i] = term
+		}
+		parts = append(parts, "ordered by "+strings.Join(terms, ", "))
+	}
+	if b.limitSet {
+		parts = append(parts, "limit "+strconv.Itoa(b.limit))
+	}
+	if b.offsetSet {
+		parts = append(parts, "offset "+strconv.Itoa(b.offset))
+	}
+	if b.lock != "" {
+		parts = append(parts, strings.ToLower(b.lock))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeTable returns the name of a FROM or JOIN table for Describe.
+func describeTable(table interface{}) string {
+	switch t := table.(type) {
+	case string:
+		if fields := strings.Fields(t); len(fields) > 0 {
+			return fields[0]
+		}
+	case Identifier:
+		return t.String()
+	case AliasExpr:
+		switch expr := t.Expr.(type) {
+		case string, Identifier:
+			return describeTable(expr)
+		}
+		return "a subquery"
+	case *SelectBuilder, derivedTable, topNTable:
+		return "a subquery"
+	}
+	return "an expression"
+}
+
+// countConditions counts the conditions ANDed at the top level of a condition, so that a
+// ConditionBuilder of three conditions counts as three. The AND of a BETWEEN is not counted.
+func countConditions(lx sqllex.Lexer, cond string) int {
+	n, depth := 1, 0
+	for _, t := range lx.Tokens(cond) {
+		switch {
+		case t.IsPunct("("):
+			depth++
+		case t.IsPunct(")"):
+			depth--
+		case depth > 0:
+		case t.Is("AND"):
+			n++
+		case t.Is("BETWEEN"):
+			n--
+		}
+	}
+	return n
+}
+
+// isWordChar reports whether c can be part of an SQL keyword or identifier.
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// plural returns n followed by noun, with an s unless n is 1.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+// joinWords joins names with commas and a final "and".
+func joinWords(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
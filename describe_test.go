@@ -0,0 +1,43 @@
+package sqltk
+
+import "testing"
+
+func TestSelectDescribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		want    string
+	}{
+		{
+			name: "report",
+			builder: Select("u.id", "u.name", "o.total", "o.created_at").From("users u").
+				Join("orders o").On("o.user_id", "u.id").
+				Where(NewCond().Equal("u.active", true).Between("o.total", 10, 100).GreaterThan("o.created_at", "2024-01-01")).
+				OrderByDesc("created_at").Limit(20),
+			want: "select 4 columns from users joined with orders, filtered by 3 conditions, ordered by created_at desc, limit 20",
+		},
+		{
+			name:    "all columns",
+			builder: Select().From("users"),
+			want:    "select all columns from users",
+		},
+		{
+			name: "grouped",
+			builder: Select("status", "COUNT(*) AS n").Distinct().From("tickets").WhereEqual("open", true).
+				GroupBy("status").Having(NewStringCondition("COUNT(*) > ?", 1)).Offset(10),
+			want: "select distinct 2 columns from tickets, filtered by 1 condition, grouped by status, having 1 condition, offset 10",
+		},
+		{
+			name:    "subquery and several joins",
+			builder: Select("id").From(Alias(Select("id").From("events"), "e")).Join("a").On("a.id", "e.id").LeftJoin("b").On("b.id", "e.id").OrderBy("LOWER(name)"),
+			want:    "select 1 column from a subquery joined with a and b, ordered by LOWER(name)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.Describe(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,10 @@ package sqltk
 
 import "github.com/sprylic/sqltk/sqldialect"
 
+// SetDialect sets the global dialect of builders that have none, see sqldialect.SetDialect.
+//
+// Deprecated: Create builders with New(dialect), or set the dialect of each builder with
+// WithDialect.
 func SetDialect(dialect sqldialect.Dialect) {
 	sqldialect.SetDialect(dialect)
 }
@@ -0,0 +1,79 @@
+// Package enc provides field-level encryption helpers for sqltk builders.
+//
+// Values and columns are wrapped so that the database encrypts on write and decrypts
+// on read: pgp_sym_encrypt/pgp_sym_decrypt (pgcrypto) on Postgres and
+// AES_ENCRYPT/AES_DECRYPT on other dialects. Key material is never stored in a builder:
+// the key is bound as a KeyRef argument, which runner.Runner replaces with the key
+// configured through runner.WithKey when the statement is executed.
+package enc
+
+import (
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// DefaultKey is the key name used by Encrypt and Decrypt.
+const DefaultKey = "default"
+
+// KeyRef is a bound argument that stands for named key material.
+// It must be resolved before execution, which runner.Runner does automatically.
+type KeyRef struct {
+	Name string
+}
+
+// EncryptedValue is a value that is encrypted by the database when bound.
+type EncryptedValue struct {
+	value interface{}
+	key   string
+}
+
+// Encrypt wraps a value so it is encrypted with the default key, e.g. in Insert Values or Update Set.
+func Encrypt(value interface{}) EncryptedValue {
+	return EncryptWith(DefaultKey, value)
+}
+
+// EncryptWith wraps a value so it is encrypted with the named key.
+func EncryptWith(key string, value interface{}) EncryptedValue {
+	return EncryptedValue{value: value, key: key}
+}
+
+// BindSQL implements sqltk.Binder.
+func (v EncryptedValue) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	args := []interface{}{v.value, KeyRef{Name: v.key}}
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
+		return "pgp_sym_encrypt(?, ?)", args
+	}
+	return "AES_ENCRYPT(?, ?)", args
+}
+
+// DecryptedColumn is a column that is decrypted by the database when selected.
+type DecryptedColumn struct {
+	column string
+	key    string
+}
+
+// Decrypt wraps a column so it is decrypted with the default key when used as a SELECT column.
+// Use sqltk.Alias to name the result.
+func Decrypt(column string) DecryptedColumn {
+	return DecryptWith(DefaultKey, column)
+}
+
+// DecryptWith wraps a column so it is decrypted with the named key.
+func DecryptWith(key, column string) DecryptedColumn {
+	return DecryptedColumn{column: column, key: key}
+}
+
+// BindSQL implements sqltk.Binder.
+func (c DecryptedColumn) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	parts := strings.Split(c.column, ".")
+	for i, part := range parts {
+		parts[i] = dialect.QuoteIdent(strings.TrimSpace(part))
+	}
+	col := strings.Join(parts, ".")
+	args := []interface{}{KeyRef{Name: c.key}}
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
+		return "pgp_sym_decrypt(" + col + ", ?)", args
+	}
+	return "AES_DECRYPT(" + col + ", ?)", args
+}
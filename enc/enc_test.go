@@ -0,0 +1,82 @@
+package enc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  sqldialect.Dialect
+		value    EncryptedValue
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "postgres",
+			dialect:  sqldialect.Postgres(),
+			value:    Encrypt("secret"),
+			wantSQL:  "pgp_sym_encrypt(?, ?)",
+			wantArgs: []interface{}{"secret", KeyRef{Name: DefaultKey}},
+		},
+		{
+			name:     "mysql with named key",
+			dialect:  sqldialect.MySQL(),
+			value:    EncryptWith("pii", "secret"),
+			wantSQL:  "AES_ENCRYPT(?, ?)",
+			wantArgs: []interface{}{"secret", KeyRef{Name: "pii"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.value.BindSQL(tt.dialect)
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  sqldialect.Dialect
+		column   DecryptedColumn
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "postgres",
+			dialect:  sqldialect.Postgres(),
+			column:   Decrypt("users.ssn"),
+			wantSQL:  `pgp_sym_decrypt("users"."ssn", ?)`,
+			wantArgs: []interface{}{KeyRef{Name: DefaultKey}},
+		},
+		{
+			name:     "mysql with named key",
+			dialect:  sqldialect.MySQL(),
+			column:   DecryptWith("pii", "ssn"),
+			wantSQL:  "AES_DECRYPT(`ssn`, ?)",
+			wantArgs: []interface{}{KeyRef{Name: "pii"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.column.BindSQL(tt.dialect)
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// SQLExpr is a SQL expression with bound parameters, created with Expr.
+type SQLExpr struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Expr creates a SQL expression that carries bound parameters, using ? for each of args.
+// Builders number the placeholders for the dialect. An Expr can be used as a SELECT column,
+// in Alias, in OrderBy, OrderByExpr, and GroupBy, as a value wherever a Binder is accepted,
+// and as a condition in Where, for comparisons with a computed left-hand side.
+//
+// Example usage:
+//
+//	sqltk.Select("id", sqltk.Alias(sqltk.Expr("CAST(? AS DECIMAL(10,2))", price), "price"))
+//	sqltk.Select("name").From("users").OrderBy(sqltk.Expr("FIELD(status, ?, ?)", "active", "pending"))
+//	sqltk.Select("sku").From("stock").Where(sqltk.Expr("quantity - reserved >= ?", n))
+func Expr(sql string, args ...interface{}) SQLExpr {
+	return SQLExpr{SQL: sql, Args: args}
+}
+
+// BindSQL implements Binder.
+func (e SQLExpr) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	return e.SQL, e.Args
+}
+
+// BuildCondition implements Condition. It is an error if the expression is empty, and Build
+// returns an error if the number of placeholders and args differ. The placeholders are
+// counted with the dialect of the statement the condition is added to, which decides whether
+// a backslash escapes a quote in the strings of the expression.
+func (e SQLExpr) BuildCondition() (string, []interface{}, error) {
+	if e.SQL == "" {
+		return "", nil, errors.New("Expr: condition must not be empty")
+	}
+	return "?", []interface{}{checkedExpr{SQLExpr: e, op: "Expr"}}, nil
+}
+
+// checkedExpr is an Expr whose placeholders are counted when it is built, with the dialect of
+// the statement, such as an Expr condition or the expression of SetExpr. It is bound in place
+// of a ? like a subqueryArg.
+type checkedExpr struct {
+	SQLExpr
+	op string // the method, for errors
+}
+
+// checkBind implements bindChecker.
+func (c checkedExpr) checkBind(dialect sqldialect.Dialect) error {
+	if n := countPlaceholders(sqllex.For(dialect), c.SQL); n != len(c.Args) {
+		return fmt.Errorf("%s: %q has %d placeholders, got %d args", c.op, c.SQL, n, len(c.Args))
+	}
+	if err := argsErr(c.Args); err != nil {
+		return err
+	}
+	_, _, err := bindArgs(dialect, c.SQL, c.Args)
+	return err
+}
+
+// BindSQL implements Binder.
+func (c checkedExpr) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	sql, args, _ := bindArgs(dialect, c.SQL, c.Args) // checked by checkBind
+	return sql, args
+}
@@ -0,0 +1,126 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  Builder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "cast column with alias",
+			builder: Select("id", Alias(Expr("CAST(? AS DECIMAL(10,2))", "12.5"), "price")).
+				From("products").WhereEqual("active", true),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id", CAST($1 AS DECIMAL(10,2)) AS "price" FROM "products" WHERE active = $2`,
+			wantArgs: []interface{}{"12.5", true},
+		},
+		{
+			name:     "collation column",
+			builder:  Select(Expr("name COLLATE utf8mb4_bin")).From("users"),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT name COLLATE utf8mb4_bin FROM `users`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "group by and order by",
+			builder: Select(Alias(Expr("date_trunc(?, created_at)", "day"), "bucket"), "COUNT(*) AS n").
+				From("events").WhereEqual("kind", "click").
+				GroupBy(Expr("date_trunc(?, created_at)", "day")).
+				OrderByExpr(Expr("CASE WHEN kind = ? THEN 0 ELSE 1 END", "vip"), Asc, NullsDefault),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT date_trunc($1, created_at) AS "bucket", COUNT(*) AS "n" FROM "events" WHERE kind = $2 GROUP BY date_trunc($3, created_at) ORDER BY CASE WHEN kind = $4 THEN 0 ELSE 1 END ASC`,
+			wantArgs: []interface{}{"day", "click", "day", "vip"},
+		},
+		{
+			name:     "order by with nulls emulation repeats args",
+			builder:  Select("id").From("users").OrderByExpr(Expr("FIELD(status, ?, ?)", "a", "b"), Desc, NullsLast),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `id` FROM `users` ORDER BY FIELD(status, ?, ?) IS NULL, FIELD(status, ?, ?) DESC",
+			wantArgs: []interface{}{"a", "b", "a", "b"},
+		},
+		{
+			name: "compound order by",
+			builder: Select("id").From("a").Union(Select("id").From("b")).
+				OrderBy(Expr("id = ? DESC", 7)),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "a" UNION SELECT "id" FROM "b" ORDER BY id = $1 DESC`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "insert value",
+			builder:  Insert("points").Columns("geom").Values(Expr("ST_GeomFromText(?, ?)", "POINT(1 2)", 4326)),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "points" ("geom") VALUES (ST_GeomFromText($1, $2))`,
+			wantArgs: []interface{}{"POINT(1 2)", 4326},
+		},
+		{
+			name: "where condition",
+			builder: Select("sku").From("stock").WhereEqual("warehouse", 3).
+				Where(Expr("quantity - reserved >= ?", 5)).
+				Where(NewCond().Equal("active", true).BitAnd("flags", 4, 4)),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "sku" FROM "stock" WHERE warehouse = $1 AND quantity - reserved >= $2 AND active = $3 AND (flags & $4) = $5`,
+			wantArgs: []interface{}{3, 5, true, 4, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sql string
+			var args []interface{}
+			var err error
+			switch b := tt.builder.(type) {
+			case *SelectBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			case *CompoundBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			case *InsertBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("condition errors", func(t *testing.T) {
+		for name, expr := range map[string]SQLExpr{
+			"empty":         Expr(""),
+			"too many args": Expr("quantity >= ?", 1, 2),
+			"too few args":  Expr("quantity BETWEEN ? AND ?", 1),
+		} {
+			if _, _, err := Select("id").From("t").Where(expr).Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("condition placeholders of the statement dialect", func(t *testing.T) {
+		expr := Expr(`note = 'it\'' AND id = ?`, 1)
+		sql, _, err := Select("id").From("t").Where(expr).WithDialect(sqldialect.MySQL()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "SELECT `id` FROM `t` WHERE note = 'it\\'' AND id = ?"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if _, _, err := Select("id").From("t").Where(expr).WithDialect(sqldialect.Postgres()).Build(); err == nil {
+			t.Error("expected error: the string of the expression hides its placeholder on Postgres")
+		}
+	})
+}
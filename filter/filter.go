@@ -0,0 +1,342 @@
+// Package filter compiles declarative filters, such as those sent by API clients as JSON or
+// in a URL query string, into sqltk conditions. A Spec lists the fields that may be filtered, the column and type
+// of each, and the operators allowed on it; anything else is rejected, so client input
+// never reaches the SQL as anything but bound values.
+//
+//	[{"field": "age", "op": "gte", "value": 18}, {"field": "status", "op": "in", "value": ["new", "open"]}]
+//	?age[gte]=18&status[in]=new,open&sort=-created&limit=20
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Op is a filter operator.
+type Op string
+
+const (
+	Eq      Op = "eq"      // column = value, or IS NULL if value is null
+	Ne      Op = "ne"      // column != value, or IS NOT NULL if value is null
+	Gt      Op = "gt"      // column > value
+	Gte     Op = "gte"     // column >= value
+	Lt      Op = "lt"      // column < value
+	Lte     Op = "lte"     // column <= value
+	Like    Op = "like"    // column LIKE value, for string fields
+	In      Op = "in"      // column IN (values), value is a non-empty list
+	NotIn   Op = "nin"     // column NOT IN (values), value is a non-empty list
+	Between Op = "between" // column BETWEEN min AND max, value is a list of two
+	Null    Op = "null"    // column IS NULL if value is true, IS NOT NULL if false
+)
+
+// Filter is a single filter on a field.
+type Filter struct {
+	Field string      `json:"field"`
+	Op    Op          `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+// field is a filterable field of a Spec.
+type field struct {
+	column string
+	typ    sqltk.ColumnType
+	ops    []Op
+}
+
+// Spec lists the fields filters may use. Values are coerced to the type of their field:
+// numbers and numeric strings to int64 or float64, "true" and "false" to bool, and RFC 3339
+// timestamps or 2006-01-02 dates to time.Time. Fields of type sqltk.AnyType take values as
+// they are.
+//
+// Example usage:
+//
+//	spec := filter.NewSpec().
+//		Allow("age", sqltk.IntType, filter.Eq, filter.Gte, filter.Lte).
+//		Allow("status", sqltk.StringType, filter.Eq, filter.In).
+//		AllowColumn("created", "users.created_at", sqltk.TimeType, filter.Between)
+//	cond, err := spec.CompileJSON(body)
+//	if err != nil {
+//		return err // a 400 for the client
+//	}
+//	q := sqltk.Select("id").From("users").Where(cond)
+type Spec struct {
+	fields       map[string]field
+	sortable     []string // fields Query may sort by, see AllowSort
+	defaultLimit int      // see Limit
+	maxLimit     int
+	dialect      sqldialect.Dialect
+	err          error
+}
+
+// NewSpec creates a Spec that allows no fields.
+func NewSpec() *Spec {
+	return &Spec{fields: make(map[string]field)}
+}
+
+// Allow allows filters on the column named like the field, with the operators ops. With no
+// ops, only Eq is allowed.
+func (s *Spec) Allow(name string, typ sqltk.ColumnType, ops ...Op) *Spec {
+	return s.AllowColumn(name, name, typ, ops...)
+}
+
+// AllowColumn allows filters on a field that maps to column, which may be table-qualified,
+// with the operators ops. With no ops, only Eq is allowed.
+func (s *Spec) AllowColumn(name, column string, typ sqltk.ColumnType, ops ...Op) *Spec {
+	if s.err != nil {
+		return s
+	}
+	if name == "" || column == "" {
+		s.err = fmt.Errorf("filter: field %q: name and column must be set", name)
+		return s
+	}
+	if len(ops) == 0 {
+		ops = []Op{Eq}
+	}
+	for _, op := range ops {
+		switch op {
+		case Eq, Ne, Gt, Gte, Lt, Lte, In, NotIn, Between, Null:
+		case Like:
+			if typ != sqltk.StringType && typ != sqltk.AnyType {
+				s.err = fmt.Errorf("filter: field %q: like requires a string field", name)
+				return s
+			}
+		default:
+			s.err = fmt.Errorf("filter: field %q: unknown operator %q", name, op)
+			return s
+		}
+	}
+	s.fields[name] = field{column: column, typ: typ, ops: slices.Clone(ops)}
+	return s
+}
+
+// WithDialect sets the dialect the columns of compiled conditions are quoted for. The global
+// dialect is used otherwise.
+func (s *Spec) WithDialect(d sqldialect.Dialect) *Spec {
+	s.dialect = d
+	return s
+}
+
+// CompileJSON decodes a JSON array of filters and compiles them, see Compile.
+func (s *Spec) CompileJSON(data []byte) (*sqltk.ConditionBuilder, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var filters []Filter
+	if err := dec.Decode(&filters); err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+	return s.Compile(filters)
+}
+
+// Compile compiles filters into a condition that ANDs them. It is an error if a filter uses
+// a field or operator the Spec does not allow, or a value that does not fit its field. No
+// filters compile to an empty condition, which Where ignores.
+func (s *Spec) Compile(filters []Filter) (*sqltk.ConditionBuilder, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	cond := sqltk.NewCond()
+	if s.dialect != nil {
+		cond.WithDialect(s.dialect)
+	}
+	for i, f := range filters {
+		if err := s.add(cond, f); err != nil {
+			return nil, fmt.Errorf("filter %d: %w", i+1, err)
+		}
+	}
+	if _, _, err := cond.Build(); err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+	return cond, nil
+}
+
+// add adds the condition of a filter to cond.
+func (s *Spec) add(cond *sqltk.ConditionBuilder, f Filter) error {
+	fd, ok := s.fields[f.Field]
+	if !ok {
+		return fmt.Errorf("unknown field %q", f.Field)
+	}
+	if !slices.Contains(fd.ops, f.Op) {
+		return fmt.Errorf("operator %q is not allowed for field %q", f.Op, f.Field)
+	}
+
+	switch f.Op {
+	case Eq, Ne:
+		if f.Value == nil {
+			if f.Op == Eq {
+				cond.IsNull(fd.column)
+			} else {
+				cond.IsNotNull(fd.column)
+			}
+			return nil
+		}
+	case Null:
+		isNull, ok := f.Value.(bool)
+		if !ok {
+			return fmt.Errorf("field %q: null takes true or false", f.Field)
+		}
+		if isNull {
+			cond.IsNull(fd.column)
+		} else {
+			cond.IsNotNull(fd.column)
+		}
+		return nil
+	case In, NotIn, Between:
+		values, err := fd.list(f)
+		if err != nil {
+			return err
+		}
+		switch {
+		case f.Op == Between && len(values) != 2:
+			return fmt.Errorf("field %q: between takes a list of two values", f.Field)
+		case f.Op == Between:
+			cond.Between(fd.column, values[0], values[1])
+		case f.Op == In:
+			cond.In(fd.column, values...)
+		default:
+			cond.NotIn(fd.column, values...)
+		}
+		return nil
+	}
+
+	value, err := coerce(fd.typ, f.Value)
+	if err != nil {
+		return fmt.Errorf("field %q: %w", f.Field, err)
+	}
+	switch f.Op {
+	case Eq:
+		cond.Equal(fd.column, value)
+	case Ne:
+		cond.NotEqual(fd.column, value)
+	case Gt:
+		cond.GreaterThan(fd.column, value)
+	case Gte:
+		cond.GreaterThanOrEqual(fd.column, value)
+	case Lt:
+		cond.LessThan(fd.column, value)
+	case Lte:
+		cond.LessThanOrEqual(fd.column, value)
+	case Like:
+		pattern, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("field %q: like takes a string", f.Field)
+		}
+		cond.Like(fd.column, pattern)
+	}
+	return nil
+}
+
+// list returns the coerced values of a filter whose value is a non-empty list.
+func (fd field) list(f Filter) ([]interface{}, error) {
+	v := reflect.ValueOf(f.Value)
+	if f.Value == nil || v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("field %q: %s takes a list", f.Field, f.Op)
+	}
+	if v.Len() == 0 {
+		return nil, fmt.Errorf("field %q: %s takes a non-empty list", f.Field, f.Op)
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		value, err := coerce(fd.typ, v.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("field %q: value %d: %w", f.Field, i+1, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// coerce converts a filter value to the type of its field.
+func coerce(typ sqltk.ColumnType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, fmt.Errorf("value must not be null")
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			value = i
+		} else if f, err := n.Float64(); err == nil {
+			value = f
+		}
+	}
+
+	switch typ {
+	case sqltk.IntType:
+		switch v := reflect.ValueOf(value); {
+		case v.CanInt():
+			return v.Int(), nil
+		case v.CanUint() && v.Uint() <= math.MaxInt64:
+			return int64(v.Uint()), nil
+		case v.CanFloat() && v.Float() == math.Trunc(v.Float()) && math.Abs(v.Float()) < 1<<63:
+			return int64(v.Float()), nil
+		case v.Kind() == reflect.String:
+			if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not an integer", value)
+	case sqltk.FloatType:
+		switch v := reflect.ValueOf(value); {
+		case v.CanInt():
+			return float64(v.Int()), nil
+		case v.CanUint():
+			return float64(v.Uint()), nil
+		case v.CanFloat():
+			return v.Float(), nil
+		case v.Kind() == reflect.String:
+			if f, err := strconv.ParseFloat(v.String(), 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a number", value)
+	case sqltk.StringType:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("%v is not a string", value)
+	case sqltk.BoolType:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not a boolean", value)
+	case sqltk.TimeType:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+			if t, err := time.Parse(time.DateOnly, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%v is not an RFC 3339 time or a date", value)
+	case sqltk.BytesType:
+		switch v := value.(type) {
+		case []byte:
+			return v, nil
+		case string:
+			return []byte(v), nil
+		}
+		return nil, fmt.Errorf("%v is not a string", value)
+	default:
+		switch value.(type) {
+		case []interface{}, map[string]interface{}:
+			return nil, fmt.Errorf("value must not be a list or object")
+		}
+		return value, nil
+	}
+}
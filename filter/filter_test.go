@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCompile(t *testing.T) {
+	spec := NewSpec().
+		Allow("age", sqltk.IntType, Eq, Gte, Lte, In, Between).
+		Allow("score", sqltk.FloatType, Gt).
+		Allow("status", sqltk.StringType, Eq, Ne, In, NotIn, Like).
+		Allow("active", sqltk.BoolType).
+		Allow("deleted_at", sqltk.TimeType, Null).
+		AllowColumn("created", "u.created_at", sqltk.TimeType, Lt).
+		WithDialect(sqldialect.Postgres())
+
+	tests := []struct {
+		name     string
+		json     string
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "operators and coercion",
+			json:     `[{"field":"age","op":"gte","value":18},{"field":"age","op":"lte","value":"65"},{"field":"score","op":"gt","value":4},{"field":"active","op":"eq","value":"true"}]`,
+			wantSQL:  `"age" >= $1 AND "age" <= $2 AND "score" > $3 AND "active" = $4`,
+			wantArgs: []interface{}{int64(18), int64(65), float64(4), true},
+		},
+		{
+			name:     "lists",
+			json:     `[{"field":"status","op":"in","value":["new","open"]},{"field":"status","op":"nin","value":["spam"]},{"field":"age","op":"between","value":[1,9.0]}]`,
+			wantSQL:  `"status" IN ($1, $2) AND "status" NOT IN ($3) AND "age" BETWEEN $4 AND $5`,
+			wantArgs: []interface{}{"new", "open", "spam", int64(1), int64(9)},
+		},
+		{
+			name:     "nulls, like, and columns",
+			json:     `[{"field":"status","op":"ne","value":null},{"field":"deleted_at","op":"null","value":true},{"field":"status","op":"like","value":"a%"},{"field":"created","op":"lt","value":"2024-03-01"}]`,
+			wantSQL:  `"status" IS NOT NULL AND "deleted_at" IS NULL AND "status" LIKE $1 AND "u"."created_at" < $2`,
+			wantArgs: []interface{}{"a%", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "empty",
+			json:     `[]`,
+			wantSQL:  "",
+			wantArgs: []interface{}{},
+		},
+		{name: "unknown field", json: `[{"field":"password","op":"eq","value":"x"}]`, wantErr: true},
+		{name: "operator not allowed", json: `[{"field":"score","op":"eq","value":1}]`, wantErr: true},
+		{name: "default operator", json: `[{"field":"active","op":"ne","value":true}]`, wantErr: true},
+		{name: "not an integer", json: `[{"field":"age","op":"eq","value":1.5}]`, wantErr: true},
+		{name: "not a string", json: `[{"field":"status","op":"eq","value":3}]`, wantErr: true},
+		{name: "bad time", json: `[{"field":"created","op":"lt","value":"yesterday"}]`, wantErr: true},
+		{name: "empty list", json: `[{"field":"status","op":"in","value":[]}]`, wantErr: true},
+		{name: "list expected", json: `[{"field":"status","op":"in","value":"new"}]`, wantErr: true},
+		{name: "between of three", json: `[{"field":"age","op":"between","value":[1,2,3]}]`, wantErr: true},
+		{name: "null not bool", json: `[{"field":"deleted_at","op":"null","value":"yes"}]`, wantErr: true},
+		{name: "bad json", json: `{"field":"age"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := spec.CompileJSON([]byte(tt.json))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sql, args, err := sqltk.Select("id").From("users").Where(cond).WithDialect(sqldialect.Postgres()).Build()
+			if err != nil {
+				t.Fatalf("unexpected build error: %v", err)
+			}
+			want := `SELECT "id" FROM "users"`
+			if tt.wantSQL != "" {
+				want += " WHERE " + tt.wantSQL
+			}
+			if sql != want {
+				t.Errorf("got SQL %q, want %q", sql, want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *Spec
+	}{
+		{"like on int", NewSpec().Allow("age", sqltk.IntType, Like)},
+		{"unknown operator", NewSpec().Allow("age", sqltk.IntType, "regex")},
+		{"empty column", NewSpec().AllowColumn("age", "", sqltk.IntType)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.spec.Compile(nil); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
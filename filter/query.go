@@ -0,0 +1,185 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sprylic/sqltk"
+)
+
+// Query is a filter compiled from the parameters of a URL query string, see CompileQuery.
+type Query struct {
+	Cond   *sqltk.ConditionBuilder // the filters, ANDed
+	Sort   []Sort                  // the sort order, from the sort parameter
+	Limit  int                     // 0 for no limit
+	Offset int
+}
+
+// Sort is a term of the sort order of a Query.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Apply adds the condition, sort order, limit, and offset of the query to q.
+func (r *Query) Apply(q *sqltk.SelectBuilder) *sqltk.SelectBuilder {
+	q.Where(r.Cond)
+	for _, s := range r.Sort {
+		if s.Desc {
+			q.OrderByDesc(s.Column)
+		} else {
+			q.OrderByAsc(s.Column)
+		}
+	}
+	if r.Limit > 0 {
+		q.Limit(r.Limit)
+	}
+	if r.Offset > 0 {
+		q.Offset(r.Offset)
+	}
+	return q
+}
+
+// AllowSort allows CompileQuery to sort by the fields names, which must be allowed fields.
+func (s *Spec) AllowSort(names ...string) *Spec {
+	if s.err != nil {
+		return s
+	}
+	for _, name := range names {
+		if _, ok := s.fields[name]; !ok {
+			s.err = fmt.Errorf("filter: sort field %q is not an allowed field", name)
+			return s
+		}
+		if !slices.Contains(s.sortable, name) {
+			s.sortable = append(s.sortable, name)
+		}
+	}
+	return s
+}
+
+// Limit sets the limit of CompileQuery when the query string has none, and the largest limit
+// it may ask for. A def of 0 means no limit by default, and a max of 0 no maximum.
+func (s *Spec) Limit(def, max int) *Spec {
+	if s.err != nil {
+		return s
+	}
+	if def < 0 || max < 0 || max > 0 && def > max {
+		s.err = fmt.Errorf("filter: invalid limits %d and %d", def, max)
+		return s
+	}
+	s.defaultLimit, s.maxLimit = def, max
+	return s
+}
+
+// CompileQuery compiles the parameters of a URL query string, such as r.URL.Query():
+//
+//	?age[gte]=18&status[in]=new,open&deleted_at[null]=true&sort=-created,id&limit=20&offset=40
+//
+// A parameter field=value filters with Eq, and field[op]=value with op; the values of in, nin,
+// and between are comma-separated, and null takes true or false. Repeated parameters are
+// ANDed. The sort parameter lists fields allowed with AllowSort, each prefixed with - for
+// descending order, and limit and offset page the rows, see Limit. Any other parameter is an
+// error, as for Compile.
+func (s *Spec) CompileQuery(values url.Values) (*Query, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	r := &Query{Limit: s.defaultLimit}
+	var filters []Filter
+	for _, key := range keys {
+		vals := values[key]
+		switch key {
+		case "sort":
+			for _, v := range vals {
+				if err := s.addSort(r, v); err != nil {
+					return nil, err
+				}
+			}
+		case "limit", "offset":
+			if len(vals) != 1 {
+				return nil, fmt.Errorf("filter: %s must be given once", key)
+			}
+			n, err := strconv.Atoi(vals[0])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("filter: %s must be a non-negative integer", key)
+			}
+			switch {
+			case key == "offset":
+				r.Offset = n
+			case n == 0:
+				return nil, fmt.Errorf("filter: limit must be positive")
+			case s.maxLimit > 0 && n > s.maxLimit:
+				return nil, fmt.Errorf("filter: limit must be at most %d", s.maxLimit)
+			default:
+				r.Limit = n
+			}
+		default:
+			name, op, err := parseQueryKey(key)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range vals {
+				filters = append(filters, Filter{Field: name, Op: op, Value: queryValue(op, v)})
+			}
+		}
+	}
+
+	cond, err := s.Compile(filters)
+	if err != nil {
+		return nil, err
+	}
+	r.Cond = cond
+	return r, nil
+}
+
+// addSort adds the terms of a sort parameter to r.
+func (s *Spec) addSort(r *Query, param string) error {
+	for _, term := range strings.Split(param, ",") {
+		name, desc := strings.CutPrefix(term, "-")
+		if !slices.Contains(s.sortable, name) {
+			return fmt.Errorf("filter: cannot sort by %q", name)
+		}
+		r.Sort = append(r.Sort, Sort{Column: s.fields[name].column, Desc: desc})
+	}
+	return nil
+}
+
+// parseQueryKey splits a parameter name of the form field or field[op].
+func parseQueryKey(key string) (string, Op, error) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		return key, Eq, nil
+	}
+	if open == 0 || !strings.HasSuffix(key, "]") {
+		return "", "", fmt.Errorf("filter: invalid parameter %q", key)
+	}
+	return key[:open], Op(key[open+1 : len(key)-1]), nil
+}
+
+// queryValue converts the value of a parameter to the value of a Filter with op.
+func queryValue(op Op, v string) interface{} {
+	switch op {
+	case In, NotIn, Between:
+		parts := strings.Split(v, ",")
+		list := make([]interface{}, len(parts))
+		for i, p := range parts {
+			list[i] = p
+		}
+		return list
+	case Null:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
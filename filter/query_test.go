@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestCompileQuery(t *testing.T) {
+	spec := NewSpec().
+		Allow("age", sqltk.IntType, Eq, Gte, Between).
+		Allow("status", sqltk.StringType, Eq, In).
+		Allow("deleted_at", sqltk.TimeType, Null).
+		AllowColumn("created", "users.created_at", sqltk.TimeType, Lt).
+		AllowSort("created", "age").
+		Limit(20, 100).
+		WithDialect(sqldialect.Postgres())
+
+	tests := []struct {
+		name     string
+		query    string
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "filters",
+			query:    "age[gte]=18&status[in]=new,open&deleted_at[null]=true",
+			wantSQL:  `SELECT "id" FROM "users" WHERE "age" >= $1 AND "deleted_at" IS NULL AND "status" IN ($2, $3) LIMIT 20`,
+			wantArgs: []interface{}{int64(18), "new", "open"},
+		},
+		{
+			name:     "eq, sort, and page",
+			query:    "status=open&sort=-created,age&limit=5&offset=10",
+			wantSQL:  `SELECT "id" FROM "users" WHERE "status" = $1 ORDER BY "users"."created_at" DESC, "age" ASC LIMIT 5 OFFSET 10`,
+			wantArgs: []interface{}{"open"},
+		},
+		{
+			name:     "repeated and between",
+			query:    "age[between]=1,9&status=a&status=b",
+			wantSQL:  `SELECT "id" FROM "users" WHERE "age" BETWEEN $1 AND $2 AND "status" = $3 AND "status" = $4 LIMIT 20`,
+			wantArgs: []interface{}{int64(1), int64(9), "a", "b"},
+		},
+		{
+			name:    "empty",
+			query:   "",
+			wantSQL: `SELECT "id" FROM "users" LIMIT 20`,
+		},
+		{name: "unknown field", query: "password=x", wantErr: true},
+		{name: "operator not allowed", query: "status[like]=a%25", wantErr: true},
+		{name: "bad value", query: "age[gte]=old", wantErr: true},
+		{name: "bad key", query: "age[gte=1", wantErr: true},
+		{name: "not sortable", query: "sort=status", wantErr: true},
+		{name: "limit too large", query: "limit=101", wantErr: true},
+		{name: "limit zero", query: "limit=0", wantErr: true},
+		{name: "negative offset", query: "offset=-1", wantErr: true},
+		{name: "limit twice", query: "limit=1&limit=2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("bad test query: %v", err)
+			}
+			r, err := spec.CompileQuery(values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sql, args, err := r.Apply(sqltk.Select("id").From("users")).WithDialect(sqldialect.Postgres()).Build()
+			if err != nil {
+				t.Fatalf("unexpected build error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) == 0 && len(tt.wantArgs) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("spec errors", func(t *testing.T) {
+		for name, spec := range map[string]*Spec{
+			"sort unknown field": NewSpec().AllowSort("age"),
+			"default over max":   NewSpec().Limit(50, 10),
+			"negative limit":     NewSpec().Limit(-1, 0),
+		} {
+			if _, err := spec.CompileQuery(nil); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
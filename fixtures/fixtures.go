@@ -0,0 +1,362 @@
+// Package fixtures loads rows from JSON or YAML fixture files and turns them into Insert
+// builders, ordered so that referenced tables are filled first, for integration test setup.
+//
+// A fixture file maps table names to lists of rows:
+//
+//	{
+//	  "users":  [{"id": 1, "name": "Alice"}],
+//	  "orders": [{"id": 10, "user_id": 1, "total": 9.5}]
+//	}
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/ddl"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// DefaultChunkSize is the number of rows per INSERT statement unless set with ChunkSize.
+const DefaultChunkSize = 100
+
+// Loader collects fixture rows and builds the statements that insert them.
+type Loader struct {
+	tables    []string // in the order they were added
+	rows      map[string][]map[string]interface{}
+	deps      map[string][]string
+	schema    *sqltk.Schema
+	yaml      func([]byte, interface{}) error
+	chunkSize int
+	truncate  bool
+	dialect   sqldialect.Dialect
+}
+
+// Option configures a Loader.
+type Option func(*Loader)
+
+// WithSchema takes the order of tables from the foreign keys of schema, see
+// sqltk.Schema.AddForeignKey.
+func WithSchema(schema *sqltk.Schema) Option {
+	return func(l *Loader) {
+		l.schema = schema
+	}
+}
+
+// WithYAML enables YAML fixture files, parsed with unmarshal, e.g. yaml.Unmarshal from
+// gopkg.in/yaml.v3. The package itself has no YAML dependency.
+func WithYAML(unmarshal func([]byte, interface{}) error) Option {
+	return func(l *Loader) {
+		l.yaml = unmarshal
+	}
+}
+
+// ChunkSize sets the maximum number of rows per INSERT statement.
+func ChunkSize(n int) Option {
+	return func(l *Loader) {
+		l.chunkSize = n
+	}
+}
+
+// TruncateFirst empties the fixture tables before inserting. On Postgres, all tables are
+// truncated with one TRUNCATE TABLE statement, which foreign keys between them allow. Other
+// databases refuse to truncate a table referenced by a foreign key, so their tables are
+// emptied with DELETE, referencing tables first.
+func TruncateFirst() Option {
+	return func(l *Loader) {
+		l.truncate = true
+	}
+}
+
+// WithDialect sets the dialect of the built statements. The global dialect is used otherwise.
+func WithDialect(d sqldialect.Dialect) Option {
+	return func(l *Loader) {
+		l.dialect = d
+	}
+}
+
+// New creates an empty Loader.
+func New(opts ...Option) *Loader {
+	l := &Loader{
+		rows:      make(map[string][]map[string]interface{}),
+		deps:      make(map[string][]string),
+		chunkSize: DefaultChunkSize,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Add adds rows to table. Each row maps column names to values.
+func (l *Loader) Add(table string, rows ...map[string]interface{}) *Loader {
+	if _, ok := l.rows[table]; !ok {
+		l.tables = append(l.tables, table)
+	}
+	l.rows[table] = append(l.rows[table], rows...)
+	return l
+}
+
+// DependsOn records that table references refs, so refs are filled first. Use it for foreign
+// keys that are not in the schema set with WithSchema.
+func (l *Loader) DependsOn(table string, refs ...string) *Loader {
+	l.deps[table] = append(l.deps[table], refs...)
+	return l
+}
+
+// LoadFile adds the rows of a .json, .yaml or .yml fixture file.
+func (l *Loader) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("fixtures: %w", err)
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = l.LoadJSON(data)
+	case ".yaml", ".yml":
+		err = l.LoadYAML(data)
+	default:
+		return fmt.Errorf("fixtures: %s: unknown file type %q", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	return nil
+}
+
+// LoadJSON adds the rows of a JSON fixture. Tables are added in the order of the file.
+// Integers are decoded as int64, and objects and arrays as JSON text, for JSON columns.
+func (l *Loader) LoadJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return errors.New("fixtures: JSON fixture must be an object of tables")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("fixtures: %w", err)
+		}
+		table := tok.(string)
+		var rows []map[string]interface{}
+		if err := dec.Decode(&rows); err != nil {
+			return fmt.Errorf("fixtures: table %s: %w", table, err)
+		}
+		if err := l.addDecoded(table, rows); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("fixtures: %w", err)
+	}
+	return nil
+}
+
+// LoadYAML adds the rows of a YAML fixture, parsed with the function set with WithYAML. Map
+// order is not kept by YAML decoders, so tables are added in alphabetical order.
+func (l *Loader) LoadYAML(data []byte) error {
+	if l.yaml == nil {
+		return errors.New("fixtures: YAML fixtures require WithYAML")
+	}
+	var tables map[string][]map[string]interface{}
+	if err := l.yaml(data, &tables); err != nil {
+		return fmt.Errorf("fixtures: %w", err)
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := l.addDecoded(name, tables[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addDecoded adds decoded rows, converting their values to database arguments.
+func (l *Loader) addDecoded(table string, rows []map[string]interface{}) error {
+	for i, row := range rows {
+		for col, v := range row {
+			value, err := columnValue(v)
+			if err != nil {
+				return fmt.Errorf("fixtures: table %s, row %d, column %s: %w", table, i+1, col, err)
+			}
+			row[col] = value
+		}
+	}
+	l.Add(table, rows...)
+	return nil
+}
+
+// columnValue converts a decoded fixture value to a database argument.
+func columnValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return v, nil
+	}
+}
+
+// Order returns the fixture tables in insert order: every table after the tables it
+// references, and otherwise in the order they were added. It is an error if the foreign keys
+// between the tables form a cycle.
+func (l *Loader) Order() ([]string, error) {
+	refs := make(map[string][]string, len(l.tables))
+	for _, table := range l.tables {
+		deps := l.deps[table]
+		if l.schema != nil {
+			deps = append(deps[:len(deps):len(deps)], l.schema.References(table)...)
+		}
+		for _, dep := range deps {
+			ref := l.tableNamed(dep)
+			if ref != "" && ref != table && !slices.Contains(refs[table], ref) {
+				refs[table] = append(refs[table], ref)
+			}
+		}
+	}
+
+	order := make([]string, 0, len(l.tables))
+	done := make(map[string]bool, len(l.tables))
+	for len(order) < len(l.tables) {
+		progress := false
+		for _, table := range l.tables {
+			if done[table] {
+				continue
+			}
+			ready := true
+			for _, ref := range refs[table] {
+				if !done[ref] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, table)
+				done[table] = true
+				progress = true
+			}
+		}
+		if !progress {
+			var cycle []string
+			for _, table := range l.tables {
+				if !done[table] {
+					cycle = append(cycle, table)
+				}
+			}
+			return nil, fmt.Errorf("fixtures: foreign key cycle between %s", strings.Join(cycle, ", "))
+		}
+	}
+	return order, nil
+}
+
+// tableNamed returns the fixture table named name, ignoring case, or "".
+func (l *Loader) tableNamed(name string) string {
+	for _, table := range l.tables {
+		if strings.EqualFold(table, name) {
+			return table
+		}
+	}
+	return ""
+}
+
+// Builders returns the statements that load the fixtures: the truncation, if TruncateFirst
+// is set, then INSERT statements of at most ChunkSize rows, in the order of Order. Rows of a
+// table that share the same columns are inserted together.
+func (l *Loader) Builders() ([]sqltk.Builder, error) {
+	if l.chunkSize <= 0 {
+		return nil, errors.New("fixtures: chunk size must be positive")
+	}
+	order, err := l.Order()
+	if err != nil {
+		return nil, err
+	}
+	dialect := l.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+
+	var builders []sqltk.Builder
+	if l.truncate && len(order) > 0 {
+		reversed := slices.Clone(order)
+		slices.Reverse(reversed)
+		if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
+			builders = append(builders, ddl.TruncateTable(reversed...).WithDialect(dialect))
+		} else {
+			for _, table := range reversed {
+				builders = append(builders, sqltk.Delete(table).WithDialect(dialect))
+			}
+		}
+	}
+
+	for _, table := range order {
+		var insert *sqltk.InsertBuilder
+		var columns []string
+		n := 0
+		for i, row := range l.rows[table] {
+			if len(row) == 0 {
+				return nil, fmt.Errorf("fixtures: table %s, row %d has no columns", table, i+1)
+			}
+			rowColumns := make([]string, 0, len(row))
+			for col := range row {
+				rowColumns = append(rowColumns, col)
+			}
+			sort.Strings(rowColumns)
+			if insert == nil || n == l.chunkSize || !slices.Equal(rowColumns, columns) {
+				columns = rowColumns
+				insert = sqltk.Insert(table).Columns(columns...).WithDialect(dialect)
+				builders = append(builders, insert)
+				n = 0
+			}
+			values := make([]interface{}, len(columns))
+			for j, col := range columns {
+				values[j] = row[col]
+			}
+			insert.Values(values...)
+			n++
+		}
+	}
+	return builders, nil
+}
+
+// Batch returns the statements of Builders as a batch, e.g. for runner.ExecBatch.
+//
+// Example usage:
+//
+//	l := fixtures.New(fixtures.WithSchema(schema), fixtures.TruncateFirst())
+//	if err := l.LoadFile("testdata/users.json"); err != nil {
+//		t.Fatal(err)
+//	}
+//	batch, err := l.Batch()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	if err := runner.New(tx).ExecBatch(ctx, batch); err != nil {
+//		t.Fatal(err)
+//	}
+func (l *Loader) Batch() (*sqltk.Batch, error) {
+	builders, err := l.Builders()
+	if err != nil {
+		return nil, err
+	}
+	return sqltk.NewBatch(builders...), nil
+}
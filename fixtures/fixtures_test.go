@@ -0,0 +1,169 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+type statement struct {
+	sql  string
+	args []interface{}
+}
+
+func buildAll(t *testing.T, l *Loader) []statement {
+	t.Helper()
+	builders, err := l.Builders()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stmts := make([]statement, len(builders))
+	for i, b := range builders {
+		sql, args, err := b.Build()
+		if err != nil {
+			t.Fatalf("statement %d: unexpected error: %v", i, err)
+		}
+		stmts[i] = statement{sql, args}
+	}
+	return stmts
+}
+
+func TestLoader(t *testing.T) {
+	schema := sqltk.NewSchema().
+		AddForeignKey("orders", []string{"user_id"}, "users", []string{"id"}).
+		AddForeignKey("order_items", []string{"order_id"}, "orders", []string{"id"})
+
+	fixture := []byte(`{
+		"order_items": [{"order_id": 10, "sku": "A-1", "meta": {"gift": true}}],
+		"orders": [{"id": 10, "user_id": 1, "total": 9.5}],
+		"users": [{"id": 1, "name": "Alice"}, {"id": 2, "name": "Bob"}, {"id": 3}]
+	}`)
+
+	t.Run("foreign key order", func(t *testing.T) {
+		l := New(WithSchema(schema), WithDialect(sqldialect.Postgres()))
+		if err := l.LoadJSON(fixture); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := buildAll(t, l)
+		want := []statement{
+			{`INSERT INTO "users" ("id", "name") VALUES ($1, $2), ($3, $4)`, []interface{}{int64(1), "Alice", int64(2), "Bob"}},
+			{`INSERT INTO "users" ("id") VALUES ($1)`, []interface{}{int64(3)}},
+			{`INSERT INTO "orders" ("id", "total", "user_id") VALUES ($1, $2, $3)`, []interface{}{int64(10), 9.5, int64(1)}},
+			{`INSERT INTO "order_items" ("meta", "order_id", "sku") VALUES ($1, $2, $3)`, []interface{}{`{"gift":true}`, int64(10), "A-1"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("file order without schema", func(t *testing.T) {
+		l := New()
+		if err := l.LoadJSON(fixture); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		order, err := l.Order()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"order_items", "orders", "users"}; !reflect.DeepEqual(order, want) {
+			t.Errorf("got order %v, want %v", order, want)
+		}
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		l := New(ChunkSize(2), WithDialect(sqldialect.MySQL()))
+		for i := 1; i <= 5; i++ {
+			l.Add("tags", map[string]interface{}{"id": i})
+		}
+		got := buildAll(t, l)
+		want := []statement{
+			{"INSERT INTO `tags` (`id`) VALUES (?), (?)", []interface{}{1, 2}},
+			{"INSERT INTO `tags` (`id`) VALUES (?), (?)", []interface{}{3, 4}},
+			{"INSERT INTO `tags` (`id`) VALUES (?)", []interface{}{5}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("truncate first on postgres", func(t *testing.T) {
+		l := New(WithSchema(schema), TruncateFirst(), WithDialect(sqldialect.Postgres()))
+		l.Add("orders", map[string]interface{}{"id": 10, "user_id": 1}).
+			Add("users", map[string]interface{}{"id": 1})
+		got := buildAll(t, l)
+		if want := `TRUNCATE TABLE "orders", "users"`; got[0].sql != want {
+			t.Errorf("got SQL %q, want %q", got[0].sql, want)
+		}
+		if len(got) != 3 {
+			t.Errorf("got %d statements, want 3", len(got))
+		}
+	})
+
+	t.Run("truncate first on mysql", func(t *testing.T) {
+		l := New(TruncateFirst(), WithDialect(sqldialect.MySQL()))
+		l.Add("orders", map[string]interface{}{"id": 10, "user_id": 1}).
+			Add("users", map[string]interface{}{"id": 1}).
+			DependsOn("orders", "users")
+		got := buildAll(t, l)
+		var sqls []string
+		for _, s := range got {
+			sqls = append(sqls, s.sql)
+		}
+		want := []string{
+			"DELETE FROM `orders`",
+			"DELETE FROM `users`",
+			"INSERT INTO `users` (`id`) VALUES (?)",
+			"INSERT INTO `orders` (`id`, `user_id`) VALUES (?, ?)",
+		}
+		if !reflect.DeepEqual(sqls, want) {
+			t.Errorf("got %q, want %q", sqls, want)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		l := New().
+			Add("a", map[string]interface{}{"id": 1}).
+			Add("b", map[string]interface{}{"id": 1}).
+			DependsOn("a", "b").
+			DependsOn("b", "a")
+		if _, err := l.Builders(); err == nil {
+			t.Fatal("expected cycle error")
+		}
+	})
+
+	t.Run("yaml without unmarshal", func(t *testing.T) {
+		if err := New().LoadYAML([]byte("users: []")); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("yaml file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "users.yml")
+		if err := os.WriteFile(path, []byte(`{"users": [{"id": 1}], "accounts": [{"id": 2}]}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// JSON is valid YAML, so json.Unmarshal stands in for a YAML decoder.
+		l := New(WithYAML(json.Unmarshal))
+		if err := l.LoadFile(path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		order, err := l.Order()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"accounts", "users"}; !reflect.DeepEqual(order, want) {
+			t.Errorf("got order %v, want %v", order, want)
+		}
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		if err := New().LoadJSON([]byte(`[{"id": 1}]`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
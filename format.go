@@ -0,0 +1,204 @@
+package sqltk
+
+import (
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// FormatOptions configures FormatSQL.
+type FormatOptions struct {
+	// Indent is written once per level of indentation. It is two spaces if empty.
+	Indent string
+}
+
+// FormatSQL returns sql indented over several lines, for logging and reviewing generated
+// queries. Each clause, such as FROM, a JOIN, or WHERE, starts a line, as do the columns of
+// SELECT, the assignments of SET, and the ANDs and ORs of WHERE and HAVING. Subqueries are
+// indented one more level. Only whitespace between tokens changes: quoted strings, quoted
+// identifiers, and comments are kept as written, so the result runs like sql.
+//
+// Example usage:
+//
+//	sqltk.FormatSQL("SELECT id, name FROM users WHERE active = ? AND age > ?", sqltk.FormatOptions{})
+//	// SELECT
+//	//   id,
+//	//   name
+//	// FROM users
+//	// WHERE active = ?
+//	//   AND age > ?
+func FormatSQL(sql string, opts FormatOptions) string {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	f := &formatter{indent: opts.Indent, levels: []formatLevel{{}}}
+	tokens := sqllex.For(sqldialect.GetDialect()).Tokens(sql)
+	for i, tok := range tokens {
+		f.token(tokens, i, tok)
+	}
+	return f.sb.String()
+}
+
+// formatLevel is a query or subquery being formatted.
+type formatLevel struct {
+	depth   int    // indentation of its clauses
+	close   int    // indentation of the parenthesis that ends a subquery
+	clause  string // the clause being formatted, such as "SELECT" or "WHERE"
+	parens  int    // parentheses open in the level that are not subqueries
+	between bool   // a BETWEEN waits for its AND
+}
+
+type formatter struct {
+	sb        strings.Builder
+	indent    string
+	levels    []formatLevel
+	newLine   bool // the next token starts a line
+	lineDepth int  // indentation of the current line
+}
+
+func (f *formatter) level() *formatLevel {
+	return &f.levels[len(f.levels)-1]
+}
+
+// newline starts a new line indented depth times before the next token.
+func (f *formatter) newline(depth int) {
+	f.newLine = true
+	f.lineDepth = depth
+}
+
+// write writes text on a new line if one was started, or else after a space if space is set.
+func (f *formatter) write(text string, space bool) {
+	if f.newLine {
+		if f.sb.Len() > 0 {
+			f.sb.WriteString("\n")
+		}
+		f.sb.WriteString(strings.Repeat(f.indent, f.lineDepth))
+		f.newLine = false
+	} else if space && f.sb.Len() > 0 {
+		f.sb.WriteString(" ")
+	}
+	f.sb.WriteString(text)
+}
+
+// token formats tokens[i].
+func (f *formatter) token(tokens []sqllex.Token, i int, tok sqllex.Token) {
+	l := f.level()
+	word := strings.ToUpper(tok.Text)
+	switch {
+	case tok.Kind == sqllex.Comment && strings.HasPrefix(tok.Text, "--"):
+		f.write(strings.TrimRight(tok.Text, "\r"), tok.Space)
+		f.newline(f.lineDepth)
+		return
+	case tok.Text == "(":
+		if next := nextWord(tokens, i); next == "SELECT" || next == "WITH" {
+			f.write("(", tok.Space)
+			f.levels = append(f.levels, formatLevel{depth: f.lineDepth + 1, close: f.lineDepth})
+			return
+		}
+		l.parens++
+	case tok.Text == ")":
+		if l.parens == 0 && len(f.levels) > 1 {
+			f.levels = f.levels[:len(f.levels)-1]
+			f.newline(l.close)
+			f.write(")", false)
+			return
+		}
+		l.parens--
+	case l.parens > 0:
+	case tok.Text == ",":
+		if l.clause == "SELECT" || l.clause == "SET" {
+			f.write(",", tok.Space)
+			f.newline(l.depth + 1)
+			return
+		}
+	case word == "BETWEEN":
+		l.between = true
+	case word == "AND" && l.between:
+		l.between = false
+	case (word == "AND" || word == "OR") && (l.clause == "WHERE" || l.clause == "HAVING"):
+		f.newline(l.depth + 1)
+		f.write(tok.Text, false)
+		return
+	case isClauseStart(tokens, i, word):
+		f.newline(l.depth)
+		f.write(tok.Text, false)
+		l.clause = word
+		if word == "SELECT" || word == "SET" {
+			f.newline(l.depth + 1)
+		}
+		return
+	}
+	f.write(tok.Text, tok.Space)
+}
+
+// isClauseStart reports whether tokens[i], whose text is word in upper case, starts a clause
+// that FormatSQL writes on its own line.
+func isClauseStart(tokens []sqllex.Token, i int, word string) bool {
+	prev := ""
+	if i > 0 {
+		prev = strings.ToUpper(tokens[i-1].Text)
+	}
+	switch word {
+	case "SELECT":
+		return true
+	case "FROM":
+		return prev != "DELETE" && prev != "DISTINCT"
+	case "WITH", "WHERE", "HAVING", "LIMIT", "OFFSET", "FETCH", "RETURNING", "WINDOW", "QUALIFY",
+		"UNION", "INTERSECT", "EXCEPT", "INSERT", "DELETE", "MERGE", "SET":
+		return true
+	case "UPDATE":
+		return i == 0 || prev == ";"
+	case "VALUES":
+		return i+1 < len(tokens) && tokens[i+1].Space
+	case "GROUP", "ORDER":
+		return nextWord(tokens, i) == "BY"
+	case "JOIN":
+		return !isJoinModifier(prev)
+	case "LEFT", "RIGHT", "FULL", "INNER", "CROSS", "NATURAL":
+		next := nextWord(tokens, i)
+		return (next == "JOIN" || next == "OUTER") && !isJoinModifier(prev)
+	case "ON":
+		next := nextWord(tokens, i)
+		return next == "CONFLICT" || next == "DUPLICATE"
+	case "FOR":
+		next := nextWord(tokens, i)
+		return next == "UPDATE" || next == "SHARE" || next == "NO"
+	}
+	return false
+}
+
+func isJoinModifier(word string) bool {
+	switch word {
+	case "LEFT", "RIGHT", "FULL", "INNER", "CROSS", "NATURAL", "OUTER":
+		return true
+	}
+	return false
+}
+
+// nextWord returns the text of the token after tokens[i] in upper case, or "".
+func nextWord(tokens []sqllex.Token, i int) string {
+	if i+1 < len(tokens) {
+		return strings.ToUpper(tokens[i+1].Text)
+	}
+	return ""
+}
+
+// BuildPretty is like Build but returns the SQL formatted over several lines with FormatSQL,
+// for logging and code review. The formatted SQL runs like that of Build.
+func (b *SelectBuilder) BuildPretty() (string, []interface{}, error) {
+	sql, args, err := b.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	return FormatSQL(sql, FormatOptions{}), args, nil
+}
+
+// BuildPretty is like Build but returns the SQL formatted over several lines with FormatSQL.
+func (c *CompoundBuilder) BuildPretty() (string, []interface{}, error) {
+	sql, args, err := c.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	return FormatSQL(sql, FormatOptions{}), args, nil
+}
@@ -0,0 +1,79 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestFormatSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		opts FormatOptions
+		want string
+	}{
+		{
+			name: "clauses",
+			sql:  "SELECT u.id, COUNT(*) AS n FROM users u LEFT JOIN orders o ON o.user_id = u.id WHERE u.active = ? AND (o.total > ? OR o.total IS NULL) AND u.age BETWEEN ? AND ? GROUP BY u.id HAVING COUNT(*) > 1 ORDER BY n DESC LIMIT 10",
+			want: `SELECT
+  u.id,
+  COUNT(*) AS n
+FROM users u
+LEFT JOIN orders o ON o.user_id = u.id
+WHERE u.active = ?
+  AND (o.total > ? OR o.total IS NULL)
+  AND u.age BETWEEN ? AND ?
+GROUP BY u.id
+HAVING COUNT(*) > 1
+ORDER BY n DESC
+LIMIT 10`,
+		},
+		{
+			name: "subquery and literals",
+			sql:  "SELECT id FROM users WHERE id IN (SELECT user_id FROM admins WHERE role = 'a, b AND c' AND x = 1) AND y = 2",
+			opts: FormatOptions{Indent: "\t"},
+			want: "SELECT\n\tid\nFROM users\nWHERE id IN (\n\tSELECT\n\t\tuser_id\n\tFROM admins\n\tWHERE role = 'a, b AND c'\n\t\tAND x = 1\n)\n\tAND y = 2",
+		},
+		{
+			name: "window and comment",
+			sql:  "SELECT SUM(x) OVER (PARTITION BY y ORDER BY z) FROM t -- note\nWHERE a = 1",
+			want: "SELECT\n  SUM(x) OVER (PARTITION BY y ORDER BY z)\nFROM t -- note\nWHERE a = 1",
+		},
+		{
+			name: "dollar-quoted string",
+			sql:  "SELECT $fn$ a, b FROM c $fn$ AS body, x FROM t",
+			want: "SELECT\n  $fn$ a, b FROM c $fn$ AS body,\n  x\nFROM t",
+		},
+		{
+			name: "update",
+			sql:  "UPDATE users SET a = ?, b = ? WHERE id = ?",
+			want: "UPDATE users\nSET\n  a = ?,\n  b = ?\nWHERE id = ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSQL(tt.sql, tt.opts); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("build pretty", func(t *testing.T) {
+		q := Select("id").From("users").WhereEqual("status", "active").WithDialect(sqldialect.Postgres())
+		sql, args, err := q.BuildPretty()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "SELECT\n  \"id\"\nFROM \"users\"\nWHERE status = $1"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if !reflect.DeepEqual(args, []interface{}{"active"}) {
+			t.Errorf("got args %v", args)
+		}
+		if built, _ := q.MustBuild(); Normalize(sql) != Normalize(built) {
+			t.Error("formatting changed more than whitespace")
+		}
+	})
+}
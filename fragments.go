@@ -0,0 +1,161 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Fragments is a registry of named query pieces, conditions and subqueries, that are
+// registered once and referenced by name from any builder, so that conventions such as
+// soft-delete filters are written in one place. It is safe for concurrent use.
+//
+// Example usage:
+//
+//	sqltk.RegisterFragment("not_deleted", sqltk.NewCond().IsNull("deleted_at"))
+//	sqltk.RegisterFragment("active_users", sqltk.Select("id").From("users").WhereEqual("active", true))
+//
+//	q := sqltk.Select("id").From("orders").
+//		Where(sqltk.Fragment("not_deleted")).
+//		Where(sqltk.NewCond().InQuery("user_id", sqltk.FragmentQuery("active_users")))
+type Fragments struct {
+	mu    sync.RWMutex
+	items map[string]interface{} // Condition or *SelectBuilder
+}
+
+// DefaultFragments is the registry of RegisterFragment, Fragment and FragmentQuery.
+var DefaultFragments = NewFragments()
+
+// NewFragments creates an empty registry.
+func NewFragments() *Fragments {
+	return &Fragments{items: make(map[string]interface{})}
+}
+
+// Register adds a Condition or a *SelectBuilder under name. It is an error if name is taken.
+// A query is cloned, so later changes to it do not affect the fragment; a condition should
+// not be changed after it is registered.
+func (f *Fragments) Register(name string, fragment interface{}) error {
+	if name == "" {
+		return errors.New("Fragments: name must not be empty")
+	}
+	switch fr := fragment.(type) {
+	case *SelectBuilder:
+		if fr == nil {
+			return fmt.Errorf("Fragments: fragment %q is nil", name)
+		}
+		fragment = fr.Clone()
+	case Condition:
+	default:
+		return fmt.Errorf("Fragments: fragment %q must be a Condition or *SelectBuilder (got %T)", name, fragment)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.items[name]; ok {
+		return fmt.Errorf("Fragments: fragment %q is already registered", name)
+	}
+	f.items[name] = fragment
+	return nil
+}
+
+// MustRegister is like Register but panics on error, for registering fragments in package
+// initialization.
+func (f *Fragments) MustRegister(name string, fragment interface{}) *Fragments {
+	if err := f.Register(name, fragment); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Names returns the names of the registered fragments, sorted.
+func (f *Fragments) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := make([]string, 0, len(f.items))
+	for name := range f.items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cond returns the condition fragment registered under name. It is looked up when the
+// condition is added to a builder, which then fails to build if there is no such condition.
+func (f *Fragments) Cond(name string) Condition {
+	return fragmentCondition{fragments: f, name: name}
+}
+
+// Query returns a copy of the query fragment registered under name, which can be used
+// wherever a *SelectBuilder is accepted and changed freely. If there is no such query,
+// building it returns an error.
+func (f *Fragments) Query(name string) *SelectBuilder {
+	f.mu.RLock()
+	fragment, ok := f.items[name]
+	f.mu.RUnlock()
+	if q, isQuery := fragment.(*SelectBuilder); isQuery {
+		return q.Clone()
+	}
+	b := &SelectBuilder{}
+	if ok {
+		b.whereClause.err = fmt.Errorf("Fragments: fragment %q is not a query", name)
+	} else {
+		b.whereClause.err = fmt.Errorf("Fragments: unknown fragment %q", name)
+	}
+	return b
+}
+
+// fragmentCondition is a condition fragment referenced by name.
+type fragmentCondition struct {
+	fragments *Fragments
+	name      string
+}
+
+// resolve returns the registered condition.
+func (fc fragmentCondition) resolve() (Condition, error) {
+	fc.fragments.mu.RLock()
+	fragment, ok := fc.fragments.items[fc.name]
+	fc.fragments.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Fragments: unknown fragment %q", fc.name)
+	}
+	cond, isCond := fragment.(Condition)
+	if !isCond {
+		return nil, fmt.Errorf("Fragments: fragment %q is not a condition", fc.name)
+	}
+	return cond, nil
+}
+
+// BuildCondition implements Condition.
+func (fc fragmentCondition) BuildCondition() (string, []interface{}, error) {
+	cond, err := fc.resolve()
+	if err != nil {
+		return "", nil, err
+	}
+	return cond.BuildCondition()
+}
+
+func (fc fragmentCondition) subqueryBuilders() []*SelectBuilder {
+	cond, err := fc.resolve()
+	if err != nil {
+		return nil
+	}
+	return condSubqueries(cond)
+}
+
+// RegisterFragment adds a Condition or a *SelectBuilder to DefaultFragments, see
+// Fragments.Register.
+func RegisterFragment(name string, fragment interface{}) error {
+	return DefaultFragments.Register(name, fragment)
+}
+
+// Fragment returns the condition registered in DefaultFragments under name, see Fragments.Cond.
+func Fragment(name string) Condition {
+	return DefaultFragments.Cond(name)
+}
+
+// FragmentQuery returns a copy of the query registered in DefaultFragments under name, see
+// Fragments.Query.
+func FragmentQuery(name string) *SelectBuilder {
+	return DefaultFragments.Query(name)
+}
@@ -0,0 +1,106 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestFragments(t *testing.T) {
+	f := NewFragments().
+		MustRegister("not_deleted", NewStringCondition("deleted_at IS NULL")).
+		MustRegister("paid", NewCond().Equal("status", "paid"))
+	active := Select("id").From("users").WhereEqual("active", true)
+	f.MustRegister("active_users", active)
+	active.WhereEqual("admin", true) // registering clones the query
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "conditions and queries",
+			builder: Select("id").From("orders").
+				Where(f.Cond("not_deleted")).
+				Where(f.Cond("paid")).
+				Where(NewCond().InQuery("user_id", f.Query("active_users"))).
+				WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "orders" WHERE deleted_at IS NULL AND status = $1 AND user_id IN (SELECT "id" FROM "users" WHERE active = $2)`,
+			wantArgs: []interface{}{"paid", true},
+		},
+		{
+			name:     "update and delete",
+			builder:  Delete("orders").Where(f.Cond("not_deleted")),
+			wantSQL:  "DELETE FROM orders WHERE deleted_at IS NULL",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "unknown condition",
+			builder: Select("id").From("orders").Where(f.Cond("missing")),
+			wantErr: true,
+		},
+		{
+			name:    "query used as condition",
+			builder: Select("id").From("orders").Where(f.Cond("active_users")),
+			wantErr: true,
+		},
+		{
+			name:    "condition used as query",
+			builder: f.Query("paid"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	if err := f.Register("paid", NewStringCondition("1 = 1")); err == nil {
+		t.Error("expected error for duplicate name")
+	}
+	if err := f.Register("bad", 42); err == nil {
+		t.Error("expected error for unsupported fragment")
+	}
+	if got, want := f.Names(), []string{"active_users", "not_deleted", "paid"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got names %v, want %v", got, want)
+	}
+}
+
+func TestDefaultFragments(t *testing.T) {
+	if err := RegisterFragment("test_visible", NewStringCondition("visible = ?", true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sql, args, err := Select("id").From("posts").Where(Fragment("test_visible")).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "SELECT id FROM posts WHERE visible = ?"; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if want := []interface{}{true}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got args %v, want %v", args, want)
+	}
+	if _, _, err := FragmentQuery("test_missing").Build(); err == nil {
+		t.Error("expected error for unknown query")
+	}
+}
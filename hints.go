@@ -0,0 +1,164 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// indexHint is a MySQL index hint on a table reference, e.g. USE INDEX (idx_a).
+type indexHint struct {
+	kind    string // "USE", "FORCE", or "IGNORE"
+	indexes []string
+}
+
+func newIndexHint(kind string, indexes []string) (indexHint, error) {
+	if len(indexes) == 0 {
+		return indexHint{}, fmt.Errorf("%s INDEX: at least one index is required", kind)
+	}
+	for _, idx := range indexes {
+		if idx == "" {
+			return indexHint{}, fmt.Errorf("%s INDEX: index name must not be empty", kind)
+		}
+	}
+	return indexHint{kind: kind, indexes: append([]string(nil), indexes...)}, nil
+}
+
+// isTableName reports whether table is a plain or aliased table name, which index hints can follow.
+func isTableName(table interface{}) bool {
+	switch t := table.(type) {
+	case string, Identifier:
+		return true
+	case AliasExpr:
+		switch t.Expr.(type) {
+		case string, Identifier:
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// buildIndexHints renders index hints for a table reference. Index hints are MySQL-only;
+// on other dialects they are left out with UnsupportedStrip.
+func buildIndexHints(dialect sqldialect.Dialect, hints []indexHint, mode UnsupportedMode) (string, error) {
+	if len(hints) == 0 {
+		return "", nil
+	}
+	if baseDialect(dialect) != sqldialect.MySQL() {
+		if _, err := mode.strip("index hints", ""); err != nil {
+			return "", errors.New("index hints are only supported by MySQL")
+		}
+		return "", nil
+	}
+	var sb strings.Builder
+	for _, h := range hints {
+		sb.WriteString(" ")
+		sb.WriteString(h.kind)
+		sb.WriteString(" INDEX (")
+		for i, idx := range h.indexes {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(dialect.QuoteIdent(idx))
+		}
+		sb.WriteString(")")
+	}
+	return sb.String(), nil
+}
+
+// UseIndex adds a USE INDEX hint to the FROM table (MySQL only).
+//
+// Example usage:
+//
+//	Select("id").From("orders").UseIndex("idx_orders_user")
+//	// SELECT `id` FROM `orders` USE INDEX (`idx_orders_user`)
+func (b *SelectBuilder) UseIndex(indexes ...string) *SelectBuilder {
+	return b.addIndexHint("USE", indexes)
+}
+
+// ForceIndex adds a FORCE INDEX hint to the FROM table (MySQL only).
+func (b *SelectBuilder) ForceIndex(indexes ...string) *SelectBuilder {
+	return b.addIndexHint("FORCE", indexes)
+}
+
+// IgnoreIndex adds an IGNORE INDEX hint to the FROM table (MySQL only).
+func (b *SelectBuilder) IgnoreIndex(indexes ...string) *SelectBuilder {
+	return b.addIndexHint("IGNORE", indexes)
+}
+
+func (b *SelectBuilder) addIndexHint(kind string, indexes []string) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	h, err := newIndexHint(kind, indexes)
+	if err != nil {
+		b.whereClause.err = err
+		return b
+	}
+	b.indexHints = append(b.indexHints, h)
+	return b
+}
+
+// UseIndex adds a USE INDEX hint to the joined table (MySQL only).
+//
+// Example usage:
+//
+//	Join("orders o").UseIndex("idx_orders_user").On("o.user_id", "u.id")
+func (jb *JoinBuilder) UseIndex(indexes ...string) *JoinBuilder {
+	return jb.addIndexHint("USE", indexes)
+}
+
+// ForceIndex adds a FORCE INDEX hint to the joined table (MySQL only).
+func (jb *JoinBuilder) ForceIndex(indexes ...string) *JoinBuilder {
+	return jb.addIndexHint("FORCE", indexes)
+}
+
+// IgnoreIndex adds an IGNORE INDEX hint to the joined table (MySQL only).
+func (jb *JoinBuilder) IgnoreIndex(indexes ...string) *JoinBuilder {
+	return jb.addIndexHint("IGNORE", indexes)
+}
+
+func (jb *JoinBuilder) addIndexHint(kind string, indexes []string) *JoinBuilder {
+	if jb.err != nil {
+		return jb
+	}
+	h, err := newIndexHint(kind, indexes)
+	if err != nil {
+		jb.err = fmt.Errorf("join: %w", err)
+		return jb
+	}
+	jb.indexHints = append(jb.indexHints, h)
+	return jb
+}
+
+// Hint adds an optimizer hint, placed in a /*+ ... */ comment right after SELECT. The hint may
+// be given with or without the comment markers; all hints of a query share one comment, which
+// on MySQL also holds the MAX_EXECUTION_TIME hint.
+//
+// Example usage:
+//
+//	Select("id").From("orders").Hint("/*+ NO_INDEX_MERGE(orders) */")
+//	// SELECT /*+ NO_INDEX_MERGE(orders) */ `id` FROM `orders`
+func (b *SelectBuilder) Hint(hint string) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	hint = strings.TrimSpace(hint)
+	if strings.HasPrefix(hint, "/*+") && strings.HasSuffix(hint, "*/") && len(hint) >= len("/*+*/") {
+		hint = strings.TrimSpace(hint[len("/*+") : len(hint)-len("*/")])
+	}
+	if hint == "" {
+		b.whereClause.err = errors.New("Hint: hint must not be empty")
+		return b
+	}
+	if strings.Contains(hint, "/*") || strings.Contains(hint, "*/") {
+		b.whereClause.err = errors.New("Hint: hint must not contain comment markers")
+		return b
+	}
+	b.hints = append(b.hints, hint)
+	return b
+}
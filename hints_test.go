@@ -0,0 +1,138 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  func() *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "use index on from table",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").UseIndex("idx_orders_user", "idx_orders_date").WhereEqual("user_id", 1)
+			},
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `id` FROM `orders` USE INDEX (`idx_orders_user`, `idx_orders_date`) WHERE user_id = ?",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "force and ignore index on aliased table",
+			builder: func() *SelectBuilder {
+				return Select("o.id").From(Alias("orders", "o")).ForceIndex("idx_a").IgnoreIndex("idx_b")
+			},
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `o`.`id` FROM `orders` AS o FORCE INDEX (`idx_a`) IGNORE INDEX (`idx_b`)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "index hint on join",
+			builder: func() *SelectBuilder {
+				return Select("u.id").WithDialect(sqldialect.MySQL()).From(Alias("users", "u")).
+					Join(Alias("orders", "o")).UseIndex("idx_orders_user").On("o.user_id", "u.id")
+			},
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `u`.`id` FROM `users` AS u JOIN `orders` AS o USE INDEX (`idx_orders_user`) ON o.user_id = u.id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "optimizer hints share one comment",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").Hint("/*+ NO_INDEX_MERGE(orders) */").Hint("BKA(orders)").
+					MaxExecutionTime(2 * time.Second)
+			},
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT /*+ MAX_EXECUTION_TIME(2000) NO_INDEX_MERGE(orders) BKA(orders) */ `id` FROM `orders`",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "optimizer hint on postgres",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").Hint("SeqScan(orders)")
+			},
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT /*+ SeqScan(orders) */ "id" FROM "orders"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "index hint on postgres",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").UseIndex("idx_orders_user")
+			},
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name: "index hint on subquery",
+			builder: func() *SelectBuilder {
+				return Select("id").From(Alias(Select("id").From("orders"), "o")).UseIndex("idx")
+			},
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name: "index hint without indexes",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").ForceIndex()
+			},
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name: "join index hint on postgres",
+			builder: func() *SelectBuilder {
+				return Select("u.id").WithDialect(sqldialect.Postgres()).From("users").
+					Join("orders").IgnoreIndex("idx").On("orders.user_id", "users.id")
+			},
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name: "hint closing the comment",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").Hint("BKA(orders) */ DROP TABLE users; /*")
+			},
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name: "empty hint",
+			builder: func() *SelectBuilder {
+				return Select("id").From("orders").Hint("/*+ */")
+			},
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder().WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package sqltk
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Identifier is a possibly qualified name, such as a schema-qualified table or a
+// table-qualified column, whose parts are quoted one by one, so that a part may contain dots.
+// It is accepted as a table by From, AddFrom, Join, and Alias, and as a column by Select.
+// Builders that take a table or column name as a string accept its String form.
+//
+// Example usage:
+//
+//	Select("id").From(Ident("analytics", "events")).WithDialect(sqldialect.MySQL())
+//	// SELECT `id` FROM `analytics`.`events`
+type Identifier struct {
+	parts []string
+}
+
+// Ident returns the identifier with the given parts, e.g. Ident("catalog", "schema", "table").
+func Ident(parts ...string) Identifier {
+	return Identifier{parts: parts}
+}
+
+// Table returns the identifier for a qualified name such as "schema.table", split at its dots.
+// A part in double quotes or backticks may contain dots, as in `"my.schema".events`. A name
+// that cannot be split is a single part.
+func Table(name string) Identifier {
+	if parts, ok := sqldialect.SplitQualified(name); ok {
+		return Identifier{parts: parts}
+	}
+	return Identifier{parts: []string{name}}
+}
+
+// Parts returns the parts of the identifier.
+func (i Identifier) Parts() []string {
+	return append([]string(nil), i.parts...)
+}
+
+// Name returns the last part of the identifier, e.g. the table of a schema-qualified table.
+func (i Identifier) Name() string {
+	if len(i.parts) == 0 {
+		return ""
+	}
+	return i.parts[len(i.parts)-1]
+}
+
+// validate reports an identifier without parts or with an empty part.
+func (i Identifier) validate() error {
+	if len(i.parts) == 0 {
+		return errors.New("Ident: at least one part is required")
+	}
+	for _, part := range i.parts {
+		if part == "" {
+			return errors.New("Ident: parts must not be empty")
+		}
+	}
+	return nil
+}
+
+// Quote returns the identifier with each part quoted with dialect.
+func (i Identifier) Quote(dialect sqldialect.Dialect) string {
+	quoted := make([]string, len(i.parts))
+	for n, part := range i.parts {
+		quoted[n] = dialect.QuoteIdent(part)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// String returns the identifier as a qualified name that builders and Table split into the
+// same parts: its parts joined with dots, with parts that contain dots or quotes in double
+// quotes.
+func (i Identifier) String() string {
+	parts := make([]string, len(i.parts))
+	for n, part := range i.parts {
+		if part == "" || strings.ContainsAny(part, ".\"`") || strings.TrimSpace(part) != part {
+			part = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+		}
+		parts[n] = part
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteTable quotes a table given as a string or an Identifier.
+func quoteTable(dialect sqldialect.Dialect, table interface{}) string {
+	if id, ok := table.(Identifier); ok {
+		return id.Quote(dialect)
+	}
+	return quoteQualifiedIdent(dialect, table.(string))
+}
@@ -0,0 +1,86 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestIdent(t *testing.T) {
+	events := Ident("analytics", "events")
+	dotted := Ident("my.schema", "events")
+
+	if got := dotted.String(); got != `"my.schema".events` {
+		t.Errorf("got String %q", got)
+	}
+	if got := Table(dotted.String()); !reflect.DeepEqual(got, dotted) {
+		t.Errorf("Table did not split String into the parts: %v", got.Parts())
+	}
+	if got := Table("warehouse.analytics.events").Parts(); !reflect.DeepEqual(got, []string{"warehouse", "analytics", "events"}) {
+		t.Errorf("got parts %q", got)
+	}
+
+	tests := []struct {
+		name    string
+		builder Builder
+		wantSQL string
+	}{
+		{
+			name:    "from",
+			builder: Select("id").From(events).WithDialect(sqldialect.MySQL()),
+			wantSQL: "SELECT `id` FROM `analytics`.`events`",
+		},
+		{
+			name:    "from with a dot in a part",
+			builder: Select("id").From(dotted).WithDialect(sqldialect.Postgres()),
+			wantSQL: `SELECT "id" FROM "my.schema"."events"`,
+		},
+		{
+			name:    "qualified string",
+			builder: Select("id").From("analytics.events").WithDialect(sqldialect.Postgres()),
+			wantSQL: `SELECT "id" FROM "analytics"."events"`,
+		},
+		{
+			name: "join and columns",
+			builder: Select(Ident("e", "id"), Alias(Ident("u", "my.name"), "name")).
+				From(Alias(events, "e")).
+				Join(Alias(Ident("crm", "users"), "u")).On("u.id", "e.user_id").
+				WithDialect(sqldialect.Postgres()),
+			wantSQL: `SELECT "e"."id", "u"."my.name" AS "name" FROM "analytics"."events" AS e JOIN "crm"."users" AS u ON u.id = e.user_id`,
+		},
+		{
+			name:    "condition",
+			builder: NewCond().WithDialect(sqldialect.MySQL()).Equal(Ident("e", "my.col").String(), 1),
+			wantSQL: "`e`.`my.col` = ?",
+		},
+		{
+			name:    "insert",
+			builder: Insert(dotted.String()).Columns("id").Values(1).WithDialect(sqldialect.Postgres()),
+			wantSQL: `INSERT INTO "my.schema"."events" ("id") VALUES ($1)`,
+		},
+		{
+			name:    "delete",
+			builder: Delete(events.String()).WhereEqual("id", 1).WithDialect(sqldialect.MySQL()),
+			wantSQL: "DELETE FROM `analytics`.`events` WHERE id = ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+
+	if got := Select("e.id").From(Alias(events, "e")).Join(Ident("crm", "users")).On("users.id", "e.user_id").GetTables(); !reflect.DeepEqual(got, []string{"analytics.events", "crm.users"}) {
+		t.Errorf("got tables %v", got)
+	}
+	if _, _, err := Select("id").From(Ident("analytics", "")).Build(); err == nil {
+		t.Error("expected error for an empty part")
+	}
+}
@@ -2,6 +2,9 @@ package sqltk
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
 
 	"github.com/sprylic/sqltk/sqldebug"
@@ -10,11 +13,22 @@ import (
 
 // InsertBuilder builds SQL INSERT queries.
 type InsertBuilder struct {
-	table   string
-	columns []string
-	values  [][]interface{}
-	err     error
-	dialect sqldialect.Dialect // per-builder dialect, if set
+	table       string
+	columns     []string
+	values      [][]interface{}
+	err         error
+	dialect     sqldialect.Dialect // per-builder dialect, if set
+	schema      *Schema            // schema to check against, see WithSchema
+	conflict    *onConflict        // see OnConflict
+	idemKey     *idempotencyKey    // see IdempotencyKey
+	unsupported UnsupportedMode    // see OnUnsupported
+	reuseArgs   bool               // see ReuseArgs
+	returning   []interface{}      // see Returning
+	ignore      bool               // see Ignore
+	replace     bool               // see Replace
+	defaults    bool               // see DefaultValues
+	query       *SelectBuilder     // see FromSelect
+	with        withClause         // see With
 }
 
 // Insert creates a new InsertBuilder for the given table.
@@ -44,12 +58,119 @@ func (b *InsertBuilder) Values(vals ...interface{}) *InsertBuilder {
 	return b
 }
 
+// DefaultValues inserts a single row with the default value of every column, as INSERT INTO
+// ... DEFAULT VALUES, or INSERT INTO ... () VALUES () on MySQL. It cannot be combined with
+// Columns and Values; use Default for the default of a single column.
+func (b *InsertBuilder) DefaultValues() *InsertBuilder {
+	b.defaults = true
+	return b
+}
+
+// DefaultValue is the type of Default.
+type DefaultValue struct{}
+
+// Default is a value that stands for the DEFAULT keyword, which sets the column to its default,
+// in Values, Rows, or UpdateBuilder.Set.
+//
+// Example usage:
+//
+//	Insert("users").Columns("id", "email").Values(sqltk.Default, "a@example.com")
+//	// INSERT INTO users (id, email) VALUES (DEFAULT, ?)
+var Default DefaultValue
+
+// BindSQL implements Binder.
+func (DefaultValue) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	return "DEFAULT", nil
+}
+
+// Rows adds rows of values to insert, as Values does for each. It is meant for bulk inserts
+// of many rows, which BuildBatches splits into statements of a size the database accepts.
+func (b *InsertBuilder) Rows(rows ...[]interface{}) *InsertBuilder {
+	for i, row := range rows {
+		if b.err != nil {
+			return b
+		}
+		if len(row) != len(b.columns) {
+			b.err = fmt.Errorf("Rows: row %d: number of values must match number of columns", i+1)
+			return b
+		}
+		b.values = append(b.values, row)
+	}
+	return b
+}
+
+// FromSelect inserts the rows of the query q instead of Values, as INSERT INTO ... SELECT.
+// Without Columns, the query gives a value for every column of the table.
+func (b *InsertBuilder) FromSelect(q *SelectBuilder) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if q == nil {
+		b.err = errors.New("FromSelect: query must not be nil")
+		return b
+	}
+	b.query = q
+	return b
+}
+
+// With adds a common table expression named name, which the statement reads like a table. body
+// is a *SelectBuilder or, on Postgres, an INSERT, UPDATE, or DELETE builder with Returning,
+// whose returned rows the statement reads. On MySQL, the WITH clause of an insert is written
+// before its FromSelect query.
+//
+// Example usage:
+//
+//	moved := sqltk.Delete("events").WhereLessThan("created_at", cutoff).Returning("*")
+//	q := sqltk.Insert("events_archive").With("moved", moved).
+//		FromSelect(sqltk.Select().From("moved"))
+//	// WITH "moved" AS (DELETE FROM "events" WHERE created_at < $1 RETURNING *)
+//	//   INSERT INTO "events_archive" SELECT * FROM "moved"
+func (b *InsertBuilder) With(name string, body Builder) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.err = b.with.add(name, body)
+	return b
+}
+
+// ApplyIf calls fn with the builder only if cond is true.
+func (b *InsertBuilder) ApplyIf(cond bool, fn func(*InsertBuilder) *InsertBuilder) *InsertBuilder {
+	if !cond || fn == nil {
+		return b
+	}
+	if next := fn(b); next != nil {
+		return next
+	}
+	return b
+}
+
+// Returning adds columns to a RETURNING clause, which returns them from the inserted rows.
+// A plain column name is quoted for the dialect; other strings, such as "*", and raw.Raw or
+// sqlfunc.SqlFunc expressions are written as-is. Dialects without RETURNING for INSERT, such as
+// MySQL, are handled as set with OnUnsupported; see sqldialect.SupportsReturning.
+func (b *InsertBuilder) Returning(cols ...interface{}) *InsertBuilder {
+	if err := checkReturning(cols); err != nil {
+		b.err = err
+		return b
+	}
+	b.returning = append(b.returning, cols...)
+	return b
+}
+
 // WithDialect sets the dialect for this builder instance.
 func (b *InsertBuilder) WithDialect(d sqldialect.Dialect) *InsertBuilder {
 	b.dialect = d
 	return b
 }
 
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (b *InsertBuilder) GetDialect() sqldialect.Dialect {
+	if b.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return b.dialect
+}
+
 // Build builds the SQL INSERT query and returns the query string, arguments, and error if any.
 func (b *InsertBuilder) Build() (string, []interface{}, error) {
 	if b.err != nil {
@@ -58,34 +179,113 @@ func (b *InsertBuilder) Build() (string, []interface{}, error) {
 	if b.table == "" {
 		return "", nil, errors.New("Insert: table must be set")
 	}
-	if len(b.columns) == 0 {
+	if b.defaults {
+		if len(b.columns) > 0 || len(b.values) > 0 {
+			return "", nil, errors.New("DefaultValues: cannot be combined with Columns or Values")
+		}
+		if b.idemKey != nil {
+			return "", nil, errors.New("DefaultValues: cannot be combined with IdempotencyKey")
+		}
+		if b.query != nil {
+			return "", nil, errors.New("DefaultValues: cannot be combined with FromSelect")
+		}
+	} else if b.query != nil {
+		if len(b.values) > 0 {
+			return "", nil, errors.New("FromSelect: cannot be combined with Values")
+		}
+	} else if len(b.columns) == 0 {
 		return "", nil, errors.New("Insert: columns must be set")
-	}
-	if len(b.values) == 0 {
+	} else if len(b.values) == 0 {
 		return "", nil, errors.New("Insert: at least one row of values must be set")
 	}
+	columns, values := b.columns, b.values
+	if b.idemKey != nil {
+		if len(values) != 1 {
+			return "", nil, errors.New("IdempotencyKey: the insert must have exactly one row")
+		}
+		if slices.Contains(columns, b.idemKey.column) {
+			return "", nil, fmt.Errorf("IdempotencyKey: column %q is already inserted", b.idemKey.column)
+		}
+		columns = append(columns[:len(columns):len(columns)], b.idemKey.column)
+		values = [][]interface{}{append(values[0][:len(values[0]):len(values[0])], b.idemKey.key)}
+	}
+	if b.schema != nil {
+		if err := b.schema.checkInsert(b); err != nil {
+			return "", nil, err
+		}
+	}
 
 	dialect := b.dialect
 	if dialect == nil {
 		dialect = sqldialect.GetDialect()
 	}
-	placeholderIdx := 1
+	out := dialect
+	dialect = renderDialect(dialect)
+
+	verb, doNothing, err := b.insertVerb(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	withSQL, withArgs, err := b.with.build(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	mysql := baseDialect(dialect) == sqldialect.MySQL()
+	if withSQL != "" && mysql && b.query == nil {
+		return "", nil, errors.New("With: MySQL requires FromSelect")
+	}
 
 	var sb strings.Builder
 	args := make([]interface{}, 0, len(b.values)*len(b.columns))
+	if !mysql {
+		sb.WriteString(withSQL)
+		args = append(args, withArgs...)
+	}
 
-	sb.WriteString("INSERT INTO ")
-	sb.WriteString(dialect.QuoteIdent(b.table))
-	sb.WriteString(" (")
-	for i, col := range b.columns {
-		if i > 0 {
-			sb.WriteString(", ")
+	sb.WriteString(verb)
+	sb.WriteString(quoteQualifiedIdent(dialect, b.table))
+	switch {
+	case b.defaults:
+		if mysql {
+			sb.WriteString(" () VALUES ()")
+		} else {
+			sb.WriteString(" DEFAULT VALUES")
+		}
+	case b.query != nil:
+		if len(columns) > 0 {
+			sb.WriteString(" (")
+			for i, col := range columns {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(dialect.QuoteIdent(col))
+			}
+			sb.WriteString(")")
+		}
+		querySQL, queryArgs, err := buildPositional(b.query, dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("FromSelect: %w", err)
+		}
+		sb.WriteString(" ")
+		if mysql {
+			sb.WriteString(withSQL)
+			args = append(args, withArgs...)
 		}
-		sb.WriteString(dialect.QuoteIdent(col))
+		sb.WriteString(querySQL)
+		args = append(args, queryArgs...)
+	default:
+		sb.WriteString(" (")
+		for i, col := range columns {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(dialect.QuoteIdent(col))
+		}
+		sb.WriteString(") VALUES ")
 	}
-	sb.WriteString(") VALUES ")
 
-	for i, row := range b.values {
+	for i, row := range values {
 		if i > 0 {
 			sb.WriteString(", ")
 		}
@@ -94,14 +294,138 @@ func (b *InsertBuilder) Build() (string, []interface{}, error) {
 			if j > 0 {
 				sb.WriteString(", ")
 			}
-			sb.WriteString(dialect.Placeholder(placeholderIdx))
-			placeholderIdx++
+			if binder, ok := row[j].(Binder); ok {
+				bindSQL, bindArgs, err := bindExpr(dialect, binder)
+				if err != nil {
+					return "", nil, err
+				}
+				sb.WriteString(bindSQL)
+				args = append(args, bindArgs...)
+				continue
+			}
+			sb.WriteString("?")
 			args = append(args, row[j])
 		}
 		sb.WriteString(")")
 	}
 
-	return sb.String(), args, nil
+	if b.conflict != nil {
+		conflictSQL, conflictArgs, err := b.buildConflict(dialect, columns)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(conflictSQL)
+		args = append(args, conflictArgs...)
+	}
+	if doNothing {
+		sb.WriteString(" ON CONFLICT DO NOTHING")
+	}
+
+	returning, err := returningClause(dialect, b.unsupported, "INSERT", b.returning)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(returning)
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, b.reuseArgs)
+	placeholderIdx := 1
+	return numberPlaceholders(out, sb.String(), &placeholderIdx, numbers), args, nil
+}
+
+// BuildBatches builds the insert as statements of at most maxRows rows each, and of no more
+// placeholders than the dialect allows (see sqldialect.MaxPlaceholdersOf), so that any number
+// of rows can be inserted. With maxRows 0, only the placeholder limit applies. The statements
+// are not atomic together; run them in a transaction to insert all rows or none.
+//
+// Example usage:
+//
+//	q := sqltk.Insert("events").Columns("id", "name").Rows(rows...)
+//	stmts, err := q.BuildBatches(1000)
+//	for _, stmt := range stmts {
+//		_, err = tx.Exec(stmt.SQL, stmt.Args...)
+//	}
+func (b *InsertBuilder) BuildBatches(maxRows int) ([]*Statement, error) {
+	return b.buildBatches(maxRows, func(chunk *InsertBuilder) (string, []interface{}, error) {
+		return chunk.Build()
+	})
+}
+
+// buildBatches splits the rows of b into chunks, see BuildBatches, and builds each with build.
+func (b *InsertBuilder) buildBatches(maxRows int, build func(*InsertBuilder) (string, []interface{}, error)) ([]*Statement, error) {
+	if maxRows < 0 {
+		return nil, errors.New("BuildBatches: maxRows must not be negative")
+	}
+	if b.err != nil || len(b.values) == 0 {
+		sql, args, err := build(b)
+		if err != nil {
+			return nil, err
+		}
+		return []*Statement{{SQL: sql, Args: args}}, nil
+	}
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	limit := sqldialect.MaxPlaceholdersOf(baseDialect(dialect))
+
+	// The placeholders outside the rows, e.g. of ON CONFLICT DO UPDATE, are in every statement.
+	probe := *b
+	probe.values, probe.reuseArgs = b.values[:1], false
+	_, args, err := build(&probe)
+	if err != nil {
+		return nil, err
+	}
+	fixed := len(args) - rowPlaceholders(dialect, b.values[0])
+
+	var stmts []*Statement
+	start, count := 0, fixed
+	flush := func(end int) error {
+		chunk := *b
+		chunk.values = b.values[start:end]
+		sql, args, err := build(&chunk)
+		if err != nil {
+			return err
+		}
+		stmts = append(stmts, &Statement{SQL: sql, Args: args})
+		start, count = end, fixed
+		return nil
+	}
+	for i, row := range b.values {
+		n := rowPlaceholders(dialect, row)
+		if fixed+n > limit {
+			return nil, fmt.Errorf("BuildBatches: row %d needs %d placeholders, more than the %d allowed", i+1, fixed+n, limit)
+		}
+		if i > start && (count+n > limit || maxRows > 0 && i-start == maxRows) {
+			if err := flush(i); err != nil {
+				return nil, err
+			}
+		}
+		count += n
+	}
+	if err := flush(len(b.values)); err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+// rowPlaceholders returns the number of placeholders of a row of values.
+func rowPlaceholders(dialect sqldialect.Dialect, row []interface{}) int {
+	n := 0
+	for _, v := range row {
+		if binder, ok := v.(Binder); ok {
+			_, args, _ := bindExpr(dialect, binder) // checked by build
+			n += len(args)
+			continue
+		}
+		n++
+	}
+	return n
 }
 
 // PostgresInsertBuilder extends InsertBuilder with RETURNING support for Postgres.
@@ -123,23 +447,40 @@ func (b *PostgresInsertBuilder) Returning(cols ...string) *PostgresInsertBuilder
 
 // Build builds the SQL INSERT query with RETURNING (if set) and returns the query string, arguments, and error if any.
 func (b *PostgresInsertBuilder) Build() (string, []interface{}, error) {
-	sql, args, err := b.InsertBuilder.Build()
-	if err != nil {
-		return sql, args, err
-	}
-	if len(b.returning) > 0 {
-		sql += " RETURNING " + strings.Join(b.returning, ", ")
-	}
-	return sql, args, nil
+	c := *b.InsertBuilder
+	c.returning = append(c.returning[:len(c.returning):len(c.returning)], rawColumns(b.returning)...)
+	return c.Build()
+}
+
+// BuildBatches builds the insert with RETURNING (if set) as several statements, see
+// InsertBuilder.BuildBatches.
+func (b *PostgresInsertBuilder) BuildBatches(maxRows int) ([]*Statement, error) {
+	return b.buildBatches(maxRows, func(chunk *InsertBuilder) (string, []interface{}, error) {
+		pq := *b
+		pq.InsertBuilder = chunk
+		return pq.Build()
+	})
+}
+
+// BuildTo builds the statement with RETURNING (if set) and writes its SQL to w, returning the
+// args. See BuildTo.
+func (b *PostgresInsertBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
 
 // Example usage:
 //   pq := sq.NewPostgresInsert("users").Columns("name").Values("Alice").Returning("id")
 //   sql, args, err := pq.Build()
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *InsertBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. See BuildTo.
+func (b *InsertBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
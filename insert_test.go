@@ -81,7 +81,7 @@ func TestInsertBuilder(t *testing.T) {
 	t.Run("debugsql single row", func(t *testing.T) {
 		q := Insert("users").Columns("id", "name").Values(1, "Alice")
 		got := q.DebugSQL()
-		want := "INSERT INTO users (id, name) VALUES (1, 'Alice')"
+		want := "/* sqltk debug SQL, do not execute */ INSERT INTO users (id, name) VALUES (1, 'Alice')"
 		if got != want {
 			t.Errorf("DebugSQL got %q, want %q", got, want)
 		}
@@ -90,7 +90,16 @@ func TestInsertBuilder(t *testing.T) {
 	t.Run("debugsql multi row", func(t *testing.T) {
 		q := Insert("users").Columns("id", "name").Values(1, "Alice").Values(2, "Bob")
 		got := q.DebugSQL()
-		want := "INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')"
+		want := "/* sqltk debug SQL, do not execute */ INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')"
+		if got != want {
+			t.Errorf("DebugSQL got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("debugsql postgres", func(t *testing.T) {
+		q := Insert("users").Columns("name", "active").Values("O'Brien", true).WithDialect(sqldialect.Postgres())
+		got := q.DebugSQL()
+		want := `/* sqltk debug SQL, do not execute */ INSERT INTO "users" ("name", "active") VALUES ('O''Brien', TRUE)`
 		if got != want {
 			t.Errorf("DebugSQL got %q, want %q", got, want)
 		}
@@ -172,3 +181,199 @@ func TestPostgresInsertBuilder_PGArray(t *testing.T) {
 		t.Errorf("expected array %v, got %v", want, got)
 	}
 }
+
+// placeholderLimitDialect is a dialect allowing few placeholders per statement.
+type placeholderLimitDialect struct {
+	sqldialect.Dialect
+}
+
+func (placeholderLimitDialect) MaxPlaceholders() int { return 7 }
+
+func TestInsertBuilder_BuildBatches(t *testing.T) {
+	rows := [][]interface{}{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}, {5, "e"}}
+
+	t.Run("max rows", func(t *testing.T) {
+		stmts, err := Insert("t").Columns("id", "name").Rows(rows...).WithDialect(sqldialect.Postgres()).BuildBatches(2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []*Statement{
+			{SQL: `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4)`, Args: []interface{}{1, "a", 2, "b"}},
+			{SQL: `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4)`, Args: []interface{}{3, "c", 4, "d"}},
+			{SQL: `INSERT INTO "t" ("id", "name") VALUES ($1, $2)`, Args: []interface{}{5, "e"}},
+		}
+		if !reflect.DeepEqual(stmts, want) {
+			t.Errorf("got %+v, want %+v", stmts, want)
+		}
+	})
+
+	t.Run("placeholder limit", func(t *testing.T) {
+		stmts, err := Insert("t").Columns("id", "name").Rows(rows...).
+			WithDialect(placeholderLimitDialect{sqldialect.NoQuoteIdent()}).BuildBatches(0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stmts) != 2 || len(stmts[0].Args) != 6 || len(stmts[1].Args) != 4 {
+			t.Errorf("got %+v, want batches of 3 and 2 rows", stmts)
+		}
+	})
+
+	t.Run("returning", func(t *testing.T) {
+		pq := NewPostgresInsert("t").Returning("id")
+		pq.Columns("id", "name").Rows(rows[:3]...)
+		stmts, err := pq.BuildBatches(2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stmts) != 2 || stmts[1].SQL != `INSERT INTO "t" ("id", "name") VALUES ($1, $2) RETURNING id` {
+			t.Errorf("got %+v", stmts)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := Insert("t").Columns("id", "name").Rows([]interface{}{1}).BuildBatches(2); err == nil {
+			t.Error("expected error for a short row")
+		}
+		if _, err := Insert("t").Columns("id", "name").BuildBatches(2); err == nil {
+			t.Error("expected error for no rows")
+		}
+		wide := Insert("t").Columns("a", "b", "c", "d", "e", "f", "g", "h").Values(1, 2, 3, 4, 5, 6, 7, 8)
+		if _, err := wide.WithDialect(placeholderLimitDialect{sqldialect.NoQuoteIdent()}).BuildBatches(0); err == nil {
+			t.Error("expected error for a row over the placeholder limit")
+		}
+	})
+}
+
+type insertAccount struct {
+	ID      int64  `db:"id,auto"`
+	Email   string `db:"email"`
+	Plan    string `db:"plan,omitempty"`
+	Visits  int
+	Comment string `db:"-"`
+}
+
+func TestInsertStruct(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *InsertBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "auto and empty fields",
+			builder:  InsertStruct("accounts", &insertAccount{ID: 7, Email: "a@example.com", Comment: "x"}),
+			wantSQL:  "INSERT INTO accounts (email, visits) VALUES (?, ?)",
+			wantArgs: []interface{}{"a@example.com", 0},
+		},
+		{
+			name:     "omitempty set",
+			builder:  InsertStruct("accounts", insertAccount{Email: "a@example.com", Plan: "pro", Visits: 2}),
+			wantSQL:  "INSERT INTO accounts (email, plan, visits) VALUES (?, ?, ?)",
+			wantArgs: []interface{}{"a@example.com", "pro", 2},
+		},
+		{
+			name: "slice",
+			builder: InsertStructs("accounts", []*insertAccount{
+				{Email: "a@example.com"},
+				{Email: "b@example.com", Plan: "pro"},
+			}),
+			wantSQL:  "INSERT INTO accounts (email, plan, visits) VALUES (?, ?, ?), (?, ?, ?)",
+			wantArgs: []interface{}{"a@example.com", "", 0, "b@example.com", "pro", 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		var nilAccount *insertAccount
+		for name, b := range map[string]*InsertBuilder{
+			"nil":          InsertStruct("accounts", nil),
+			"nil pointer":  InsertStruct("accounts", nilAccount),
+			"not a struct": InsertStruct("accounts", 1),
+			"slice":        InsertStruct("accounts", []insertAccount{{}}),
+			"empty slice":  InsertStructs("accounts", []insertAccount{}),
+			"not a slice":  InsertStructs("accounts", insertAccount{}),
+			"mixed types":  InsertStructs("accounts", []interface{}{insertAccount{}, typedUser{}}),
+			"no columns": InsertStruct("accounts", struct {
+				ID int `db:"id,auto"`
+			}{}),
+		} {
+			if _, _, err := b.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
+func TestInsertBuilder_DefaultValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:    "default values",
+			builder: Insert("events").DefaultValues(),
+			wantSQL: "INSERT INTO events DEFAULT VALUES",
+		},
+		{
+			name:    "default values on mysql",
+			builder: Insert("events").DefaultValues().WithDialect(sqldialect.MySQL()),
+			wantSQL: "INSERT INTO `events` () VALUES ()",
+		},
+		{
+			name:    "default values returning",
+			builder: Insert("events").DefaultValues().Returning("id").WithDialect(sqldialect.Postgres()),
+			wantSQL: `INSERT INTO "events" DEFAULT VALUES RETURNING "id"`,
+		},
+		{
+			name:     "default keyword",
+			builder:  Insert("users").Columns("id", "email").Values(Default, "a@example.com").Values(7, Default).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `INSERT INTO "users" ("id", "email") VALUES (DEFAULT, $1), ($2, DEFAULT)`,
+			wantArgs: []interface{}{"a@example.com", 7},
+		},
+		{
+			name:     "default in update",
+			builder:  Update("users").Set("status", Default).WhereEqual("id", 1),
+			wantSQL:  "UPDATE users SET status = DEFAULT WHERE id = ?",
+			wantArgs: []interface{}{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if len(args) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("got args %v, want %v", args, tt.wantArgs)
+				}
+			}
+		})
+	}
+
+	stmts, err := Insert("events").DefaultValues().BuildBatches(10)
+	if err != nil || len(stmts) != 1 || stmts[0].SQL != "INSERT INTO events DEFAULT VALUES" {
+		t.Errorf("got %+v, %v, want one DEFAULT VALUES statement", stmts, err)
+	}
+	if _, _, err := Insert("events").Columns("id").Values(1).DefaultValues().Build(); err == nil {
+		t.Error("expected error for DefaultValues with Values")
+	}
+}
@@ -0,0 +1,300 @@
+// Package sqllex splits SQL into tokens the way the databases of sqltk's dialects read it. It
+// is shared by sqltk and its packages, so that placeholder numbering, binding, normalizing,
+// formatting, linting, parsing and debug interpolation agree on where strings, quoted
+// identifiers, comments and placeholders start and end.
+package sqllex
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	Word    Kind = iota // keyword or bare identifier
+	Quoted              // "quoted" or `quoted` identifier
+	String              // 'string', E'string', or $tag$string$tag$
+	Number              // numeric literal
+	Param               // placeholder
+	Punct               // operator or punctuation
+	Comment             // -- comment, without its newline, or /* comment */
+)
+
+// Token is a token of SQL.
+type Token struct {
+	Kind Kind
+	Text string // as written
+	// Val is the value of a Quoted or String token, without its quotes and escapes, and the
+	// text of other tokens.
+	Val   string
+	Pos   int  // byte offset in the SQL
+	Space bool // whitespace comes before it
+	// Unterminated is set for a Quoted or String token or a comment that runs to the end of
+	// the SQL without its closing quote or */.
+	Unterminated bool
+}
+
+// Is reports whether t is the keyword kw, ignoring case.
+func (t Token) Is(kw string) bool {
+	return t.Kind == Word && strings.EqualFold(t.Text, kw)
+}
+
+// IsPunct reports whether t is the punctuation or operator p.
+func (t Token) IsPunct(p string) bool {
+	return t.Kind == Punct && t.Text == p
+}
+
+// ParamNumber returns the number n of a $n or :n placeholder, or 0 for ?.
+func (t Token) ParamNumber() int {
+	n, _ := strconv.Atoi(t.Text[1:])
+	return n
+}
+
+// operators are the multi-character operators, longest first.
+var operators = []string{"->>", "#>>", "<>", "!=", "<=", ">=", "||", "::", "->", "#>", "@>", "<@", "&&"}
+
+// Lexer reads SQL. The zero Lexer reads strings without backslash escapes, and ?, $n and :n
+// placeholders.
+type Lexer struct {
+	// Backslash reports whether a backslash escapes the next character of a string, as in
+	// MySQL, BigQuery and ClickHouse. Postgres E'...' strings always use backslash escapes.
+	Backslash bool
+	// BackquoteBackslash reports whether a backslash escapes the next character of a
+	// backquoted identifier, as in BigQuery and ClickHouse but not MySQL.
+	BackquoteBackslash bool
+	// Dialect, if set, limits placeholders to those of Dialect, so that a ? in SQL for
+	// Postgres is an operator.
+	Dialect sqldialect.Dialect
+}
+
+// For returns the Lexer of SQL written for dialect, which tells whether strings and
+// identifiers use backslash escapes from how dialect quotes them.
+func For(dialect sqldialect.Dialect) Lexer {
+	return Lexer{
+		Backslash:          strings.Contains(dialect.QuoteString(`\`), `\\`),
+		BackquoteBackslash: strings.Contains(dialect.QuoteIdent(`\`), `\\`),
+		Dialect:            dialect,
+	}
+}
+
+// Skip returns the index just past the quoted string, dollar-quoted string, quoted identifier
+// or comment starting at sql[start], or start if there is none. A quote preceded by a lone E
+// starts a Postgres E'...' string.
+func (l Lexer) Skip(sql string, start int) int {
+	switch c := sql[start]; {
+	case c == '\'' || c == '"' || c == '`':
+		backslash := l.backslash(c)
+		if c == '\'' && start > 0 && (sql[start-1] == 'E' || sql[start-1] == 'e') && (start == 1 || !isWordByte(sql[start-2])) {
+			backslash = true
+		}
+		end, _, _ := scanQuoted(sql, start, backslash)
+		return end
+	case c == '$':
+		if start > 0 && (isWordByte(sql[start-1]) || sql[start-1] == '$') {
+			return start
+		}
+		end, _, _ := scanDollarQuoted(sql, start)
+		return end
+	case c == '-' || c == '/':
+		end, _ := scanComment(sql, start)
+		return end
+	}
+	return start
+}
+
+// Tokens splits sql into tokens, dropping whitespace. Comments are kept as Comment tokens.
+func (l Lexer) Tokens(sql string) []Token {
+	var toks []Token
+	space := false
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			space = true
+			i++
+			continue
+		}
+		t := Token{Kind: Punct, Pos: i, Space: space}
+		end := i + 1
+		switch {
+		case c == '-' && strings.HasPrefix(sql[i:], "--") || c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			var ok bool
+			end, ok = scanComment(sql, i)
+			t.Kind, t.Unterminated = Comment, !ok
+		case c == '\'' || c == '"' || c == '`':
+			var ok bool
+			end, t.Val, ok = scanQuoted(sql, i, l.backslash(c))
+			t.Kind, t.Unterminated = Quoted, !ok
+			if c == '\'' {
+				t.Kind = String
+			}
+		case (c == 'E' || c == 'e') && i+1 < len(sql) && sql[i+1] == '\'':
+			var ok bool
+			end, t.Val, ok = scanQuoted(sql, i+1, true)
+			t.Kind, t.Unterminated = String, !ok
+		case isDigit(c) || c == '.' && i+1 < len(sql) && isDigit(sql[i+1]):
+			end = scanNumber(sql, i)
+			t.Kind = Number
+		case isWordStart(c):
+			for end < len(sql) && isWordByte(sql[end]) {
+				end++
+			}
+			t.Kind = Word
+		case c == '?' && l.positional():
+			t.Kind = Param
+		case (c == '$' || c == ':') && i+1 < len(sql) && isDigit(sql[i+1]) && l.numbered(sql, i):
+			for end < len(sql) && isDigit(sql[end]) {
+				end++
+			}
+			t.Kind = Param
+		case c == '$':
+			if n, val, ok := scanDollarQuoted(sql, i); n > i {
+				end, t.Val = n, val
+				t.Kind, t.Unterminated = String, !ok
+			}
+		default:
+			for _, op := range operators {
+				if strings.HasPrefix(sql[i:], op) {
+					end = i + len(op)
+					break
+				}
+			}
+		}
+		t.Text = sql[i:end]
+		if t.Kind != Quoted && t.Kind != String {
+			t.Val = t.Text
+		}
+		toks = append(toks, t)
+		space = false
+		i = end
+	}
+	return toks
+}
+
+// backslash reports whether a backslash is an escape in a string or identifier quoted with q.
+func (l Lexer) backslash(q byte) bool {
+	switch q {
+	case '\'':
+		return l.Backslash
+	case '`':
+		return l.BackquoteBackslash
+	}
+	return false
+}
+
+// positional reports whether ? is a placeholder.
+func (l Lexer) positional() bool {
+	return l.Dialect == nil || l.Dialect.Placeholder(1) == "?"
+}
+
+// numbered reports whether the $n or :n at sql[start] is a placeholder.
+func (l Lexer) numbered(sql string, start int) bool {
+	if l.Dialect == nil {
+		return true
+	}
+	end := start + 1
+	for end < len(sql) && isDigit(sql[end]) {
+		end++
+	}
+	n, err := strconv.Atoi(sql[start+1 : end])
+	return err == nil && l.Dialect.Placeholder(n) == sql[start:end]
+}
+
+// scanQuoted scans the string or quoted identifier at sql[start], where a doubled quote stands
+// for the quote and, if backslash is set, a backslash escapes the next character. It returns
+// the end offset, the unquoted value, and whether the closing quote was found.
+func scanQuoted(sql string, start int, backslash bool) (int, string, bool) {
+	q := sql[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\\' && backslash && i+1 < len(sql):
+			i++
+			sb.WriteByte(sql[i])
+		case c == q && i+1 < len(sql) && sql[i+1] == q:
+			i++
+			sb.WriteByte(q)
+		case c == q:
+			return i + 1, sb.String(), true
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return len(sql), sb.String(), false
+}
+
+// scanDollarQuoted scans the dollar-quoted string ($$...$$ or $tag$...$tag$) at sql[start].
+// It returns start if there is none: a tag cannot start with a digit, so $1 is not one.
+func scanDollarQuoted(sql string, start int) (int, string, bool) {
+	j := start + 1
+	for j < len(sql) && isWordByte(sql[j]) && sql[j] != '$' && (j > start+1 || !isDigit(sql[j])) {
+		j++
+	}
+	if j >= len(sql) || sql[j] != '$' {
+		return start, "", false
+	}
+	tag := sql[start : j+1]
+	end := strings.Index(sql[j+1:], tag)
+	if end < 0 {
+		return len(sql), sql[j+1:], false
+	}
+	return j + 1 + end + len(tag), sql[j+1 : j+1+end], true
+}
+
+// scanComment scans the -- or /* comment at sql[start], returning start if there is none. A
+// -- comment ends before its newline.
+func scanComment(sql string, start int) (int, bool) {
+	switch {
+	case strings.HasPrefix(sql[start:], "--"):
+		if end := strings.IndexByte(sql[start:], '\n'); end >= 0 {
+			return start + end, true
+		}
+		return len(sql), true
+	case strings.HasPrefix(sql[start:], "/*"):
+		if end := strings.Index(sql[start+2:], "*/"); end >= 0 {
+			return start + 2 + end + 2, true
+		}
+		return len(sql), false
+	}
+	return start, true
+}
+
+func scanNumber(sql string, i int) int {
+	for i < len(sql) && isDigit(sql[i]) {
+		i++
+	}
+	if i < len(sql) && sql[i] == '.' {
+		i++
+		for i < len(sql) && isDigit(sql[i]) {
+			i++
+		}
+	}
+	if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+		j := i + 1
+		if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+			j++
+		}
+		if j < len(sql) && isDigit(sql[j]) {
+			i = j
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isWordByte(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$'
+}
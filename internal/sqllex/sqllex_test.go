@@ -0,0 +1,112 @@
+package sqllex
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// texts returns the texts of toks.
+func texts(toks []Token) []string {
+	var out []string
+	for _, t := range toks {
+		out = append(out, t.Text)
+	}
+	return out
+}
+
+func TestTokens(t *testing.T) {
+	t.Run("words, operators and placeholders", func(t *testing.T) {
+		toks := Lexer{}.Tokens("SELECT u.id, data->>'name' FROM users u WHERE id <> ? AND n >= $2 AND x::int = :3")
+		want := []string{"SELECT", "u", ".", "id", ",", "data", "->>", "'name'", "FROM", "users", "u", "WHERE",
+			"id", "<>", "?", "AND", "n", ">=", "$2", "AND", "x", "::", "int", "=", ":3"}
+		if got := texts(toks); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens %q, want %q", got, want)
+		}
+		if toks[14].Kind != Param || toks[18].Kind != Param || toks[18].ParamNumber() != 2 || toks[24].ParamNumber() != 3 {
+			t.Errorf("got placeholders %+v %+v %+v", toks[14], toks[18], toks[24])
+		}
+	})
+
+	t.Run("placeholders of a dialect", func(t *testing.T) {
+		toks := For(sqldialect.Postgres()).Tokens("data ? 'a' AND id = $1")
+		if toks[1].Kind != Punct || toks[6].Kind != Param {
+			t.Errorf("got tokens %+v", toks)
+		}
+		toks = For(sqldialect.MySQL()).Tokens("id = $1 AND n = ?")
+		if toks[2].Kind != Punct || toks[7].Kind != Param {
+			t.Errorf("got tokens %+v", toks)
+		}
+	})
+
+	t.Run("strings and identifiers", func(t *testing.T) {
+		toks := Lexer{}.Tokens(`'it''s' "a""b" 'C:\' E'x\'y' $$a'b$$ $fn$ c $fn$`)
+		want := []string{"it's", `a"b`, `C:\`, "x'y", "a'b", " c "}
+		var got []string
+		for _, tok := range toks {
+			got = append(got, tok.Val)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got values %q, want %q", got, want)
+		}
+	})
+
+	t.Run("backslash escapes", func(t *testing.T) {
+		toks := For(sqldialect.BigQuery()).Tokens(`'it\'s ?' "a\" ?`)
+		want := []string{`'it\'s ?'`, `"a\"`, "?"}
+		if got := texts(toks); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens %q, want %q", got, want)
+		}
+		toks = For(sqldialect.MySQL()).Tokens("'it\\'s ?' `a\\` ?")
+		want = []string{`'it\'s ?'`, "`a\\`", "?"}
+		if got := texts(toks); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens %q, want %q", got, want)
+		}
+	})
+
+	t.Run("comments and spacing", func(t *testing.T) {
+		toks := Lexer{}.Tokens("a -- x ?\n/* ? */b(c)")
+		want := []string{"a", "-- x ?", "/* ? */", "b", "(", "c", ")"}
+		if got := texts(toks); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens %q, want %q", got, want)
+		}
+		if toks[1].Kind != Comment || !toks[1].Space || !toks[2].Space || toks[3].Space || toks[4].Space {
+			t.Errorf("got tokens %+v", toks)
+		}
+	})
+
+	t.Run("unterminated", func(t *testing.T) {
+		for _, sql := range []string{"'abc", `"abc`, "/* abc", "$x$ abc"} {
+			toks := Lexer{}.Tokens(sql)
+			if len(toks) != 1 || !toks[0].Unterminated {
+				t.Errorf("%q: got tokens %+v", sql, toks)
+			}
+		}
+	})
+}
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		lexer Lexer
+		sql   string
+		start int
+		want  int
+	}{
+		{Lexer{}, `'a''b' x`, 0, 6},
+		{Lexer{}, `'C:\' x`, 0, 5},
+		{Lexer{Backslash: true}, `'C:\' x'`, 0, 8},
+		{Lexer{}, `E'C:\' x'`, 1, 9},
+		{Lexer{}, "$$a$$ x", 0, 5},
+		{Lexer{}, "$1 x", 0, 0},
+		{Lexer{}, "a$b$ x", 1, 1},
+		{Lexer{}, "-- a\nb", 0, 4},
+		{Lexer{}, "/* a */b", 0, 7},
+		{Lexer{}, "- a", 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.lexer.Skip(tt.sql, tt.start); got != tt.want {
+			t.Errorf("Skip(%q, %d) = %d, want %d", tt.sql, tt.start, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package sqltk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// JSONContains adds a condition that the JSON document in column contains value, with value
+// encoded by encoding/json and bound as an arg. Like Regexp, it is built with the dialect of
+// the statement it is added to: column @> ?::jsonb on Postgres, json_contains(column, ?) on
+// DuckDB, and JSON_CONTAINS(column, ?) on MySQL and NoQuoteIdent. Build returns an error for
+// other dialects, such as BigQuery, which have no JSON containment. Pass a json.RawMessage to
+// bind JSON text as is.
+//
+// Example usage:
+//
+//	NewCond().JSONContains("data", map[string]interface{}{"tags": []string{"go"}})
+//	// data @> ?::jsonb, with the arg {"tags":["go"]}
+func (c *ConditionBuilder) JSONContains(column string, value interface{}) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	doc, err := json.Marshal(value)
+	if err != nil {
+		c.err = fmt.Errorf("JSONContains: %w", err)
+		return c
+	}
+	c.add("?", jsonContainsArg{column: column, doc: string(doc)})
+	return c
+}
+
+// jsonContainsArg is the condition of JSONContains, bound in place of a ? of a condition like
+// a subqueryArg.
+type jsonContainsArg struct {
+	column string
+	doc    string
+}
+
+// checkBind implements bindChecker.
+func (a jsonContainsArg) checkBind(dialect sqldialect.Dialect) error {
+	switch dialect {
+	case sqldialect.Postgres(), sqldialect.DuckDB(), sqldialect.MySQL(), sqldialect.NoQuoteIdent():
+		return nil
+	}
+	return fmt.Errorf("JSONContains: JSON containment is not supported by this dialect")
+}
+
+// BindSQL implements Binder.
+func (a jsonContainsArg) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	col := quoteQualifiedIdent(dialect, a.column)
+	switch dialect {
+	case sqldialect.Postgres():
+		return col + " @> ?::jsonb", []interface{}{a.doc}
+	case sqldialect.DuckDB():
+		return "json_contains(" + col + ", ?)", []interface{}{a.doc}
+	}
+	return "JSON_CONTAINS(" + col + ", ?)", []interface{}{a.doc}
+}
+
+// JSONPathEquals adds a condition that the scalar at path in the JSON document in column
+// equals value, which is bound as an arg. The path is a JSONPath of keys and array indexes,
+// such as $.a.b or $.items[0].id, and is written into the SQL as a literal, so that indexes
+// on the expression can be used. Like Regexp, the condition is built with the dialect of the
+// statement it is added to: column #>> '{a,b}' = ? on Postgres,
+// json_extract_string(column, '$.a.b') = ? on DuckDB, JSON_VALUE(column, '$.a.b') = ? on
+// BigQuery, Oracle and ClickHouse, and JSON_EXTRACT(column, '$.a.b') = ? on MySQL and
+// NoQuoteIdent. Build returns an error for other dialects. On Postgres the value is compared
+// as text.
+func (c *ConditionBuilder) JSONPathEquals(column, path string, value interface{}) *ConditionBuilder {
+	if c.err != nil {
+		return c
+	}
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		c.err = fmt.Errorf("JSONPathEquals: %w", err)
+		return c
+	}
+	c.add("?", jsonPathArg{column: column, path: path, steps: steps, value: value})
+	return c
+}
+
+// jsonPathArg is the condition of JSONPathEquals, bound in place of a ? of a condition like a
+// subqueryArg.
+type jsonPathArg struct {
+	column string
+	path   string
+	steps  []string // the keys and indexes of path
+	value  interface{}
+}
+
+// checkBind implements bindChecker.
+func (a jsonPathArg) checkBind(dialect sqldialect.Dialect) error {
+	switch dialect {
+	case sqldialect.Postgres(), sqldialect.DuckDB(), sqldialect.BigQuery(), sqldialect.Oracle(),
+		sqldialect.ClickHouse(), sqldialect.MySQL(), sqldialect.NoQuoteIdent():
+		return nil
+	}
+	return fmt.Errorf("JSONPathEquals: JSON paths are not supported by this dialect")
+}
+
+// BindSQL implements Binder.
+func (a jsonPathArg) BindSQL(dialect sqldialect.Dialect) (string, []interface{}) {
+	col := quoteQualifiedIdent(dialect, a.column)
+	var sql string
+	switch dialect {
+	case sqldialect.Postgres():
+		sql = col + " #>> " + dialect.QuoteString("{"+strings.Join(a.steps, ",")+"}")
+	case sqldialect.DuckDB():
+		sql = "json_extract_string(" + col + ", " + dialect.QuoteString(a.path) + ")"
+	case sqldialect.BigQuery(), sqldialect.Oracle(), sqldialect.ClickHouse():
+		sql = "JSON_VALUE(" + col + ", " + dialect.QuoteString(a.path) + ")"
+	default:
+		sql = "JSON_EXTRACT(" + col + ", " + dialect.QuoteString(a.path) + ")"
+	}
+	if binder, ok := a.value.(Binder); ok {
+		valueSQL, args := binder.BindSQL(dialect)
+		return sql + " = " + valueSQL, args
+	}
+	return sql + " = ?", []interface{}{a.value}
+}
+
+// parseJSONPath checks a JSONPath of the form $.key[0].key and returns its keys and indexes.
+// Keys are limited to letters, digits, and underscores, so that the path needs no quoting in
+// any dialect.
+func parseJSONPath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+	var steps []string
+	rest := path[1:]
+	for rest != "" {
+		var step string
+		switch rest[0] {
+		case '.':
+			end := 1
+			for end < len(rest) && isWordChar(rest[end]) {
+				end++
+			}
+			step, rest = rest[1:end], rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed [", path)
+			}
+			step, rest = rest[1:end], rest[end+1:]
+			if strings.TrimLeft(step, "0123456789") != "" {
+				return nil, fmt.Errorf("path %q has an invalid array index %q", path, step)
+			}
+		default:
+			return nil, fmt.Errorf("path %q has an invalid step at %q", path, rest)
+		}
+		if step == "" {
+			return nil, fmt.Errorf("path %q has an empty step", path)
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("path %q must have at least one step", path)
+	}
+	return steps, nil
+}
@@ -0,0 +1,139 @@
+package sqltk
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestConditionBuilder_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     *ConditionBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "contains postgres",
+			cond:     NewCond().WithDialect(sqldialect.Postgres()).JSONContains("data", map[string]interface{}{"tags": []string{"go"}}),
+			wantSQL:  `"data" @> ?::jsonb`,
+			wantArgs: []interface{}{`{"tags":["go"]}`},
+		},
+		{
+			name:     "contains mysql",
+			cond:     NewCond().WithDialect(sqldialect.MySQL()).JSONContains("data", json.RawMessage(`"go"`)),
+			wantSQL:  "JSON_CONTAINS(`data`, ?)",
+			wantArgs: []interface{}{`"go"`},
+		},
+		{
+			name:     "contains duckdb",
+			cond:     NewCond().WithDialect(sqldialect.DuckDB()).JSONContains("data", 1),
+			wantSQL:  `json_contains("data", ?)`,
+			wantArgs: []interface{}{"1"},
+		},
+		{
+			name:     "path equals mysql",
+			cond:     NewCond().WithDialect(sqldialect.MySQL()).JSONPathEquals("data", "$.a.b", "x"),
+			wantSQL:  "JSON_EXTRACT(`data`, '$.a.b') = ?",
+			wantArgs: []interface{}{"x"},
+		},
+		{
+			name:     "path equals postgres",
+			cond:     NewCond().WithDialect(sqldialect.Postgres()).JSONPathEquals("events.data", "$.items[0].id", 7),
+			wantSQL:  `"events"."data" #>> '{items,0,id}' = ?`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "path equals duckdb",
+			cond:     NewCond().WithDialect(sqldialect.DuckDB()).JSONPathEquals("data", "$.a", "x"),
+			wantSQL:  `json_extract_string("data", '$.a') = ?`,
+			wantArgs: []interface{}{"x"},
+		},
+		{
+			name:     "path equals bigquery",
+			cond:     NewCond().WithDialect(sqldialect.BigQuery()).JSONPathEquals("data", "$.a", "x"),
+			wantSQL:  "JSON_VALUE(`data`, '$.a') = ?",
+			wantArgs: []interface{}{"x"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.cond.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("where postgres", func(t *testing.T) {
+		cond := NewCond().WithDialect(sqldialect.Postgres()).
+			JSONContains("data", map[string]string{"plan": "pro"}).
+			JSONPathEquals("data", "$.owner.id", 7)
+		sql, _, err := Select("id").From("accounts").Where(cond).WithDialect(sqldialect.Postgres()).Build()
+		want := `SELECT "id" FROM "accounts" WHERE "data" @> $1::jsonb AND "data" #>> '{owner,id}' = $2`
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+	})
+
+	t.Run("statement dialect", func(t *testing.T) {
+		tests := []struct {
+			dialect sqldialect.Dialect
+			wantSQL string
+		}{
+			{sqldialect.Postgres(), `SELECT "id" FROM "accounts" WHERE "data" @> $1::jsonb AND "data" #>> '{owner,id}' = $2`},
+			{sqldialect.MySQL(), "SELECT `id` FROM `accounts` WHERE JSON_CONTAINS(`data`, ?) AND JSON_EXTRACT(`data`, '$.owner.id') = ?"},
+			{sqldialect.DuckDB(), `SELECT "id" FROM "accounts" WHERE json_contains("data", $1) AND json_extract_string("data", '$.owner.id') = $2`},
+		}
+		for _, tt := range tests {
+			cond := NewCond().JSONContains("data", map[string]string{"plan": "pro"}).JSONPathEquals("data", "$.owner.id", 7)
+			sql, _, err := Select("id").From("accounts").Where(cond).WithDialect(tt.dialect).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		}
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		for name, q := range map[string]*SelectBuilder{
+			"bigquery contains": Select("id").From("accounts").Where(NewCond().JSONContains("data", 1)).WithDialect(sqldialect.BigQuery()),
+			"oracle contains":   Select("id").From("accounts").Where(NewCond().JSONContains("data", 1)).WithDialect(sqldialect.Oracle()),
+			"custom path":       Select("id").From("accounts").Where(NewCond().JSONPathEquals("data", "$.a", 1)).WithDialect(customDialect{sqldialect.MySQL()}),
+		} {
+			if _, _, err := q.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for name, cond := range map[string]*ConditionBuilder{
+			"unencodable value": NewCond().JSONContains("data", func() {}),
+			"bigquery contains": NewCond().WithDialect(sqldialect.BigQuery()).JSONContains("data", 1),
+			"no $":              NewCond().JSONPathEquals("data", "a.b", 1),
+			"no steps":          NewCond().JSONPathEquals("data", "$", 1),
+			"wildcard":          NewCond().JSONPathEquals("data", "$.*", 1),
+			"quoting":           NewCond().JSONPathEquals("data", "$.a') OR ('1", 1),
+			"bad index":         NewCond().JSONPathEquals("data", "$.a[x]", 1),
+			"unclosed index":    NewCond().JSONPathEquals("data", "$.a[0", 1),
+		} {
+			if _, _, err := cond.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,469 @@
+// Package lint checks queries built with sqltk for patterns that are usually mistakes or slow
+// in production: SELECT *, UPDATE and DELETE without WHERE, cartesian joins, LIKE patterns
+// that start with a wildcard, and ORDER BY without LIMIT on large tables. Findings are
+// structured, so tests and CI can fail on them.
+//
+// Example usage:
+//
+//	findings, err := lint.Check(q, lint.Config{Production: true, LargeTables: []string{"events"}})
+//	if err != nil {
+//		return err
+//	}
+//	if err := findings.Err(lint.Warning); err != nil {
+//		t.Fatal(err)
+//	}
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Rule names a check.
+type Rule string
+
+const (
+	SelectStar      Rule = "select-star"         // SELECT * or t.*, reported in production mode
+	MissingWhere    Rule = "missing-where"       // UPDATE or DELETE without WHERE
+	CartesianJoin   Rule = "cartesian-join"      // CROSS JOIN, JOIN without ON or USING, or FROM a, b without WHERE
+	LeadingWildcard Rule = "leading-wildcard"    // LIKE pattern starting with % or _
+	OrderNoLimit    Rule = "order-without-limit" // ORDER BY without LIMIT over a large table
+)
+
+// Severity is how serious a finding is.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// severities are the severities of the rules.
+var severities = map[Rule]Severity{
+	SelectStar:      Warning,
+	MissingWhere:    Error,
+	CartesianJoin:   Warning,
+	LeadingWildcard: Warning,
+	OrderNoLimit:    Warning,
+}
+
+// String returns "info", "warning" or "error".
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText writes the severity as its String form, so findings marshal to readable JSON.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a severity written by MarshalText.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, sev := range []Severity{Info, Warning, Error} {
+		if string(text) == sev.String() {
+			*s = sev
+			return nil
+		}
+	}
+	return fmt.Errorf("lint: unknown severity %q", text)
+}
+
+// Finding is a problem found in a query.
+type Finding struct {
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String returns the finding as "severity rule: message".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Severity, f.Rule, f.Message)
+}
+
+// Findings are the findings of a check, in the order they were found.
+type Findings []Finding
+
+// Err returns an error listing the findings of at least severity min, or nil if there are
+// none, for failing a test or a CI job.
+func (fs Findings) Err(min Severity) error {
+	var errs []error
+	for _, f := range fs {
+		if f.Severity >= min {
+			errs = append(errs, errors.New(f.String()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("lint: %d finding(s):\n%w", len(errs), errors.Join(errs...))
+}
+
+// Config configures a check.
+type Config struct {
+	// Production reports SELECT *, which is fine while exploring but breaks or slows down
+	// queries in production when columns are added.
+	Production bool
+	// LargeTables are the tables on which an ORDER BY without LIMIT is reported. A name
+	// without a schema matches the table in any schema. Names are compared ignoring case.
+	LargeTables []string
+	// Disable lists rules not to check.
+	Disable []Rule
+	// Dialect is the dialect of the SQL, which decides its placeholders and string escapes.
+	// If nil, the dialect of the builder is used if it has a GetDialect method, or else the
+	// global dialect.
+	Dialect sqldialect.Dialect
+}
+
+// Check builds b and checks its SQL. Only Build is called, so any builder can be checked. It
+// returns the error of Build if there is one. A SELECT marked with Unlimited is not reported
+// for ORDER BY without LIMIT.
+func Check(b sqltk.Builder, cfg Config) (Findings, error) {
+	if b == nil {
+		return nil, errors.New("lint: builder is nil")
+	}
+	sql, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Dialect == nil {
+		if d, ok := b.(interface{ GetDialect() sqldialect.Dialect }); ok {
+			cfg.Dialect = d.GetDialect()
+		}
+	}
+	if u, ok := b.(interface{ IsUnlimited() bool }); ok && u.IsUnlimited() {
+		cfg.Disable = append(slices.Clip(cfg.Disable), OrderNoLimit)
+	}
+	return CheckSQL(sql, args, cfg), nil
+}
+
+// CheckSQL checks a SQL statement and its args, such as one built earlier or written by hand.
+func CheckSQL(sql string, args []interface{}, cfg Config) Findings {
+	if cfg.Dialect == nil {
+		cfg.Dialect = sqldialect.GetDialect()
+	}
+	c := &checker{cfg: cfg, args: args}
+	// Strings and quoted identifiers are read with the escapes of the dialect, and only its
+	// placeholders are sqllex.Param, so a ? operator in SQL for Postgres is not one.
+	for _, t := range sqllex.For(cfg.Dialect).Tokens(sql) {
+		if t.Kind != sqllex.Comment {
+			c.toks = append(c.toks, t)
+		}
+	}
+	c.ends = matchParens(c.toks)
+	c.scope(0, len(c.toks), true, false)
+	c.likes()
+	return c.findings
+}
+
+type checker struct {
+	cfg      Config
+	toks     []sqllex.Token
+	ends     []int // index of the matching ")" of a "(" in toks, or -1
+	args     []interface{}
+	findings Findings
+}
+
+// matchParens returns the index of the matching ")" of each "(" of toks, and -1 for the other
+// tokens.
+func matchParens(toks []sqllex.Token) []int {
+	ends := make([]int, len(toks))
+	var open []int
+	for i, t := range toks {
+		ends[i] = -1
+		switch {
+		case t.IsPunct("("):
+			open = append(open, i)
+		case t.IsPunct(")") && len(open) > 0:
+			ends[open[len(open)-1]] = i
+			open = open[:len(open)-1]
+		}
+	}
+	return ends
+}
+
+// report adds a finding unless its rule is disabled.
+func (c *checker) report(rule Rule, format string, a ...interface{}) {
+	if slices.Contains(c.cfg.Disable, rule) {
+		return
+	}
+	c.findings = append(c.findings, Finding{Rule: rule, Severity: severities[rule], Message: fmt.Sprintf(format, a...)})
+}
+
+// isLarge reports whether table is one of the large tables of the config.
+func (c *checker) isLarge(table string) bool {
+	for _, large := range c.cfg.LargeTables {
+		if strings.EqualFold(table, large) {
+			return true
+		}
+		if !strings.Contains(large, ".") {
+			if dot := strings.LastIndexByte(table, '.'); dot >= 0 && strings.EqualFold(table[dot+1:], large) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// join is a JOIN whose ON or USING clause has not been seen yet.
+type join struct {
+	table string
+	cross bool // CROSS JOIN, reported whatever follows
+	open  bool // no ON or USING yet
+}
+
+// scope checks the tokens toks[start:end], which are a statement or the inside of a pair of
+// parentheses, and returns the tables its FROM and JOIN clauses read, including those of
+// derived tables. Parenthesized groups inside it are checked as scopes of their own. top is
+// set for the statement itself, and exists for the subquery of an EXISTS, whose select list
+// does not matter.
+func (c *checker) scope(start, end int, top, exists bool) []string {
+	var (
+		kind      string // first statement keyword: SELECT, UPDATE, DELETE, INSERT, ...
+		clause    string // clause being read
+		tables    []string
+		where     bool // the statement has a WHERE clause
+		partWhere bool // the current part of a set operation has a WHERE clause
+		commas    bool // the current part lists tables in FROM separated by commas
+		order     bool
+		limit     bool
+		wantTable bool // the next name is a table
+		pending   *join
+	)
+	closeJoin := func() {
+		if pending == nil {
+			return
+		}
+		switch {
+		case pending.cross:
+			c.report(CartesianJoin, "CROSS JOIN of %s pairs every row with every row of the other tables", pending.table)
+		case pending.open:
+			c.report(CartesianJoin, "JOIN of %s has no ON or USING clause", pending.table)
+		}
+		pending = nil
+	}
+	closePart := func() {
+		closeJoin()
+		if commas && !partWhere {
+			c.report(CartesianJoin, "tables listed in FROM without a WHERE clause are joined row by row")
+		}
+		commas, partWhere, wantTable = false, false, false
+	}
+
+	for i := start; i < end; i++ {
+		t := c.toks[i]
+		if t.IsPunct("(") {
+			groupEnd := c.ends[i]
+			if groupEnd < 0 || groupEnd > end {
+				groupEnd = end
+			}
+			sub := c.scope(i+1, groupEnd, false, i > start && c.toks[i-1].Is("EXISTS"))
+			if clause == "" || clause == "FROM" {
+				tables = append(tables, sub...)
+			}
+			if wantTable && pending != nil && pending.table == "" {
+				pending.table = "a subquery"
+			}
+			wantTable = false
+			i = groupEnd
+			continue
+		}
+		if wantTable && (t.Kind == sqllex.Word && !t.Is("LATERAL") && !t.Is("ONLY") || t.Kind == sqllex.Quoted) {
+			name, next := c.name(i, end)
+			tables = append(tables, name)
+			if pending != nil && pending.table == "" {
+				pending.table = name
+			}
+			wantTable = false
+			i = next - 1
+			continue
+		}
+		if t.IsPunct("*") && clause == "SELECT" && !exists && c.cfg.Production {
+			if prev := c.toks[i-1]; prev.Is("SELECT") || prev.Is("DISTINCT") || prev.Is("ALL") || prev.IsPunct(",") || prev.IsPunct(".") {
+				c.report(SelectStar, "SELECT * reads every column, including ones added later; list the columns the query needs")
+			}
+			continue
+		}
+		if t.IsPunct(",") {
+			if clause == "FROM" || clause == "UPDATE" && kind == "UPDATE" {
+				closeJoin()
+				if next := c.next(i, end); !next.Is("LATERAL") && !next.Is("UNNEST") {
+					commas = true
+				}
+				wantTable = true
+			}
+			continue
+		}
+		if t.Kind != sqllex.Word {
+			continue
+		}
+		word := strings.ToUpper(t.Text)
+		if kind == "" {
+			switch word {
+			case "SELECT", "UPDATE", "DELETE", "INSERT", "MERGE", "VALUES":
+				kind = word
+			}
+		}
+		switch word {
+		case "SELECT", "SET", "VALUES", "GROUP", "HAVING", "WINDOW", "QUALIFY", "RETURNING", "OFFSET":
+			closeJoin()
+			clause = word
+		case "WITH":
+			if i == start {
+				clause = word
+			}
+		case "FROM":
+			if !c.toks[i-1].Is("DISTINCT") {
+				closeJoin()
+				clause, wantTable = word, true
+			}
+		case "UPDATE":
+			if kind == word && clause == "" {
+				clause, wantTable = word, true
+			} else {
+				clause = "FOR UPDATE"
+			}
+		case "JOIN":
+			closeJoin()
+			clause, wantTable = "FROM", true
+			pending = &join{open: true}
+			for j := i - 1; j >= start && isJoinModifier(c.toks[j]); j-- {
+				switch {
+				case c.toks[j].Is("CROSS"):
+					pending.cross, pending.open = true, false
+				case c.toks[j].Is("NATURAL"):
+					pending.open = false
+				}
+			}
+			if next := c.next(i, end); next.Is("LATERAL") || next.Is("UNNEST") {
+				pending.cross, pending.open = false, false
+			}
+		case "ON", "USING":
+			if pending != nil {
+				pending.open = false
+			}
+			if word == "USING" && kind == "DELETE" && pending == nil {
+				clause, wantTable = "FROM", true
+			} else {
+				clause = word
+			}
+		case "WHERE":
+			closeJoin()
+			clause, where, partWhere = word, true, true
+		case "ORDER":
+			if c.next(i, end).Is("BY") {
+				closeJoin()
+				clause, order = word, true
+			}
+		case "LIMIT", "FETCH":
+			closeJoin()
+			clause, limit = word, true
+		case "UNION", "INTERSECT", "EXCEPT":
+			closePart()
+			clause = word
+		}
+	}
+	closePart()
+
+	if (kind == "UPDATE" || kind == "DELETE") && !where {
+		table := "the table"
+		if len(tables) > 0 {
+			table = tables[0]
+		}
+		if kind == "UPDATE" {
+			c.report(MissingWhere, "UPDATE without WHERE changes every row of %s", table)
+		} else {
+			c.report(MissingWhere, "DELETE without WHERE removes every row of %s", table)
+		}
+	}
+	if top && order && !limit && (kind == "SELECT" || kind == "") {
+		for _, table := range tables {
+			if c.isLarge(table) {
+				c.report(OrderNoLimit, "ORDER BY without LIMIT sorts every matching row of %s", table)
+				break
+			}
+		}
+	}
+	return tables
+}
+
+// name reads the possibly qualified name that starts at toks[i] and returns it without quotes,
+// with the index of the token after it.
+func (c *checker) name(i, end int) (string, int) {
+	parts := []string{c.toks[i].Val}
+	i++
+	for i+1 < end && c.toks[i].IsPunct(".") && (c.toks[i+1].Kind == sqllex.Word || c.toks[i+1].Kind == sqllex.Quoted) {
+		parts = append(parts, c.toks[i+1].Val)
+		i += 2
+	}
+	return strings.Join(parts, "."), i
+}
+
+// next returns the token after toks[i], or an empty token at end.
+func (c *checker) next(i, end int) sqllex.Token {
+	if i+1 < end {
+		return c.toks[i+1]
+	}
+	return sqllex.Token{Kind: sqllex.Punct}
+}
+
+func isJoinModifier(t sqllex.Token) bool {
+	for _, kw := range []string{"LEFT", "RIGHT", "FULL", "INNER", "OUTER", "CROSS", "NATURAL"} {
+		if t.Is(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// likes reports LIKE and ILIKE patterns, written in the SQL or bound to a placeholder, that
+// start with a wildcard. The arg of a ? is the next one after those of the ? placeholders
+// before it, and that of a $n or :n is the nth.
+func (c *checker) likes() {
+	next := 0
+	for i, t := range c.toks {
+		if t.Kind == sqllex.Param && t.ParamNumber() == 0 {
+			next++
+		}
+		if !t.Is("LIKE") && !t.Is("ILIKE") || i+1 == len(c.toks) {
+			continue
+		}
+		var pattern string
+		switch p := c.toks[i+1]; p.Kind {
+		case sqllex.String:
+			pattern = p.Val
+		case sqllex.Param:
+			arg := next
+			if n := p.ParamNumber(); n > 0 {
+				arg = n - 1
+			}
+			if arg >= len(c.args) {
+				continue
+			}
+			s, ok := c.args[arg].(string)
+			if !ok {
+				continue
+			}
+			pattern = s
+		default:
+			continue
+		}
+		if strings.HasPrefix(pattern, "%") || strings.HasPrefix(pattern, "_") {
+			c.report(LeadingWildcard, "%s pattern %q starts with a wildcard, so an index on the column cannot be used", strings.ToUpper(t.Text), pattern)
+		}
+	}
+}
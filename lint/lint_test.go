@@ -0,0 +1,275 @@
+package lint
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func rules(findings Findings) []Rule {
+	var out []Rule
+	for _, f := range findings {
+		out = append(out, f.Rule)
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	pg := sqldialect.Postgres()
+	prod := Config{Production: true}
+	large := Config{LargeTables: []string{"events"}}
+	tests := []struct {
+		name string
+		b    sqltk.Builder
+		cfg  Config
+		want []Rule
+	}{
+		{
+			name: "clean select",
+			b:    sqltk.Select("id", "name").From("users").WhereEqual("id", 1).WithDialect(pg),
+			cfg:  prod,
+		},
+		{
+			name: "select star in production",
+			b:    sqltk.Select().From("users").WithDialect(pg),
+			cfg:  prod,
+			want: []Rule{SelectStar},
+		},
+		{
+			name: "select star outside production",
+			b:    sqltk.Select().From("users").WithDialect(pg),
+		},
+		{
+			name: "qualified star",
+			b:    sqltk.Select(raw.Raw("u.*")).From("users u").WithDialect(pg),
+			cfg:  prod,
+			want: []Rule{SelectStar},
+		},
+		{
+			name: "count star and exists subquery",
+			b: sqltk.Select(raw.Raw("COUNT(*)")).From("users u").
+				WhereExists(sqltk.Select().From("orders o").Where(sqltk.NewStringCondition("o.user_id = u.id"))).WithDialect(pg),
+			cfg: prod,
+		},
+		{
+			name: "update without where",
+			b:    sqltk.Update("users").Set("active", false).WithDialect(pg),
+			want: []Rule{MissingWhere},
+		},
+		{
+			name: "update with where",
+			b:    sqltk.Update("users").Set("active", false).WhereEqual("id", 1).WithDialect(pg),
+		},
+		{
+			name: "delete without where",
+			b:    sqltk.Delete("sessions").WithDialect(sqldialect.MySQL()),
+			want: []Rule{MissingWhere},
+		},
+		{
+			name: "delete with where",
+			b:    sqltk.Delete("sessions").WhereLessThan("expires_at", "2024-01-01").WithDialect(pg),
+		},
+		{
+			name: "cross join",
+			b:    sqltk.Select("u.id").From("users u").CrossJoin("orders o").WithDialect(pg),
+			want: []Rule{CartesianJoin},
+		},
+		{
+			name: "join with on",
+			b:    sqltk.Select("u.id").From("users u").Join("orders o").On("o.user_id", "u.id").WithDialect(pg),
+		},
+		{
+			name: "comma join without where",
+			b:    sqltk.Select("u.id").From("users u", "orders o").WithDialect(pg),
+			want: []Rule{CartesianJoin},
+		},
+		{
+			name: "comma join with where",
+			b:    sqltk.Select("u.id").From("users u", "orders o").Where(sqltk.NewStringCondition("o.user_id = u.id")).WithDialect(pg),
+		},
+		{
+			name: "leading wildcard arg",
+			b:    sqltk.Select("id").From("users").WhereLike("name", "%smith").WithDialect(pg),
+			want: []Rule{LeadingWildcard},
+		},
+		{
+			name: "trailing wildcard arg",
+			b:    sqltk.Select("id").From("users").WhereLike("name", "smith%").WithDialect(pg),
+		},
+		{
+			name: "order without limit on large table",
+			b:    sqltk.Select("id").From("events").OrderBy("created_at").WithDialect(pg),
+			cfg:  large,
+			want: []Rule{OrderNoLimit},
+		},
+		{
+			name: "order with limit on large table",
+			b:    sqltk.Select("id").From("events").OrderBy("created_at").Limit(10).WithDialect(pg),
+			cfg:  large,
+		},
+		{
+			name: "order without limit on small table",
+			b:    sqltk.Select("id").From("countries").OrderBy("name").WithDialect(pg),
+			cfg:  large,
+		},
+		{
+			name: "order on large schema-qualified table",
+			b:    sqltk.Select("id").From(sqltk.Ident("analytics", "events")).OrderBy("created_at").WithDialect(pg),
+			cfg:  large,
+			want: []Rule{OrderNoLimit},
+		},
+		{
+			name: "order on large table marked unlimited",
+			b:    sqltk.Select("id").From("events").OrderBy("created_at").Unlimited().WithDialect(pg),
+			cfg:  large,
+		},
+		{
+			name: "disabled rule",
+			b:    sqltk.Delete("sessions").WithDialect(pg),
+			cfg:  Config{Disable: []Rule{MissingWhere}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Check(tt.b, tt.cfg)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if got := rules(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules = %v, want %v\nfindings: %v", got, tt.want, findings)
+			}
+		})
+	}
+}
+
+func TestCheckSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		args    []interface{}
+		dialect sqldialect.Dialect
+		want    []Rule
+	}{
+		{
+			name: "literal leading wildcard",
+			sql:  "SELECT id FROM users WHERE name NOT LIKE '%son'",
+			want: []Rule{LeadingWildcard},
+		},
+		{
+			name:    "ilike numbered placeholder",
+			sql:     `SELECT id FROM users WHERE active = $1 AND name ILIKE $2`,
+			args:    []interface{}{true, "_mith"},
+			dialect: sqldialect.Postgres(),
+			want:    []Rule{LeadingWildcard},
+		},
+		{
+			name:    "question mark operator is not a placeholder",
+			sql:     `SELECT id FROM docs WHERE data ? 'key' AND title LIKE $1`,
+			args:    []interface{}{"%draft"},
+			dialect: sqldialect.Postgres(),
+			want:    []Rule{LeadingWildcard},
+		},
+		{
+			name: "keywords in strings and comments",
+			sql:  "SELECT 'DELETE FROM users' AS q /* CROSS JOIN */ FROM logs WHERE note = 'a, b'",
+		},
+		{
+			name: "join without on",
+			sql:  "SELECT a.id FROM a JOIN b WHERE a.x = 1",
+			want: []Rule{CartesianJoin},
+		},
+		{
+			name: "natural and lateral joins",
+			sql:  "SELECT a.id FROM a NATURAL JOIN b CROSS JOIN LATERAL (SELECT 1 FROM c WHERE c.a_id = a.id) l",
+		},
+		{
+			name: "delete in cte",
+			sql:  "WITH gone AS (DELETE FROM carts RETURNING id) SELECT id FROM gone",
+			want: []Rule{MissingWhere},
+		},
+		{
+			name: "upsert is not an update",
+			sql:  "INSERT INTO counters (id, n) VALUES (?, 1) ON DUPLICATE KEY UPDATE n = n + 1",
+			args: []interface{}{1},
+		},
+		{
+			name: "order on large table in union",
+			sql:  "(SELECT id FROM events WHERE kind = 1) UNION ALL (SELECT id FROM archive) ORDER BY id",
+			want: []Rule{OrderNoLimit},
+		},
+		{
+			name: "order on large derived table",
+			sql:  "SELECT e.id FROM (SELECT id FROM events WHERE kind = 1) e ORDER BY e.id",
+			want: []Rule{OrderNoLimit},
+		},
+		{
+			name: "order in subquery only",
+			sql:  "SELECT id FROM users WHERE id IN (SELECT user_id FROM events ORDER BY created_at)",
+		},
+		{
+			name: "fetch first",
+			sql:  "SELECT id FROM events ORDER BY id FETCH FIRST 10 ROWS ONLY",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := tt.dialect
+			if dialect == nil {
+				dialect = sqldialect.MySQL()
+			}
+			findings := CheckSQL(tt.sql, tt.args, Config{LargeTables: []string{"events"}, Dialect: dialect})
+			if got := rules(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules = %v, want %v\nfindings: %v", got, tt.want, findings)
+			}
+		})
+	}
+}
+
+func TestCheckBuildError(t *testing.T) {
+	if _, err := Check(sqltk.Select(), Config{}); err == nil {
+		t.Error("expected the Build error")
+	}
+	if _, err := Check(nil, Config{}); err == nil {
+		t.Error("expected an error for a nil builder")
+	}
+}
+
+func TestFindings(t *testing.T) {
+	findings, err := Check(sqltk.Delete("sessions").WhereLike("token", "%x").WithDialect(sqldialect.Postgres()), Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Severity != Warning {
+		t.Fatalf("findings = %v", findings)
+	}
+	if err := findings.Err(Error); err != nil {
+		t.Errorf("Err(Error) = %v, want nil", err)
+	}
+	err = findings.Err(Warning)
+	if err == nil || !strings.Contains(err.Error(), `warning leading-wildcard: LIKE pattern "%x" starts with a wildcard`) {
+		t.Errorf("Err(Warning) = %v", err)
+	}
+
+	data, err := json.Marshal(findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"rule":"leading-wildcard","severity":"warning","message":"LIKE pattern \"%x\" starts with a wildcard, so an index on the column cannot be used"}]`
+	if string(data) != want {
+		t.Errorf("json = %s\nwant %s", data, want)
+	}
+	var back Findings
+	if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, findings) {
+		t.Errorf("round trip = %v, %v", back, err)
+	}
+
+	missing, _ := Check(sqltk.Update("users").Set("a", 1).WithDialect(sqldialect.Postgres()), Config{})
+	if err := missing.Err(Error); err == nil || !strings.Contains(err.Error(), `error missing-where: UPDATE without WHERE changes every row of users`) {
+		t.Errorf("Err(Error) = %v", err)
+	}
+}
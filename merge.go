@@ -0,0 +1,236 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldebug"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// MergeBuilder builds SQL MERGE statements, which update, delete, or insert the rows of a
+// target table depending on whether they match the rows of a source. It is the upsert of
+// Postgres 15+, SQL Server, Oracle, BigQuery, and DuckDB; MySQL has no MERGE, see OnConflict.
+type MergeBuilder struct {
+	target  string
+	source  interface{}
+	on      string        // ON condition, with ? placeholders
+	onArgs  []interface{} // args of on
+	whens   []mergeWhen
+	err     error
+	dialect sqldialect.Dialect // per-builder dialect, if set
+}
+
+// mergeWhen is a WHEN clause of a MERGE.
+type mergeWhen struct {
+	matched bool
+	action  string           // "UPDATE", "DELETE", or "INSERT"
+	sets    []conflictUpdate // see WhenMatchedUpdate
+	columns []string         // see WhenNotMatchedInsert
+	values  []interface{}    // see WhenNotMatchedInsert
+}
+
+// Merge creates a new MergeBuilder for the given target table.
+//
+// Example usage:
+//
+//	sqltk.Merge("accounts").
+//		Using(sqltk.Alias("staged_accounts", "s")).
+//		On(sqltk.NewStringCondition("accounts.id = s.id")).
+//		WhenMatchedUpdate(map[string]interface{}{"balance": raw.Raw("s.balance")}).
+//		WhenNotMatchedInsert([]string{"id", "balance"}, raw.Raw("s.id"), raw.Raw("s.balance"))
+//	// MERGE INTO accounts USING staged_accounts AS s ON (accounts.id = s.id)
+//	//   WHEN MATCHED THEN UPDATE SET balance = s.balance
+//	//   WHEN NOT MATCHED THEN INSERT (id, balance) VALUES (s.id, s.balance)
+func Merge(target string) *MergeBuilder {
+	b := &MergeBuilder{target: target}
+	if target == "" {
+		b.err = errors.New("Merge: target must be set")
+	}
+	return b
+}
+
+// Using sets the source of the rows. Accepts the same tables as SelectBuilder.From: a table
+// name, raw.Raw, a *SelectBuilder, or an AliasExpr; a subquery needs an alias.
+func (b *MergeBuilder) Using(source interface{}) *MergeBuilder {
+	if source == nil {
+		b.err = errors.Join(b.err, errors.New("Using: source must not be nil"))
+		return b
+	}
+	b.source = source
+	return b
+}
+
+// On sets the condition that matches the rows of the source to those of the target.
+func (b *MergeBuilder) On(cond Condition) *MergeBuilder {
+	if cond == nil {
+		b.err = errors.Join(b.err, errors.New("On: condition must not be nil"))
+		return b
+	}
+	sql, args, err := cond.BuildCondition()
+	if err != nil {
+		b.err = errors.Join(b.err, fmt.Errorf("On: condition error: %w", err))
+		return b
+	}
+	b.on, b.onArgs = sql, args
+	return b
+}
+
+// WhenMatchedUpdate adds a WHEN MATCHED THEN UPDATE clause setting the columns of set, in
+// column order. Values are bound as args; use raw.Raw, such as raw.Raw("s.name"), for the
+// columns of the source, and Expr for expressions with args.
+func (b *MergeBuilder) WhenMatchedUpdate(set map[string]interface{}) *MergeBuilder {
+	if len(set) == 0 {
+		b.err = errors.Join(b.err, errors.New("WhenMatchedUpdate: set must not be empty"))
+		return b
+	}
+	columns := make([]string, 0, len(set))
+	for col := range set {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	w := mergeWhen{matched: true, action: "UPDATE"}
+	for _, col := range columns {
+		w.sets = append(w.sets, conflictUpdate{column: col, value: set[col]})
+	}
+	b.whens = append(b.whens, w)
+	return b
+}
+
+// WhenMatchedDelete adds a WHEN MATCHED THEN DELETE clause.
+func (b *MergeBuilder) WhenMatchedDelete() *MergeBuilder {
+	b.whens = append(b.whens, mergeWhen{matched: true, action: "DELETE"})
+	return b
+}
+
+// WhenNotMatchedInsert adds a WHEN NOT MATCHED THEN INSERT clause with a value for each of
+// columns, as for WhenMatchedUpdate.
+func (b *MergeBuilder) WhenNotMatchedInsert(columns []string, values ...interface{}) *MergeBuilder {
+	if len(columns) == 0 || len(values) != len(columns) {
+		b.err = errors.Join(b.err, errors.New("WhenNotMatchedInsert: number of values must match number of columns"))
+		return b
+	}
+	b.whens = append(b.whens, mergeWhen{action: "INSERT", columns: columns, values: values})
+	return b
+}
+
+// WithDialect sets the dialect for this builder instance.
+func (b *MergeBuilder) WithDialect(d sqldialect.Dialect) *MergeBuilder {
+	b.dialect = d
+	return b
+}
+
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (b *MergeBuilder) GetDialect() sqldialect.Dialect {
+	if b.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return b.dialect
+}
+
+// Build builds the SQL MERGE statement and returns the query string, arguments, and error if any.
+func (b *MergeBuilder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if b.source == nil {
+		return "", nil, errors.New("Merge: Using must be set")
+	}
+	if b.on == "" {
+		return "", nil, errors.New("Merge: On must be set")
+	}
+	if len(b.whens) == 0 {
+		return "", nil, errors.New("Merge: at least one WHEN clause must be set")
+	}
+
+	dialect := b.dialect
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	out := dialect
+	dialect = renderDialect(dialect)
+	if baseDialect(dialect) == sqldialect.MySQL() {
+		return "", nil, errors.New("Merge: MySQL does not support MERGE, see OnConflict")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+	sb.WriteString("MERGE INTO ")
+	sb.WriteString(quoteQualifiedIdent(dialect, b.target))
+	sourceSQL, sourceArgs, err := buildFromTable(dialect, b.source)
+	if err != nil {
+		return "", nil, fmt.Errorf("Using: %w", err)
+	}
+	sb.WriteString(" USING ")
+	sb.WriteString(sourceSQL)
+	args = append(args, sourceArgs...)
+	onSQL, onArgs, err := bindArgs(dialect, b.on, b.onArgs)
+	if err != nil {
+		return "", nil, fmt.Errorf("On: %w", err)
+	}
+	sb.WriteString(" ON (" + onSQL + ")")
+	args = append(args, onArgs...)
+
+	for _, w := range b.whens {
+		if w.matched {
+			sb.WriteString(" WHEN MATCHED THEN ")
+		} else {
+			sb.WriteString(" WHEN NOT MATCHED THEN ")
+		}
+		switch w.action {
+		case "UPDATE":
+			sets := make([]string, len(w.sets))
+			for i, u := range w.sets {
+				value, valueArgs, err := buildValue(dialect, u.value)
+				if err != nil {
+					return "", nil, fmt.Errorf("WhenMatchedUpdate: %w", err)
+				}
+				sets[i] = dialect.QuoteIdent(u.column) + " = " + value
+				args = append(args, valueArgs...)
+			}
+			sb.WriteString("UPDATE SET " + strings.Join(sets, ", "))
+		case "INSERT":
+			columns := make([]string, len(w.columns))
+			values := make([]string, len(w.values))
+			for i, col := range w.columns {
+				columns[i] = dialect.QuoteIdent(col)
+				value, valueArgs, err := buildValue(dialect, w.values[i])
+				if err != nil {
+					return "", nil, fmt.Errorf("WhenNotMatchedInsert: %w", err)
+				}
+				values[i] = value
+				args = append(args, valueArgs...)
+			}
+			sb.WriteString("INSERT (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ")")
+		default:
+			sb.WriteString(w.action)
+		}
+	}
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, false)
+	placeholderIdx := 1
+	return numberPlaceholders(out, sb.String(), &placeholderIdx, numbers), args, nil
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
+// DO NOT use the result for execution (not safe against SQL injection).
+func (b *MergeBuilder) DebugSQL() string {
+	sql, args, _ := b.Build()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. Nothing is
+// written if Build fails.
+func (b *MergeBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
+}
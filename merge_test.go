@@ -0,0 +1,89 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestMergeBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *MergeBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "update and insert",
+			builder: Merge("accounts").
+				Using(Alias("staged_accounts", "s")).
+				On(NewStringCondition("accounts.id = s.id")).
+				WhenMatchedUpdate(map[string]interface{}{"balance": raw.Raw("s.balance"), "note": "merged"}).
+				WhenNotMatchedInsert([]string{"id", "balance"}, raw.Raw("s.id"), raw.Raw("s.balance")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "MERGE INTO accounts USING staged_accounts AS s ON (accounts.id = s.id) WHEN MATCHED THEN UPDATE SET balance = s.balance, note = ? WHEN NOT MATCHED THEN INSERT (id, balance) VALUES (s.id, s.balance)",
+			wantArgs: []interface{}{"merged"},
+		},
+		{
+			name: "subquery source with args",
+			builder: Merge("accounts").
+				Using(Alias(Select("id", "balance").From("staged").Where(NewStringCondition("batch = ?", 7)), "s")).
+				On(NewStringCondition("accounts.id = s.id AND accounts.region = ?", "eu")).
+				WhenMatchedDelete().
+				WhenNotMatchedInsert([]string{"id", "balance", "created_at"}, raw.Raw("s.id"), Expr("s.balance * ?", 100), "now"),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `MERGE INTO "accounts" USING (SELECT "id", "balance" FROM "staged" WHERE batch = $1) AS s ON (accounts.id = s.id AND accounts.region = $2) WHEN MATCHED THEN DELETE WHEN NOT MATCHED THEN INSERT ("id", "balance", "created_at") VALUES (s.id, s.balance * $3, $4)`,
+			wantArgs: []interface{}{7, "eu", 100, "now"},
+		},
+		{
+			name:    "mysql",
+			builder: Merge("accounts").Using("staged").On(NewStringCondition("accounts.id = staged.id")).WhenMatchedDelete(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "missing on",
+			builder: Merge("accounts").Using("staged").WhenMatchedDelete(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "missing when",
+			builder: Merge("accounts").Using("staged").On(NewStringCondition("accounts.id = staged.id")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name: "insert values mismatch",
+			builder: Merge("accounts").Using("staged").On(NewStringCondition("accounts.id = staged.id")).
+				WhenNotMatchedInsert([]string{"id", "balance"}, raw.Raw("staged.id")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
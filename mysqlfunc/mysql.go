@@ -33,8 +33,7 @@ func Concat(args ...interface{}) sqlfunc.SqlFunc {
 	var argStrs []string
 	for _, arg := range args {
 		if err := sqlfunc.ValidateSqlFuncInput(arg); err != nil {
-			// In production, you might want to panic or log this
-			panic(fmt.Sprintf("Concat: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("Concat: %w", err))
 		}
 		argStrs = append(argStrs, fmt.Sprintf("%v", arg))
 	}
@@ -46,7 +45,7 @@ func ConcatWs(separator string, args ...interface{}) sqlfunc.SqlFunc {
 	argStrs = append(argStrs, fmt.Sprintf("'%s'", separator))
 	for _, arg := range args {
 		if err := sqlfunc.ValidateSqlFuncInput(arg); err != nil {
-			panic(fmt.Sprintf("ConcatWs: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("ConcatWs: %w", err))
 		}
 		argStrs = append(argStrs, fmt.Sprintf("%v", arg))
 	}
@@ -55,102 +54,102 @@ func ConcatWs(separator string, args ...interface{}) sqlfunc.SqlFunc {
 
 func Substring(str interface{}, pos, length interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Substring: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Substring: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(pos); err != nil {
-		panic(fmt.Sprintf("Substring: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Substring: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(length); err != nil {
-		panic(fmt.Sprintf("Substring: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Substring: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("SUBSTRING(%v, %v, %v)", str, pos, length))
 }
 
 func Left(str interface{}, length interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Left: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Left: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(length); err != nil {
-		panic(fmt.Sprintf("Left: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Left: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("LEFT(%v, %v)", str, length))
 }
 
 func Right(str interface{}, length interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Right: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Right: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(length); err != nil {
-		panic(fmt.Sprintf("Right: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Right: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("RIGHT(%v, %v)", str, length))
 }
 
 func Upper(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Upper: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Upper: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("UPPER(%v)", str))
 }
 
 func Lower(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Lower: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Lower: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("LOWER(%v)", str))
 }
 
 func Trim(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Trim: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Trim: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("TRIM(%v)", str))
 }
 
 func Ltrim(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Ltrim: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Ltrim: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("LTRIM(%v)", str))
 }
 
 func Rtrim(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Rtrim: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Rtrim: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("RTRIM(%v)", str))
 }
 
 func Replace(str, from, to interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Replace: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Replace: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(from); err != nil {
-		panic(fmt.Sprintf("Replace: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Replace: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(to); err != nil {
-		panic(fmt.Sprintf("Replace: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Replace: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("REPLACE(%v, %v, %v)", str, from, to))
 }
 
 func Reverse(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Reverse: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Reverse: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("REVERSE(%v)", str))
 }
 
 func Length(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Length: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Length: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("LENGTH(%v)", str))
 }
 
 func CharLength(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("CharLength: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("CharLength: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("CHAR_LENGTH(%v)", str))
 }
@@ -218,7 +217,7 @@ func Max(expr interface{}) sqlfunc.SqlFunc {
 
 func GroupConcat(expr interface{}, separator ...string) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("GroupConcat: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("GroupConcat: %w", err))
 	}
 	if len(separator) > 0 {
 		return sqlfunc.SqlFunc(fmt.Sprintf("GROUP_CONCAT(%v SEPARATOR '%s')", expr, separator[0]))
@@ -229,33 +228,33 @@ func GroupConcat(expr interface{}, separator ...string) sqlfunc.SqlFunc {
 // Conditional Functions
 func If(condition, trueVal, falseVal interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(condition); err != nil {
-		panic(fmt.Sprintf("If: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("If: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(trueVal); err != nil {
-		panic(fmt.Sprintf("If: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("If: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(falseVal); err != nil {
-		panic(fmt.Sprintf("If: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("If: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("IF(%v, %v, %v)", condition, trueVal, falseVal))
 }
 
 func IfNull(expr, nullVal interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("IfNull: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("IfNull: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(nullVal); err != nil {
-		panic(fmt.Sprintf("IfNull: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("IfNull: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("IFNULL(%v, %v)", expr, nullVal))
 }
 
 func NullIf(expr1, expr2 interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(expr1); err != nil {
-		panic(fmt.Sprintf("NullIf: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("NullIf: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(expr2); err != nil {
-		panic(fmt.Sprintf("NullIf: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("NullIf: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("NULLIF(%v, %v)", expr1, expr2))
 }
@@ -263,14 +262,14 @@ func NullIf(expr1, expr2 interface{}) sqlfunc.SqlFunc {
 // Type Conversion Functions
 func Cast(expr interface{}, asType string) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("Cast: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Cast: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("CAST(%v AS %s)", expr, asType))
 }
 
 func Convert(expr interface{}, asType string) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("Convert: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Convert: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("CONVERT(%v, %s)", expr, asType))
 }
@@ -278,28 +277,28 @@ func Convert(expr interface{}, asType string) sqlfunc.SqlFunc {
 // JSON Functions (MySQL 5.7+)
 func JsonExtract(jsonDoc, path interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonDoc); err != nil {
-		panic(fmt.Sprintf("JsonExtract: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonExtract: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(path); err != nil {
-		panic(fmt.Sprintf("JsonExtract: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonExtract: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("JSON_EXTRACT(%v, %v)", jsonDoc, path))
 }
 
 func JsonUnquote(jsonVal interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonVal); err != nil {
-		panic(fmt.Sprintf("JsonUnquote: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonUnquote: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("JSON_UNQUOTE(%v)", jsonVal))
 }
 
 func JsonLength(jsonDoc interface{}, path ...interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonDoc); err != nil {
-		panic(fmt.Sprintf("JsonLength: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonLength: %w", err))
 	}
 	if len(path) > 0 {
 		if err := sqlfunc.ValidateSqlFuncInput(path[0]); err != nil {
-			panic(fmt.Sprintf("JsonLength: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("JsonLength: %w", err))
 		}
 		return sqlfunc.SqlFunc(fmt.Sprintf("JSON_LENGTH(%v, %v)", jsonDoc, path[0]))
 	}
@@ -308,11 +307,11 @@ func JsonLength(jsonDoc interface{}, path ...interface{}) sqlfunc.SqlFunc {
 
 func JsonKeys(jsonDoc interface{}, path ...interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonDoc); err != nil {
-		panic(fmt.Sprintf("JsonKeys: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonKeys: %w", err))
 	}
 	if len(path) > 0 {
 		if err := sqlfunc.ValidateSqlFuncInput(path[0]); err != nil {
-			panic(fmt.Sprintf("JsonKeys: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("JsonKeys: %w", err))
 		}
 		return sqlfunc.SqlFunc(fmt.Sprintf("JSON_KEYS(%v, %v)", jsonDoc, path[0]))
 	}
@@ -321,14 +320,14 @@ func JsonKeys(jsonDoc interface{}, path ...interface{}) sqlfunc.SqlFunc {
 
 func JsonContains(jsonDoc, val interface{}, path ...interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonDoc); err != nil {
-		panic(fmt.Sprintf("JsonContains: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonContains: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(val); err != nil {
-		panic(fmt.Sprintf("JsonContains: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonContains: %w", err))
 	}
 	if len(path) > 0 {
 		if err := sqlfunc.ValidateSqlFuncInput(path[0]); err != nil {
-			panic(fmt.Sprintf("JsonContains: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("JsonContains: %w", err))
 		}
 		return sqlfunc.SqlFunc(fmt.Sprintf("JSON_CONTAINS(%v, %v, %v)", jsonDoc, val, path[0]))
 	}
@@ -337,17 +336,17 @@ func JsonContains(jsonDoc, val interface{}, path ...interface{}) sqlfunc.SqlFunc
 
 func JsonSearch(jsonDoc, oneOrAll, searchStr interface{}, escapeChar ...interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(jsonDoc); err != nil {
-		panic(fmt.Sprintf("JsonSearch: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonSearch: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(oneOrAll); err != nil {
-		panic(fmt.Sprintf("JsonSearch: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonSearch: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(searchStr); err != nil {
-		panic(fmt.Sprintf("JsonSearch: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("JsonSearch: %w", err))
 	}
 	if len(escapeChar) > 0 {
 		if err := sqlfunc.ValidateSqlFuncInput(escapeChar[0]); err != nil {
-			panic(fmt.Sprintf("JsonSearch: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("JsonSearch: %w", err))
 		}
 		return sqlfunc.SqlFunc(fmt.Sprintf("JSON_SEARCH(%v, %v, %v, %v)", jsonDoc, oneOrAll, searchStr, escapeChar[0]))
 	}
@@ -357,31 +356,31 @@ func JsonSearch(jsonDoc, oneOrAll, searchStr interface{}, escapeChar ...interfac
 // Encryption Functions
 func Md5(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Md5: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Md5: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("MD5(%v)", str))
 }
 
 func Sha1(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Sha1: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Sha1: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("SHA1(%v)", str))
 }
 
 func Sha2(str, hashLength interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Sha2: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Sha2: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(hashLength); err != nil {
-		panic(fmt.Sprintf("Sha2: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Sha2: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("SHA2(%v, %v)", str, hashLength))
 }
 
 func Password(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Password: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Password: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("PASSWORD(%v)", str))
 }
@@ -418,107 +417,107 @@ func Version() sqlfunc.SqlFunc {
 // Date/Time Manipulation Functions
 func DateAdd(date interface{}, interval string, expr interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DateAdd: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateAdd: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("DateAdd: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateAdd: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DATE_ADD(%v, INTERVAL %v %s)", date, expr, interval))
 }
 
 func DateSub(date interface{}, interval string, expr interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DateSub: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateSub: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(expr); err != nil {
-		panic(fmt.Sprintf("DateSub: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateSub: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DATE_SUB(%v, INTERVAL %v %s)", date, expr, interval))
 }
 
 func DateDiff(date1, date2 interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date1); err != nil {
-		panic(fmt.Sprintf("DateDiff: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateDiff: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(date2); err != nil {
-		panic(fmt.Sprintf("DateDiff: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateDiff: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DATEDIFF(%v, %v)", date1, date2))
 }
 
 func TimeDiff(time1, time2 interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(time1); err != nil {
-		panic(fmt.Sprintf("TimeDiff: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("TimeDiff: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(time2); err != nil {
-		panic(fmt.Sprintf("TimeDiff: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("TimeDiff: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("TIMEDIFF(%v, %v)", time1, time2))
 }
 
 func Year(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("Year: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Year: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("YEAR(%v)", date))
 }
 
 func Month(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("Month: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Month: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("MONTH(%v)", date))
 }
 
 func Day(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("Day: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Day: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DAY(%v)", date))
 }
 
 func Hour(time interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(time); err != nil {
-		panic(fmt.Sprintf("Hour: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Hour: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("HOUR(%v)", time))
 }
 
 func Minute(time interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(time); err != nil {
-		panic(fmt.Sprintf("Minute: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Minute: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("MINUTE(%v)", time))
 }
 
 func Second(time interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(time); err != nil {
-		panic(fmt.Sprintf("Second: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Second: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("SECOND(%v)", time))
 }
 
 func DayOfWeek(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DayOfWeek: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DayOfWeek: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DAYOFWEEK(%v)", date))
 }
 
 func DayOfYear(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DayOfYear: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DayOfYear: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DAYOFYEAR(%v)", date))
 }
 
 func Week(date interface{}, mode ...interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("Week: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Week: %w", err))
 	}
 	if len(mode) > 0 {
 		if err := sqlfunc.ValidateSqlFuncInput(mode[0]); err != nil {
-			panic(fmt.Sprintf("Week: %v", err))
+			return sqlfunc.Invalid(fmt.Errorf("Week: %w", err))
 		}
 		return sqlfunc.SqlFunc(fmt.Sprintf("WEEK(%v, %v)", date, mode[0]))
 	}
@@ -527,14 +526,14 @@ func Week(date interface{}, mode ...interface{}) sqlfunc.SqlFunc {
 
 func MonthName(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("MonthName: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("MonthName: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("MONTHNAME(%v)", date))
 }
 
 func DayName(date interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DayName: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DayName: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DAYNAME(%v)", date))
 }
@@ -542,30 +541,30 @@ func DayName(date interface{}) sqlfunc.SqlFunc {
 // Formatting Functions
 func DateFormat(date, format interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(date); err != nil {
-		panic(fmt.Sprintf("DateFormat: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateFormat: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(format); err != nil {
-		panic(fmt.Sprintf("DateFormat: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("DateFormat: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("DATE_FORMAT(%v, %v)", date, format))
 }
 
 func TimeFormat(time, format interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(time); err != nil {
-		panic(fmt.Sprintf("TimeFormat: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("TimeFormat: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(format); err != nil {
-		panic(fmt.Sprintf("TimeFormat: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("TimeFormat: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("TIME_FORMAT(%v, %v)", time, format))
 }
 
 func Format(num, decimals interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(num); err != nil {
-		panic(fmt.Sprintf("Format: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Format: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(decimals); err != nil {
-		panic(fmt.Sprintf("Format: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Format: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("FORMAT(%v, %v)", num, decimals))
 }
@@ -573,27 +572,30 @@ func Format(num, decimals interface{}) sqlfunc.SqlFunc {
 // String Search Functions
 func Like(str, pattern interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Like: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Like: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(pattern); err != nil {
-		panic(fmt.Sprintf("Like: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Like: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("%v LIKE %v", str, pattern))
 }
 
+// Regexp returns str REGEXP pattern, with both interpolated into the SQL.
+//
+// Deprecated: use sqltk.ConditionBuilder.Regexp, which binds the pattern as an arg.
 func Regexp(str, pattern interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Regexp: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Regexp: %w", err))
 	}
 	if err := sqlfunc.ValidateSqlFuncInput(pattern); err != nil {
-		panic(fmt.Sprintf("Regexp: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Regexp: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("%v REGEXP %v", str, pattern))
 }
 
 func Soundex(str interface{}) sqlfunc.SqlFunc {
 	if err := sqlfunc.ValidateSqlFuncInput(str); err != nil {
-		panic(fmt.Sprintf("Soundex: %v", err))
+		return sqlfunc.Invalid(fmt.Errorf("Soundex: %w", err))
 	}
 	return sqlfunc.SqlFunc(fmt.Sprintf("SOUNDEX(%v)", str))
 }
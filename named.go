@@ -0,0 +1,94 @@
+package sqltk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// NamedParam is a placeholder argument whose value is supplied by BindNamed.
+type NamedParam struct {
+	Name string
+}
+
+// Param returns a NamedParam for use as a value, e.g. WhereEqual("tenant_id", sqltk.Param("tenant")).
+func Param(name string) NamedParam {
+	return NamedParam{Name: name}
+}
+
+// NewNamedCondition creates a condition from SQL with named parameters (:name), as used by sqlx.
+// Each parameter becomes a placeholder whose value is supplied by BindNamed. Parameters in quoted
+// strings and identifiers, comments, and Postgres casts (::type), are left alone.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id").From("users").Where(sqltk.NewNamedCondition("tenant_id = :tenant AND status = :status"))
+//	sql, args, err := sqltk.BindNamed(q, map[string]interface{}{"tenant": 7, "status": "active"})
+func NewNamedCondition(sql string) *StringCondition {
+	var sb strings.Builder
+	var args []interface{}
+	lx := sqllex.For(sqldialect.GetDialect())
+	for i := 0; i < len(sql); {
+		if end := lx.Skip(sql, i); end > i {
+			sb.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+		c := sql[i]
+		switch {
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			sb.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(sql) && isIdentByte(sql[i+1]):
+			j := i + 1
+			for j < len(sql) && isIdentByte(sql[j]) {
+				j++
+			}
+			sb.WriteByte('?')
+			args = append(args, NamedParam{Name: sql[i+1 : j]})
+			i = j
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return &StringCondition{SQL: sb.String(), Args: args}
+}
+
+// BindNamed builds b and replaces its NamedParam arguments with the values in params, so the
+// result has positional args for the builder's dialect. It is an error if a parameter has no value.
+func BindNamed(b Builder, params map[string]interface{}) (string, []interface{}, error) {
+	sql, args, err := SafeBuild(b)
+	if err != nil {
+		return "", nil, err
+	}
+	bound := make([]interface{}, len(args))
+	for i, arg := range args {
+		p, ok := arg.(NamedParam)
+		if !ok {
+			bound[i] = arg
+			continue
+		}
+		value, ok := params[p.Name]
+		if !ok {
+			return "", nil, fmt.Errorf("BindNamed: no value for parameter %q", p.Name)
+		}
+		bound[i] = value
+	}
+	return sql, bound, nil
+}
+
+// Rebind converts the ? placeholders of sql to the placeholders of dialect, e.g. $1, $2 on
+// Postgres. Placeholders in quoted strings, quoted identifiers and comments are left alone,
+// and ?? is an escaped literal ?.
+//
+// Example usage:
+//
+//	sqltk.Rebind(sqldialect.Postgres(), "SELECT * FROM users WHERE id = ? AND name = '?'")
+//	// SELECT * FROM users WHERE id = $1 AND name = '?'
+func Rebind(dialect sqldialect.Dialect, sql string) string {
+	placeholderIdx := 1
+	return numberPlaceholders(dialect, sql, &placeholderIdx, nil)
+}
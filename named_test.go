@@ -0,0 +1,111 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestBindNamed(t *testing.T) {
+	params := map[string]interface{}{"tenant": 7, "status": "active", "since": "2024-01-01"}
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		params   map[string]interface{}
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "named condition on postgres",
+			builder: Select("id").From("users").
+				Where(NewNamedCondition("tenant_id = :tenant AND status = :status")).
+				WhereGreaterThan("age", 18).
+				WithDialect(sqldialect.Postgres()),
+			params:   params,
+			wantSQL:  `SELECT "id" FROM "users" WHERE tenant_id = $1 AND status = $2 AND age > $3`,
+			wantArgs: []interface{}{7, "active", 18},
+		},
+		{
+			name: "repeated parameter, cast and quoted colon",
+			builder: Select("id").From("events").
+				Where(NewNamedCondition("created_at::date >= :since AND note <> ':since' AND tenant_id = :tenant OR owner_id = :tenant")).
+				WithDialect(sqldialect.MySQL()),
+			params:   params,
+			wantSQL:  "SELECT `id` FROM `events` WHERE created_at::date >= ? AND note <> ':since' AND tenant_id = ? OR owner_id = ?",
+			wantArgs: []interface{}{"2024-01-01", 7, 7},
+		},
+		{
+			name:     "param as a value",
+			builder:  Update("users").Set("status", Param("status")).WhereEqual("id", 3).WithDialect(sqldialect.Postgres()),
+			params:   params,
+			wantSQL:  `UPDATE "users" SET status = $1 WHERE id = $2`,
+			wantArgs: []interface{}{"active", 3},
+		},
+		{
+			name:    "missing parameter",
+			builder: Select("id").From("users").Where(NewNamedCondition("id = :id")),
+			params:  params,
+			wantErr: true,
+		},
+		{
+			name:    "build error",
+			builder: Select("id"),
+			params:  params,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := BindNamed(tt.builder, tt.params)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqldialect.Dialect
+		sql     string
+		want    string
+	}{
+		{
+			name:    "postgres",
+			dialect: sqldialect.Postgres(),
+			sql:     "SELECT * FROM users WHERE id = ? AND name = '?' AND \"col?\" IN (?, ?)",
+			want:    "SELECT * FROM users WHERE id = $1 AND name = '?' AND \"col?\" IN ($2, $3)",
+		},
+		{
+			name:    "mysql is unchanged",
+			dialect: sqldialect.MySQL(),
+			sql:     "SELECT * FROM users WHERE id = ?",
+			want:    "SELECT * FROM users WHERE id = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(tt.dialect, tt.sql); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package sqltk
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// inListPattern matches an IN list whose items are all placeholders.
+var inListPattern = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+
+// Normalize reduces a SQL string to a shape that is stable across argument values, so that
+// queries from any source can be grouped in logs and metrics. It strips comments, replaces
+// string and numeric literals and placeholders ($1, ?) with ?, collapses IN lists to IN (...),
+// and collapses whitespace. Quoted identifiers are kept as written. A backslash escapes the next
+// character of a string, as in MySQL by default; use NormalizeDialect for SQL of a dialect.
+//
+// Example:
+//
+//	sqltk.Normalize("SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'bob'")
+//	// "SELECT * FROM users WHERE id IN (...) AND name = ?"
+func Normalize(sql string) string {
+	return normalize(sqllex.Lexer{Backslash: true}, sql)
+}
+
+// NormalizeDialect is Normalize for SQL written for dialect, or the global dialect if dialect
+// is nil: strings are read with the escapes of dialect, so a backslash ends a Postgres string
+// such as 'C:\' rather than escaping its quote.
+func NormalizeDialect(dialect sqldialect.Dialect, sql string) string {
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	lx := sqllex.For(dialect)
+	return normalize(sqllex.Lexer{Backslash: lx.Backslash, BackquoteBackslash: lx.BackquoteBackslash}, sql)
+}
+
+func normalize(lx sqllex.Lexer, sql string) string {
+	var sb strings.Builder
+	space := false
+	write := func(s string) {
+		// Spacing around list punctuation is dropped so "( a , b )" and "(a, b)" normalize alike.
+		if space && sb.Len() > 0 && s != "," && s != ")" && !strings.HasSuffix(sb.String(), "(") {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteString(s)
+	}
+
+	for _, t := range lx.Tokens(sql) {
+		space = space || t.Space
+		switch t.Kind {
+		case sqllex.Comment:
+			space = true
+		case sqllex.String, sqllex.Number, sqllex.Param:
+			write("?")
+		default:
+			write(t.Text)
+		}
+	}
+
+	return inListPattern.ReplaceAllString(sb.String(), "IN (...)")
+}
@@ -0,0 +1,88 @@
+package sqltk
+
+import (
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "literals and in list",
+			sql:  "SELECT * FROM users WHERE id IN (1, 2, 3) AND name = 'bob'",
+			want: "SELECT * FROM users WHERE id IN (...) AND name = ?",
+		},
+		{
+			name: "whitespace and comments",
+			sql:  "SELECT id\n\tFROM users -- all users\nWHERE /* active */ active = true",
+			want: "SELECT id FROM users WHERE active = true",
+		},
+		{
+			name: "postgres placeholders",
+			sql:  `SELECT "id" FROM "users" WHERE "id" IN ($1,$2) AND age > $3`,
+			want: `SELECT "id" FROM "users" WHERE "id" IN (...) AND age > ?`,
+		},
+		{
+			name: "escaped quotes and quoted identifiers",
+			sql:  "SELECT `it's` FROM t WHERE a = 'it''s' AND b = 'x\\'y'",
+			want: "SELECT `it's` FROM t WHERE a = ? AND b = ?",
+		},
+		{
+			name: "numbers inside identifiers are kept",
+			sql:  "SELECT t1.col_2 FROM t1 LIMIT 10 OFFSET 20",
+			want: "SELECT t1.col_2 FROM t1 LIMIT ? OFFSET ?",
+		},
+		{
+			name: "decimals and dollar quotes",
+			sql:  "SELECT 1.5, $$a 'b'$$, $fn$x$fn$",
+			want: "SELECT ?, ?, ?",
+		},
+		{
+			name: "not in",
+			sql:  "DELETE FROM t WHERE id NOT IN ( ?, ? , ? )",
+			want: "DELETE FROM t WHERE id NOT IN (...)",
+		},
+		{
+			name: "spacing around punctuation",
+			sql:  "SELECT COALESCE( a , b ) FROM t",
+			want: "SELECT COALESCE(a, b) FROM t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.sql); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDialect(t *testing.T) {
+	sql := `SELECT id FROM files WHERE path <> 'C:\' AND owner = $1 AND note = E'it\'s'`
+	want := `SELECT id FROM files WHERE path <> ? AND owner = ? AND note = ?`
+	if got := NormalizeDialect(sqldialect.Postgres(), sql); got != want {
+		t.Errorf("NormalizeDialect(Postgres, %q) = %q, want %q", sql, got, want)
+	}
+	sql = `SELECT id FROM t WHERE a = 'x\'y' AND b = 1`
+	want = `SELECT id FROM t WHERE a = ? AND b = ?`
+	if got := NormalizeDialect(sqldialect.BigQuery(), sql); got != want {
+		t.Errorf("NormalizeDialect(BigQuery, %q) = %q, want %q", sql, got, want)
+	}
+}
+
+func TestNormalizeMatchesBuilderOutput(t *testing.T) {
+	built, _, err := Select("id").From("users").WhereIn("id", 1, 2, 3).WithDialect(sqldialect.NoQuoteIdent()).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logged := "SELECT id FROM users WHERE id IN (7, 8)"
+	if Normalize(built) != Normalize(logged) {
+		t.Errorf("got %q and %q, want equal", Normalize(built), Normalize(logged))
+	}
+}
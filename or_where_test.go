@@ -0,0 +1,129 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestOrWhereAndWhereGroup(t *testing.T) {
+	active := NewStringCondition("status = ?", "active")
+	admin := NewStringCondition("role = ?", "admin")
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "select or where",
+			builder:  Select("id").From("users").Where(active).OrWhere(admin),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users WHERE (status = ?) OR (role = ?)",
+			wantArgs: []interface{}{"active", "admin"},
+		},
+		{
+			name: "or where then and",
+			builder: Select("id").From("users").Where(active).OrWhere(admin).
+				WhereEqual("tenant_id", 7),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "users" WHERE ((status = $1) OR (role = $2)) AND tenant_id = $3`,
+			wantArgs: []interface{}{"active", "admin", 7},
+		},
+		{
+			name: "chained or where",
+			builder: Select("id").From("users").WhereEqual("a", 1).WhereEqual("b", 2).
+				OrWhere(NewStringCondition("c = ?", 3)).OrWhere(NewStringCondition("d = ?", 4)),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users WHERE (a = ? AND b = ?) OR (c = ?) OR (d = ?)",
+			wantArgs: []interface{}{1, 2, 3, 4},
+		},
+		{
+			name:     "or where without earlier conditions",
+			builder:  Select("id").From("users").OrWhere(admin),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users WHERE role = ?",
+			wantArgs: []interface{}{"admin"},
+		},
+		{
+			name: "select where group",
+			builder: Select("id").From("users").WithDialect(sqldialect.Postgres()).WhereEqual("tenant_id", 7).
+				WhereGroup(func(c *ConditionBuilder) {
+					c.Equal("status", "active").Or(NewCond().WithDialect(sqldialect.Postgres()).Equal("role", "admin"))
+				}),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "users" WHERE tenant_id = $1 AND (("status" = $2) OR ("role" = $3))`,
+			wantArgs: []interface{}{7, "active", "admin"},
+		},
+		{
+			name:     "empty where group",
+			builder:  Select("id").From("users").WhereGroup(func(c *ConditionBuilder) {}),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "update or where",
+			builder:  Update("users").Set("locked", true).Where(active).OrWhere(admin),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "UPDATE users SET locked = ? WHERE (status = ?) OR (role = ?)",
+			wantArgs: []interface{}{true, "active", "admin"},
+		},
+		{
+			name: "delete where group",
+			builder: Delete("sessions").WhereGroup(func(c *ConditionBuilder) {
+				c.LessThan("expires_at", 100).Or(NewCond().WithDialect(sqldialect.NoQuoteIdent()).IsNull("user_id"))
+			}),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "DELETE FROM sessions WHERE ((expires_at < ?) OR (user_id IS NULL))",
+			wantArgs: []interface{}{100},
+		},
+		{
+			name:    "nil or where",
+			builder: Select("id").From("users").Where(active).OrWhere(nil),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "nil where group",
+			builder: Delete("sessions").WhereGroup(nil),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sql string
+			var args []interface{}
+			var err error
+			switch b := tt.builder.(type) {
+			case *SelectBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			case *UpdateBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			case *DeleteBuilder:
+				sql, args, err = b.WithDialect(tt.dialect).Build()
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
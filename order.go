@@ -0,0 +1,168 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+// OrderDirection is the sort direction of an ORDER BY term.
+type OrderDirection int
+
+const (
+	Asc OrderDirection = iota
+	Desc
+)
+
+// NullsOrder sets where NULL values sort in an ORDER BY term.
+type NullsOrder int
+
+const (
+	// NullsDefault leaves NULL ordering to the database.
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
+// orderTerm is a single ORDER BY term.
+type orderTerm struct {
+	expr   string // column name, or SQL expression if raw is set
+	raw    bool
+	suffix string // direction as written, e.g. "DESC"
+	nulls  NullsOrder
+	args   []interface{} // args of an Expr term
+	binder Binder        // expression rendered for the dialect, such as a CaseExpr
+}
+
+// newOrderTerm parses an OrderBy argument. A string is a column optionally followed by
+// a direction (e.g. "created_at DESC"); a string that is not a plain column name, such as
+// "COUNT(*) DESC", is used as written.
+func newOrderTerm(expr interface{}) (orderTerm, error) {
+	switch e := expr.(type) {
+	case sqlfunc.SqlFunc:
+		if err := e.Err(); err != nil {
+			return orderTerm{}, fmt.Errorf("OrderBy: %w", err)
+		}
+		return orderTerm{expr: string(e), raw: true}, nil
+	case raw.Raw:
+		return orderTerm{expr: string(e), raw: true}, nil
+	case SQLExpr:
+		return orderTerm{expr: e.SQL, raw: true, args: e.Args}, nil
+	case Binder:
+		if err := exprErr(e); err != nil {
+			return orderTerm{}, fmt.Errorf("OrderBy: %w", err)
+		}
+		return orderTerm{raw: true, binder: e}, nil
+	case string:
+		col, suffix := e, ""
+		if idx := strings.LastIndexAny(e, " "); idx > 0 {
+			switch dir := strings.ToUpper(strings.TrimSpace(e[idx+1:])); dir {
+			case "ASC", "DESC":
+				col, suffix = strings.TrimSpace(e[:idx]), dir
+			}
+		}
+		return orderTerm{expr: col, raw: !isPlainIdent(col), suffix: suffix}, nil
+	default:
+		return orderTerm{}, errors.New("OrderBy: expr must be string, sq.Raw, sq.SQLExpr, or sq.Binder")
+	}
+}
+
+// newOrderExprTerm builds a structured ORDER BY term. A string expr is a column name.
+func newOrderExprTerm(expr interface{}, dir OrderDirection, nulls NullsOrder) (orderTerm, error) {
+	var t orderTerm
+	switch e := expr.(type) {
+	case sqlfunc.SqlFunc:
+		if err := e.Err(); err != nil {
+			return orderTerm{}, fmt.Errorf("OrderByExpr: %w", err)
+		}
+		t = orderTerm{expr: string(e), raw: true}
+	case raw.Raw:
+		t = orderTerm{expr: string(e), raw: true}
+	case SQLExpr:
+		t = orderTerm{expr: e.SQL, raw: true, args: e.Args}
+	case Binder:
+		if err := exprErr(e); err != nil {
+			return orderTerm{}, fmt.Errorf("OrderByExpr: %w", err)
+		}
+		t = orderTerm{raw: true, binder: e}
+	case string:
+		if !isPlainIdent(e) {
+			return orderTerm{}, fmt.Errorf("OrderByExpr: %q is not a column name, use sq.Raw for expressions", e)
+		}
+		t = orderTerm{expr: e}
+	default:
+		return orderTerm{}, errors.New("OrderByExpr: expr must be string, sq.Raw, sq.SQLExpr, sqlfunc.SqlFunc, or sq.Binder")
+	}
+
+	switch dir {
+	case Asc:
+		t.suffix = "ASC"
+	case Desc:
+		t.suffix = "DESC"
+	default:
+		return orderTerm{}, errors.New("OrderByExpr: invalid direction")
+	}
+	if nulls < NullsDefault || nulls > NullsLast {
+		return orderTerm{}, errors.New("OrderByExpr: invalid NULLS ordering")
+	}
+	t.nulls = nulls
+	return t, nil
+}
+
+// isPlainIdent reports whether s is a column name, optionally table-qualified.
+func isPlainIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// buildOrderBys renders ORDER BY terms and returns the args of their placeholders, which use ?.
+// MySQL has no NULLS FIRST/LAST, so it is emulated with a leading "expr IS NULL" term.
+func buildOrderBys(dialect sqldialect.Dialect, terms []orderTerm) ([]string, []interface{}, error) {
+	var orderBys []string
+	var args []interface{}
+	for _, t := range terms {
+		expr := t.expr
+		if !t.raw {
+			expr = quoteQualifiedIdent(dialect, expr)
+		}
+		if t.binder != nil {
+			var err error
+			if expr, t.args, err = bindExpr(dialect, t.binder); err != nil {
+				return nil, nil, err
+			}
+		}
+		term := expr
+		if t.suffix != "" {
+			term += " " + t.suffix
+		}
+
+		switch {
+		case t.nulls == NullsDefault:
+		case baseDialect(dialect) == sqldialect.MySQL():
+			if t.nulls == NullsFirst {
+				orderBys = append(orderBys, expr+" IS NULL DESC")
+			} else {
+				orderBys = append(orderBys, expr+" IS NULL")
+			}
+			args = append(args, t.args...)
+		case t.nulls == NullsFirst:
+			term += " NULLS FIRST"
+		default:
+			term += " NULLS LAST"
+		}
+		orderBys = append(orderBys, term)
+		args = append(args, t.args...)
+	}
+	return orderBys, args, nil
+}
@@ -0,0 +1,389 @@
+package sqltk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// OrderKey is a column and direction of a keyset pagination order.
+type OrderKey struct {
+	Column string
+	Dir    OrderDirection
+}
+
+// seekSpec holds the keyset pagination set by SeekAfter.
+type seekSpec struct {
+	keys   []OrderKey
+	values []interface{}
+}
+
+// SeekAfter adds keyset (cursor) pagination: an ORDER BY for keys and a WHERE condition selecting
+// rows that sort after values, the key values of the last row of the previous page. With no
+// values (the first page), only the ORDER BY is added. The keys must uniquely order the rows
+// (end with a unique column such as the primary key) and must not be NULL.
+//
+// Postgres, MySQL, and DuckDB use a row comparison when all keys have the same direction, e.g.
+// ("created_at", "id") < (?, ?); otherwise the comparison is expanded into OR-ed terms.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id", "title").From("posts").
+//		SeekAfter([]sqltk.OrderKey{{"created_at", sqltk.Desc}, {"id", sqltk.Desc}}, lastCreatedAt, lastID).
+//		Limit(20)
+func (b *SelectBuilder) SeekAfter(keys []OrderKey, values ...interface{}) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	if len(keys) == 0 {
+		b.whereClause.err = errors.New("SeekAfter: at least one key is required")
+		return b
+	}
+	if len(values) > 0 && len(values) != len(keys) {
+		b.whereClause.err = fmt.Errorf("SeekAfter: got %d values for %d keys", len(values), len(keys))
+		return b
+	}
+	if b.seek != nil {
+		b.whereClause.err = errors.New("SeekAfter: already set")
+		return b
+	}
+	for _, k := range keys {
+		term, err := newOrderExprTerm(k.Column, k.Dir, NullsDefault)
+		if err != nil {
+			b.whereClause.err = fmt.Errorf("SeekAfter: %w", err)
+			return b
+		}
+		b.orderBy = append(b.orderBy, term)
+	}
+	if len(values) > 0 {
+		b.seek = &seekSpec{keys: keys, values: values}
+		b.whereClause.recordCall()
+	}
+	b.recordCall("ORDER BY")
+	return b
+}
+
+// DeterministicOrder makes Build fail if the query is paginated, with Limit, Offset, or
+// SeekAfter, but its ORDER BY does not end in a unique column: rows that tie on the order may
+// then be returned on two pages or on none. The columns named by unique, optionally
+// table-qualified, are unique, as are those marked Unique in the schema set with WithSchema.
+// An unqualified name in unique matches the column of any table.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id", "title").From("posts").
+//		OrderBy("created_at DESC").OrderBy("id DESC").
+//		Limit(20).Offset(40).
+//		DeterministicOrder("id")
+func (b *SelectBuilder) DeterministicOrder(unique ...string) *SelectBuilder {
+	b.deterministic = true
+	b.uniqueColumns = append(b.uniqueColumns, unique...)
+	return b
+}
+
+// checkDeterministicOrder checks the ORDER BY of a paginated query, see DeterministicOrder.
+func (b *SelectBuilder) checkDeterministicOrder() error {
+	if !b.deterministic || !b.limitSet && !b.offsetSet && b.seek == nil {
+		return nil
+	}
+	if len(b.orderBy) == 0 {
+		return errors.New("DeterministicOrder: paginated query has no ORDER BY")
+	}
+	last := b.orderBy[len(b.orderBy)-1]
+	if last.raw {
+		return fmt.Errorf("DeterministicOrder: ORDER BY ends in expression %q, not a unique column", last.expr)
+	}
+	if !b.isUniqueColumn(last.expr) {
+		return fmt.Errorf("DeterministicOrder: ORDER BY ends in %q, which is not a unique column", last.expr)
+	}
+	return nil
+}
+
+// isUniqueColumn reports whether a column, optionally table-qualified, is unique.
+func (b *SelectBuilder) isUniqueColumn(column string) bool {
+	qualifier, name := "", column
+	if idx := strings.LastIndex(column, "."); idx >= 0 {
+		qualifier, name = column[:idx], column[idx+1:]
+	}
+	for _, u := range b.uniqueColumns {
+		uq, un := "", u
+		if idx := strings.LastIndex(u, "."); idx >= 0 {
+			uq, un = u[:idx], u[idx+1:]
+		}
+		if strings.EqualFold(un, name) && (uq == "" || strings.EqualFold(uq, qualifier)) {
+			return true
+		}
+	}
+	if b.schema == nil {
+		return false
+	}
+	// An unqualified column is taken to be of the FROM table.
+	tables := []interface{}{b.tableClauseInterface.table}
+	if qualifier != "" {
+		tables = append(tables, b.fromTables...)
+		for _, j := range b.joins {
+			tables = append(tables, j.table)
+		}
+	}
+	for _, t := range tables {
+		if qualifier != "" && !strings.EqualFold(tableAlias(t), qualifier) {
+			continue
+		}
+		table := ""
+		switch t := t.(type) {
+		case string:
+			if fields := strings.Fields(t); len(fields) > 0 {
+				table = fields[0]
+			}
+		case AliasExpr:
+			table, _ = t.Expr.(string)
+		}
+		if table != "" && b.schema.isUnique(table, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// condition renders the seek condition with ? placeholders.
+func (s *seekSpec) condition(dialect sqldialect.Dialect) (string, []interface{}) {
+	cols := make([]string, len(s.keys))
+	sameDir := true
+	for i, k := range s.keys {
+		cols[i] = quoteQualifiedIdent(dialect, k.Column)
+		if k.Dir != s.keys[0].Dir {
+			sameDir = false
+		}
+	}
+
+	base := baseDialect(dialect)
+	rowCompare := base == sqldialect.Postgres() || base == sqldialect.MySQL() || base == sqldialect.DuckDB()
+	if len(s.keys) == 1 || sameDir && rowCompare {
+		op := seekOp(s.keys[0].Dir)
+		if len(s.keys) == 1 {
+			return cols[0] + " " + op + " ?", s.values
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+		return "(" + strings.Join(cols, ", ") + ") " + op + " (" + placeholders + ")", s.values
+	}
+
+	// (a < ? OR (a = ? AND b > ?) OR ...)
+	var terms []string
+	var args []interface{}
+	for i, k := range s.keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, cols[j]+" = ?")
+			args = append(args, s.values[j])
+		}
+		parts = append(parts, cols[i]+" "+seekOp(k.Dir)+" ?")
+		args = append(args, s.values[i])
+		if len(parts) == 1 {
+			terms = append(terms, parts[0])
+		} else {
+			terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		}
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// seekOp returns the comparison that selects rows after a key value in the given direction.
+func seekOp(dir OrderDirection) string {
+	if dir == Desc {
+		return "<"
+	}
+	return ">"
+}
+
+// PageRequest requests a page of keyset pagination, as GraphQL connections do: the First rows
+// After a cursor, or the Last rows Before a cursor. Cursors are those of the Edges and PageInfo
+// of a Connection; with no cursor the first or last page is requested. Keys are as for SeekAfter.
+type PageRequest struct {
+	Keys   []OrderKey
+	First  int
+	After  string
+	Last   int
+	Before string
+}
+
+// PageQuery is the query for a page, returned by Page. It fetches one row more than the page
+// size, to tell whether more rows follow, without counting them.
+type PageQuery struct {
+	query    *SelectBuilder
+	keys     int
+	size     int
+	backward bool // Last/Before: the query runs in reverse order
+	cursor   bool // After or Before is set
+	err      error
+}
+
+// Page returns the query for the page req of b, which is not changed. Run the query and pass
+// its rows to NewConnection for the page with its cursors.
+//
+// Example usage:
+//
+//	page := sqltk.Select("id", "title").From("posts").Page(sqltk.PageRequest{
+//		Keys:  []sqltk.OrderKey{{"created_at", sqltk.Desc}, {"id", sqltk.Desc}},
+//		First: 20,
+//		After: after,
+//	})
+//	sql, args, err := page.Build()
+//	// ... scan the rows into posts
+//	conn, err := sqltk.NewConnection(page, posts, func(p Post) []interface{} {
+//		return []interface{}{p.CreatedAt, p.ID}
+//	})
+func (b *SelectBuilder) Page(req PageRequest) *PageQuery {
+	p := &PageQuery{keys: len(req.Keys)}
+	switch {
+	case req.First < 0 || req.Last < 0:
+		p.err = errors.New("Page: First and Last must not be negative")
+	case (req.First > 0) == (req.Last > 0):
+		p.err = errors.New("Page: exactly one of First and Last must be set")
+	case req.First > 0 && req.Before != "" || req.Last > 0 && req.After != "":
+		p.err = errors.New("Page: use After with First, and Before with Last")
+	}
+	if p.err != nil {
+		return p
+	}
+
+	keys, cursor, size := req.Keys, req.After, req.First
+	if req.Last > 0 {
+		p.backward = true
+		keys, cursor, size = slices.Clone(req.Keys), req.Before, req.Last
+		for i, k := range keys {
+			if k.Dir == Desc {
+				keys[i].Dir = Asc
+			} else {
+				keys[i].Dir = Desc
+			}
+		}
+	}
+	p.size, p.cursor = size, cursor != ""
+
+	var values []interface{}
+	if cursor != "" {
+		if values, p.err = decodeCursor(cursor); p.err != nil {
+			return p
+		}
+		if len(values) != len(keys) {
+			p.err = fmt.Errorf("Page: cursor has %d values for %d keys", len(values), len(keys))
+			return p
+		}
+	}
+	p.query = b.Clone().SeekAfter(keys, values...).Limit(size + 1)
+	return p
+}
+
+// Build implements Builder.
+func (p *PageQuery) Build() (string, []interface{}, error) {
+	if p.err != nil {
+		return "", nil, p.err
+	}
+	return p.query.Build()
+}
+
+// BuildTo builds the query for the page and writes its SQL to w, returning the args. See BuildTo.
+func (p *PageQuery) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, p)
+}
+
+// Connection is a page of rows with their cursors, shaped like a GraphQL connection.
+type Connection[T any] struct {
+	Edges    []Edge[T] `json:"edges"`
+	PageInfo PageInfo  `json:"pageInfo"`
+}
+
+// Edge is a row of a Connection with its cursor.
+type Edge[T any] struct {
+	Cursor string `json:"cursor"`
+	Node   T      `json:"node"`
+}
+
+// PageInfo tells whether there are rows before and after a page, and holds the cursors of its
+// first and last rows. When paging forward, HasPreviousPage is whether After was set, and when
+// paging backward, HasNextPage is whether Before was set, so no extra query is needed.
+type PageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor,omitempty"`
+	EndCursor       string `json:"endCursor,omitempty"`
+}
+
+// NewConnection returns the page for the rows fetched by the query of p, in the order of the
+// page keys. key returns the values of the keys of a row, in the order of PageRequest.Keys.
+func NewConnection[T any](p *PageQuery, rows []T, key func(T) []interface{}) (*Connection[T], error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	more := len(rows) > p.size
+	if more {
+		rows = rows[:p.size]
+	}
+	conn := &Connection[T]{Edges: make([]Edge[T], len(rows))}
+	for i, row := range rows {
+		values := key(row)
+		if len(values) != p.keys {
+			return nil, fmt.Errorf("NewConnection: row %d has %d key values for %d keys", i+1, len(values), p.keys)
+		}
+		cursor, err := encodeCursor(values)
+		if err != nil {
+			return nil, fmt.Errorf("NewConnection: row %d: %w", i+1, err)
+		}
+		at := i
+		if p.backward {
+			at = len(rows) - 1 - i
+		}
+		conn.Edges[at] = Edge[T]{Cursor: cursor, Node: row}
+	}
+	if p.backward {
+		conn.PageInfo.HasPreviousPage, conn.PageInfo.HasNextPage = more, p.cursor
+	} else {
+		conn.PageInfo.HasNextPage, conn.PageInfo.HasPreviousPage = more, p.cursor
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn, nil
+}
+
+// encodeCursor encodes key values as an opaque cursor, which keeps their types.
+func encodeCursor(values []interface{}) (string, error) {
+	args, err := argsAST(values)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the key values of a cursor made by encodeCursor.
+func decodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("Page: invalid cursor")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var args []ArgAST
+	if err := dec.Decode(&args); err != nil || len(args) == 0 {
+		return nil, errors.New("Page: invalid cursor")
+	}
+	values := make([]interface{}, len(args))
+	for i, a := range args {
+		if values[i], err = a.value(); err != nil {
+			return nil, errors.New("Page: invalid cursor")
+		}
+	}
+	return values, nil
+}
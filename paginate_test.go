@@ -0,0 +1,257 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectSeekAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "row comparison on postgres",
+			builder: Select("id").From("posts").WhereEqual("published", true).
+				SeekAfter([]OrderKey{{"created_at", Desc}, {"id", Desc}}, "2024-01-01", 42).
+				Limit(20),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "id" FROM "posts" WHERE published = $1 AND ("created_at", "id") < ($2, $3) ORDER BY "created_at" DESC, "id" DESC LIMIT 20`,
+			wantArgs: []interface{}{true, "2024-01-01", 42},
+		},
+		{
+			name:     "mixed directions are expanded",
+			builder:  Select("id").From("posts").SeekAfter([]OrderKey{{"created_at", Desc}, {"id", Asc}}, "2024-01-01", 42),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `id` FROM `posts` WHERE (`created_at` < ? OR (`created_at` = ? AND `id` > ?)) ORDER BY `created_at` DESC, `id` ASC",
+			wantArgs: []interface{}{"2024-01-01", "2024-01-01", 42},
+		},
+		{
+			name:     "dialect without row comparison",
+			builder:  Select("id").From("posts").SeekAfter([]OrderKey{{"a", Asc}, {"b", Asc}, {"id", Asc}}, 1, 2, 3),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM posts WHERE (a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)) ORDER BY a ASC, b ASC, id ASC",
+			wantArgs: []interface{}{1, 1, 2, 1, 2, 3},
+		},
+		{
+			name:     "single key",
+			builder:  Select("id").From("posts").SeekAfter([]OrderKey{{"id", Asc}}, 10).Limit(5),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM posts WHERE id > ? ORDER BY id ASC LIMIT 5",
+			wantArgs: []interface{}{10},
+		},
+		{
+			name:     "first page",
+			builder:  Select("id").From("posts").SeekAfter([]OrderKey{{"id", Desc}}).Limit(5),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM posts ORDER BY id DESC LIMIT 5",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "value count mismatch",
+			builder: Select("id").From("posts").SeekAfter([]OrderKey{{"a", Asc}, {"id", Asc}}, 1),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "no keys",
+			builder: Select("id").From("posts").SeekAfter(nil),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("build does not mutate the builder", func(t *testing.T) {
+		q := Select("id").From("posts").SeekAfter([]OrderKey{{"id", Asc}}, 1).WithDialect(sqldialect.NoQuoteIdent())
+		first, _, _ := q.Build()
+		second, _, _ := q.Build()
+		if first != second {
+			t.Errorf("got %q then %q", first, second)
+		}
+	})
+}
+
+func TestSelectPage(t *testing.T) {
+	type post struct {
+		ID   int64
+		Rank string
+	}
+	keys := []OrderKey{{"rank", Desc}, {"id", Asc}}
+	key := func(p post) []interface{} { return []interface{}{p.Rank, p.ID} }
+	base := Select("id", "rank").From("posts").WithDialect(sqldialect.Postgres())
+
+	first := base.Page(PageRequest{Keys: keys, First: 2})
+	sql, args, err := first.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `SELECT "id", "rank" FROM "posts" ORDER BY "rank" DESC, "id" ASC LIMIT 3`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	conn, err := NewConnection(first, []post{{1, "c"}, {2, "b"}, {3, "a"}}, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conn.Edges) != 2 || conn.Edges[1].Node.ID != 2 || !conn.PageInfo.HasNextPage || conn.PageInfo.HasPreviousPage {
+		t.Fatalf("got %+v, want the first two rows with a next page", conn)
+	}
+	if conn.PageInfo.EndCursor != conn.Edges[1].Cursor {
+		t.Errorf("got end cursor %q, want %q", conn.PageInfo.EndCursor, conn.Edges[1].Cursor)
+	}
+
+	next := base.Page(PageRequest{Keys: keys, First: 2, After: conn.PageInfo.EndCursor})
+	sql, args, err = next.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `SELECT "id", "rank" FROM "posts" WHERE ("rank" < $1 OR ("rank" = $2 AND "id" > $3)) ORDER BY "rank" DESC, "id" ASC LIMIT 3`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if want := []interface{}{"b", "b", int64(2)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got args %#v, want %#v", args, want)
+	}
+
+	prev := base.Page(PageRequest{Keys: keys, Last: 2, Before: conn.PageInfo.EndCursor})
+	sql, args, err = prev.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `SELECT "id", "rank" FROM "posts" WHERE ("rank" > $1 OR ("rank" = $2 AND "id" < $3)) ORDER BY "rank" ASC, "id" DESC LIMIT 3`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	// The backward query returns the rows nearest the cursor first.
+	conn, err = NewConnection(prev, []post{{1, "c"}}, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conn.Edges) != 1 || conn.PageInfo.HasPreviousPage || !conn.PageInfo.HasNextPage {
+		t.Errorf("got %+v, want one row with a next page only", conn)
+	}
+
+	last, err := NewConnection(base.Page(PageRequest{Keys: keys, Last: 2}), []post{{9, "a"}, {8, "b"}, {7, "c"}}, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := []int64{last.Edges[0].Node.ID, last.Edges[1].Node.ID}; !reflect.DeepEqual(got, []int64{8, 9}) || !last.PageInfo.HasPreviousPage {
+		t.Errorf("got rows %v and %+v, want [8 9] with a previous page", got, last.PageInfo)
+	}
+
+	for name, req := range map[string]PageRequest{
+		"no size":        {Keys: keys},
+		"first and last": {Keys: keys, First: 1, Last: 1},
+		"before forward": {Keys: keys, First: 1, Before: conn.PageInfo.EndCursor},
+		"bad cursor":     {Keys: keys, First: 1, After: "!!"},
+		"cursor keys":    {Keys: keys[:1], First: 1, After: conn.PageInfo.EndCursor},
+	} {
+		if _, _, err := base.Page(req).Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSelectDeterministicOrder(t *testing.T) {
+	schema := NewSchema().
+		AddTable("posts", SchemaColumn{Name: "id", Type: IntType, Unique: true}, SchemaColumn{Name: "created_at", Type: TimeType}).
+		AddTable("users", SchemaColumn{Name: "id", Type: IntType, Unique: true}, SchemaColumn{Name: "name", Type: StringType})
+
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		wantErr bool
+	}{
+		{
+			name:    "not paginated",
+			builder: Select("id").From("posts").OrderBy("created_at").DeterministicOrder(),
+		},
+		{
+			name:    "listed unique column",
+			builder: Select("id").From("posts").OrderBy("created_at DESC").OrderBy("id DESC").Limit(20).DeterministicOrder("id"),
+		},
+		{
+			name:    "qualified listed column",
+			builder: Select("p.id").From("posts p").OrderBy("p.created_at").OrderBy("p.id").Offset(20).DeterministicOrder("p.id"),
+		},
+		{
+			name:    "unique column of the schema",
+			builder: Select("id").From("posts").WithSchema(schema).OrderBy("created_at").OrderBy("id").Limit(20).DeterministicOrder(),
+		},
+		{
+			name: "unique column of a joined table",
+			builder: Select("p.id").From("posts p").Join("users u").On("u.id", "p.user_id").WithSchema(schema).
+				OrderBy("u.name").OrderBy("u.id").Limit(20).DeterministicOrder(),
+		},
+		{
+			name:    "keyset pagination",
+			builder: Select("id").From("posts").SeekAfter([]OrderKey{{"created_at", Desc}, {"id", Desc}}, "2024-01-01", 42).DeterministicOrder("id"),
+		},
+		{
+			name:    "no order",
+			builder: Select("id").From("posts").Limit(20).DeterministicOrder("id"),
+			wantErr: true,
+		},
+		{
+			name:    "not ending in a unique column",
+			builder: Select("id").From("posts").OrderBy("id").OrderBy("created_at").Limit(20).DeterministicOrder("id"),
+			wantErr: true,
+		},
+		{
+			name:    "qualifier of another table",
+			builder: Select("p.id").From("posts p").OrderBy("u.id").Limit(20).DeterministicOrder("p.id"),
+			wantErr: true,
+		},
+		{
+			name:    "expression",
+			builder: Select("id").From("posts").OrderBy("LOWER(title)").Limit(20).DeterministicOrder("id"),
+			wantErr: true,
+		},
+		{
+			name:    "not unique in the schema",
+			builder: Select("id").From("posts").WithSchema(schema).OrderBy("created_at").Limit(20).DeterministicOrder(),
+			wantErr: true,
+		},
+		{
+			name:    "keyset pagination not ending in a unique column",
+			builder: Select("id").From("posts").SeekAfter([]OrderKey{{"created_at", Desc}}, "2024-01-01").DeterministicOrder("id"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.Build()
+			if tt.wantErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
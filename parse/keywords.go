@@ -0,0 +1,49 @@
+package parse
+
+import "strings"
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentByte(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
+
+// isBareIdent reports whether name can be written without quotes.
+func isBareIdent(name string) bool {
+	if name == "" || !isIdentStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isIdentByte(name[i]) {
+			return false
+		}
+	}
+	return !reserved[strings.ToUpper(name)]
+}
+
+// reserved are the keywords that cannot be bare identifiers or aliases.
+var reserved = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"CASE": true, "CROSS": true, "DESC": true, "DISTINCT": true, "ELSE": true, "END": true,
+	"EXCEPT": true, "EXISTS": true, "FALSE": true, "FETCH": true, "FOR": true, "FROM": true,
+	"FULL": true, "GROUP": true, "HAVING": true, "ILIKE": true, "IN": true, "INNER": true,
+	"INTERSECT": true, "IS": true, "JOIN": true, "LEFT": true, "LIKE": true, "LIMIT": true,
+	"LOCK": true, "NATURAL": true, "NOT": true, "NULL": true, "NULLS": true, "OFFSET": true,
+	"ON": true, "OR": true, "ORDER": true, "OUTER": true, "RETURNING": true, "RIGHT": true,
+	"SELECT": true, "SET": true, "SOME": true, "THEN": true, "TRUE": true, "UNION": true,
+	"USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true, "WITH": true,
+}
+
+// spaced are the keywords followed by a space before a parenthesis, unlike function names.
+var spaced = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "LIKE": true, "ILIKE": true,
+	"BETWEEN": true, "EXISTS": true, "ANY": true, "ALL": true, "SOME": true, "CASE": true,
+	"WHEN": true, "THEN": true, "ELSE": true, "AS": true, "ON": true, "SELECT": true, "FROM": true,
+	"WHERE": true, "VALUES": true, "USING": true,
+}
@@ -0,0 +1,977 @@
+// Package parse parses a subset of SELECT, INSERT, UPDATE and DELETE statements into sqltk
+// builders, so that hand-written queries can be modified, for example with a tenant filter
+// or a limit, and built again.
+//
+// Tables and plain columns become builder tables and columns, which are quoted for the
+// dialect of the builder. Conditions and other expressions are kept as written, except that
+// quoted identifiers lose their quotes where they do not need them, and their ?, $n or :n
+// placeholders are bound to the args given to the parser. WHERE, HAVING and ON clauses are
+// split at their top-level ANDs, so conditions added afterwards are ANDed with the whole
+// clause. Double quotes and backticks quote identifiers; strings take single quotes.
+//
+// Not supported: WITH, UNION and the other set operations, table functions, INSERT ...
+// SELECT, ON CONFLICT, RETURNING, schema-qualified tables in INSERT, UPDATE and DELETE,
+// and UPDATE or DELETE with joins.
+//
+// Example usage:
+//
+//	q, err := parse.Select("SELECT id, name FROM users WHERE active = ? OR admin", true)
+//	if err != nil {
+//		return err
+//	}
+//	q.WhereEqual("tenant_id", tenantID).Limit(100).WithDialect(sqldialect.Postgres())
+//	// SELECT "id", "name" FROM "users" WHERE (active = $1 OR admin) AND tenant_id = $2 LIMIT 100
+package parse
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/raw"
+)
+
+// Parse parses a SELECT, INSERT, UPDATE or DELETE statement into the matching builder.
+func Parse(sql string, args ...interface{}) (sqltk.Builder, error) {
+	toks, err := tokens(sql)
+	if err != nil {
+		return nil, err
+	}
+	switch t := toks[0]; {
+	case t.Is("SELECT"):
+		return builder(Select(sql, args...))
+	case t.Is("INSERT"):
+		return builder(Insert(sql, args...))
+	case t.Is("UPDATE"):
+		return builder(Update(sql, args...))
+	case t.Is("DELETE"):
+		return builder(Delete(sql, args...))
+	default:
+		return nil, fmt.Errorf("parse: unexpected %s at offset %d", describe(t), t.Pos)
+	}
+}
+
+// builder returns b as a Builder, or a nil Builder on error.
+func builder[T sqltk.Builder](b T, err error) (sqltk.Builder, error) {
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Select parses a SELECT statement.
+func Select(sql string, args ...interface{}) (*sqltk.SelectBuilder, error) {
+	return run(sql, args, (*parser).selectStmt)
+}
+
+// Insert parses an INSERT INTO ... (columns) VALUES ... statement. Literal values become
+// args; other values, such as function calls, are kept as expressions.
+func Insert(sql string, args ...interface{}) (*sqltk.InsertBuilder, error) {
+	return run(sql, args, (*parser).insertStmt)
+}
+
+// Update parses an UPDATE statement. As with Insert, literal values become args.
+func Update(sql string, args ...interface{}) (*sqltk.UpdateBuilder, error) {
+	return run(sql, args, (*parser).updateStmt)
+}
+
+// Delete parses a DELETE statement.
+func Delete(sql string, args ...interface{}) (*sqltk.DeleteBuilder, error) {
+	return run(sql, args, (*parser).deleteStmt)
+}
+
+// eof is the kind of the token that tokens appends at the end of a statement.
+const eof sqllex.Kind = -1
+
+// tokens splits a statement into tokens with sqllex, dropping comments, and appends an eof
+// token. Strings have no backslash escapes, and ?, $n and :n are all placeholders.
+func tokens(sql string) ([]sqllex.Token, error) {
+	var toks []sqllex.Token
+	for _, t := range (sqllex.Lexer{}).Tokens(sql) {
+		switch {
+		case t.Unterminated && t.Kind == sqllex.Comment:
+			return nil, fmt.Errorf("parse: unterminated comment at offset %d", t.Pos)
+		case t.Unterminated:
+			return nil, fmt.Errorf("parse: unterminated %c at offset %d", strings.TrimLeft(t.Text, "Ee")[0], t.Pos)
+		case t.Kind == sqllex.Comment:
+			continue
+		}
+		toks = append(toks, t)
+	}
+	return append(toks, sqllex.Token{Kind: eof, Pos: len(sql)}), nil
+}
+
+// describe returns t as written, quoted, for an error message.
+func describe(t sqllex.Token) string {
+	if t.Kind == eof {
+		return "end of input"
+	}
+	return strconv.Quote(t.Text)
+}
+
+// run parses sql with stmt, and checks that stmt read all of it and bound all args.
+func run[T any](sql string, args []interface{}, stmt func(*parser) (T, error)) (T, error) {
+	var zero T
+	toks, err := tokens(sql)
+	if err != nil {
+		return zero, err
+	}
+	p := &parser{toks: toks, args: args}
+	b, err := stmt(p)
+	if err != nil {
+		return zero, err
+	}
+	p.acceptPunct(";")
+	if p.peek().Kind != eof {
+		return zero, p.unexpected()
+	}
+	used := p.next
+	if p.style == '$' {
+		used = p.maxN
+	}
+	if used != len(args) {
+		return zero, fmt.Errorf("parse: got %d args for %d placeholders", len(args), used)
+	}
+	return b, nil
+}
+
+// parser is a recursive descent parser over the tokens of a statement.
+type parser struct {
+	toks  []sqllex.Token
+	pos   int
+	args  []interface{}
+	style byte // '?' or '$', once a placeholder is bound
+	next  int  // number of ? placeholders bound
+	maxN  int  // highest $n placeholder bound
+}
+
+// clauseKeywords end an expression.
+var clauseKeywords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true,
+	"OFFSET": true, "FOR": true, "LOCK": true, "UNION": true, "INTERSECT": true, "EXCEPT": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true,
+	"NATURAL": true, "ON": true, "USING": true, "WINDOW": true, "FETCH": true, "RETURNING": true,
+}
+
+func (p *parser) peek() sqllex.Token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) peekAt(n int) sqllex.Token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+// accept consumes the keywords kws if they come next, all or none.
+func (p *parser) accept(kws ...string) bool {
+	for i, kw := range kws {
+		if !p.peekAt(i).Is(kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *parser) expect(kws ...string) error {
+	for _, kw := range kws {
+		if !p.accept(kw) {
+			return p.unexpected()
+		}
+	}
+	return nil
+}
+
+func (p *parser) acceptPunct(s string) bool {
+	if p.peek().IsPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.acceptPunct(s) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	return fmt.Errorf("parse: unexpected %s at offset %d", describe(t), t.Pos)
+}
+
+// name parses an identifier and returns it without quotes.
+func (p *parser) name() (string, error) {
+	t := p.peek()
+	if t.Kind == sqllex.Quoted && t.Val != "" || t.Kind == sqllex.Word && !reserved[strings.ToUpper(t.Text)] {
+		p.pos++
+		return t.Val, nil
+	}
+	return "", p.unexpected()
+}
+
+// names parses a parenthesized list of identifiers.
+func (p *parser) names() ([]string, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return names, p.expectPunct(")")
+}
+
+// alias parses an optional alias, with or without AS.
+func (p *parser) alias() (string, error) {
+	if p.accept("AS") {
+		return p.name()
+	}
+	if t := p.peek(); t.Kind == sqllex.Quoted || t.Kind == sqllex.Word && !reserved[strings.ToUpper(t.Text)] {
+		return p.name()
+	}
+	return "", nil
+}
+
+// tableName parses the unqualified table of an INSERT, UPDATE or DELETE.
+func (p *parser) tableName() (string, error) {
+	table, err := p.name()
+	if err != nil {
+		return "", err
+	}
+	if p.peek().IsPunct(".") {
+		return "", fmt.Errorf("parse: schema-qualified table %s is not supported here", table)
+	}
+	return table, nil
+}
+
+// expr returns the tokens of the expression at the current position. It ends at a clause
+// keyword, a keyword of extra, a closing parenthesis, or a comma if comma is set.
+func (p *parser) expr(comma bool, extra ...string) ([]sqllex.Token, error) {
+	start, depth := p.pos, 0
+	for {
+		t := p.peek()
+		if t.Kind == eof {
+			if depth > 0 {
+				return nil, fmt.Errorf("parse: unclosed parenthesis at offset %d", t.Pos)
+			}
+			break
+		}
+		if depth == 0 && (t.IsPunct(")") || t.IsPunct(";") || comma && t.IsPunct(",") || p.endsExpr(t, extra)) {
+			break
+		}
+		if t.IsPunct("(") {
+			depth++
+		} else if t.IsPunct(")") {
+			depth--
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.unexpected()
+	}
+	return p.toks[start:p.pos], nil
+}
+
+// endsExpr reports whether t, the current token, ends an expression.
+func (p *parser) endsExpr(t sqllex.Token, extra []string) bool {
+	if t.Kind != sqllex.Word {
+		return false
+	}
+	kw := strings.ToUpper(t.Text)
+	switch {
+	case (kw == "LEFT" || kw == "RIGHT") && p.peekAt(1).IsPunct("("):
+		return false // the functions LEFT and RIGHT
+	case kw == "FROM" && p.pos > 0 && p.toks[p.pos-1].Is("DISTINCT"):
+		return false // IS DISTINCT FROM
+	}
+	return clauseKeywords[kw] || slices.Contains(extra, kw)
+}
+
+// text renders toks as SQL with ? placeholders, and returns the args bound to them.
+func (p *parser) text(toks []sqllex.Token) (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+	for i, t := range toks {
+		if i > 0 && spaceBefore(toks, i) {
+			sb.WriteByte(' ')
+		}
+		switch t.Kind {
+		case sqllex.Param:
+			arg, err := p.bind(t)
+			if err != nil {
+				return "", nil, err
+			}
+			sb.WriteByte('?')
+			args = append(args, arg)
+		case sqllex.Quoted:
+			if isBareIdent(t.Val) {
+				sb.WriteString(t.Val)
+			} else {
+				sb.WriteString(t.Text)
+			}
+		default:
+			sb.WriteString(t.Text)
+		}
+	}
+	return sb.String(), args, nil
+}
+
+// spaceBefore reports whether toks[i] is written with a space before it.
+func spaceBefore(toks []sqllex.Token, i int) bool {
+	prev, t := toks[i-1], toks[i]
+	switch {
+	case prev.IsPunct("(") || prev.IsPunct(".") || prev.IsPunct("::"):
+		return false
+	case t.IsPunct(")") || t.IsPunct(",") || t.IsPunct(".") || t.IsPunct("::"):
+		return false
+	case t.IsPunct("("):
+		// No space between a function and its arguments.
+		return !(prev.Kind == sqllex.Quoted || prev.Kind == sqllex.Word && !spaced[strings.ToUpper(prev.Text)])
+	case prev.IsPunct("-") || prev.IsPunct("+"):
+		// No space after a sign.
+		return i >= 2 && endsOperand(toks[i-2])
+	}
+	return true
+}
+
+// endsOperand reports whether t can be the last token of an operand.
+func endsOperand(t sqllex.Token) bool {
+	switch t.Kind {
+	case sqllex.Quoted, sqllex.String, sqllex.Number, sqllex.Param:
+		return true
+	case sqllex.Word:
+		return !reserved[strings.ToUpper(t.Text)] || t.Is("END") || t.Is("NULL") || t.Is("TRUE") || t.Is("FALSE")
+	}
+	return t.IsPunct(")")
+}
+
+// bind returns the arg of a placeholder.
+func (p *parser) bind(t sqllex.Token) (interface{}, error) {
+	style := t.Text[0]
+	if p.style == 0 {
+		p.style = style
+	} else if p.style != style {
+		return nil, fmt.Errorf("parse: ?, $n and :n placeholders cannot be mixed (offset %d)", t.Pos)
+	}
+	if style == '?' {
+		if p.next >= len(p.args) {
+			return nil, fmt.Errorf("parse: no arg for placeholder %d at offset %d", p.next+1, t.Pos)
+		}
+		p.next++
+		return p.args[p.next-1], nil
+	}
+	n := t.ParamNumber()
+	if n < 1 || n > len(p.args) {
+		return nil, fmt.Errorf("parse: no arg for placeholder %s at offset %d", t.Text, t.Pos)
+	}
+	p.maxN = max(p.maxN, n)
+	return p.args[n-1], nil
+}
+
+// conditions parses the condition at the current position, split at its top-level ANDs.
+// Parts with a top-level OR are parenthesized, so they keep their meaning when more
+// conditions are ANDed.
+func (p *parser) conditions() ([]sqltk.Condition, error) {
+	toks, err := p.expr(false)
+	if err != nil {
+		return nil, err
+	}
+	var conds []sqltk.Condition
+	for _, part := range splitTop(toks, "AND") {
+		if len(part) == 0 {
+			return nil, fmt.Errorf("parse: missing condition near offset %d", toks[0].Pos)
+		}
+		sql, args, err := p.text(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(splitTop(part, "OR")) > 1 {
+			sql = "(" + sql + ")"
+		}
+		conds = append(conds, sqltk.NewStringCondition(sql, args...))
+	}
+	return conds, nil
+}
+
+// splitTop splits toks at the keyword sep outside parentheses and CASE expressions. The AND
+// of a BETWEEN does not split.
+func splitTop(toks []sqllex.Token, sep string) [][]sqllex.Token {
+	var parts [][]sqllex.Token
+	depth, cases, between, start := 0, 0, false, 0
+	for i, t := range toks {
+		switch {
+		case t.IsPunct("("):
+			depth++
+		case t.IsPunct(")"):
+			depth--
+		case depth > 0:
+		case t.Is("CASE"):
+			cases++
+		case t.Is("END"):
+			cases--
+		case cases > 0:
+		case t.Is("BETWEEN"):
+			between = true
+		case t.Is(sep):
+			if sep == "AND" && between {
+				between = false
+				continue
+			}
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, toks[start:])
+}
+
+// where parses an optional WHERE clause and adds its conditions with add.
+func (p *parser) where(add func(sqltk.Condition)) error {
+	if !p.accept("WHERE") {
+		return nil
+	}
+	conds, err := p.conditions()
+	if err != nil {
+		return err
+	}
+	for _, cond := range conds {
+		add(cond)
+	}
+	return nil
+}
+
+// columnName returns toks as a possibly table-qualified column name, if they are one.
+func columnName(toks []sqllex.Token) (string, bool) {
+	var parts []string
+	for i, t := range toks {
+		if i%2 == 1 {
+			if !t.IsPunct(".") {
+				return "", false
+			}
+			continue
+		}
+		if !(t.Kind == sqllex.Quoted && t.Val != "" && !strings.Contains(t.Val, ".") ||
+			t.Kind == sqllex.Word && !reserved[strings.ToUpper(t.Text)]) {
+			return "", false
+		}
+		parts = append(parts, t.Val)
+	}
+	if len(toks)%2 == 0 {
+		return "", false
+	}
+	return strings.Join(parts, "."), true
+}
+
+// expression returns toks as a column name if they are one, or else as SQL.
+func (p *parser) expression(toks []sqllex.Token) (interface{}, error) {
+	if name, ok := columnName(toks); ok {
+		return name, nil
+	}
+	sql, args, err := p.text(toks)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return raw.Raw(sql), nil
+	}
+	return sqltk.Expr(sql, args...), nil
+}
+
+// value returns toks as the Go value of a literal or placeholder, or else as an expression.
+func (p *parser) value(toks []sqllex.Token) (interface{}, error) {
+	if len(toks) == 2 && toks[0].IsPunct("-") && toks[1].Kind == sqllex.Number {
+		return number("-" + toks[1].Text)
+	}
+	if len(toks) == 1 {
+		switch t := toks[0]; {
+		case t.Kind == sqllex.String:
+			return t.Val, nil
+		case t.Kind == sqllex.Number:
+			return number(t.Text)
+		case t.Kind == sqllex.Param:
+			return p.bind(t)
+		case t.Is("NULL"):
+			return nil, nil
+		case t.Is("TRUE"), t.Is("FALSE"):
+			return t.Is("TRUE"), nil
+		}
+	}
+	sql, args, err := p.text(toks)
+	if err != nil {
+		return nil, err
+	}
+	return sqltk.Expr(sql, args...), nil
+}
+
+// number converts a numeric literal to int64, or to float64 if it is not an integer.
+func number(s string) (interface{}, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse: invalid number %s", s)
+	}
+	return f, nil
+}
+
+// count parses the number of a LIMIT or OFFSET, a literal or a placeholder bound to an integer.
+func (p *parser) count() (int, error) {
+	t := p.peek()
+	switch t.Kind {
+	case sqllex.Number:
+		p.pos++
+		n, err := strconv.Atoi(t.Text)
+		if err != nil {
+			return 0, fmt.Errorf("parse: invalid count %s at offset %d", t.Text, t.Pos)
+		}
+		return n, nil
+	case sqllex.Param:
+		p.pos++
+		arg, err := p.bind(t)
+		if err != nil {
+			return 0, err
+		}
+		switch v := reflect.ValueOf(arg); {
+		case v.CanInt():
+			return int(v.Int()), nil
+		case v.CanUint():
+			return int(v.Uint()), nil
+		}
+		return 0, fmt.Errorf("parse: placeholder at offset %d must be bound to an integer (got %T)", t.Pos, arg)
+	}
+	return 0, p.unexpected()
+}
+
+// selectStmt parses a SELECT statement.
+func (p *parser) selectStmt() (*sqltk.SelectBuilder, error) {
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	b := sqltk.Select()
+	if p.accept("DISTINCT") {
+		b.Distinct()
+	} else {
+		p.accept("ALL")
+	}
+	if err := p.selectColumns(b); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.tableRef()
+	if err != nil {
+		return nil, err
+	}
+	b.From(table)
+	for p.acceptPunct(",") {
+		if table, err = p.tableRef(); err != nil {
+			return nil, err
+		}
+		b.AddFrom(table)
+	}
+	if err := p.joins(b); err != nil {
+		return nil, err
+	}
+
+	if err := p.where(func(cond sqltk.Condition) { b.Where(cond) }); err != nil {
+		return nil, err
+	}
+	if p.accept("GROUP", "BY") {
+		for {
+			toks, err := p.expr(true)
+			if err != nil {
+				return nil, err
+			}
+			expr, err := p.expression(toks)
+			if err != nil {
+				return nil, err
+			}
+			b.GroupBy(expr)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+	if p.accept("HAVING") {
+		conds, err := p.conditions()
+		if err != nil {
+			return nil, err
+		}
+		for _, cond := range conds {
+			b.Having(cond)
+		}
+	}
+	if p.accept("ORDER", "BY") {
+		if err := p.orderBy(b); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.limit(b); err != nil {
+		return nil, err
+	}
+	p.lock(b)
+	return b, nil
+}
+
+// selectColumns parses the columns of a SELECT. A lone * selects all columns.
+func (p *parser) selectColumns(b *sqltk.SelectBuilder) error {
+	if p.peek().IsPunct("*") && (p.peekAt(1).Is("FROM") || p.peekAt(1).Kind == eof) {
+		p.pos++
+		return nil
+	}
+	for {
+		toks, err := p.expr(true)
+		if err != nil {
+			return err
+		}
+		var alias string
+		if n := len(toks); n >= 3 && toks[n-2].Is("AS") {
+			alias, toks = toks[n-1].Val, toks[:n-2]
+		} else if n >= 2 && isAlias(toks[n-1]) && endsOperand(toks[n-2]) {
+			alias, toks = toks[n-1].Val, toks[:n-1]
+		}
+		var column interface{}
+		if len(toks) == 1 && toks[0].IsPunct("*") {
+			column = raw.Raw("*")
+		} else if column, err = p.expression(toks); err != nil {
+			return err
+		}
+		if alias != "" {
+			column = sqltk.Alias(column, alias)
+		}
+		b.AddField(column)
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// isAlias reports whether t can be an alias written without AS.
+func isAlias(t sqllex.Token) bool {
+	return t.Kind == sqllex.Quoted || t.Kind == sqllex.Word && !reserved[strings.ToUpper(t.Text)]
+}
+
+// tableRef parses a table of FROM or JOIN: a table name or a parenthesized subquery, with an
+// optional alias. A schema-qualified name is kept as written.
+func (p *parser) tableRef() (interface{}, error) {
+	var table interface{}
+	if p.acceptPunct("(") {
+		if !p.peek().Is("SELECT") {
+			return nil, p.unexpected()
+		}
+		sub, err := p.selectStmt()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		table = sub
+	} else {
+		start := p.pos
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		table = name
+		if p.peek().IsPunct(".") {
+			for p.acceptPunct(".") {
+				if _, err := p.name(); err != nil {
+					return nil, err
+				}
+			}
+			sql, _, _ := p.text(p.toks[start:p.pos])
+			table = raw.Raw(sql)
+		}
+		if p.peek().IsPunct("(") {
+			return nil, fmt.Errorf("parse: table function %s is not supported", name)
+		}
+	}
+	alias, err := p.alias()
+	if err != nil {
+		return nil, err
+	}
+	if alias != "" {
+		return sqltk.Alias(table, alias), nil
+	}
+	return table, nil
+}
+
+// joins parses the JOIN clauses of a SELECT.
+func (p *parser) joins(b *sqltk.SelectBuilder) error {
+	for {
+		var join func(interface{}) *sqltk.JoinBuilder
+		switch {
+		case p.accept("JOIN"), p.accept("INNER", "JOIN"):
+			join = b.Join
+		case p.accept("LEFT", "JOIN"), p.accept("LEFT", "OUTER", "JOIN"):
+			join = b.LeftJoin
+		case p.accept("RIGHT", "JOIN"), p.accept("RIGHT", "OUTER", "JOIN"):
+			join = b.RightJoin
+		case p.accept("FULL", "JOIN"), p.accept("FULL", "OUTER", "JOIN"):
+			join = b.FullJoin
+		case p.accept("CROSS", "JOIN"):
+			table, err := p.tableRef()
+			if err != nil {
+				return err
+			}
+			b.CrossJoin(table)
+			continue
+		default:
+			return nil
+		}
+		table, err := p.tableRef()
+		if err != nil {
+			return err
+		}
+		jb := join(table)
+		if p.accept("USING") {
+			columns, err := p.names()
+			if err != nil {
+				return err
+			}
+			jb.Using(columns...)
+			continue
+		}
+		if err := p.expect("ON"); err != nil {
+			return err
+		}
+		conds, err := p.conditions()
+		if err != nil {
+			return err
+		}
+		for _, cond := range conds {
+			jb.And(cond)
+		}
+		jb.End()
+	}
+}
+
+// orderBy parses the terms of ORDER BY.
+func (p *parser) orderBy(b *sqltk.SelectBuilder) error {
+	for {
+		toks, err := p.expr(true, "ASC", "DESC", "NULLS")
+		if err != nil {
+			return err
+		}
+		dir, suffix := sqltk.Asc, ""
+		if p.accept("DESC") {
+			dir, suffix = sqltk.Desc, " DESC"
+		} else if p.accept("ASC") {
+			suffix = " ASC"
+		}
+		nulls := sqltk.NullsDefault
+		if p.accept("NULLS", "FIRST") {
+			nulls = sqltk.NullsFirst
+		} else if p.accept("NULLS", "LAST") {
+			nulls = sqltk.NullsLast
+		}
+
+		expr, err := p.expression(toks)
+		if err != nil {
+			return err
+		}
+		switch e := expr.(type) {
+		case string:
+			if nulls == sqltk.NullsDefault {
+				b.OrderBy(e + suffix)
+			} else {
+				b.OrderByExpr(e, dir, nulls)
+			}
+		case raw.Raw:
+			if nulls == sqltk.NullsDefault {
+				b.OrderBy(e + raw.Raw(suffix))
+			} else {
+				b.OrderByExpr(e, dir, nulls)
+			}
+		case sqltk.SQLExpr:
+			if nulls == sqltk.NullsDefault {
+				b.OrderBy(sqltk.Expr(e.SQL+suffix, e.Args...))
+			} else {
+				b.OrderByExpr(e, dir, nulls)
+			}
+		}
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// limit parses LIMIT and OFFSET, including MySQL's LIMIT offset, count.
+func (p *parser) limit(b *sqltk.SelectBuilder) error {
+	if p.accept("LIMIT") {
+		n, err := p.count()
+		if err != nil {
+			return err
+		}
+		if p.acceptPunct(",") {
+			count, err := p.count()
+			if err != nil {
+				return err
+			}
+			b.Offset(n)
+			n = count
+		}
+		b.Limit(n)
+	}
+	if p.accept("OFFSET") {
+		n, err := p.count()
+		if err != nil {
+			return err
+		}
+		b.Offset(n)
+		if !p.accept("ROWS") {
+			p.accept("ROW")
+		}
+	}
+	return nil
+}
+
+// lock parses a locking clause.
+func (p *parser) lock(b *sqltk.SelectBuilder) {
+	switch {
+	case p.accept("FOR", "UPDATE"):
+		b.ForUpdate()
+	case p.accept("FOR", "SHARE"):
+		b.ForShare()
+	case p.accept("LOCK", "IN", "SHARE", "MODE"):
+		b.LockInShareMode()
+		return
+	default:
+		return
+	}
+	if p.accept("NOWAIT") {
+		b.NoWait()
+	} else if p.accept("SKIP", "LOCKED") {
+		b.SkipLocked()
+	}
+}
+
+// insertStmt parses an INSERT statement.
+func (p *parser) insertStmt() (*sqltk.InsertBuilder, error) {
+	if err := p.expect("INSERT", "INTO"); err != nil {
+		return nil, err
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek().IsPunct("(") {
+		return nil, fmt.Errorf("parse: INSERT without a column list is not supported")
+	}
+	columns, err := p.names()
+	if err != nil {
+		return nil, err
+	}
+	b := sqltk.Insert(table).Columns(columns...)
+	if err := p.expect("VALUES"); err != nil {
+		return nil, err
+	}
+	for {
+		start := p.peek()
+		if err := p.expectPunct("("); err != nil {
+			return nil, err
+		}
+		var row []interface{}
+		for {
+			toks, err := p.expr(true)
+			if err != nil {
+				return nil, err
+			}
+			v, err := p.value(toks)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, v)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("parse: row at offset %d has %d values for %d columns", start.Pos, len(row), len(columns))
+		}
+		b.Values(row...)
+		if !p.acceptPunct(",") {
+			return b, nil
+		}
+	}
+}
+
+// updateStmt parses an UPDATE statement.
+func (p *parser) updateStmt() (*sqltk.UpdateBuilder, error) {
+	if err := p.expect("UPDATE"); err != nil {
+		return nil, err
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	b := sqltk.Update(table)
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	for {
+		// Update does not quote SET columns, so a column that needs quotes keeps them.
+		col := p.peek()
+		if _, err := p.name(); err != nil {
+			return nil, err
+		}
+		column, _, _ := p.text([]sqllex.Token{col})
+		if err := p.expectPunct("="); err != nil {
+			return nil, err
+		}
+		toks, err := p.expr(true)
+		if err != nil {
+			return nil, err
+		}
+		v, err := p.value(toks)
+		if err != nil {
+			return nil, err
+		}
+		b.Set(column, v)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	if err := p.where(func(cond sqltk.Condition) { b.Where(cond) }); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// deleteStmt parses a DELETE statement.
+func (p *parser) deleteStmt() (*sqltk.DeleteBuilder, error) {
+	if err := p.expect("DELETE", "FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.tableName()
+	if err != nil {
+		return nil, err
+	}
+	b := sqltk.Delete(table)
+	if err := p.where(func(cond sqltk.Condition) { b.Where(cond) }); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
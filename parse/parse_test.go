@@ -0,0 +1,154 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		args     []interface{}
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "select",
+			sql:      `SELECT DISTINCT u.id, u.name AS "Name", COUNT(*) total FROM users u WHERE u.active = ? AND u.age BETWEEN 18 AND 65 GROUP BY u.id, u.name HAVING COUNT(*) > ? ORDER BY total DESC, u.name LIMIT 10 OFFSET 20`,
+			args:     []interface{}{true, 2},
+			wantSQL:  `SELECT DISTINCT "u"."id", "u"."name" AS "Name", COUNT(*) AS "total" FROM "users" AS u WHERE u.active = $1 AND u.age BETWEEN 18 AND 65 GROUP BY "u"."id", "u"."name" HAVING COUNT(*) > $2 ORDER BY "total" DESC, "u"."name" LIMIT 10 OFFSET 20`,
+			wantArgs: []interface{}{true, 2},
+		},
+		{
+			name:     "star, joins and dollar placeholders",
+			sql:      `select * from "orders" o left outer join customers c on c.id = o.customer_id and c.region = $2 join items i on i.order_id = o.id where o.total > $1 or o.vip`,
+			args:     []interface{}{100, "EU"},
+			wantSQL:  `SELECT * FROM "orders" AS o LEFT JOIN "customers" AS c ON c.id = o.customer_id AND c.region = $1 JOIN "items" AS i ON i.order_id = o.id WHERE (o.total > $2 or o.vip)`,
+			wantArgs: []interface{}{"EU", 100},
+		},
+		{
+			name:     "subqueries",
+			sql:      "SELECT t.n FROM (SELECT `status`, COUNT(*) AS n FROM tickets WHERE opened > ? GROUP BY `status`) AS t WHERE t.status IN (SELECT name FROM statuses WHERE open = ?) FOR UPDATE SKIP LOCKED",
+			args:     []interface{}{"2024-01-01", true},
+			wantSQL:  `SELECT "t"."n" FROM (SELECT "status", COUNT(*) AS "n" FROM "tickets" WHERE opened > $1 GROUP BY "status") AS t WHERE t.status IN (SELECT name FROM statuses WHERE open = $2) FOR UPDATE SKIP LOCKED`,
+			wantArgs: []interface{}{"2024-01-01", true},
+		},
+		{
+			name:     "expressions",
+			sql:      "SELECT id, price * -1, CAST(total AS int) FROM public.sales WHERE CASE WHEN a AND b THEN 1 ELSE 0 END = 1 AND note <> 'it''s' -- trailing\nORDER BY COALESCE(score, ?) DESC NULLS LAST, LEFT(name, 1);",
+			args:     []interface{}{0},
+			wantSQL:  `SELECT "id", price * -1, CAST(total AS int) FROM public.sales WHERE CASE WHEN a AND b THEN 1 ELSE 0 END = 1 AND note <> 'it''s' ORDER BY COALESCE(score, $1) DESC NULLS LAST, LEFT(name, 1)`,
+			wantArgs: []interface{}{0},
+		},
+		{
+			name:     "mysql limit",
+			sql:      "SELECT id FROM users LIMIT ?, ?",
+			args:     []interface{}{40, 20},
+			wantSQL:  `SELECT "id" FROM "users" LIMIT 20 OFFSET 40`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "insert",
+			sql:      "INSERT INTO users (id, name, score, active, note, created_at) VALUES (1, 'Al', -2.5, TRUE, NULL, NOW()), (?, ?, 0, false, 'x', NOW())",
+			args:     []interface{}{2, "Bo"},
+			wantSQL:  `INSERT INTO "users" ("id", "name", "score", "active", "note", "created_at") VALUES ($1, $2, $3, $4, $5, NOW()), ($6, $7, $8, $9, $10, NOW())`,
+			wantArgs: []interface{}{int64(1), "Al", -2.5, true, nil, 2, "Bo", int64(0), false, "x"},
+		},
+		{
+			name:     "update",
+			sql:      `UPDATE accounts SET balance = balance - ?, "order" = 3 WHERE id = ?`,
+			args:     []interface{}{50, 7},
+			wantSQL:  `UPDATE "accounts" SET balance = balance - $1, "order" = $2 WHERE id = $3`,
+			wantArgs: []interface{}{50, int64(3), 7},
+		},
+		{
+			name:     "delete",
+			sql:      "DELETE FROM sessions WHERE expires_at < NOW() OR revoked",
+			wantSQL:  `DELETE FROM "sessions" WHERE (expires_at < NOW() OR revoked)`,
+			wantArgs: []interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.sql, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch b := b.(type) {
+			case *sqltk.SelectBuilder:
+				b.WithDialect(sqldialect.Postgres())
+			case *sqltk.InsertBuilder:
+				b.WithDialect(sqldialect.Postgres())
+			case *sqltk.UpdateBuilder:
+				b.WithDialect(sqldialect.Postgres())
+			case *sqltk.DeleteBuilder:
+				b.WithDialect(sqldialect.Postgres())
+			}
+			sql, args, err := b.Build()
+			if err != nil {
+				t.Fatalf("unexpected build error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseModify(t *testing.T) {
+	q, err := Select("SELECT id, name FROM users WHERE active = ? OR admin", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	q.WhereEqual("tenant_id", 42).Limit(100).WithDialect(sqldialect.MySQL())
+
+	sql, args, err := q.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "SELECT `id`, `name` FROM `users` WHERE (active = ? OR admin) AND tenant_id = ? LIMIT 100"; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if want := []interface{}{true, 42}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got args %v, want %v", args, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		args []interface{}
+	}{
+		{"empty", "", nil},
+		{"unknown statement", "TRUNCATE users", nil},
+		{"union", "SELECT id FROM a UNION SELECT id FROM b", nil},
+		{"no from", "SELECT 1", nil},
+		{"too few args", "SELECT id FROM users WHERE id = ? AND name = ?", []interface{}{1}},
+		{"too many args", "SELECT id FROM users WHERE id = ?", []interface{}{1, 2}},
+		{"mixed placeholders", "SELECT id FROM users WHERE id = ? AND name = $2", []interface{}{1, "a"}},
+		{"unclosed parenthesis", "SELECT id FROM users WHERE (id = 1", nil},
+		{"unterminated string", "SELECT id FROM users WHERE name = 'x", nil},
+		{"insert without columns", "INSERT INTO users VALUES (1)", nil},
+		{"insert row length", "INSERT INTO users (id, name) VALUES (1)", nil},
+		{"insert returning", "INSERT INTO users (id) VALUES (1) RETURNING id", nil},
+		{"qualified update", "UPDATE public.users SET a = 1", nil},
+		{"limit not an integer", "SELECT id FROM users LIMIT ?", []interface{}{"ten"}},
+		{"empty condition", "DELETE FROM users WHERE a = 1 AND", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.sql, tt.args...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package sqltk
+
+import (
+	"strings"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Builders render every fragment of a query with ? placeholders (see positionalDialect) and
+// number them once, in a single pass over the finished statement. Placeholders are only
+// recognised outside quoted strings, dollar-quoted strings, quoted identifiers and comments, so
+// a literal '?' in a string is left alone. Strings are read with the escapes of the dialect
+// (see sqllex.For): 'C:\' is a whole string in Postgres, while in BigQuery its backslash
+// escapes the quote. On dialects with numbered placeholders, ?? is an escaped literal ?, for
+// operators such as the Postgres JSONB ?, ?| and ?&.
+
+// renderDialect returns the dialect fragments of a query are rendered with: dialect itself
+// if it already renders ? placeholders, or a positionalDialect wrapping it.
+func renderDialect(dialect sqldialect.Dialect) sqldialect.Dialect {
+	if dialect.Placeholder(0) == "?" {
+		return dialect
+	}
+	return positionalDialect{dialect}
+}
+
+// numberPlaceholders replaces the ? placeholders of sql with the placeholders of dialect,
+// starting at *placeholderIdx, and collapses ?? to ?. If numbers is set, the nth ? of the
+// query gets numbers[n-1] instead of n; see reuseNumbers. It returns sql unchanged for
+// dialects that use ? placeholders, so that ?? survives until the enclosing query is numbered.
+func numberPlaceholders(dialect sqldialect.Dialect, sql string, placeholderIdx *int, numbers []int) string {
+	if dialect.Placeholder(0) == "?" || !strings.Contains(sql, "?") {
+		return sql
+	}
+	lx := sqllex.For(dialect)
+	var sb strings.Builder
+	sb.Grow(len(sql) + 8)
+	for i := 0; i < len(sql); {
+		end := lx.Skip(sql, i)
+		if end > i {
+			sb.WriteString(sql[i:end])
+			i = end
+			continue
+		}
+		c := sql[i]
+		switch {
+		case c == '?' && i+1 < len(sql) && sql[i+1] == '?':
+			sb.WriteByte('?')
+			i += 2
+		case c == '?':
+			n := *placeholderIdx
+			if n <= len(numbers) {
+				n = numbers[n-1]
+			}
+			sb.WriteString(dialect.Placeholder(n))
+			*placeholderIdx++
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestPlaceholderNumbering(t *testing.T) {
+	pg := sqldialect.Postgres()
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "literal question mark in a string",
+			builder: Select("id").From("posts").WithDialect(pg).
+				Where(NewStringCondition("title <> '?' AND author_id = ?", 5)).
+				Having(NewStringCondition("COUNT(*) > ? AND MAX(note) <> 'why?'", 1)),
+			wantSQL:  `SELECT "id" FROM "posts" WHERE title <> '?' AND author_id = $1 HAVING COUNT(*) > $2 AND MAX(note) <> 'why?'`,
+			wantArgs: []interface{}{5, 1},
+		},
+		{
+			name: "escaped jsonb operators",
+			builder: Select("id").From("docs").WithDialect(pg).
+				Where(NewStringCondition("data ?? ? AND tags ??| ?", "key", "{a,b}")),
+			wantSQL:  `SELECT "id" FROM "docs" WHERE data ? $1 AND tags ?| $2`,
+			wantArgs: []interface{}{"key", "{a,b}"},
+		},
+		{
+			name: "comments are left alone",
+			builder: Select("id").From("users").WithDialect(pg).
+				Where(NewStringCondition("id = ? /* why? */", 1)),
+			wantSQL:  `SELECT "id" FROM "users" WHERE id = $1 /* why? */`,
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "subqueries and compounds share one numbering",
+			builder: Select("id").From("users").WhereEqual("role", "admin").
+				Union(Select("id").From("users").
+					Where(NewStringCondition("data ?? ?", "vip")).
+					WhereIn("id", Select("user_id").From("orders").WhereGreaterThan("total", 100))).
+				WithDialect(pg),
+			wantSQL:  `SELECT "id" FROM "users" WHERE role = $1 UNION SELECT "id" FROM "users" WHERE data ? $2 AND id IN (SELECT "user_id" FROM "orders" WHERE total > $3)`,
+			wantArgs: []interface{}{"admin", "vip", 100},
+		},
+		{
+			name: "backslash ends a postgres string",
+			builder: Select("id").From("files").WithDialect(pg).
+				Where(NewStringCondition(`path <> 'C:\' AND owner = ?`, 7)).
+				Where(NewStringCondition("size > ?", 10)),
+			wantSQL:  `SELECT "id" FROM "files" WHERE path <> 'C:\' AND owner = $1 AND size > $2`,
+			wantArgs: []interface{}{7, 10},
+		},
+		{
+			name: "backslash escapes in postgres E strings",
+			builder: Select("id").From("files").WithDialect(pg).
+				Where(NewStringCondition(`name <> E'it\'s ?' AND owner = ?`, 7)),
+			wantSQL:  `SELECT "id" FROM "files" WHERE name <> E'it\'s ?' AND owner = $1`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name: "dollar-quoted strings",
+			builder: Select("id").From("notes").WithDialect(pg).
+				Where(NewStringCondition("body <> $$it's ?$$ AND tag <> $t$?$t$ AND id = ?", 3)),
+			wantSQL:  `SELECT "id" FROM "notes" WHERE body <> $$it's ?$$ AND tag <> $t$?$t$ AND id = $1`,
+			wantArgs: []interface{}{3},
+		},
+		{
+			name: "backslash escapes on bigquery",
+			builder: Select("id").From("files").WithDialect(sqldialect.BigQuery()).
+				Where(NewStringCondition(`path <> 'it\'s ?' AND owner = ?`, 7)).
+				WhereExists(Select("id").From("shares").WhereEqual("kind", "public")),
+			wantSQL:  "SELECT `id` FROM `files` WHERE path <> 'it\\'s ?' AND owner = ? AND EXISTS (SELECT `id` FROM `shares` WHERE kind = ?)",
+			wantArgs: []interface{}{7, "public"},
+		},
+		{
+			name: "question marks are kept on mysql",
+			builder: Select("id").From("docs").WithDialect(sqldialect.MySQL()).
+				Where(NewStringCondition("note <> '?' AND id = ?", 1)),
+			wantSQL:  "SELECT `id` FROM `docs` WHERE note <> '?' AND id = ?",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name: "update",
+			builder: Update("docs").WithDialect(pg).
+				Set("note", "what?").
+				Where(NewStringCondition("data ?? ? AND title <> '?'", "draft")),
+			wantSQL:  `UPDATE "docs" SET note = $1 WHERE data ? $2 AND title <> '?'`,
+			wantArgs: []interface{}{"what?", "draft"},
+		},
+		{
+			name: "delete",
+			builder: Delete("docs").WithDialect(pg).
+				Where(NewStringCondition("title = '?' OR id = ?", 9)),
+			wantSQL:  `DELETE FROM "docs" WHERE title = '?' OR id = $1`,
+			wantArgs: []interface{}{9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
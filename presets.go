@@ -0,0 +1,100 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ColumnSet is a named list of SELECT columns returned by Preset. Select and AddField expand
+// it in place, so a preset can be combined with other columns and presets.
+type ColumnSet struct {
+	name    string
+	columns []interface{}
+	err     error
+}
+
+// Name returns the name of the preset.
+func (s ColumnSet) Name() string { return s.name }
+
+// Columns returns a copy of the columns of the preset.
+func (s ColumnSet) Columns() []interface{} { return slices.Clone(s.columns) }
+
+var (
+	presetMu sync.RWMutex
+	presets  = make(map[string][]interface{})
+)
+
+// RegisterPreset registers a named set of columns, such as the columns of an API response,
+// so that it is defined once and selected with Preset. Columns are anything Select accepts,
+// including other presets, which are expanded when the preset is registered. It is an error
+// if name is taken.
+//
+// Example usage:
+//
+//	sqltk.RegisterPreset("users.public", "id", "name", "avatar_url")
+//	sqltk.RegisterPreset("users.admin", sqltk.Preset("users.public"), "email", "created_at")
+//
+//	q := sqltk.Select(sqltk.Preset("users.public")).From("users")
+//	// SELECT `id`, `name`, `avatar_url` FROM `users`
+func RegisterPreset(name string, columns ...interface{}) error {
+	if name == "" {
+		return errors.New("RegisterPreset: name must not be empty")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("RegisterPreset: preset %q has no columns", name)
+	}
+	expanded, err := expandColumns(columns)
+	if err != nil {
+		return fmt.Errorf("RegisterPreset: preset %q: %w", name, err)
+	}
+
+	presetMu.Lock()
+	defer presetMu.Unlock()
+	if _, ok := presets[name]; ok {
+		return fmt.Errorf("RegisterPreset: preset %q is already registered", name)
+	}
+	presets[name] = expanded
+	return nil
+}
+
+// MustRegisterPreset is like RegisterPreset but panics on error, for registering presets in
+// package initialization.
+func MustRegisterPreset(name string, columns ...interface{}) {
+	if err := RegisterPreset(name, columns...); err != nil {
+		panic(err)
+	}
+}
+
+// Preset returns the columns registered under name with RegisterPreset. If there is no such
+// preset, the builder it is passed to fails to build.
+func Preset(name string) ColumnSet {
+	presetMu.RLock()
+	columns, ok := presets[name]
+	presetMu.RUnlock()
+	if !ok {
+		return ColumnSet{name: name, err: fmt.Errorf("unknown preset %q", name)}
+	}
+	return ColumnSet{name: name, columns: columns}
+}
+
+// expandColumns replaces the presets among columns with their columns.
+func expandColumns(columns []interface{}) ([]interface{}, error) {
+	if !slices.ContainsFunc(columns, func(col interface{}) bool { _, ok := col.(ColumnSet); return ok }) {
+		return columns, nil
+	}
+	expanded := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		set, ok := col.(ColumnSet)
+		if !ok {
+			expanded = append(expanded, col)
+			continue
+		}
+		if set.err != nil {
+			return nil, set.err
+		}
+		expanded = append(expanded, set.columns...)
+	}
+	return expanded, nil
+}
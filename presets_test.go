@@ -0,0 +1,88 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestPresets(t *testing.T) {
+	saved := SnapshotConfig()
+	t.Cleanup(func() { RestoreConfig(saved) })
+
+	MustRegisterPreset("users.public", "id", "name", "avatar_url")
+	MustRegisterPreset("users.admin", Preset("users.public"), "email", Expr("COALESCE(role, ?) AS role", "user"))
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "preset",
+			builder:  Select(Preset("users.public")).From("users").WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id", "name", "avatar_url" FROM "users"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "nested preset and more columns",
+			builder:  Select(Preset("users.admin")).AddField("last_login").From("users").WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id", "name", "avatar_url", "email", COALESCE(role, $1) AS role, "last_login" FROM "users"`,
+			wantArgs: []interface{}{"user"},
+		},
+		{
+			name:     "added preset",
+			builder:  Select("created_at").AddField(Preset("users.public")).From("users").WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "created_at", "id", "name", "avatar_url" FROM "users"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "unknown preset",
+			builder: Select(Preset("users.private")).From("users"),
+			wantErr: true,
+		},
+		{
+			name:    "unknown added preset",
+			builder: Select("id").AddField(Preset("users.private")).From("users"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	if got, want := Preset("users.public").Columns(), []interface{}{"id", "name", "avatar_url"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got columns %v, want %v", got, want)
+	}
+	for name, columns := range map[string][]interface{}{
+		"":             {"id"},
+		"users.empty":  nil,
+		"users.public": {"id"},
+		"users.broken": {Preset("users.private")},
+	} {
+		if err := RegisterPreset(name, columns...); err == nil {
+			t.Errorf("RegisterPreset(%q): expected error", name)
+		}
+	}
+}
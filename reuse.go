@@ -0,0 +1,106 @@
+package sqltk
+
+import (
+	"reflect"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// ReuseArgs makes Build pass each distinct arg once, on dialects with numbered placeholders
+// such as Postgres: a placeholder whose arg equals the arg of an earlier one, including one
+// in a subquery, takes its number. This shrinks large generated queries that repeat values,
+// such as those composed from many filters. Args are equal if they have the same type and
+// compare equal with ==; nil and incomparable args, such as slices, are never shared, since
+// Postgres infers one type per parameter. It has no effect on dialects with ? placeholders.
+//
+// Example usage:
+//
+//	Select("id").From("events").
+//		Where(NewCond().Equal("tenant_id", 7)).
+//		WhereIn("user_id", Select("id").From("users").WhereEqual("tenant_id", 7)).
+//		ReuseArgs().WithDialect(sqldialect.Postgres())
+//	// SELECT "id" FROM "events" WHERE tenant_id = $1 AND user_id IN (SELECT id FROM users WHERE tenant_id = $1)
+//	// args: [7]
+func (b *SelectBuilder) ReuseArgs() *SelectBuilder {
+	b.reuseArgs = true
+	return b
+}
+
+// ReuseArgs makes Build pass each distinct arg once, on dialects with numbered placeholders.
+// See SelectBuilder.ReuseArgs.
+func (c *CompoundBuilder) ReuseArgs() *CompoundBuilder {
+	c.reuseArgs = true
+	return c
+}
+
+// ReuseArgs makes Build pass each distinct arg once, on dialects with numbered placeholders.
+// See SelectBuilder.ReuseArgs.
+func (b *InsertBuilder) ReuseArgs() *InsertBuilder {
+	b.reuseArgs = true
+	return b
+}
+
+// ReuseArgs makes Build pass each distinct arg once, on dialects with numbered placeholders.
+// See SelectBuilder.ReuseArgs.
+func (b *UpdateBuilder) ReuseArgs() *UpdateBuilder {
+	b.reuseArgs = true
+	return b
+}
+
+// ReuseArgs makes Build pass each distinct arg once, on dialects with numbered placeholders.
+// See SelectBuilder.ReuseArgs.
+func (b *DeleteBuilder) ReuseArgs() *DeleteBuilder {
+	b.reuseArgs = true
+	return b
+}
+
+// reuseNumbers returns the placeholder number of each ? placeholder of sql, which has one per
+// arg, giving a ? whose arg equals an earlier one the number of that one, and the args of the
+// distinct numbers. It returns nil numbers and args as they are if reuse is not set, dialect
+// uses ? placeholders, or the placeholders and args do not match up.
+func reuseNumbers(dialect sqldialect.Dialect, sql string, args []interface{}, reuse bool) ([]int, []interface{}) {
+	if !reuse || dialect.Placeholder(1) == dialect.Placeholder(2) || countPlaceholders(sqllex.For(dialect), sql) != len(args) {
+		return nil, args
+	}
+	numbers := make([]int, len(args))
+	kept := make([]interface{}, 0, len(args))
+	seen := make(map[interface{}]int)
+	for i, arg := range args {
+		comparable := arg != nil && reflect.ValueOf(arg).Comparable()
+		if comparable {
+			if n, ok := seen[arg]; ok {
+				numbers[i] = n
+				continue
+			}
+		}
+		kept = append(kept, arg)
+		numbers[i] = len(kept)
+		if comparable {
+			seen[arg] = len(kept)
+		}
+	}
+	return numbers, kept
+}
+
+// countPlaceholders returns the number of ? placeholders of sql, outside quoted text and
+// comments as lx reads them, and not counting the ?? escape.
+func countPlaceholders(lx sqllex.Lexer, sql string) int {
+	n := 0
+	for i := 0; i < len(sql); {
+		if end := lx.Skip(sql, i); end > i {
+			i = end
+			continue
+		}
+		switch {
+		case sql[i] == '?' && i+1 < len(sql) && sql[i+1] == '?':
+			i += 2
+		case sql[i] == '?':
+			n++
+			i++
+		default:
+			i++
+		}
+	}
+	return n
+}
@@ -0,0 +1,89 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestReuseArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "select with subquery",
+			builder: Select("id").From("events").
+				Where(NewCond().Equal("tenant_id", 7).Equal("kind", "click")).
+				WhereIn("user_id", Select("id").From("users").WhereEqual("tenant_id", 7).WhereEqual("seen", int64(7))).
+				OrderByExpr(Expr("kind = ?", "click"), Desc, NullsDefault).
+				ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "events" WHERE tenant_id = $1 AND kind = $2 AND user_id IN (SELECT "id" FROM "users" WHERE tenant_id = $1 AND seen = $3) ORDER BY kind = $2 DESC`,
+			wantArgs: []interface{}{7, "click", int64(7)},
+		},
+		{
+			name: "nil and incomparable args are not shared",
+			builder: Select("id").From("files").
+				Where(NewStringCondition("a = ? AND b = ? AND c = ? AND d = ?", nil, nil, []byte("x"), []byte("x"))).
+				ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "files" WHERE a = $1 AND b = $2 AND c = $3 AND d = $4`,
+			wantArgs: []interface{}{nil, nil, []byte("x"), []byte("x")},
+		},
+		{
+			name:     "question mark dialect",
+			builder:  Select("id").From("t").WhereEqual("a", 1).WhereEqual("b", 1).ReuseArgs().WithDialect(sqldialect.MySQL()),
+			wantSQL:  "SELECT `id` FROM `t` WHERE a = ? AND b = ?",
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name:     "not enabled",
+			builder:  Select("id").From("t").WhereEqual("a", 1).WhereEqual("b", 1).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "t" WHERE a = $1 AND b = $2`,
+			wantArgs: []interface{}{1, 1},
+		},
+		{
+			name: "union",
+			builder: Select("id").From("a").WhereEqual("org", 3).
+				Union(Select("id").From("b").WhereEqual("org", 3)).
+				ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "id" FROM "a" WHERE org = $1 UNION SELECT "id" FROM "b" WHERE org = $1`,
+			wantArgs: []interface{}{3},
+		},
+		{
+			name:     "update",
+			builder:  Update("users").Set("status", "off").WhereEqual("status", "on").WhereEqual("prev", "off").ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `UPDATE "users" SET status = $1 WHERE status = $2 AND prev = $1`,
+			wantArgs: []interface{}{"off", "on"},
+		},
+		{
+			name:     "delete",
+			builder:  Delete("t").Where(NewStringCondition("a = ? OR b = ?", 5, 5)).ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `DELETE FROM "t" WHERE a = $1 OR b = $1`,
+			wantArgs: []interface{}{5},
+		},
+		{
+			name:     "insert",
+			builder:  Insert("t").Columns("a", "b").Values(1, 2).Values(1, 3).ReuseArgs().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `INSERT INTO "t" ("a", "b") VALUES ($1, $2), ($1, $3)`,
+			wantArgs: []interface{}{1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sprylic/sqltk"
+)
+
+// CopyIn loads rows into the table of b with COPY ... FROM STDIN, as pq.CopyIn does for the
+// lib/pq driver: the statement is prepared, each row is sent with Exec, and a final Exec ends
+// the copy. It returns the number of rows loaded. lib/pq only runs COPY in a transaction, so
+// the Runner must be on a *sql.Tx. See sqltk.CopyBuilder.BuildCopyIn.
+//
+// pgx has its own CopyFrom, which takes the table and columns directly; with a CSV reader,
+// its PgConn().CopyFrom runs the statement of b.CSV().Build().
+//
+// Example usage:
+//
+//	tx, err := db.BeginTx(ctx, nil)
+//	n, err := runner.New(tx).CopyIn(ctx, sqltk.Copy("events").Columns("id", "name"), rows)
+//	err = tx.Commit()
+func (r *Runner) CopyIn(ctx context.Context, b *sqltk.CopyBuilder, rows [][]interface{}) (int64, error) {
+	if r.strictReadOnly {
+		return 0, errors.New("runner: COPY is not allowed in read-only mode")
+	}
+	query, err := b.BuildCopyIn()
+	if err != nil {
+		return 0, err
+	}
+	tx, ok := r.db.(*sql.Tx)
+	if !ok {
+		return 0, errors.New("runner: CopyIn requires a Runner on a *sql.Tx")
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for i, row := range rows {
+		args, err := r.resolveArgs(row)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return 0, fmt.Errorf("runner: row %d: %w", i+1, err)
+		}
+	}
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
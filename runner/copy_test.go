@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestRunnerCopyIn(t *testing.T) {
+	ctx := context.Background()
+	copyEvents := func() *sqltk.CopyBuilder {
+		return sqltk.Copy("events").Columns("id", "name").WithDialect(sqldialect.Postgres())
+	}
+
+	t.Run("sends rows", func(t *testing.T) {
+		d := &fakeDriver{}
+		tx, err := openFake(t, d).BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+
+		n, err := New(tx).CopyIn(ctx, copyEvents(), [][]interface{}{{1, "a"}, {2, "b"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 1 {
+			t.Errorf("got %d rows, want the result of the final Exec", n)
+		}
+		query := `COPY "events" ("id", "name") FROM STDIN`
+		wantQueries := []string{"BEGIN", query, query, query}
+		if !reflect.DeepEqual(d.queries, wantQueries) {
+			t.Errorf("got queries %v, want %v", d.queries, wantQueries)
+		}
+		wantArgs := [][]driver.Value{{}, {int64(1), "a"}, {int64(2), "b"}, {}}
+		if !reflect.DeepEqual(d.args, wantArgs) {
+			t.Errorf("got args %v, want %v", d.args, wantArgs)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		d := &fakeDriver{}
+		db := openFake(t, d)
+		if _, err := New(db).CopyIn(ctx, copyEvents(), nil); err == nil {
+			t.Error("expected error for a Runner on *sql.DB")
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+		if _, err := New(tx).CopyIn(ctx, copyEvents().CSV(), nil); err == nil {
+			t.Error("expected error for CSV")
+		}
+		if _, err := New(tx).CopyIn(ctx, copyEvents().WithDialect(sqldialect.MySQL()), nil); err == nil {
+			t.Error("expected error for MySQL")
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Estimate is the query planner's estimate for a statement.
+type Estimate struct {
+	Cost float64         // total plan cost, in the database's own units
+	Rows float64         // estimated number of rows returned
+	Plan json.RawMessage // the full EXPLAIN output
+}
+
+// dialectBuilder is implemented by builders that report their dialect.
+type dialectBuilder interface {
+	GetDialect() sqldialect.Dialect
+}
+
+// EstimateCost asks the database to plan b without running it, using EXPLAIN (FORMAT JSON) on
+// Postgres and EXPLAIN FORMAT=JSON on MySQL, and returns the cost and row estimate. Use it for
+// admission control, e.g. rejecting user-built report queries above a cost threshold.
+// The dialect is the builder's own, or the global dialect if the builder does not report one.
+func (r *Runner) EstimateCost(ctx context.Context, b sqltk.Builder) (*Estimate, error) {
+	query, args, err := r.build(b)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := sqldialect.GetDialect()
+	if db, ok := b.(dialectBuilder); ok {
+		dialect = db.GetDialect()
+	}
+	var explain string
+	switch sqldialect.Unwrap(dialect) {
+	case sqldialect.Postgres():
+		explain = "EXPLAIN (FORMAT JSON) " + query
+	case sqldialect.MySQL():
+		explain = "EXPLAIN FORMAT=JSON " + query
+	default:
+		return nil, errors.New("runner: EstimateCost is only supported for the Postgres and MySQL dialects")
+	}
+
+	var plan []byte
+	rows, err := r.db.QueryContext(ctx, explain, args...)
+	if err != nil {
+		return nil, fmt.Errorf("runner: explain: %w", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("runner: explain: %w", err)
+		}
+		return nil, errors.New("runner: explain returned no rows")
+	}
+	if err := rows.Scan(&plan); err != nil {
+		return nil, fmt.Errorf("runner: explain: %w", err)
+	}
+
+	est := &Estimate{Plan: json.RawMessage(plan)}
+	if sqldialect.Unwrap(dialect) == sqldialect.Postgres() {
+		err = parsePostgresPlan(plan, est)
+	} else {
+		err = parseMySQLPlan(plan, est)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("runner: parse plan: %w", err)
+	}
+	return est, nil
+}
+
+// parsePostgresPlan reads the top plan node of EXPLAIN (FORMAT JSON) output:
+// [{"Plan": {"Total Cost": 12.5, "Plan Rows": 100, ...}}]
+func parsePostgresPlan(plan []byte, est *Estimate) error {
+	var out []struct {
+		Plan struct {
+			TotalCost float64 `json:"Total Cost"`
+			PlanRows  float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &out); err != nil {
+		return err
+	}
+	if len(out) == 0 {
+		return errors.New("empty plan")
+	}
+	est.Cost = out[0].Plan.TotalCost
+	est.Rows = out[0].Plan.PlanRows
+	return nil
+}
+
+// mysqlTable is a table access in MySQL EXPLAIN FORMAT=JSON output.
+type mysqlTable struct {
+	RowsProduced json.Number `json:"rows_produced_per_join"`
+}
+
+// mysqlBlock is a query block of MySQL EXPLAIN FORMAT=JSON output, or an operation nested in
+// one. ORDER BY, GROUP BY, DISTINCT and window functions wrap the table accesses in an
+// operation, and a UNION lists a query block per query in its union_result.
+type mysqlBlock struct {
+	CostInfo struct {
+		QueryCost string `json:"query_cost"`
+	} `json:"cost_info"`
+	Table      *mysqlTable `json:"table"`
+	NestedLoop []struct {
+		Table *mysqlTable `json:"table"`
+	} `json:"nested_loop"`
+	OrderingOperation *mysqlBlock `json:"ordering_operation"`
+	GroupingOperation *mysqlBlock `json:"grouping_operation"`
+	DuplicatesRemoval *mysqlBlock `json:"duplicates_removal"`
+	Windowing         *mysqlBlock `json:"windowing"`
+	UnionResult       *struct {
+		QuerySpecifications []struct {
+			QueryBlock mysqlBlock `json:"query_block"`
+		} `json:"query_specifications"`
+	} `json:"union_result"`
+}
+
+// parseMySQLPlan reads the query block of EXPLAIN FORMAT=JSON output:
+// {"query_block": {"cost_info": {"query_cost": "1.20"}, "table": {...}}}
+// The row estimate is taken from the single table or the last table of a nested loop, found
+// through the operations that wrap them. A UNION has the cost and rows of its queries added up.
+func parseMySQLPlan(plan []byte, est *Estimate) error {
+	var out struct {
+		QueryBlock mysqlBlock `json:"query_block"`
+	}
+	if err := json.Unmarshal(plan, &out); err != nil {
+		return err
+	}
+	cost, rows, err := out.QueryBlock.estimate()
+	if err != nil {
+		return err
+	}
+	est.Cost, est.Rows = cost, rows
+	return nil
+}
+
+// estimate returns the cost and row estimate of a query block.
+func (b *mysqlBlock) estimate() (cost, rows float64, err error) {
+	if b.UnionResult != nil && b.CostInfo.QueryCost == "" {
+		if len(b.UnionResult.QuerySpecifications) == 0 {
+			return 0, 0, errors.New("union plan has no queries")
+		}
+		for _, spec := range b.UnionResult.QuerySpecifications {
+			c, r, err := spec.QueryBlock.estimate()
+			if err != nil {
+				return 0, 0, err
+			}
+			cost += c
+			rows += r
+		}
+		return cost, rows, nil
+	}
+	if b.CostInfo.QueryCost == "" {
+		return 0, 0, errors.New("plan has no query cost")
+	}
+	if cost, err = strconv.ParseFloat(b.CostInfo.QueryCost, 64); err != nil {
+		return 0, 0, err
+	}
+	rows, err = b.rows()
+	return cost, rows, err
+}
+
+// rows returns the rows produced by the last table accessed in an operation.
+func (b *mysqlBlock) rows() (float64, error) {
+	for _, op := range []*mysqlBlock{b.OrderingOperation, b.GroupingOperation, b.DuplicatesRemoval, b.Windowing} {
+		if op != nil {
+			return op.rows()
+		}
+	}
+	table := b.Table
+	if n := len(b.NestedLoop); n > 0 {
+		table = b.NestedLoop[n-1].Table
+	}
+	if table == nil || table.RowsProduced == "" {
+		return 0, nil
+	}
+	return table.RowsProduced.Float64()
+}
@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestRunnerEstimateCost(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		plan := `[{"Plan": {"Node Type": "Seq Scan", "Total Cost": 35.5, "Plan Rows": 1200}}]`
+		d := &fakeDriver{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{plan}}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").WhereEqual("active", true).WithDialect(sqldialect.Postgres())
+		est, err := r.EstimateCost(context.Background(), q)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if est.Cost != 35.5 || est.Rows != 1200 {
+			t.Errorf("got cost %v rows %v", est.Cost, est.Rows)
+		}
+		want := []string{`EXPLAIN (FORMAT JSON) SELECT "id" FROM "users" WHERE active = $1`}
+		if !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+		if !reflect.DeepEqual(d.args[0], []driver.Value{true}) {
+			t.Errorf("got args %v", d.args[0])
+		}
+	})
+
+	t.Run("mysql nested loop", func(t *testing.T) {
+		plan := `{"query_block": {"cost_info": {"query_cost": "12.40"}, "nested_loop": [
+			{"table": {"table_name": "u", "rows_produced_per_join": 10}},
+			{"table": {"table_name": "o", "rows_produced_per_join": 42}}]}}`
+		d := &fakeDriver{columns: []string{"EXPLAIN"}, rows: [][]driver.Value{{[]byte(plan)}}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("u.id").From("users u").Join("orders o").On("o.user_id", "u.id").WithDialect(sqldialect.MySQL())
+		est, err := r.EstimateCost(context.Background(), q)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if est.Cost != 12.4 || est.Rows != 42 {
+			t.Errorf("got cost %v rows %v", est.Cost, est.Rows)
+		}
+		if len(d.queries) != 1 || d.queries[0][:20] != "EXPLAIN FORMAT=JSON " {
+			t.Errorf("got queries %q", d.queries)
+		}
+	})
+
+	estimateMySQL := func(t *testing.T, plan string) (*Estimate, error) {
+		d := &fakeDriver{columns: []string{"EXPLAIN"}, rows: [][]driver.Value{{[]byte(plan)}}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").OrderBy("id").WithDialect(sqldialect.MySQL())
+		return r.EstimateCost(context.Background(), q)
+	}
+
+	t.Run("mysql order by", func(t *testing.T) {
+		est, err := estimateMySQL(t, `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "25.75"}, "ordering_operation": {
+			"using_filesort": true, "table": {"table_name": "users", "rows_produced_per_join": 240}}}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if est.Cost != 25.75 || est.Rows != 240 {
+			t.Errorf("got cost %v rows %v", est.Cost, est.Rows)
+		}
+	})
+
+	t.Run("mysql group by and order by", func(t *testing.T) {
+		est, err := estimateMySQL(t, `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "104.10"}, "ordering_operation": {
+			"using_filesort": true, "grouping_operation": {"using_temporary_table": true, "nested_loop": [
+				{"table": {"table_name": "u", "rows_produced_per_join": 100}},
+				{"table": {"table_name": "o", "rows_produced_per_join": 950}}]}}}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if est.Cost != 104.1 || est.Rows != 950 {
+			t.Errorf("got cost %v rows %v", est.Cost, est.Rows)
+		}
+	})
+
+	t.Run("mysql union", func(t *testing.T) {
+		est, err := estimateMySQL(t, `{"query_block": {"union_result": {"using_temporary_table": true, "table_name": "<union1,2>", "query_specifications": [
+			{"dependent": false, "query_block": {"select_id": 1, "cost_info": {"query_cost": "10.50"},
+				"table": {"table_name": "users", "rows_produced_per_join": 30}}},
+			{"dependent": false, "query_block": {"select_id": 2, "cost_info": {"query_cost": "4.25"},
+				"table": {"table_name": "admins", "rows_produced_per_join": 5}}}]}}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if est.Cost != 14.75 || est.Rows != 35 {
+			t.Errorf("got cost %v rows %v", est.Cost, est.Rows)
+		}
+	})
+
+	t.Run("mysql plan without cost", func(t *testing.T) {
+		if _, err := estimateMySQL(t, `{"query_block": {"table": {"table_name": "users"}}}`); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		r := New(openFake(t, &fakeDriver{}))
+		q := sqltk.Select("id").From("users").WithDialect(sqldialect.NoQuoteIdent())
+		if _, err := r.EstimateCost(context.Background(), q); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("malformed plan", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"QUERY PLAN"}, rows: [][]driver.Value{{"not json"}}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").WithDialect(sqldialect.Postgres())
+		if _, err := r.EstimateCost(context.Background(), q); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sprylic/sqltk"
+)
+
+// resultColumnsBuilder is implemented by builders that know the names of their result
+// columns, such as *sqltk.SelectBuilder.
+type resultColumnsBuilder interface {
+	ResultColumns() []string
+}
+
+// ExportCSV builds b, runs it, and streams the result to w as CSV: a header row, then one
+// record per row. It returns the number of rows written. Headers are the column names and
+// aliases of the builder, falling back to the names reported by the driver. NULL is written
+// as an empty field, []byte as text, and times in RFC 3339 format.
+//
+// Example usage:
+//
+//	w.Header().Set("Content-Type", "text/csv")
+//	n, err := r.ExportCSV(ctx, w, sqltk.Select("id", "email", "created_at").From("users"))
+func (r *Runner) ExportCSV(ctx context.Context, w io.Writer, b sqltk.Builder) (int, error) {
+	cw := csv.NewWriter(w)
+	n, err := r.export(ctx, b, func(headers []string) error {
+		return cw.Write(headers)
+	}, func(headers []string, values []interface{}) error {
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = csvField(v)
+		}
+		return cw.Write(record)
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	return n, err
+}
+
+// ExportNDJSON builds b, runs it, and streams the result to w as newline-delimited JSON: one
+// object per row, with keys named like the headers of ExportCSV, in column order. It returns
+// the number of rows written. []byte values are written as strings.
+//
+// Example usage:
+//
+//	w.Header().Set("Content-Type", "application/x-ndjson")
+//	n, err := r.ExportNDJSON(ctx, w, sqltk.Select("id", "email").From("users"))
+func (r *Runner) ExportNDJSON(ctx context.Context, w io.Writer, b sqltk.Builder) (int, error) {
+	bw := bufio.NewWriter(w)
+	var keys [][]byte
+	n, err := r.export(ctx, b, func(headers []string) error {
+		keys = make([][]byte, len(headers))
+		for i, h := range headers {
+			key, err := json.Marshal(h)
+			if err != nil {
+				return err
+			}
+			keys[i] = key
+		}
+		return nil
+	}, func(headers []string, values []interface{}) error {
+		bw.WriteByte('{')
+		for i, v := range values {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			if raw, ok := v.([]byte); ok {
+				v = string(raw)
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("column %s: %w", headers[i], err)
+			}
+			bw.Write(keys[i])
+			bw.WriteByte(':')
+			bw.Write(value)
+		}
+		_, err := bw.WriteString("}\n")
+		return err
+	})
+	if flushErr := bw.Flush(); err == nil {
+		err = flushErr
+	}
+	return n, err
+}
+
+// export runs b and calls header once with the column headers, then row for each row.
+func (r *Runner) export(ctx context.Context, b sqltk.Builder, header func([]string) error, row func([]string, []interface{}) error) (int, error) {
+	query, args, err := r.build(b)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	err = r.withSession(ctx, b, func(q Querier) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		headers := exportHeaders(b, columns)
+		if err := header(headers); err != nil {
+			return fmt.Errorf("runner: export: %w", err)
+		}
+
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return err
+			}
+			if err := row(headers, values); err != nil {
+				return fmt.Errorf("runner: export: row %d: %w", n+1, err)
+			}
+			n++
+		}
+		return rows.Err()
+	})
+	return n, err
+}
+
+// exportHeaders returns the result column names of b where it knows them, and the names
+// reported by the driver otherwise.
+func exportHeaders(b sqltk.Builder, columns []string) []string {
+	headers := append([]string(nil), columns...)
+	rb, ok := b.(resultColumnsBuilder)
+	if !ok {
+		return headers
+	}
+	names := rb.ResultColumns()
+	if len(names) != len(columns) {
+		return headers
+	}
+	for i, name := range names {
+		if name != "" {
+			headers[i] = name
+		}
+	}
+	return headers
+}
+
+// csvField formats a scanned value as a CSV field.
+func csvField(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
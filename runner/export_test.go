@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestRunnerExport(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	newDriver := func() *fakeDriver {
+		return &fakeDriver{
+			columns: []string{"id", "email", "count", "created_at"},
+			rows: [][]driver.Value{
+				{int64(1), "a@example.com", int64(3), created},
+				{int64(2), []byte("b,c@example.com"), nil, nil},
+			},
+		}
+	}
+	q := func() *sqltk.SelectBuilder {
+		return sqltk.Select("u.id", "u.email AS contact", raw.Raw("COUNT(o.id)"), "u.created_at").From("users u")
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var sb strings.Builder
+		n, err := New(openFake(t, newDriver())).ExportCSV(context.Background(), &sb, q())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "id,contact,count,created_at\n" +
+			"1,a@example.com,3,2024-05-01T12:30:00Z\n" +
+			"2,\"b,c@example.com\",,\n"
+		if sb.String() != want {
+			t.Errorf("got %q, want %q", sb.String(), want)
+		}
+		if n != 2 {
+			t.Errorf("got %d rows, want 2", n)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var sb strings.Builder
+		n, err := New(openFake(t, newDriver())).ExportNDJSON(context.Background(), &sb, q())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := `{"id":1,"contact":"a@example.com","count":3,"created_at":"2024-05-01T12:30:00Z"}` + "\n" +
+			`{"id":2,"contact":"b,c@example.com","count":null,"created_at":null}` + "\n"
+		if sb.String() != want {
+			t.Errorf("got %q, want %q", sb.String(), want)
+		}
+		if n != 2 {
+			t.Errorf("got %d rows, want 2", n)
+		}
+	})
+
+	t.Run("driver names for star", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id", "name"}}
+		var sb strings.Builder
+		n, err := New(openFake(t, d)).ExportCSV(context.Background(), &sb, sqltk.Select().From("users"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sb.String() != "id,name\n" || n != 0 {
+			t.Errorf("got %q and %d rows, want only the header", sb.String(), n)
+		}
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		var sb strings.Builder
+		if _, err := New(openFake(t, &fakeDriver{})).ExportNDJSON(context.Background(), &sb, sqltk.Select("id")); err == nil {
+			t.Fatal("expected build error")
+		}
+		if sb.Len() != 0 {
+			t.Errorf("got output %q, want none", sb.String())
+		}
+	})
+}
@@ -0,0 +1,358 @@
+// Package runner executes sqltk builders with database/sql.
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/enc"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// txBeginner is implemented by *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// timeoutBuilder is implemented by builders that support MaxExecutionTime.
+type timeoutBuilder interface {
+	GetMaxExecutionTime() time.Duration
+	GetDialect() sqldialect.Dialect
+}
+
+// readOnlyBuilder is implemented by builders that support ReadOnly.
+type readOnlyBuilder interface {
+	IsReadOnly() bool
+}
+
+// Runner executes builders against a database.
+//
+// Builders with a MaxExecutionTime on the Postgres dialect run after SET LOCAL statement_timeout.
+// SET LOCAL only lasts until the end of a transaction, so a Runner on *sql.DB or *sql.Conn runs
+// such statements in a transaction of their own. A Runner on *sql.Tx sets the timeout on the
+// caller's transaction, where it stays in effect until the transaction ends.
+//
+// Builders marked ReadOnly run on the replica set with WithReplica. Without a replica, a Runner
+// on *sql.DB or *sql.Conn runs them in a read-only transaction of their own; on *sql.Tx they
+// run in the caller's transaction, whose access mode is the caller's.
+type Runner struct {
+	db             Querier
+	replica        Querier
+	strictReadOnly bool
+	maxRows        int
+	keys           map[string][]byte
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithKey configures named key material used to resolve enc.KeyRef arguments.
+func WithKey(name string, key []byte) Option {
+	return func(r *Runner) {
+		if r.keys == nil {
+			r.keys = make(map[string][]byte)
+		}
+		r.keys[name] = key
+	}
+}
+
+// WithReplica routes builders marked ReadOnly to replica, e.g. a *sql.DB for a read replica.
+func WithReplica(replica Querier) Option {
+	return func(r *Runner) {
+		r.replica = replica
+	}
+}
+
+// StrictReadOnly makes the Runner reject any builder that is not a SELECT marked ReadOnly,
+// and all batches, for endpoints that must never write.
+func StrictReadOnly() Option {
+	return func(r *Runner) {
+		r.strictReadOnly = true
+	}
+}
+
+// MaxRows limits every SELECT without a LIMIT to n rows, as a guard against reading whole
+// tables by accident. Queries marked with Unlimited are left as they are.
+func MaxRows(n int) Option {
+	return func(r *Runner) {
+		r.maxRows = n
+	}
+}
+
+// New creates a new Runner for the given database, transaction, or connection.
+func New(db Querier, opts ...Option) *Runner {
+	r := &Runner{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Exec builds b and executes it.
+func (r *Runner) Exec(ctx context.Context, b sqltk.Builder) (sql.Result, error) {
+	query, args, err := r.build(b)
+	if err != nil {
+		return nil, err
+	}
+	var res sql.Result
+	err = r.withSession(ctx, b, func(q Querier) error {
+		var err error
+		res, err = q.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+// Query builds b and runs it as a query. The caller must close the returned rows.
+// A Postgres statement timeout or a read-only transaction needs a transaction that outlives
+// the rows, so Query returns an error for one unless the Runner is on a *sql.Tx; use Rows instead.
+func (r *Runner) Query(ctx context.Context, b sqltk.Builder) (*sql.Rows, error) {
+	query, args, err := r.build(b)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.querier(b).(txBeginner); ok {
+		if statementTimeout(b) > 0 {
+			return nil, errors.New("runner: Query with a statement timeout requires a transaction, use Rows instead")
+		}
+		if r.readOnlyTx(b) {
+			return nil, errors.New("runner: Query of a read-only builder requires a transaction or a replica, use Rows instead")
+		}
+	}
+	var rows *sql.Rows
+	err = r.withSession(ctx, b, func(q Querier) error {
+		var err error
+		rows, err = q.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// statementTimeout returns the Postgres statement timeout of b in milliseconds, or 0 if none applies.
+func statementTimeout(b sqltk.Builder) int64 {
+	tb, ok := b.(timeoutBuilder)
+	if !ok || sqldialect.Unwrap(tb.GetDialect()) != sqldialect.Postgres() {
+		return 0
+	}
+	d := tb.GetMaxExecutionTime()
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
+
+// isReadOnly reports whether b is marked ReadOnly.
+func isReadOnly(b sqltk.Builder) bool {
+	rb, ok := b.(readOnlyBuilder)
+	return ok && rb.IsReadOnly()
+}
+
+// querier returns the Querier b runs on: the replica for read-only builders, if set.
+func (r *Runner) querier(b sqltk.Builder) Querier {
+	if r.replica != nil && isReadOnly(b) {
+		return r.replica
+	}
+	return r.db
+}
+
+// readOnlyTx reports whether b needs a read-only transaction, which is when it is read-only
+// and there is no replica to route it to.
+func (r *Runner) readOnlyTx(b sqltk.Builder) bool {
+	return r.replica == nil && isReadOnly(b)
+}
+
+// withSession calls fn with a Querier on which the statement timeout and access mode of b,
+// if any, are in effect.
+func (r *Runner) withSession(ctx context.Context, b sqltk.Builder, fn func(q Querier) error) error {
+	db := r.querier(b)
+	ms := statementTimeout(b)
+	readOnly := r.readOnlyTx(b)
+	if ms == 0 && !readOnly {
+		return fn(db)
+	}
+	setTimeout := fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)
+
+	beginner, ok := db.(txBeginner)
+	if !ok {
+		if ms > 0 {
+			if _, err := db.ExecContext(ctx, setTimeout); err != nil {
+				return fmt.Errorf("runner: set statement timeout: %w", err)
+			}
+		}
+		return fn(db)
+	}
+
+	tx, err := beginner.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+	if err != nil {
+		return fmt.Errorf("runner: begin: %w", err)
+	}
+	if ms > 0 {
+		if _, err := tx.ExecContext(ctx, setTimeout); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("runner: set statement timeout: %w", err)
+		}
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// build builds b and resolves runner-provided arguments.
+func (r *Runner) build(b sqltk.Builder) (string, []interface{}, error) {
+	if r.strictReadOnly && !isReadOnly(b) {
+		return "", nil, fmt.Errorf("runner: %T is not a read-only query", b)
+	}
+	query, args, err := sqltk.SafeBuild(r.limitRows(b))
+	if err != nil {
+		return "", nil, fmt.Errorf("runner: build: %w", err)
+	}
+	args, err = r.resolveArgs(args)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// limitRows returns a copy of b limited to the rows set with MaxRows, if b is a SELECT
+// without a LIMIT that is not marked Unlimited, or b itself.
+func (r *Runner) limitRows(b sqltk.Builder) sqltk.Builder {
+	if r.maxRows <= 0 {
+		return b
+	}
+	switch q := b.(type) {
+	case *sqltk.SelectBuilder:
+		if q != nil && !q.HasLimit() && !q.IsUnlimited() {
+			return q.Clone().Limit(r.maxRows)
+		}
+	case *sqltk.CompoundBuilder:
+		if q != nil && !q.HasLimit() && !q.IsUnlimited() {
+			return q.Clone().Limit(r.maxRows)
+		}
+	}
+	return b
+}
+
+// resolveArgs replaces enc.KeyRef arguments with the configured key material.
+func (r *Runner) resolveArgs(args []interface{}) ([]interface{}, error) {
+	var resolved []interface{}
+	for i, arg := range args {
+		ref, ok := arg.(enc.KeyRef)
+		if !ok {
+			continue
+		}
+		key, ok := r.keys[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("runner: no key configured for %q", ref.Name)
+		}
+		if resolved == nil {
+			resolved = append([]interface{}{}, args...)
+		}
+		resolved[i] = key
+	}
+	if resolved == nil {
+		return args, nil
+	}
+	return resolved, nil
+}
+
+// ExecBatch executes every statement in the batch in order, stopping at the first error.
+// Run it on a *sql.Tx to apply the batch atomically.
+func (r *Runner) ExecBatch(ctx context.Context, batch *sqltk.Batch) error {
+	if batch == nil {
+		return errors.New("runner: batch is nil")
+	}
+	if r.strictReadOnly {
+		return errors.New("runner: batches are not allowed in read-only mode")
+	}
+	i := 0
+	for stmt := range batch.All() {
+		i++
+		args, err := r.resolveArgs(stmt.Args)
+		if err != nil {
+			return err
+		}
+		if _, err := r.db.ExecContext(ctx, stmt.SQL, args...); err != nil {
+			return fmt.Errorf("runner: statement %d: %w", i, err)
+		}
+	}
+	return batch.Err()
+}
+
+// Row is the current row of a Rows iteration. It is only valid until the loop advances.
+type Row struct {
+	rows *sql.Rows
+}
+
+// Scan copies the columns of the current row into dest, like sql.Rows.Scan.
+func (r Row) Scan(dest ...interface{}) error {
+	if r.rows == nil {
+		return errors.New("runner: no current row")
+	}
+	return r.rows.Scan(dest...)
+}
+
+// Columns returns the column names of the result.
+func (r Row) Columns() ([]string, error) {
+	if r.rows == nil {
+		return nil, errors.New("runner: no current row")
+	}
+	return r.rows.Columns()
+}
+
+// Rows builds b, runs it, and returns an iterator over the result rows. Rows are closed when
+// the loop ends, including on break. Build, query, and iteration errors are yielded once
+// with a zero Row, after which iteration stops.
+//
+// Example usage:
+//
+//	for row, err := range r.Rows(ctx, q) {
+//		if err != nil {
+//			return err
+//		}
+//		var id int
+//		if err := row.Scan(&id); err != nil {
+//			return err
+//		}
+//	}
+func (r *Runner) Rows(ctx context.Context, b sqltk.Builder) iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		query, args, err := r.build(b)
+		if err != nil {
+			yield(Row{}, err)
+			return
+		}
+
+		stopped := false
+		err = r.withSession(ctx, b, func(q Querier) error {
+			rows, err := q.QueryContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if !yield(Row{rows: rows}, nil) {
+					stopped = true
+					return nil
+				}
+			}
+			return rows.Err()
+		})
+		if err != nil && !stopped {
+			yield(Row{}, err)
+		}
+	}
+}
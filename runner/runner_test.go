@@ -0,0 +1,434 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/enc"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// fakeDriver records executed statements and serves fixed rows for queries.
+type fakeDriver struct {
+	mu      sync.Mutex
+	queries []string
+	args    [][]driver.Value
+	columns []string
+	rows    [][]driver.Value
+	execErr error
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+func (d *fakeDriver) record(query string, args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	d.args = append(d.args, values)
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN", nil)
+	return fakeTx{d: c.d}, nil
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.d.record("BEGIN READ ONLY", nil)
+	} else {
+		c.d.record("BEGIN", nil)
+	}
+	return fakeTx{d: c.d}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (tx fakeTx) Commit() error   { tx.d.record("COMMIT", nil); return nil }
+func (tx fakeTx) Rollback() error { tx.d.record("ROLLBACK", nil); return nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query, args)
+	if c.d.execErr != nil {
+		return nil, c.d.execErr
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query, args)
+	return &fakeRows{columns: c.d.columns, rows: c.d.rows}, nil
+}
+
+// fakeStmt records each execution of a prepared statement as its query.
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.c.ExecContext(ctx, s.query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+var driverSeq atomic.Int64
+
+func openFake(t *testing.T, d *fakeDriver) *sql.DB {
+	t.Helper()
+	name := fmt.Sprintf("sqltk-fake-%d", driverSeq.Add(1))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRunnerRows(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	t.Run("iterates rows", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}}}
+		r := New(openFake(t, d))
+
+		var names []string
+		for row, err := range r.Rows(context.Background(), sqltk.Select("id", "name").From("users")) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var id int
+			var name string
+			if err := row.Scan(&id, &name); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			names = append(names, name)
+		}
+		if !reflect.DeepEqual(names, []string{"Alice", "Bob"}) {
+			t.Errorf("got names %v", names)
+		}
+		if !reflect.DeepEqual(d.queries, []string{"SELECT id, name FROM users"}) {
+			t.Errorf("got queries %v", d.queries)
+		}
+	})
+
+	t.Run("break stops iteration", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}}
+		r := New(openFake(t, d))
+		n := 0
+		for _, err := range r.Rows(context.Background(), sqltk.Select("id").From("users")) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			n++
+			break
+		}
+		if n != 1 {
+			t.Errorf("got %d rows, want 1", n)
+		}
+	})
+
+	t.Run("build error is yielded", func(t *testing.T) {
+		r := New(openFake(t, &fakeDriver{}))
+		n := 0
+		for _, err := range r.Rows(context.Background(), sqltk.Select("id")) {
+			n++
+			if err == nil {
+				t.Error("expected build error")
+			}
+		}
+		if n != 1 {
+			t.Errorf("got %d yields, want 1", n)
+		}
+	})
+}
+
+func TestRunnerExecBatch(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	t.Run("executes in order", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d))
+		batch := sqltk.NewBatch(sqltk.Delete("a"), sqltk.Delete("b"))
+		if err := r.ExecBatch(context.Background(), batch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(d.queries, []string{"DELETE FROM a", "DELETE FROM b"}) {
+			t.Errorf("got queries %v", d.queries)
+		}
+	})
+
+	t.Run("stops at exec error", func(t *testing.T) {
+		d := &fakeDriver{execErr: errors.New("boom")}
+		r := New(openFake(t, d))
+		if err := r.ExecBatch(context.Background(), sqltk.NewBatch(sqltk.Delete("a"), sqltk.Delete("b"))); err == nil {
+			t.Fatal("expected error")
+		}
+		if len(d.queries) != 1 {
+			t.Errorf("got %d queries, want 1", len(d.queries))
+		}
+	})
+
+	t.Run("reports build error", func(t *testing.T) {
+		r := New(openFake(t, &fakeDriver{}))
+		if err := r.ExecBatch(context.Background(), sqltk.NewBatch(sqltk.Insert("a"))); err == nil {
+			t.Fatal("expected build error")
+		}
+	})
+}
+
+func TestRunnerKeys(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	t.Run("resolves key refs", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d), WithKey(enc.DefaultKey, []byte("secret")))
+		q := sqltk.Insert("users").Columns("id", "ssn").Values(1, enc.Encrypt("123-45-6789")).WithDialect(sqldialect.Postgres())
+		if _, err := r.Exec(context.Background(), q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantSQL := `INSERT INTO "users" ("id", "ssn") VALUES ($1, pgp_sym_encrypt($2, $3))`
+		if d.queries[0] != wantSQL {
+			t.Errorf("got SQL %q, want %q", d.queries[0], wantSQL)
+		}
+		wantArgs := []driver.Value{int64(1), "123-45-6789", []byte("secret")}
+		if !reflect.DeepEqual(d.args[0], wantArgs) {
+			t.Errorf("got args %#v, want %#v", d.args[0], wantArgs)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d))
+		q := sqltk.Select(sqltk.Alias(enc.DecryptWith("pii", "ssn"), "ssn")).From("users")
+		if _, err := r.Query(context.Background(), q); err == nil {
+			t.Fatal("expected missing key error")
+		}
+		if len(d.queries) != 0 {
+			t.Errorf("got %d queries, want 0", len(d.queries))
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d), WithKey("pii", []byte("k")))
+		batch := sqltk.NewBatch(sqltk.Update("users").Set("ssn", enc.EncryptWith("pii", "x")).WhereEqual("id", 1))
+		if err := r.ExecBatch(context.Background(), batch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantArgs := []driver.Value{"x", []byte("k"), int64(1)}
+		if !reflect.DeepEqual(d.args[0], wantArgs) {
+			t.Errorf("got args %#v, want %#v", d.args[0], wantArgs)
+		}
+	})
+}
+
+func TestRunnerStatementTimeout(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	t.Run("postgres rows run in a transaction", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").MaxExecutionTime(1500 * time.Millisecond).WithDialect(sqldialect.Postgres())
+		for _, err := range r.Rows(context.Background(), q) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		want := []string{"BEGIN", "SET LOCAL statement_timeout = 1500", `SELECT "id" FROM "users"`, "COMMIT"}
+		if !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+	})
+
+	t.Run("postgres exec on a transaction", func(t *testing.T) {
+		d := &fakeDriver{}
+		db := openFake(t, d)
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		defer tx.Rollback()
+		q := sqltk.Select("id").From("users").MaxExecutionTime(time.Second).WithDialect(sqldialect.Postgres())
+		if _, err := New(tx).Exec(context.Background(), q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"BEGIN", "SET LOCAL statement_timeout = 1000", `SELECT "id" FROM "users"`}
+		if !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+	})
+
+	t.Run("postgres query without a transaction", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").MaxExecutionTime(time.Second).WithDialect(sqldialect.Postgres())
+		if _, err := r.Query(context.Background(), q); err == nil {
+			t.Fatal("expected error")
+		}
+		if len(d.queries) != 0 {
+			t.Errorf("got queries %q, want none", d.queries)
+		}
+	})
+
+	t.Run("mysql uses the hint only", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id"}}
+		r := New(openFake(t, d))
+		q := sqltk.Select("id").From("users").MaxExecutionTime(time.Second).WithDialect(sqldialect.MySQL())
+		for _, err := range r.Rows(context.Background(), q) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		want := []string{"SELECT /*+ MAX_EXECUTION_TIME(1000) */ `id` FROM `users`"}
+		if !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+	})
+}
+
+func TestRunnerReadOnly(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	t.Run("runs in a read-only transaction", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+		r := New(openFake(t, d))
+		for _, err := range r.Rows(context.Background(), sqltk.Select("id").From("users").ReadOnly()) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		want := []string{"BEGIN READ ONLY", "SELECT id FROM users", "COMMIT"}
+		if !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+	})
+
+	t.Run("routes to the replica", func(t *testing.T) {
+		primary, replica := &fakeDriver{}, &fakeDriver{columns: []string{"id"}}
+		r := New(openFake(t, primary), WithReplica(openFake(t, replica)))
+		rows, err := r.Query(context.Background(), sqltk.Select("id").From("users").ReadOnly())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows.Close()
+		if _, err := r.Exec(context.Background(), sqltk.Delete("users").WhereEqual("id", 1)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"SELECT id FROM users"}; !reflect.DeepEqual(replica.queries, want) {
+			t.Errorf("got replica queries %q, want %q", replica.queries, want)
+		}
+		if want := []string{"DELETE FROM users WHERE id = ?"}; !reflect.DeepEqual(primary.queries, want) {
+			t.Errorf("got primary queries %q, want %q", primary.queries, want)
+		}
+	})
+
+	t.Run("query without a transaction or replica", func(t *testing.T) {
+		d := &fakeDriver{}
+		r := New(openFake(t, d))
+		if _, err := r.Query(context.Background(), sqltk.Select("id").From("users").ReadOnly()); err == nil {
+			t.Fatal("expected error")
+		}
+		if len(d.queries) != 0 {
+			t.Errorf("got queries %q, want none", d.queries)
+		}
+	})
+
+	t.Run("strict mode rejects writes", func(t *testing.T) {
+		d := &fakeDriver{columns: []string{"id"}}
+		r := New(openFake(t, d), WithReplica(openFake(t, d)), StrictReadOnly())
+		if _, err := r.Exec(context.Background(), sqltk.Delete("users").WhereEqual("id", 1)); err == nil {
+			t.Error("expected error for DELETE")
+		}
+		if _, err := r.Query(context.Background(), sqltk.Select("id").From("users")); err == nil {
+			t.Error("expected error for SELECT not marked ReadOnly")
+		}
+		if err := r.ExecBatch(context.Background(), sqltk.NewBatch(sqltk.Delete("a"))); err == nil {
+			t.Error("expected error for batch")
+		}
+		rows, err := r.Query(context.Background(), sqltk.Select("id").From("users").ReadOnly())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows.Close()
+		if want := []string{"SELECT id FROM users"}; !reflect.DeepEqual(d.queries, want) {
+			t.Errorf("got queries %q, want %q", d.queries, want)
+		}
+	})
+}
+
+func TestRunnerMaxRows(t *testing.T) {
+	sqldialect.SetDialect(sqldialect.NoQuoteIdent())
+
+	d := &fakeDriver{columns: []string{"id"}}
+	r := New(openFake(t, d), MaxRows(100))
+	builders := []sqltk.Builder{
+		sqltk.Select("id").From("users"),
+		sqltk.Select("id").From("users").Limit(10),
+		sqltk.Select("id").From("users").Unlimited(),
+		sqltk.Select("id").From("a").Union(sqltk.Select("id").From("b")),
+		sqltk.Delete("sessions"),
+	}
+	for _, b := range builders {
+		if _, err := r.Exec(context.Background(), b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	want := []string{
+		"SELECT id FROM users LIMIT 100",
+		"SELECT id FROM users LIMIT 10",
+		"SELECT id FROM users",
+		"SELECT id FROM a UNION SELECT id FROM b LIMIT 100",
+		"DELETE FROM sessions",
+	}
+	if !reflect.DeepEqual(d.queries, want) {
+		t.Errorf("got queries %q, want %q", d.queries, want)
+	}
+	if builders[0].(*sqltk.SelectBuilder).HasLimit() {
+		t.Error("MaxRows changed the builder")
+	}
+}
@@ -0,0 +1,50 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Builder is implemented by every statement builder in this module.
+type Builder interface {
+	Build() (string, []interface{}, error)
+}
+
+// SafeBuild builds b, converting any panic raised while building into an error.
+// Builders report invalid input through the error returned by Build; SafeBuild is
+// defense in depth for request handlers that must never crash on bad input.
+func SafeBuild(b Builder) (sql string, args []interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			sql, args, err = "", nil, fmt.Errorf("SafeBuild: recovered from panic: %v", r)
+		}
+	}()
+	if b == nil {
+		return "", nil, errors.New("SafeBuild: builder is nil")
+	}
+	return b.Build()
+}
+
+// BuildTo builds b and writes its SQL to w, returning the args. Nothing is written if Build
+// fails. The SQL is written with io.WriteString, so a strings.Builder or bytes.Buffer receives
+// it without another copy; large statements, such as bulk inserts, can be assembled into one
+// buffer with other output.
+//
+// Example usage:
+//
+//	var buf bytes.Buffer
+//	args, err := sqltk.BuildTo(&buf, sqltk.Insert("events").Columns("id", "name").Values(rows...))
+func BuildTo(w io.Writer, b Builder) ([]interface{}, error) {
+	if b == nil {
+		return nil, errors.New("BuildTo: builder is nil")
+	}
+	sql, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, sql); err != nil {
+		return nil, fmt.Errorf("BuildTo: %w", err)
+	}
+	return args, nil
+}
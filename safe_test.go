@@ -0,0 +1,135 @@
+package sqltk
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk/ddl"
+	"github.com/sprylic/sqltk/mysqlfunc"
+	"github.com/sprylic/sqltk/pgfunc"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+type panickingBuilder struct{}
+
+func (panickingBuilder) Build() (string, []interface{}, error) {
+	panic("boom")
+}
+
+func TestSafeBuild(t *testing.T) {
+	t.Run("passes through result", func(t *testing.T) {
+		sql, args, err := SafeBuild(Select("id").From("users").WhereEqual("id", 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != "SELECT id FROM users WHERE id = ?" {
+			t.Errorf("got SQL %q", sql)
+		}
+		if !reflect.DeepEqual(args, []interface{}{1}) {
+			t.Errorf("got args %v", args)
+		}
+	})
+
+	t.Run("recovers panic", func(t *testing.T) {
+		sql, args, err := SafeBuild(panickingBuilder{})
+		if err == nil {
+			t.Fatal("expected error from recovered panic")
+		}
+		if sql != "" || args != nil {
+			t.Errorf("expected empty result, got %q %v", sql, args)
+		}
+	})
+
+	t.Run("nil builder", func(t *testing.T) {
+		if _, _, err := SafeBuild(nil); err == nil {
+			t.Error("expected error for nil builder")
+		}
+		var sb *SelectBuilder
+		if _, _, err := SafeBuild(sb); err == nil {
+			t.Error("expected error for nil *SelectBuilder")
+		}
+	})
+}
+
+func TestBuildTo(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("-- users\n")
+	args, err := Select("id").From("users").WhereEqual("id", 1).BuildTo(&sb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "-- users\nSELECT id FROM users WHERE id = ?"; sb.String() != want {
+		t.Errorf("got SQL %q, want %q", sb.String(), want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1}) {
+		t.Errorf("got args %v", args)
+	}
+
+	var buf bytes.Buffer
+	pq := NewPostgresInsert("users").Returning("id")
+	pq.Columns("name").Values("Al")
+	if _, err := pq.BuildTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `INSERT INTO "users" ("name") VALUES ($1) RETURNING id`; buf.String() != want {
+		t.Errorf("got SQL %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if _, err := Select("id").BuildTo(&buf); err == nil || buf.Len() != 0 {
+		t.Errorf("got error %v and %q, want an error and nothing written", err, buf.String())
+	}
+	if _, err := BuildTo(&buf, nil); err == nil {
+		t.Error("expected error for nil builder")
+	}
+}
+
+func TestNoPanicOnBadInput(t *testing.T) {
+	var nilSelect *SelectBuilder
+	var nilCond *ConditionBuilder
+	var nilString *StringCondition
+
+	tests := []struct {
+		name    string
+		builder Builder
+	}{
+		{"unsafe mysql function column", Select(mysqlfunc.Upper("name; DROP TABLE users --")).From("users")},
+		{"unsafe pg function alias", Select(Alias(pgfunc.Lower("/* x */"), "n")).From("users")},
+		{"unsafe nested function", Select(mysqlfunc.Count(mysqlfunc.Upper("a -- b"))).From("users")},
+		{"unsafe function in order by", Select("id").From("users").OrderBy(mysqlfunc.Lower("a -- b"))},
+		{"unsafe function in group by", Select("id").From("users").GroupBy(pgfunc.Upper("a -- b"))},
+		{"unsafe window function", Select(sqlfunc.Lag("x -- y", 1).Over()).From("users")},
+		{"nil where condition", Select("id").From("users").Where(nil)},
+		{"nil condition builder", Delete("users").Where(nilCond)},
+		{"nil string condition", Update("users").Set("a", 1).Where(nilString)},
+		{"nil having condition", Select("id").From("users").Having(nil)},
+		{"nil and condition", Select("id").From("users").Where(NewCond().Equal("a", 1).And(nil))},
+		{"nil or condition", Select("id").From("users").Where(NewCond().Equal("a", 1).Or(nil))},
+		{"nil subquery in IN", Select("id").From("users").Where(NewCond().In("id", nilSelect))},
+		{"nil subquery in EXISTS", Select("id").From("users").WhereExists(nilSelect)},
+		{"nil subquery in FROM", Select("id").From(nilSelect)},
+		{"nil column builder", ddl.CreateTable("t").AddColumn(nil)},
+		{"unsafe function default", ddl.CreateTable("t").AddColumn(ddl.Column("c").Type("TEXT").Default(mysqlfunc.Upper("a -- b")))},
+		{"unsafe function in where value", Select("id").From("users").WhereEqual("name", mysqlfunc.Upper("a -- b"))},
+		{"unsafe function in condition", Select("id").From("users").Where(NewCond().Equal("name", pgfunc.Lower("a -- b")))},
+		{"unsafe function in having value", Select("id").From("users").GroupBy("id").Having(NewStringCondition("COUNT(*) > ?", mysqlfunc.Upper("a -- b")))},
+		{"unsafe function in insert value", Insert("users").Columns("name").Values(mysqlfunc.Upper("a -- b"))},
+		{"unsafe function in update where", Update("users").Set("a", 1).Where(Expr("name = ?", pgfunc.Upper("a -- b")))},
+		{"unsafe function in delete where", Delete("users").Where(NewStringCondition("name = ?", mysqlfunc.Upper("a -- b")))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("unexpected panic: %v", r)
+				}
+			}()
+			if _, _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package sqltk
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ColumnType is the rough type of a column, used to check bound arguments against a Schema.
+type ColumnType int
+
+const (
+	// AnyType accepts any argument.
+	AnyType ColumnType = iota
+	IntType
+	FloatType
+	StringType
+	BoolType
+	TimeType
+	BytesType
+)
+
+// SchemaColumn is a column of a table in a Schema.
+type SchemaColumn struct {
+	Name string
+	Type ColumnType
+	// Unique reports whether the column is unique on its own, such as a primary key, for
+	// SelectBuilder.DeterministicOrder.
+	Unique bool
+}
+
+// Schema is a snapshot of the tables and columns of a database. Builders given a Schema with
+// WithSchema check at Build time that the tables and columns they reference exist and that
+// bound values roughly match the column types. It is meant as an opt-in check in tests and CI.
+//
+// Table and column names are compared case-insensitively. Only names the builder knows
+// structurally are checked: FROM and JOIN tables, plain SELECT columns, ORDER BY and GROUP BY
+// columns, INSERT columns and values, and UPDATE SET columns and values. WHERE conditions,
+// raw SQL, and subqueries are not checked.
+//
+// Example usage:
+//
+//	schema := sqltk.NewSchema().
+//		AddTable("users", sqltk.SchemaColumn{Name: "id", Type: sqltk.IntType}, sqltk.SchemaColumn{Name: "email", Type: sqltk.StringType})
+//	q := sqltk.Select("id", "email").From("users").WithSchema(schema)
+type Schema struct {
+	tables      map[string]map[string]ColumnType
+	unique      map[string]map[string]bool // unique columns by table
+	foreignKeys []foreignKey               // see AddForeignKey
+}
+
+// NewSchema creates an empty Schema.
+func NewSchema() *Schema {
+	return &Schema{tables: make(map[string]map[string]ColumnType), unique: make(map[string]map[string]bool)}
+}
+
+// AddTable adds a table with its columns to the schema, replacing any table of the same name.
+func (s *Schema) AddTable(name string, columns ...SchemaColumn) *Schema {
+	cols := make(map[string]ColumnType, len(columns))
+	unique := make(map[string]bool)
+	for _, col := range columns {
+		cols[strings.ToLower(col.Name)] = col.Type
+		if col.Unique {
+			unique[strings.ToLower(col.Name)] = true
+		}
+	}
+	s.tables[strings.ToLower(name)] = cols
+	s.unique[strings.ToLower(name)] = unique
+	return s
+}
+
+// isUnique reports whether column is a unique column of table.
+func (s *Schema) isUnique(table, column string) bool {
+	return s.unique[strings.ToLower(table)][strings.ToLower(column)]
+}
+
+// table returns the columns of a table, or nil if the schema has no such table.
+func (s *Schema) table(name string) map[string]ColumnType {
+	return s.tables[strings.ToLower(name)]
+}
+
+// checkTable returns the columns of a table, or an error if the schema has no such table.
+func (s *Schema) checkTable(name string) (map[string]ColumnType, error) {
+	cols := s.table(name)
+	if cols == nil {
+		return nil, fmt.Errorf("schema: unknown table %q", name)
+	}
+	return cols, nil
+}
+
+// checkValue returns an error if value does not fit the type of column in table.
+func (s *Schema) checkValue(table string, cols map[string]ColumnType, column string, value interface{}) error {
+	typ, ok := cols[strings.ToLower(column)]
+	if !ok {
+		return fmt.Errorf("schema: unknown column %q in table %q", column, table)
+	}
+	if !valueFits(typ, value) {
+		return fmt.Errorf("schema: value of type %T does not match column %q in table %q", value, column, table)
+	}
+	return nil
+}
+
+// valueFits reports whether value roughly matches typ. Values the builder renders itself, such as
+// Binders and subqueries, and values whose database type is not known, always fit.
+func valueFits(typ ColumnType, value interface{}) bool {
+	if typ == AnyType || value == nil {
+		return true
+	}
+	switch value.(type) {
+	case Binder, *SelectBuilder, driver.Valuer:
+		return true
+	case time.Time:
+		return typ == TimeType || typ == StringType
+	case []byte:
+		return typ == BytesType || typ == StringType
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return valueFits(typ, t)
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ == IntType || typ == FloatType || typ == BoolType
+	case reflect.Float32, reflect.Float64:
+		return typ == FloatType
+	case reflect.String:
+		return typ == StringType || typ == TimeType || typ == BytesType
+	case reflect.Bool:
+		return typ == BoolType || typ == IntType
+	default:
+		return true
+	}
+}
+
+// schemaScope resolves column references against the tables of a SELECT.
+type schemaScope struct {
+	schema *Schema
+	tables map[string]map[string]ColumnType // by alias; nil columns for tables not in the schema
+	names  map[string]string                // table names by alias, for errors
+	opaque bool                             // set if a table's columns cannot be known
+}
+
+// addTable adds a FROM or JOIN table to the scope, checking that a named table exists.
+func (sc *schemaScope) addTable(table interface{}) error {
+	name := ""
+	switch t := table.(type) {
+	case string:
+		if fields := strings.Fields(t); len(fields) > 0 {
+			name = fields[0]
+		}
+	case Identifier:
+		name = t.String()
+	case AliasExpr:
+		switch expr := t.Expr.(type) {
+		case string:
+			name = expr
+		case Identifier:
+			name = expr.String()
+		}
+	}
+	alias := strings.ToLower(tableAlias(table))
+	if name == "" {
+		sc.opaque = true
+		if alias != "" {
+			sc.tables[alias] = nil
+		}
+		return nil
+	}
+	cols, err := sc.schema.checkTable(name)
+	if err != nil {
+		return err
+	}
+	sc.tables[alias] = cols
+	sc.names[alias] = name
+	return nil
+}
+
+// checkColumn checks a column reference, optionally table-qualified.
+func (sc *schemaScope) checkColumn(column string) error {
+	if !isPlainIdent(column) {
+		return nil
+	}
+	if idx := strings.LastIndex(column, "."); idx >= 0 {
+		qualifier, name := strings.ToLower(column[:idx]), strings.ToLower(column[idx+1:])
+		cols, ok := sc.tables[qualifier]
+		if !ok || cols == nil {
+			// Unknown qualifiers may refer to an enclosing query.
+			return nil
+		}
+		if _, ok := cols[name]; !ok {
+			return fmt.Errorf("schema: unknown column %q in table %q", column[idx+1:], sc.names[qualifier])
+		}
+		return nil
+	}
+	if sc.opaque {
+		return nil
+	}
+	for _, cols := range sc.tables {
+		if _, ok := cols[strings.ToLower(column)]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("schema: unknown column %q", column)
+}
+
+// checkSelect checks the tables and columns referenced by a SELECT.
+func (s *Schema) checkSelect(b *SelectBuilder) error {
+	sc := &schemaScope{schema: s, tables: make(map[string]map[string]ColumnType), names: make(map[string]string)}
+	if b.tableClauseInterface.table != nil {
+		if err := sc.addTable(b.tableClauseInterface.table); err != nil {
+			return err
+		}
+	}
+	for _, t := range b.fromTables {
+		if err := sc.addTable(t); err != nil {
+			return err
+		}
+	}
+	for _, j := range b.joins {
+		if err := sc.addTable(j.table); err != nil {
+			return err
+		}
+	}
+
+	var columns []string
+	aliases := make(map[string]bool)
+	for _, col := range b.columns {
+		if alias := columnAlias(col); alias != "" {
+			aliases[strings.ToLower(alias)] = true
+		}
+		switch c := col.(type) {
+		case string:
+			if idx := strings.LastIndex(strings.ToUpper(c), " AS "); idx >= 0 {
+				c = strings.TrimSpace(c[:idx])
+			}
+			columns = append(columns, c)
+		case AliasExpr:
+			if expr, ok := c.Expr.(string); ok {
+				columns = append(columns, expr)
+			}
+		}
+	}
+	for _, col := range columns {
+		if err := sc.checkColumn(col); err != nil {
+			return err
+		}
+	}
+
+	// ORDER BY and GROUP BY may also refer to column aliases.
+	var refs []string
+	for _, t := range b.orderBy {
+		if !t.raw {
+			refs = append(refs, t.expr)
+		}
+	}
+	refs = append(refs, b.groupBy...)
+	for _, col := range refs {
+		if aliases[strings.ToLower(col)] {
+			continue
+		}
+		if err := sc.checkColumn(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkInsert checks the table, columns, and values of an INSERT.
+func (s *Schema) checkInsert(b *InsertBuilder) error {
+	cols, err := s.checkTable(b.table)
+	if err != nil {
+		return err
+	}
+	for _, row := range b.values {
+		for i, col := range b.columns {
+			if err := s.checkValue(b.table, cols, col, row[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkUpdate checks the table and SET columns and values of an UPDATE.
+func (s *Schema) checkUpdate(b *UpdateBuilder) error {
+	cols, err := s.checkTable(b.table)
+	if err != nil {
+		return err
+	}
+	argIdx := 0
+	for _, set := range b.sets {
+		column, ok := strings.CutSuffix(set, " = ?")
+		if !ok || argIdx >= len(b.setArgs) {
+			continue
+		}
+		value := b.setArgs[argIdx]
+		argIdx++
+		if !isPlainIdent(column) {
+			continue
+		}
+		if strings.Contains(column, ".") && (len(b.joins) > 0 || len(b.from) > 0) {
+			continue // may be a column of a joined table
+		}
+		column = column[strings.LastIndex(column, ".")+1:]
+		if err := s.checkValue(b.table, cols, column, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithSchema sets a schema snapshot that Build checks the query against. See Schema.
+func (b *SelectBuilder) WithSchema(s *Schema) *SelectBuilder {
+	b.schema = s
+	return b
+}
+
+// WithSchema sets a schema snapshot that Build checks the statement against. See Schema.
+func (b *InsertBuilder) WithSchema(s *Schema) *InsertBuilder {
+	b.schema = s
+	return b
+}
+
+// WithSchema sets a schema snapshot that Build checks the statement against. See Schema.
+func (b *UpdateBuilder) WithSchema(s *Schema) *UpdateBuilder {
+	b.schema = s
+	return b
+}
+
+// WithSchema sets a schema snapshot that Build checks the statement against. See Schema.
+func (b *DeleteBuilder) WithSchema(s *Schema) *DeleteBuilder {
+	b.schema = s
+	return b
+}
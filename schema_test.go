@@ -0,0 +1,124 @@
+package sqltk
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk/raw"
+)
+
+func testSchema() *Schema {
+	return NewSchema().
+		AddTable("users",
+			SchemaColumn{Name: "id", Type: IntType},
+			SchemaColumn{Name: "email", Type: StringType},
+			SchemaColumn{Name: "active", Type: BoolType},
+			SchemaColumn{Name: "created_at", Type: TimeType},
+		).
+		AddTable("orders",
+			SchemaColumn{Name: "id", Type: IntType},
+			SchemaColumn{Name: "user_id", Type: IntType},
+			SchemaColumn{Name: "total", Type: FloatType},
+			SchemaColumn{Name: "meta", Type: AnyType},
+		)
+}
+
+func TestSchemaCheck(t *testing.T) {
+	schema := testSchema()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		builder Builder
+		wantErr string
+	}{
+		{
+			name: "select with join",
+			builder: Select("u.id", "email", Alias("o.total", "amount"), "COUNT(*) AS n").
+				From(Alias("users", "u")).Join("orders o").On("o.user_id", "u.id").
+				GroupBy("u.id", "email").OrderByDesc("amount").WithSchema(schema),
+		},
+		{
+			name:    "unknown table",
+			builder: Select("id").From("accounts").WithSchema(schema),
+			wantErr: `unknown table "accounts"`,
+		},
+		{
+			name: "unknown join table",
+			builder: Select("u.id").From(Alias("users", "u")).
+				Join(Alias("payments", "p")).On("p.user_id", "u.id").WithSchema(schema),
+			wantErr: `unknown table "payments"`,
+		},
+		{
+			name:    "unknown qualified column",
+			builder: Select("u.name").From(Alias("users", "u")).WithSchema(schema),
+			wantErr: `unknown column "name" in table "users"`,
+		},
+		{
+			name:    "unknown order by column",
+			builder: Select("id").From("users").OrderBy("updated_at DESC").WithSchema(schema),
+			wantErr: `unknown column "updated_at"`,
+		},
+		{
+			name:    "case insensitive names",
+			builder: Select("ID", "Email").From("USERS").WithSchema(schema),
+		},
+		{
+			name:    "subquery tables are not checked",
+			builder: Select("x", "t.y").From(Alias(Select("id").From("users"), "t")).WithSchema(schema),
+		},
+		{
+			name:    "raw columns are not checked",
+			builder: Select(raw.Raw("nothing"), "*").From("users").WithSchema(schema),
+		},
+		{
+			name: "insert",
+			builder: Insert("users").Columns("id", "email", "active", "created_at").
+				Values(1, "a@example.com", true, now).Values(int64(2), nil, 0, "2024-01-01").WithSchema(schema),
+		},
+		{
+			name:    "insert unknown column",
+			builder: Insert("users").Columns("id", "name").Values(1, "Ann").WithSchema(schema),
+			wantErr: `unknown column "name" in table "users"`,
+		},
+		{
+			name:    "insert value type mismatch",
+			builder: Insert("orders").Columns("user_id", "total").Values("seven", 9.5).WithSchema(schema),
+			wantErr: `value of type string does not match column "user_id"`,
+		},
+		{
+			name:    "update",
+			builder: Update("orders").Set("total", 12).Set("meta", []int{1}).SetRaw("id = id").WhereEqual("id", 1).WithSchema(schema),
+		},
+		{
+			name:    "update value type mismatch",
+			builder: Update("users").SetRaw("id = id").Set("active", true).Set("created_at", 3.5).WithSchema(schema),
+			wantErr: `value of type float64 does not match column "created_at"`,
+		},
+		{
+			name:    "delete unknown table",
+			builder: Delete("sessions").WhereEqual("id", 1).WithSchema(schema),
+			wantErr: `unknown table "sessions"`,
+		},
+		{
+			name:    "no schema",
+			builder: Select("anything").From("anywhere"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.Build()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package sqltk
 import (
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/sprylic/sqltk/sqldebug"
 
@@ -15,22 +19,42 @@ import (
 // SelectBuilder builds SQL SELECT queries.
 type SelectBuilder struct {
 	tableClauseInterface
-	distinct    bool
-	columns     []interface{} // string, Raw, or *SelectBuilder
-	joinClauses []string
+	distinct   bool
+	columns    []interface{} // string, Raw, or *SelectBuilder
+	joins      []joinClause
+	fromTables []interface{} // further FROM tables, see AddFrom
 	whereClause
-	groupBy     []string
-	groupByRaw  []string
-	havingParam []string
-	havingRaw   []string
-	havingArgs  []interface{}
-	orderBy     []string
-	orderByRaw  []string
-	limitSet    bool
-	limit       int
-	offsetSet   bool
-	offset      int
-	dialect     sqldialect.Dialect // per-builder dialect, if set
+	groupBy          []string
+	groupByRaw       []string
+	groupByArgs      []interface{} // args of Expr terms in groupByRaw
+	havingParam      []string
+	havingRaw        []string
+	havingArgs       []interface{}
+	havingOr         bool             // havingParam[0] is an OR, see whereClause.whereOr
+	havingSubqueries []*SelectBuilder // subqueries of the HAVING conditions, see GetTables
+	orderBy          []orderTerm
+	limitSet         bool
+	limit            int
+	offsetSet        bool
+	offset           int
+	lock             string              // locking clause, e.g. "FOR UPDATE"
+	lockOption       string              // "NOWAIT" or "SKIP LOCKED"
+	maxExecTime      time.Duration       // server-side execution limit, if set
+	readOnly         bool                // see ReadOnly
+	rawAliases       bool                // see RawAliases
+	clauses          []Clause            // custom clauses, see AddClause
+	seek             *seekSpec           // keyset pagination, see SeekAfter
+	filterJoins      []filterJoin        // semi- and anti-joins, see SemiJoin and AntiJoin
+	schema           *Schema             // schema to check against, see WithSchema
+	hints            []string            // optimizer hints, see Hint
+	indexHints       []indexHint         // index hints on the FROM table, see UseIndex
+	callSites        map[string][]string // call sites by clause, see SetTraceCallSites
+	dialect          sqldialect.Dialect  // per-builder dialect, if set
+	unsupported      UnsupportedMode     // see OnUnsupported
+	reuseArgs        bool                // see ReuseArgs
+	unlimited        bool                // see Unlimited
+	deterministic    bool                // see DeterministicOrder
+	uniqueColumns    []string            // unique columns of DeterministicOrder
 }
 
 // Distinct sets the DISTINCT flag for the SELECT query.
@@ -40,28 +64,190 @@ func (b *SelectBuilder) Distinct() *SelectBuilder {
 }
 
 // Select creates a new SelectBuilder with the given columns. Columns can be string, Raw, or *SelectBuilder (for subqueries).
+// A Preset is expanded into its columns.
 func Select(columns ...interface{}) *SelectBuilder {
-	return &SelectBuilder{columns: columns}
+	b := &SelectBuilder{}
+	cols, err := expandColumns(columns)
+	if err != nil {
+		b.whereClause.addErr(fmt.Errorf("Select: %w", err))
+	}
+	b.columns = cols
+	b.recordCall("SELECT")
+	return b
 }
 
-// AddField adds columns to the query. Columns can be string, Raw, or *SelectBuilder (for subqueries).
+// AddField adds columns to the query. Columns can be string, Raw, *SelectBuilder (for subqueries), or a Preset.
 func (b *SelectBuilder) AddField(fields ...interface{}) *SelectBuilder {
+	fields, err := expandColumns(fields)
+	if err != nil {
+		b.whereClause.addErr(fmt.Errorf("AddField: %w", err))
+		return b
+	}
 	b.columns = append(b.columns, fields...)
+	b.recordCall("SELECT")
 	return b
 }
 
-// From sets the table for the SELECT query. Accepts string, Raw, or *SelectBuilder (for subqueries).
-func (b *SelectBuilder) From(table interface{}) *SelectBuilder {
+// From sets the table for the SELECT query. Accepts string, Identifier, Raw, or *SelectBuilder (for subqueries).
+// Further tables are listed after the first, separated by commas, for queries that join tables
+// with WHERE predicates; see AddFrom.
+func (b *SelectBuilder) From(table interface{}, more ...interface{}) *SelectBuilder {
 	b.SetTable(table)
+	b.fromTables = nil
+	for _, t := range more {
+		b.AddFrom(t)
+	}
+	b.recordCall("FROM")
+	return b
+}
+
+// AddFrom adds a table to the FROM clause, after a comma. Accepts the same tables as Join.
+// Tables joined this way are matched by WHERE predicates, as some legacy schemas require:
+//
+//	Select("u.name", "o.total").From("users u").AddFrom("orders o").
+//		Where(NewStringCondition("o.user_id = u.id"))
+//	// SELECT u.name, o.total FROM users u, orders o WHERE o.user_id = u.id
+//
+// A JOIN binds more tightly than a comma, so the ON clause of a later Join can only
+// reference the last FROM table.
+func (b *SelectBuilder) AddFrom(table interface{}) *SelectBuilder {
+	if b.tableClauseInterface.table == nil {
+		return b.From(table)
+	}
+	switch t := table.(type) {
+	case string:
+		if t == "" {
+			b.whereClause.addErr(errors.New("AddFrom: table must not be empty"))
+			return b
+		}
+	case Identifier, raw.Raw, sqlfunc.SqlFunc, *SelectBuilder:
+	case AliasExpr:
+		switch expr := t.Expr.(type) {
+		case *SelectBuilder, string, Identifier, raw.Raw:
+		default:
+			b.whereClause.addErr(fmt.Errorf("AddFrom alias: expr must be string, Raw, or *SelectBuilder (got %T)", expr))
+			return b
+		}
+	default:
+		b.whereClause.addErr(fmt.Errorf("AddFrom: table must be string, Raw, *SelectBuilder, or AliasExpr (got %T)", t))
+		return b
+	}
+	b.fromTables = append(b.fromTables, table)
+	b.recordCall("FROM")
 	return b
 }
 
+// asTableAlias returns " AS alias", or " alias" on dialects that reject AS before a table alias,
+// such as Oracle.
+func asTableAlias(dialect sqldialect.Dialect, alias string) string {
+	if !sqldialect.SupportsTableAliasAs(dialect) {
+		return " " + alias
+	}
+	return " AS " + alias
+}
+
+// buildFromTable renders a table of the FROM clause with ? placeholders.
+func buildFromTable(dialect sqldialect.Dialect, table interface{}) (string, []interface{}, error) {
+	switch t := table.(type) {
+	case derivedTable:
+		subSQL, subArgs, err := buildDerived(t.query, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + subSQL + ")" + asTableAlias(dialect, t.alias), subArgs, nil
+	case topNTable:
+		return t.build(dialect)
+	case string:
+		return quoteQualifiedIdent(dialect, t), nil, nil
+	case Identifier:
+		return t.Quote(dialect), nil, t.validate()
+	case sqlfunc.SqlFunc:
+		return string(t), nil, t.Err()
+	case raw.Raw:
+		return string(t), nil, nil
+	case *SelectBuilder:
+		subSQL, subArgs, err := buildPositional(t, dialect)
+		return "(" + subSQL + ")", subArgs, err
+	case AliasExpr:
+		switch expr := t.Expr.(type) {
+		case *SelectBuilder:
+			subSQL, subArgs, err := buildPositional(expr, dialect)
+			return "(" + subSQL + ")" + asTableAlias(dialect, t.Alias), subArgs, err
+		case string:
+			return quoteQualifiedIdent(dialect, expr) + asTableAlias(dialect, t.Alias), nil, nil
+		case Identifier:
+			return expr.Quote(dialect) + asTableAlias(dialect, t.Alias), nil, expr.validate()
+		case raw.Raw:
+			return string(expr) + asTableAlias(dialect, t.Alias), nil, nil
+		default:
+			return "", nil, errors.New("Alias: expr must be string, sq.Raw, or *SelectBuilder")
+		}
+	default:
+		return "", nil, errors.New("From: table must be string, sq.Raw, *SelectBuilder, or sq.AliasExpr")
+	}
+}
+
 // Where adds a WHERE clause to the query. Accepts a Condition.
 func (b *SelectBuilder) Where(cond Condition, args ...interface{}) *SelectBuilder {
 	b.whereClause.Where(cond, args...)
 	return b
 }
 
+// OrWhere combines the WHERE conditions added so far with cond using OR.
+// Conditions added afterwards are ANDed to the combined condition.
+//
+// Example usage:
+//
+//	q.Where(sqltk.NewStringCondition("status = ?", "active")).
+//		OrWhere(sqltk.NewStringCondition("role = ?", "admin"))
+//	// WHERE (status = ?) OR (role = ?)
+func (b *SelectBuilder) OrWhere(cond Condition) *SelectBuilder {
+	b.whereClause.OrWhere(cond)
+	return b
+}
+
+// WhereGroup adds the conditions built by fn as a single parenthesized WHERE condition.
+//
+// Example usage:
+//
+//	q.WhereEqual("tenant_id", 7).WhereGroup(func(c *sqltk.ConditionBuilder) {
+//		c.Equal("status", "active").Or(sqltk.NewCond().Equal("role", "admin"))
+//	})
+func (b *SelectBuilder) WhereGroup(fn func(*ConditionBuilder)) *SelectBuilder {
+	b.whereClause.WhereGroup(b.dialect, fn)
+	return b
+}
+
+// WhereIf adds a WHERE clause only if cond is true, for optional filters.
+//
+// Example usage:
+//
+//	q.WhereIf(name != "", sqltk.NewStringCondition("name = ?", name))
+func (b *SelectBuilder) WhereIf(cond bool, condition Condition) *SelectBuilder {
+	if cond {
+		b.whereClause.Where(condition)
+	}
+	return b
+}
+
+// ApplyIf calls fn with the builder only if cond is true, for optional clauses that need more
+// than a single condition.
+//
+// Example usage:
+//
+//	q.ApplyIf(sort == "recent", func(q *sqltk.SelectBuilder) *sqltk.SelectBuilder {
+//		return q.OrderByDesc("created_at").Limit(10)
+//	})
+func (b *SelectBuilder) ApplyIf(cond bool, fn func(*SelectBuilder) *SelectBuilder) *SelectBuilder {
+	if !cond || fn == nil {
+		return b
+	}
+	if next := fn(b); next != nil {
+		return next
+	}
+	return b
+}
+
 // WhereEqual adds a WHERE clause for equality (column = value).
 func (b *SelectBuilder) WhereEqual(column string, value interface{}) *SelectBuilder {
 	b.whereClause.WhereEqual(column, value)
@@ -134,6 +320,33 @@ func (b *SelectBuilder) WhereNotIn(column string, values ...interface{}) *Select
 	return b
 }
 
+// WhereInSlice adds a WHERE clause for IN condition with one placeholder per element of a typed
+// slice, e.g. []int64 or []string.
+func (b *SelectBuilder) WhereInSlice(column string, slice interface{}) *SelectBuilder {
+	b.Where(NewCond().InSlice(column, slice))
+	return b
+}
+
+// WhereNotInSlice adds a WHERE clause for NOT IN condition with one placeholder per element of a typed slice.
+func (b *SelectBuilder) WhereNotInSlice(column string, slice interface{}) *SelectBuilder {
+	b.Where(NewCond().NotInSlice(column, slice))
+	return b
+}
+
+// WhereInEnum adds a WHERE clause for IN condition with the requested values, which must all be
+// in allowed. See ConditionBuilder.InEnum.
+func (b *SelectBuilder) WhereInEnum(column string, allowed []string, requested []string) *SelectBuilder {
+	b.Where(NewCond().InEnum(column, allowed, requested))
+	return b
+}
+
+// WhereDateRange adds a WHERE clause for the half-open time range of the bounds that are set.
+// See ConditionBuilder.DateRange.
+func (b *SelectBuilder) WhereDateRange(column string, from, to *time.Time) *SelectBuilder {
+	b.Where(NewCond().DateRange(column, from, to))
+	return b
+}
+
 // WhereBetween adds a WHERE clause for BETWEEN condition (column BETWEEN min AND max).
 func (b *SelectBuilder) WhereBetween(column string, min, max interface{}) *SelectBuilder {
 	b.Where(NewCond().Between(column, min, max))
@@ -164,73 +377,115 @@ func (b *SelectBuilder) WhereColsEqual(column1, column2 string) *SelectBuilder {
 	return b
 }
 
-// GroupBy adds a GROUP BY clause. Accepts either a column string or Raw.
+// GroupBy adds a GROUP BY clause. Accepts a column string, Raw, or Expr.
 func (b *SelectBuilder) GroupBy(expr ...interface{}) *SelectBuilder {
-	if b.whereClause.err != nil || b.tableClauseInterface.err != nil {
-		return b
-	}
 
 	for _, e := range expr {
 		switch c := e.(type) {
 		case sqlfunc.SqlFunc:
+			if err := c.Err(); err != nil {
+				b.whereClause.addErr(fmt.Errorf("GroupBy: %w", err))
+				continue
+			}
 			b.groupByRaw = append(b.groupByRaw, string(c))
 		case raw.Raw:
 			b.groupByRaw = append(b.groupByRaw, string(c))
+		case SQLExpr:
+			b.groupByRaw = append(b.groupByRaw, c.SQL)
+			b.groupByArgs = append(b.groupByArgs, c.Args...)
 		case string:
 			b.groupBy = append(b.groupBy, c)
 		default:
-			b.whereClause.err = errors.New("GroupBy: expr must be string or sq.Raw")
+			b.whereClause.addErr(errors.New("GroupBy: expr must be string, sq.Raw, or sq.SQLExpr"))
 		}
 	}
+	b.recordCall("GROUP BY")
 	return b
 }
 
 // Having adds a HAVING clause. Accepts a Condition.
 func (b *SelectBuilder) Having(cond Condition, args ...interface{}) *SelectBuilder {
-	if b.whereClause.err != nil || b.tableClauseInterface.err != nil {
+	if cond == nil {
+		b.whereClause.addErr(errors.New("Having: condition must not be nil"))
 		return b
 	}
 
 	sql, condArgs, err := cond.BuildCondition()
 	if err != nil {
-		b.whereClause.err = fmt.Errorf("Having: condition error: %w", err)
+		b.whereClause.addErr(fmt.Errorf("Having: condition error: %w", err))
 		return b
 	}
 	if sql != "" {
+		if condNeedsGroup(cond) {
+			if len(b.havingParam) == 0 {
+				b.havingOr = true
+			} else {
+				sql = "(" + sql + ")"
+			}
+		}
 		b.havingParam = append(b.havingParam, sql)
 		b.havingArgs = append(b.havingArgs, condArgs...)
+		b.havingSubqueries = append(b.havingSubqueries, condSubqueries(cond)...)
+		b.recordCall("HAVING")
 	}
 	return b
 }
 
-// OrderBy adds an ORDER BY clause. Accepts either a column string or Raw.
+// OrderBy adds an ORDER BY clause. Accepts either a column string, optionally followed by
+// ASC or DESC (e.g. "created_at DESC"), Raw, or Expr. Prefer OrderByAsc, OrderByDesc, and OrderByExpr.
 func (b *SelectBuilder) OrderBy(expr interface{}) *SelectBuilder {
-	if b.whereClause.err != nil || b.tableClauseInterface.err != nil {
+	term, err := newOrderTerm(expr)
+	if err != nil {
+		b.whereClause.addErr(err)
 		return b
 	}
-	switch c := expr.(type) {
-	case sqlfunc.SqlFunc:
-		b.orderByRaw = append(b.orderByRaw, string(c))
-	case raw.Raw:
-		b.orderByRaw = append(b.orderByRaw, string(c))
-	case string:
-		b.orderBy = append(b.orderBy, c)
-	default:
-		b.whereClause.err = errors.New("OrderBy: expr must be string or sq.Raw")
+	b.orderBy = append(b.orderBy, term)
+	b.recordCall("ORDER BY")
+	return b
+}
+
+// OrderByAsc adds an ascending ORDER BY term for a column.
+func (b *SelectBuilder) OrderByAsc(column string) *SelectBuilder {
+	return b.OrderByExpr(column, Asc, NullsDefault)
+}
+
+// OrderByDesc adds a descending ORDER BY term for a column.
+func (b *SelectBuilder) OrderByDesc(column string) *SelectBuilder {
+	return b.OrderByExpr(column, Desc, NullsDefault)
+}
+
+// OrderByExpr adds an ORDER BY term with a direction and NULLS ordering. expr is a column name,
+// Raw, Expr, or sqlfunc.SqlFunc. On MySQL, NullsFirst and NullsLast are emulated with an IS NULL term.
+//
+// Example usage:
+//
+//	OrderByExpr("last_login", sqltk.Desc, sqltk.NullsLast)
+//	OrderByExpr(mysqlfunc.Count("*"), sqltk.Desc, sqltk.NullsDefault)
+func (b *SelectBuilder) OrderByExpr(expr interface{}, dir OrderDirection, nulls NullsOrder) *SelectBuilder {
+	term, err := newOrderExprTerm(expr, dir, nulls)
+	if err != nil {
+		b.whereClause.addErr(err)
+		return b
 	}
+	b.orderBy = append(b.orderBy, term)
+	b.recordCall("ORDER BY")
 	return b
 }
 
 // JoinBuilder is used for fluent JOIN ... ON ... chaining.
 type JoinBuilder struct {
-	parent    *SelectBuilder
-	joinType  string
-	joinTable interface{}
-	err       error
+	parent     *SelectBuilder
+	joinType   string
+	joinTable  interface{}
+	onSQL      string
+	onArgs     []interface{}
+	indexHints []indexHint
+	err        error
 }
 
 // Join starts an INNER JOIN clause. Accepts a table, subquery, or alias.
 //   - string: table name (optionally with alias, e.g. "users u")
+//   - Identifier: qualified table name (use sqltk.Ident or sqltk.Table)
 //   - Raw: raw SQL for the table
 //   - *SelectBuilder: subquery as table
 //   - AliasExpr: alias for a table or subquery (use sqltk.Alias)
@@ -239,6 +494,7 @@ type JoinBuilder struct {
 //
 //	Join("orders o")
 //	Join(sqltk.Alias("orders", "o"))
+//	Join(sqltk.Alias(sqltk.Ident("sales", "orders"), "o"))
 //	Join(sqltk.Alias(sqltk.Select("id").From("orders"), "o"))
 func (b *SelectBuilder) Join(table interface{}) *JoinBuilder {
 	return &JoinBuilder{parent: b, joinType: "JOIN", joinTable: table}
@@ -246,6 +502,7 @@ func (b *SelectBuilder) Join(table interface{}) *JoinBuilder {
 
 // LeftJoin starts a LEFT JOIN clause. Accepts a table, subquery, or alias.
 //   - string: table name (optionally with alias)
+//   - Identifier: qualified table name (use sqltk.Ident or sqltk.Table)
 //   - Raw: raw SQL for the table
 //   - *SelectBuilder: subquery as table
 //   - AliasExpr: alias for a table or subquery (use sqltk.Alias)
@@ -261,6 +518,7 @@ func (b *SelectBuilder) LeftJoin(table interface{}) *JoinBuilder {
 
 // RightJoin starts a RIGHT JOIN clause. Accepts a table, subquery, or alias.
 //   - string: table name (optionally with alias)
+//   - Identifier: qualified table name (use sqltk.Ident or sqltk.Table)
 //   - Raw: raw SQL for the table
 //   - *SelectBuilder: subquery as table
 //   - AliasExpr: alias for a table or subquery (use sqltk.Alias)
@@ -276,6 +534,7 @@ func (b *SelectBuilder) RightJoin(table interface{}) *JoinBuilder {
 
 // FullJoin starts a FULL JOIN clause. Accepts a table, subquery, or alias.
 //   - string: table name (optionally with alias)
+//   - Identifier: qualified table name (use sqltk.Ident or sqltk.Table)
 //   - Raw: raw SQL for the table
 //   - *SelectBuilder: subquery as table
 //   - AliasExpr: alias for a table or subquery (use sqltk.Alias)
@@ -289,66 +548,242 @@ func (b *SelectBuilder) FullJoin(table interface{}) *JoinBuilder {
 	return &JoinBuilder{parent: b, joinType: "FULL JOIN", joinTable: table}
 }
 
+// CrossJoin adds a CROSS JOIN clause. Accepts a table, subquery, or alias, like Join.
+// A cross join has no ON condition, so the parent SelectBuilder is returned directly.
+//
+// Example usage:
+//
+//	CrossJoin("regions")
+func (b *SelectBuilder) CrossJoin(table interface{}) *SelectBuilder {
+	jb := &JoinBuilder{parent: b, joinType: "CROSS JOIN", joinTable: table}
+	return jb.finish(nil)
+}
+
 // On finalizes the JOIN ... ON ... clause and returns the parent SelectBuilder.
 func (jb *JoinBuilder) On(left, right string) *SelectBuilder {
+	return jb.OnEqual(left, right).End()
+}
+
+// OnCond finalizes the JOIN ... ON ... clause with a Condition and returns the parent SelectBuilder.
+//
+// Example usage:
+//
+//	Join("orders o").OnCond(NewStringCondition("o.user_id = u.id AND o.status = ?", "paid"))
+func (jb *JoinBuilder) OnCond(cond Condition) *SelectBuilder {
+	return jb.And(cond).End()
+}
+
+// OnEqual adds a column equality (left = right) to the ON clause. Finish with End.
+func (jb *JoinBuilder) OnEqual(left, right string) *JoinBuilder {
+	return jb.And(raw.Raw(left + " = " + right))
+}
+
+// And adds a Condition to the ON clause with AND. Finish with End.
+//
+// Example usage:
+//
+//	Join("orders o").OnEqual("o.user_id", "u.id").And(NewStringCondition("o.status = ?", "paid")).End()
+func (jb *JoinBuilder) And(cond Condition) *JoinBuilder {
+	sql, args := jb.buildOn(cond)
+	if sql == "" {
+		return jb
+	}
+	if jb.onSQL == "" {
+		jb.onSQL = sql
+	} else {
+		jb.onSQL += " AND " + sql
+	}
+	jb.onArgs = append(jb.onArgs, args...)
+	return jb
+}
+
+// Or combines the ON clause built so far with a Condition using OR. Finish with End.
+func (jb *JoinBuilder) Or(cond Condition) *JoinBuilder {
+	sql, args := jb.buildOn(cond)
+	if sql == "" {
+		return jb
+	}
+	if jb.onSQL == "" {
+		jb.onSQL = sql
+	} else {
+		jb.onSQL = "(" + jb.onSQL + ") OR (" + sql + ")"
+	}
+	jb.onArgs = append(jb.onArgs, args...)
+	return jb
+}
+
+func (jb *JoinBuilder) buildOn(cond Condition) (string, []interface{}) {
 	if jb.err != nil {
-		jb.parent.whereClause.err = jb.err
-		return jb.parent
+		return "", nil
+	}
+	if cond == nil {
+		jb.err = errors.New("join: condition must not be nil")
+		return "", nil
 	}
+	sql, args, err := cond.BuildCondition()
+	if err != nil {
+		jb.err = fmt.Errorf("join: condition error: %w", err)
+		return "", nil
+	}
+	return sql, args
+}
 
-	clause := jb.joinType + " "
-	dialect := jb.parent.dialect
-	if dialect == nil {
-		dialect = sqldialect.GetDialect()
+// End finalizes the JOIN ... ON ... clause and returns the parent SelectBuilder.
+func (jb *JoinBuilder) End() *SelectBuilder {
+	if jb.err == nil && jb.onSQL == "" {
+		jb.err = errors.New("join: ON condition is required")
+	}
+	return jb.finish(nil)
+}
+
+// Using finalizes the JOIN with a USING (columns...) clause and returns the parent SelectBuilder.
+//
+// Example usage:
+//
+//	Join("accounts").Using("tenant_id", "user_id")
+func (jb *JoinBuilder) Using(columns ...string) *SelectBuilder {
+	if jb.err == nil && len(columns) == 0 {
+		jb.err = errors.New("join: USING requires at least one column")
+	}
+	if jb.err == nil && jb.onSQL != "" {
+		jb.err = errors.New("join: USING cannot be combined with ON")
+	}
+	return jb.finish(slices.Clone(columns))
+}
+
+// joinClause is a JOIN of a SelectBuilder. It keeps its table, ON condition, and USING
+// columns, and is rendered when the query is built, so that identifiers are quoted for the
+// dialect the query is built with, and the placeholders and args of a joined subquery take
+// their place in the enclosing query.
+type joinClause struct {
+	joinType   string
+	table      interface{}
+	indexHints []indexHint
+	on         string        // ON condition, with ? placeholders
+	onArgs     []interface{} // args of on
+	using      []string      // USING columns, unquoted
+}
+
+// finish checks the joined table and adds the join, with the ON condition built so far or the
+// USING columns, to the parent.
+func (jb *JoinBuilder) finish(using []string) *SelectBuilder {
+	if jb.err != nil {
+		jb.parent.whereClause.addErr(jb.err)
+		return jb.parent
 	}
 
 	switch t := jb.joinTable.(type) {
+	case string, Identifier, raw.Raw, *SelectBuilder:
+	case AliasExpr:
+		switch expr := t.Expr.(type) {
+		case *SelectBuilder, string, Identifier, raw.Raw:
+		default:
+			jb.parent.whereClause.addErr(fmt.Errorf("join alias: expr must be string, Raw, or *SelectBuilder (got %T)", expr))
+			return jb.parent
+		}
+	default:
+		jb.parent.whereClause.addErr(fmt.Errorf("join: table must be string, Raw, *SelectBuilder, or AliasExpr (got %T)", t))
+		return jb.parent
+	}
+	if len(jb.indexHints) > 0 && !isTableName(jb.joinTable) {
+		jb.parent.whereClause.addErr(errors.New("join: index hints require a table name"))
+		return jb.parent
+	}
+
+	jb.parent.joins = append(jb.parent.joins, joinClause{
+		joinType:   jb.joinType,
+		table:      jb.joinTable,
+		indexHints: jb.indexHints,
+		on:         jb.onSQL,
+		onArgs:     jb.onArgs,
+		using:      using,
+	})
+	jb.parent.recordCall("JOIN")
+	return jb.parent
+}
+
+// build renders the join with ? placeholders for dialect, returning its args in SQL order:
+// those of a joined subquery, then those of the ON clause.
+func (j joinClause) build(dialect sqldialect.Dialect, mode UnsupportedMode) (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+	joinType := j.joinType
+	if joinType == "FULL JOIN" && baseDialect(dialect) == sqldialect.MySQL() {
+		if _, err := mode.strip("FULL JOIN", "LEFT JOIN"); err != nil {
+			return "", nil, err
+		}
+		joinType = "LEFT JOIN"
+	}
+	sb.WriteString(joinType)
+	sb.WriteString(" ")
+
+	switch t := j.table.(type) {
 	case string:
-		clause += dialect.QuoteIdent(t)
+		sb.WriteString(quoteQualifiedIdent(dialect, t))
+	case Identifier:
+		if err := t.validate(); err != nil {
+			return "", nil, fmt.Errorf("join: %w", err)
+		}
+		sb.WriteString(t.Quote(dialect))
 	case raw.Raw:
-		clause += string(t)
+		sb.WriteString(string(t))
 	case *SelectBuilder:
-		subSQL, subArgs, subErr := t.Build()
-		if subErr != nil {
-			jb.parent.whereClause.err = fmt.Errorf("join subquery error: %w", subErr)
-			return jb.parent
+		subSQL, subArgs, err := buildPositional(t, dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("join subquery error: %w", err)
 		}
-		clause += "(" + subSQL + ")"
-		// Store the subquery args in the parent's whereClause for later use
-		jb.parent.whereClause.whereArgs = append(jb.parent.whereClause.whereArgs, subArgs...)
+		sb.WriteString("(" + subSQL + ")")
+		args = append(args, subArgs...)
 	case AliasExpr:
 		switch expr := t.Expr.(type) {
 		case *SelectBuilder:
-			subSQL, subArgs, subErr := expr.Build()
-			if subErr != nil {
-				jb.parent.whereClause.err = fmt.Errorf("join alias subquery error: %w", subErr)
-				return jb.parent
+			subSQL, subArgs, err := buildPositional(expr, dialect)
+			if err != nil {
+				return "", nil, fmt.Errorf("join alias subquery error: %w", err)
 			}
-			clause += "(" + subSQL + ") AS " + t.Alias
-			// Store the subquery args in the parent's whereClause for later use
-			jb.parent.whereClause.whereArgs = append(jb.parent.whereClause.whereArgs, subArgs...)
+			sb.WriteString("(" + subSQL + ")" + asTableAlias(dialect, t.Alias))
+			args = append(args, subArgs...)
 		case string:
-			clause += dialect.QuoteIdent(expr) + " AS " + t.Alias
+			sb.WriteString(quoteQualifiedIdent(dialect, expr) + asTableAlias(dialect, t.Alias))
+		case Identifier:
+			if err := expr.validate(); err != nil {
+				return "", nil, fmt.Errorf("join: %w", err)
+			}
+			sb.WriteString(expr.Quote(dialect) + asTableAlias(dialect, t.Alias))
 		case raw.Raw:
-			clause += string(expr) + " AS " + t.Alias
-		default:
-			jb.parent.whereClause.err = fmt.Errorf("join alias: expr must be string, Raw, or *SelectBuilder (got %T)", expr)
-			return jb.parent
+			sb.WriteString(string(expr) + asTableAlias(dialect, t.Alias))
 		}
-	default:
-		jb.parent.whereClause.err = fmt.Errorf("join: table must be string, Raw, *SelectBuilder, or AliasExpr (got %T)", t)
-		return jb.parent
 	}
 
-	clause += " ON " + left + " = " + right
-	jb.parent.joinClauses = append(jb.parent.joinClauses, clause)
-	return jb.parent
+	hintSQL, err := buildIndexHints(dialect, j.indexHints, mode)
+	if err != nil {
+		return "", nil, fmt.Errorf("join: %w", err)
+	}
+	sb.WriteString(hintSQL)
+
+	if j.on != "" {
+		on, onArgs, err := bindArgs(dialect, j.on, j.onArgs)
+		if err != nil {
+			return "", nil, fmt.Errorf("join: %w", err)
+		}
+		sb.WriteString(" ON " + on)
+		args = append(args, onArgs...)
+	}
+	if len(j.using) > 0 {
+		quoted := make([]string, len(j.using))
+		for i, col := range j.using {
+			quoted[i] = dialect.QuoteIdent(col)
+		}
+		sb.WriteString(" USING (" + strings.Join(quoted, ", ") + ")")
+	}
+	return sb.String(), args, nil
 }
 
 // Limit sets a LIMIT clause.
 func (b *SelectBuilder) Limit(n int) *SelectBuilder {
 	b.limitSet = true
 	b.limit = n
+	b.recordCall("LIMIT")
 	return b
 }
 
@@ -356,9 +791,114 @@ func (b *SelectBuilder) Limit(n int) *SelectBuilder {
 func (b *SelectBuilder) Offset(n int) *SelectBuilder {
 	b.offsetSet = true
 	b.offset = n
+	b.recordCall("OFFSET")
+	return b
+}
+
+// HasLimit reports whether a LIMIT is set.
+func (b *SelectBuilder) HasLimit() bool {
+	return b.limitSet
+}
+
+// Unlimited marks the query as meant to read every matching row, so that a row limit policy,
+// such as runner.MaxRows, leaves it without a LIMIT.
+func (b *SelectBuilder) Unlimited() *SelectBuilder {
+	b.unlimited = true
+	return b
+}
+
+// IsUnlimited reports whether the query was marked with Unlimited.
+func (b *SelectBuilder) IsUnlimited() bool {
+	return b.unlimited
+}
+
+// ForUpdate adds a FOR UPDATE locking clause.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.lock = "FOR UPDATE"
+	b.recordCall("LOCK")
+	return b
+}
+
+// ForShare adds a FOR SHARE locking clause (Postgres, MySQL 8.0+). Oracle has no FOR SHARE.
+func (b *SelectBuilder) ForShare() *SelectBuilder {
+	b.lock = "FOR SHARE"
+	b.recordCall("LOCK")
 	return b
 }
 
+// LockInShareMode adds a LOCK IN SHARE MODE locking clause, for MySQL versions before 8.0.
+// It cannot be combined with NoWait or SkipLocked, and Build returns an error for other
+// dialects unless UnsupportedStrip is set, which builds it as FOR SHARE where there is one.
+func (b *SelectBuilder) LockInShareMode() *SelectBuilder {
+	b.lock = "LOCK IN SHARE MODE"
+	b.recordCall("LOCK")
+	return b
+}
+
+// NoWait makes the locking clause fail immediately instead of waiting for locked rows.
+func (b *SelectBuilder) NoWait() *SelectBuilder {
+	b.lockOption = "NOWAIT"
+	b.recordCall("LOCK")
+	return b
+}
+
+// SkipLocked makes the locking clause skip rows that are already locked.
+func (b *SelectBuilder) SkipLocked() *SelectBuilder {
+	b.lockOption = "SKIP LOCKED"
+	b.recordCall("LOCK")
+	return b
+}
+
+// lockClause renders the locking clause lock with its NOWAIT or SKIP LOCKED option. A clause
+// the dialect does not support is left out or replaced as mode says: DuckDB, BigQuery and
+// ClickHouse have no row locks, Oracle has no FOR SHARE, and LOCK IN SHARE MODE is MySQL's.
+func lockClause(dialect sqldialect.Dialect, mode UnsupportedMode, lock, option string) (string, error) {
+	clause := lock
+	if option != "" {
+		clause += " " + option
+	}
+	switch base := baseDialect(dialect); {
+	case lock == "":
+		return "", nil
+	case base == sqldialect.DuckDB() || base == sqldialect.BigQuery() || base == sqldialect.ClickHouse(),
+		base == sqldialect.Oracle() && lock != "FOR UPDATE":
+		_, err := mode.strip(clause, "")
+		return "", err
+	case lock == "LOCK IN SHARE MODE" && base != sqldialect.MySQL():
+		if _, err := mode.strip(clause, "FOR SHARE"); err != nil {
+			return "", err
+		}
+		return "FOR SHARE", nil
+	}
+	return clause, nil
+}
+
+// MaxExecutionTime limits how long the server may run the query, rounded up to whole milliseconds.
+// On MySQL it adds a /*+ MAX_EXECUTION_TIME(n) */ optimizer hint. Other dialects have no
+// per-statement syntax; runner.Runner applies the limit on Postgres with SET LOCAL statement_timeout.
+func (b *SelectBuilder) MaxExecutionTime(d time.Duration) *SelectBuilder {
+	b.maxExecTime = d
+	return b
+}
+
+// GetMaxExecutionTime returns the limit set with MaxExecutionTime, or 0 if none is set.
+func (b *SelectBuilder) GetMaxExecutionTime() time.Duration {
+	return b.maxExecTime
+}
+
+// ReadOnly marks the query as read-only. runner.Runner routes read-only queries to a replica,
+// if one is configured, or runs them in a read-only transaction; a Runner in StrictReadOnly
+// mode rejects every builder that is not a read-only SELECT.
+func (b *SelectBuilder) ReadOnly() *SelectBuilder {
+	b.readOnly = true
+	return b
+}
+
+// IsReadOnly reports whether the query was marked with ReadOnly.
+func (b *SelectBuilder) IsReadOnly() bool {
+	return b.readOnly
+}
+
 // AliasExpr represents an aliased SQL expression (column, subquery, or table).
 type AliasExpr struct {
 	Expr  interface{}
@@ -376,25 +916,96 @@ func (b *SelectBuilder) WithDialect(d sqldialect.Dialect) *SelectBuilder {
 	return b
 }
 
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (b *SelectBuilder) GetDialect() sqldialect.Dialect {
+	if b.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return b.dialect
+}
+
 // Build builds the SQL query and returns the query string, arguments, and error if any invalid type is encountered.
+// The error reports every problem found, such as an invalid column, join, and HAVING condition,
+// joined with errors.Join, and no SQL is returned with it.
 func (b *SelectBuilder) Build() (string, []interface{}, error) {
-	if b.tableClauseInterface.err != nil {
-		return "", nil, b.tableClauseInterface.err
+	return b.build(nil)
+}
+
+// MustBuild is like Build but panics if the query cannot be built, for queries that are fixed
+// when the program is written, such as those kept in package variables. The panic value is an
+// error that names the tables of the query and wraps the error of Build.
+func (b *SelectBuilder) MustBuild() (string, []interface{}) {
+	sql, args, err := b.Build()
+	if err != nil {
+		if tables := b.GetTables(); len(tables) > 0 {
+			panic(fmt.Errorf("sqltk: MustBuild: SELECT from %s: %w", strings.Join(tables, ", "), err))
+		}
+		panic(fmt.Errorf("sqltk: MustBuild: SELECT: %w", err))
 	}
-	if b.whereClause.err != nil {
-		return "", nil, b.whereClause.err
+	return sql, args
+}
+
+// build builds the query, recording where each clause starts in tr if it is not nil. It
+// returns the errors recorded by builder calls and those found while rendering the query
+// together, and no SQL if there are any.
+func (b *SelectBuilder) build(tr *buildTracer) (string, []interface{}, error) {
+	if b == nil {
+		return "", nil, errors.New("Select: builder is nil")
 	}
+	sql, args, err := b.render(tr)
+	if err = errors.Join(b.tableClauseInterface.err, b.whereClause.err, err); err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// render renders the query for build.
+func (b *SelectBuilder) render(tr *buildTracer) (string, []interface{}, error) {
 	var sb strings.Builder
-	var err error
+	var err, clauseErr error
 	args := []interface{}{}
 
 	dialect := b.dialect
 	if dialect == nil {
 		dialect = sqldialect.GetDialect()
 	}
-	placeholderIdx := 1
+	out := dialect
+	dialect = renderDialect(dialect)
 
+	if b.maxExecTime < 0 {
+		return "", nil, errors.New("MaxExecutionTime: duration must not be negative")
+	}
+	if err := b.checkAliases(dialect); err != nil {
+		return "", nil, err
+	}
+	if b.schema != nil {
+		if err := b.schema.checkSelect(b); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := b.checkDeterministicOrder(); err != nil {
+		return "", nil, err
+	}
+	if b.unsupported == UnsupportedEmulate && baseDialect(dialect) == sqldialect.MySQL() {
+		for _, j := range b.joins {
+			if j.joinType == "FULL JOIN" {
+				return b.emulateFullJoin(out)
+			}
+		}
+	}
+
+	tr.mark("SELECT", sb.Len())
 	sb.WriteString("SELECT ")
+	hints := b.hints
+	if b.maxExecTime > 0 && baseDialect(dialect) == sqldialect.MySQL() {
+		ms := intToString(int((b.maxExecTime + time.Millisecond - 1) / time.Millisecond))
+		hints = append([]string{"MAX_EXECUTION_TIME(" + ms + ")"}, hints...)
+	}
+	if len(hints) > 0 {
+		sb.WriteString("/*+ ")
+		sb.WriteString(strings.Join(hints, " "))
+		sb.WriteString(" */ ")
+	}
 	if b.distinct {
 		sb.WriteString("DISTINCT ")
 	}
@@ -407,30 +1018,18 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 			}
 			switch c := col.(type) {
 			case string:
-				// Handle expressions with aliases (e.g., "COUNT(*) as count")
-				if strings.Contains(strings.ToUpper(c), " AS ") {
-					parts := strings.SplitN(c, " AS ", 2)
-					if len(parts) == 2 {
-						expr := strings.TrimSpace(parts[0])
-						alias := strings.TrimSpace(parts[1])
-
-						// Handle table-qualified column names in expressions
-						if strings.Contains(expr, ".") {
-							exprParts := strings.Split(expr, ".")
-							for i, part := range exprParts {
-								if i > 0 {
-									sb.WriteString(".")
-								}
-								sb.WriteString(dialect.QuoteIdent(strings.TrimSpace(part)))
-							}
-						} else {
-							sb.WriteString(expr)
-						}
-						sb.WriteString(" AS ")
-						sb.WriteString(alias)
+				// Handle expressions with aliases (e.g., "COUNT(*) AS count")
+				if expr, alias, ok := splitAlias(c); ok {
+					if isIdentPath(expr) {
+						sb.WriteString(quoteQualifiedIdent(dialect, expr))
 					} else {
-						sb.WriteString(c)
+						sb.WriteString(expr)
 					}
+					sb.WriteString(" AS ")
+					sb.WriteString(b.quoteAlias(dialect, alias))
+				} else if strings.Contains(strings.ToUpper(c), " AS ") {
+					// An expression with AS but no alias, e.g. "CAST(x AS int)"
+					sb.WriteString(c)
 				} else if strings.Contains(c, ".") {
 					// Handle table-qualified column names (e.g., "table.column")
 					parts := strings.Split(c, ".")
@@ -443,14 +1042,28 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 				} else {
 					sb.WriteString(dialect.QuoteIdent(c))
 				}
+			case Identifier:
+				if idErr := c.validate(); idErr != nil {
+					err = errors.Join(err, idErr)
+				}
+				sb.WriteString(c.Quote(dialect))
 			case raw.Raw:
 				sb.WriteString(string(c))
 			case sqlfunc.SqlFunc:
+				if fnErr := c.Err(); fnErr != nil {
+					err = errors.Join(err, fnErr)
+				}
 				sb.WriteString(string(c))
+			case *sqlfunc.WindowExpr:
+				winSQL, winErr := c.SQL(dialect)
+				if winErr != nil {
+					err = errors.Join(err, winErr)
+				}
+				sb.WriteString(winSQL)
 			case *SelectBuilder:
-				subSQL, subArgs, subErr := c.Build()
+				subSQL, subArgs, subErr := buildPositional(c, dialect)
 				if subErr != nil {
-					err = subErr
+					err = errors.Join(err, subErr)
 				}
 				sb.WriteString("(")
 				sb.WriteString(subSQL)
@@ -459,14 +1072,14 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 			case AliasExpr:
 				switch expr := c.Expr.(type) {
 				case *SelectBuilder:
-					subSQL, subArgs, subErr := expr.Build()
+					subSQL, subArgs, subErr := buildPositional(expr, dialect)
 					if subErr != nil {
-						err = subErr
+						err = errors.Join(err, subErr)
 					}
 					sb.WriteString("(")
 					sb.WriteString(subSQL)
 					sb.WriteString(") AS ")
-					sb.WriteString(c.Alias)
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
 					args = append(args, subArgs...)
 				case string:
 					// Handle table-qualified column names in AliasExpr
@@ -482,73 +1095,155 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 						sb.WriteString(dialect.QuoteIdent(expr))
 					}
 					sb.WriteString(" AS ")
-					sb.WriteString(c.Alias)
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
+				case Identifier:
+					if idErr := expr.validate(); idErr != nil {
+						err = errors.Join(err, idErr)
+					}
+					sb.WriteString(expr.Quote(dialect))
+					sb.WriteString(" AS ")
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
 				case raw.Raw:
 					sb.WriteString(string(expr))
 					sb.WriteString(" AS ")
-					sb.WriteString(c.Alias)
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
 				case sqlfunc.SqlFunc:
+					if fnErr := expr.Err(); fnErr != nil {
+						err = errors.Join(err, fnErr)
+					}
 					sb.WriteString(string(expr))
 					sb.WriteString(" AS ")
-					sb.WriteString(c.Alias)
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
+				case *sqlfunc.WindowExpr:
+					winSQL, winErr := expr.SQL(dialect)
+					if winErr != nil {
+						err = errors.Join(err, winErr)
+					}
+					sb.WriteString(winSQL)
+					sb.WriteString(" AS ")
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
+				case Binder:
+					if bErr := exprErr(expr); bErr != nil {
+						err = errors.Join(err, bErr)
+					}
+					bindSQL, bindArgs, bindErr := bindExpr(dialect, expr)
+					if bindErr != nil {
+						err = errors.Join(err, bindErr)
+					}
+					sb.WriteString(bindSQL)
+					sb.WriteString(" AS ")
+					sb.WriteString(b.quoteAlias(dialect, c.Alias))
+					args = append(args, bindArgs...)
 				default:
-					err = errors.New("Alias: expr must be string, sq.Raw, *SelectBuilder, or sqlfunc.SqlFunc")
+					err = errors.Join(err, errors.New("Alias: expr must be string, sq.Raw, *SelectBuilder, sqlfunc.SqlFunc, *sqlfunc.WindowExpr, or sq.Binder"))
 				}
+			case Binder:
+				if bErr := exprErr(c); bErr != nil {
+					err = errors.Join(err, bErr)
+				}
+				bindSQL, bindArgs, bindErr := bindExpr(dialect, c)
+				if bindErr != nil {
+					err = errors.Join(err, bindErr)
+				}
+				sb.WriteString(bindSQL)
+				args = append(args, bindArgs...)
+			case existsExpr:
+				subSQL, subArgs, subErr := buildDerived(c.query, dialect)
+				if subErr != nil {
+					return "", nil, errors.Join(err, subErr)
+				}
+				if baseDialect(dialect) == sqldialect.Oracle() {
+					// Oracle has no boolean values in SQL
+					sb.WriteString("CASE WHEN EXISTS(" + subSQL + ") THEN 1 ELSE 0 END")
+				} else {
+					sb.WriteString("EXISTS(" + subSQL + ")")
+				}
+				args = append(args, subArgs...)
 			default:
-				err = errors.New("Select: column must be string, sq.Raw, *SelectBuilder, or sq.AliasExpr")
+				err = errors.Join(err, errors.New("Select: column must be string, sq.Raw, sqlfunc.SqlFunc, *sqlfunc.WindowExpr, *SelectBuilder, sq.AliasExpr, or sq.Binder"))
 			}
 		}
 	}
-	sb.WriteString(" FROM ")
-	switch t := b.tableClauseInterface.table.(type) {
-	case string:
-		sb.WriteString(dialect.QuoteIdent(t))
-	case sqlfunc.SqlFunc:
-		sb.WriteString(string(t))
-	case raw.Raw:
-		sb.WriteString(string(t))
-	case *SelectBuilder:
-		subSQL, subArgs, subErr := t.Build()
-		if subErr != nil {
-			err = subErr
+	tr.mark("FROM", sb.Len())
+	if b.tableClauseInterface.table != nil {
+		sb.WriteString(" FROM ")
+	}
+	if b.tableClauseInterface.table == nil && sqldialect.RequiresDual(baseDialect(dialect)) {
+		// A SELECT without a table reads from DUAL, as in Oracle.
+		sb.WriteString(" FROM DUAL")
+	} else if b.tableClauseInterface.table == nil {
+		// A missing FROM is only reported on its own, as builders that failed early, such
+		// as that of an unknown fragment, have none.
+		if !isExistsOnly(b.columns) && b.tableClauseInterface.err == nil && b.whereClause.err == nil {
+			err = errors.Join(err, errors.New("From: table must be string, sq.Raw, *SelectBuilder, or sq.AliasExpr"))
 		}
-		sb.WriteString("(")
-		sb.WriteString(subSQL)
-		sb.WriteString(")")
-		args = append(args, subArgs...)
-	case AliasExpr:
-		switch expr := t.Expr.(type) {
-		case *SelectBuilder:
-			subSQL, subArgs, subErr := expr.Build()
-			if subErr != nil {
-				err = subErr
-			}
-			sb.WriteString("(")
-			sb.WriteString(subSQL)
-			sb.WriteString(") AS ")
-			sb.WriteString(t.Alias)
-			args = append(args, subArgs...)
-		case string:
-			sb.WriteString(dialect.QuoteIdent(expr))
-			sb.WriteString(" AS ")
-			sb.WriteString(t.Alias)
-		case raw.Raw:
-			sb.WriteString(string(expr))
-			sb.WriteString(" AS ")
-			sb.WriteString(t.Alias)
-		default:
-			err = errors.New("Alias: expr must be string, sq.Raw, or *SelectBuilder")
+	} else {
+		fromSQL, fromArgs, fromErr := buildFromTable(dialect, b.tableClauseInterface.table)
+		if fromErr != nil {
+			err = errors.Join(err, fromErr)
 		}
-	default:
-		err = errors.New("From: table must be string, sq.Raw, *SelectBuilder, or sq.AliasExpr")
+		sb.WriteString(fromSQL)
+		args = append(args, fromArgs...)
+	}
+	if len(b.indexHints) > 0 {
+		if !isTableName(b.tableClauseInterface.table) {
+			return "", nil, errors.Join(err, errors.New("index hints require a table name in FROM"))
+		}
+		hintSQL, hintErr := buildIndexHints(dialect, b.indexHints, b.unsupported)
+		if hintErr != nil {
+			return "", nil, errors.Join(err, hintErr)
+		}
+		sb.WriteString(hintSQL)
+	}
+	for _, table := range b.fromTables {
+		fromSQL, fromArgs, fromErr := buildFromTable(dialect, table)
+		if fromErr != nil {
+			return "", nil, errors.Join(err, fromErr)
+		}
+		sb.WriteString(", ")
+		sb.WriteString(fromSQL)
+		args = append(args, fromArgs...)
 	}
 
-	if len(b.joinClauses) > 0 {
+	tr.mark("JOIN", sb.Len())
+	for _, j := range b.joins {
+		joinSQL, joinArgs, joinErr := j.build(dialect, b.unsupported)
+		if joinErr != nil {
+			err = errors.Join(err, joinErr)
+			continue
+		}
 		sb.WriteString(" ")
-		sb.WriteString(strings.Join(b.joinClauses, " "))
+		sb.WriteString(joinSQL)
+		args = append(args, joinArgs...)
+	}
+	for _, f := range b.filterJoins {
+		if f.usesLeftJoin(dialect) {
+			sb.WriteString(" ")
+			sb.WriteString(f.joinSQL(dialect))
+		}
 	}
 
-	whereSQL, whereArgs := b.whereClause.buildWhereSQL(dialect, &placeholderIdx)
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterFrom, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
+	}
+
+	where := b.whereClause
+	if t, ok := b.tableClauseInterface.table.(topNTable); ok && t.condition(dialect) != "" {
+		where.whereParam = append(where.whereParam[:len(where.whereParam):len(where.whereParam)], t.condition(dialect))
+	}
+	for _, f := range b.filterJoins {
+		where.whereParam = append(where.whereParam[:len(where.whereParam):len(where.whereParam)], f.condition(dialect))
+	}
+	if b.seek != nil {
+		seekSQL, seekArgs := b.seek.condition(dialect)
+		where.whereParam = append(where.whereParam[:len(where.whereParam):len(where.whereParam)], seekSQL)
+		where.whereArgs = append(where.whereArgs[:len(where.whereArgs):len(where.whereArgs)], seekArgs...)
+	}
+	whereSQL, whereArgs, whereErr := where.buildWhereSQL(dialect)
+	if whereErr != nil {
+		err = errors.Join(err, whereErr)
+	}
+	tr.mark("WHERE", sb.Len())
 	if whereSQL != "" {
 		sb.WriteString(" WHERE ")
 		sb.WriteString(whereSQL)
@@ -558,6 +1253,10 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 		args = append(args, whereArgs...)
 	}
 
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterWhere, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
+	}
+
 	var groupBys []string
 	if len(b.groupBy) > 0 {
 		for _, g := range b.groupBy {
@@ -580,9 +1279,23 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 	if len(b.groupByRaw) > 0 {
 		groupBys = append(groupBys, b.groupByRaw...)
 	}
+	tr.mark("GROUP BY", sb.Len())
 	if len(groupBys) > 0 {
+		groupSQL := strings.Join(groupBys, ", ")
+		if len(b.groupByArgs) > 0 {
+			var groupArgs []interface{}
+			var bindErr error
+			if groupSQL, groupArgs, bindErr = bindArgs(dialect, groupSQL, b.groupByArgs); bindErr != nil {
+				err = errors.Join(err, bindErr)
+			}
+			args = append(args, groupArgs...)
+		}
 		sb.WriteString(" GROUP BY ")
-		sb.WriteString(strings.Join(groupBys, ", "))
+		sb.WriteString(groupSQL)
+	}
+
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterGroupBy, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
 	}
 
 	var havings []string
@@ -592,73 +1305,151 @@ func (b *SelectBuilder) Build() (string, []interface{}, error) {
 	if len(b.havingRaw) > 0 {
 		havings = append(havings, b.havingRaw...)
 	}
+	if b.havingOr && len(havings) > 1 {
+		havings[0] = "(" + havings[0] + ")"
+	}
+	tr.mark("HAVING", sb.Len())
 	if len(havings) > 0 {
 		sb.WriteString(" HAVING ")
-		havingSQL := strings.Join(havings, " AND ")
-		for strings.Contains(havingSQL, "?") && dialect.Placeholder(0) != "?" {
-			havingSQL = strings.Replace(havingSQL, "?", dialect.Placeholder(placeholderIdx), 1)
-			placeholderIdx++
+		havingSQL, havingArgs, bindErr := bindArgs(dialect, strings.Join(havings, " AND "), b.havingArgs)
+		if bindErr != nil {
+			err = errors.Join(err, bindErr)
 		}
 		sb.WriteString(havingSQL)
-		args = append(args, b.havingArgs...)
-	}
-
-	var orderBys []string
-	if len(b.orderBy) > 0 {
-		for _, o := range b.orderBy {
-			// Handle expressions like 'total_amount DESC'
-			if idx := strings.IndexAny(o, " "); idx > 0 {
-				col := o[:idx]
-				dir := strings.TrimSpace(o[idx+1:])
-				if strings.Contains(col, ".") {
-					parts := strings.Split(col, ".")
-					var quoted string
-					for i, part := range parts {
-						if i > 0 {
-							quoted += "."
-						}
-						quoted += dialect.QuoteIdent(strings.TrimSpace(part))
-					}
-					orderBys = append(orderBys, quoted+" "+dir)
-				} else {
-					orderBys = append(orderBys, dialect.QuoteIdent(col)+" "+dir)
-				}
-			} else if strings.Contains(o, ".") {
-				parts := strings.Split(o, ".")
-				var quoted string
-				for i, part := range parts {
-					if i > 0 {
-						quoted += "."
-					}
-					quoted += dialect.QuoteIdent(strings.TrimSpace(part))
-				}
-				orderBys = append(orderBys, quoted)
-			} else {
-				orderBys = append(orderBys, dialect.QuoteIdent(o))
-			}
-		}
+		args = append(args, havingArgs...)
+	}
+
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterHaving, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
 	}
-	if len(b.orderByRaw) > 0 {
-		orderBys = append(orderBys, b.orderByRaw...)
+
+	orderBys, orderArgs, orderErr := buildOrderBys(dialect, b.orderBy)
+	if orderErr != nil {
+		err = errors.Join(err, orderErr)
 	}
+	tr.mark("ORDER BY", sb.Len())
 	if len(orderBys) > 0 {
+		orderSQL := strings.Join(orderBys, ", ")
+		if len(orderArgs) > 0 {
+			var bindErr error
+			if orderSQL, orderArgs, bindErr = bindArgs(dialect, orderSQL, orderArgs); bindErr != nil {
+				err = errors.Join(err, bindErr)
+			}
+			args = append(args, orderArgs...)
+		}
 		sb.WriteString(" ORDER BY ")
-		sb.WriteString(strings.Join(orderBys, ", "))
+		sb.WriteString(orderSQL)
+	}
+
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterOrderBy, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
+	}
+
+	limitSQL, offsetSQL, limitErr := buildLimit(dialect, b.limitSet, b.limit, b.offsetSet, b.offset, len(orderBys) > 0)
+	if limitErr != nil {
+		return "", nil, errors.Join(err, limitErr)
 	}
+	tr.mark("LIMIT", sb.Len())
+	sb.WriteString(limitSQL)
+	tr.mark("OFFSET", sb.Len())
+	sb.WriteString(offsetSQL)
 
-	if b.limitSet {
-		sb.WriteString(" LIMIT ")
-		sb.WriteString(intToString(b.limit))
+	if args, clauseErr = b.buildClauses(&sb, dialect, AfterLimit, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
 	}
-	if b.offsetSet {
-		sb.WriteString(" OFFSET ")
-		sb.WriteString(intToString(b.offset))
+
+	if b.lockOption != "" {
+		if b.lock == "" {
+			return "", nil, errors.Join(err, errors.New(b.lockOption+" requires ForUpdate or ForShare"))
+		}
+		if b.lock == "LOCK IN SHARE MODE" {
+			return "", nil, errors.Join(err, errors.New(b.lockOption+" cannot be combined with LOCK IN SHARE MODE"))
+		}
+	}
+	tr.mark("LOCK", sb.Len())
+	lock, lockErr := lockClause(dialect, b.unsupported, b.lock, b.lockOption)
+	if lockErr != nil {
+		return "", nil, errors.Join(err, lockErr)
+	}
+	if lock != "" {
+		sb.WriteString(" ")
+		sb.WriteString(lock)
+	}
+
+	if args, clauseErr = b.buildClauses(&sb, dialect, End, args, tr); clauseErr != nil {
+		return "", nil, errors.Join(err, clauseErr)
 	}
 
 	if err != nil {
-		return sb.String(), args, err
+		return "", nil, err
+	}
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, b.reuseArgs)
+	return tr.number(out, sb.String(), numbers), args, nil
+}
+
+// quoteQualifiedIdent quotes each part of a possibly qualified identifier (e.g. "table.column"
+// or "schema.table"), see sqldialect.QuoteQualified.
+func quoteQualifiedIdent(dialect sqldialect.Dialect, ident string) string {
+	return sqldialect.QuoteQualified(dialect, ident)
+}
+
+// buildLimit renders LIMIT and OFFSET in the style of dialect, see sqldialect.LimitStyle,
+// returning the LIMIT part and, for styles that write it separately after LIMIT, the OFFSET
+// part. ordered reports whether the query has an ORDER BY. It also reports combinations the
+// dialect does not support: BigQuery only accepts OFFSET after LIMIT, and SQL Server only
+// accepts OFFSET FETCH after ORDER BY.
+func buildLimit(dialect sqldialect.Dialect, limitSet bool, limit int, offsetSet bool, offset int, ordered bool) (string, string, error) {
+	if !limitSet && !offsetSet {
+		return "", "", nil
+	}
+	dialect = baseDialect(dialect)
+	if offsetSet && !limitSet && dialect == sqldialect.BigQuery() {
+		return "", "", errors.New("Offset: BigQuery requires Limit when Offset is set")
+	}
+
+	switch sqldialect.LimitStyleOf(dialect) {
+	case sqldialect.LimitComma:
+		if !limitSet {
+			return " LIMIT " + intToString(offset) + ", 18446744073709551615", "", nil
+		}
+		if !offsetSet {
+			return " LIMIT " + intToString(limit), "", nil
+		}
+		return " LIMIT " + intToString(offset) + ", " + intToString(limit), "", nil
+	case sqldialect.OffsetFetchNext:
+		if !ordered {
+			return "", "", errors.New("Limit: OFFSET FETCH requires ORDER BY on this dialect")
+		}
+		sql := " OFFSET " + intToString(offset) + " ROWS"
+		if limitSet {
+			sql += " FETCH NEXT " + intToString(limit) + " ROWS ONLY"
+		}
+		return sql, "", nil
+	case sqldialect.FetchFirst:
+		var sql string
+		if offsetSet {
+			sql = " OFFSET " + intToString(offset) + " ROWS"
+		}
+		if limitSet {
+			sql += " FETCH FIRST " + intToString(limit) + " ROWS ONLY"
+		}
+		return sql, "", nil
+	default:
+		var limitSQL, offsetSQL string
+		if limitSet {
+			limitSQL = " LIMIT " + intToString(limit)
+		}
+		if offsetSet {
+			offsetSQL = " OFFSET " + intToString(offset)
+		}
+		return limitSQL, offsetSQL, nil
 	}
-	return sb.String(), args, nil
 }
 
 // intToString is a helper to convert int to string without importing strconv for this small use case.
@@ -695,6 +1486,10 @@ func (b *SelectBuilder) GetColumns() []string {
 			cols = append(cols, string(col.(raw.Raw)))
 		case sqlfunc.SqlFunc:
 			cols = append(cols, string(col.(sqlfunc.SqlFunc)))
+		case *sqlfunc.WindowExpr:
+			if winSQL, err := col.(*sqlfunc.WindowExpr).SQL(sqldialect.NoQuoteIdent()); err == nil {
+				cols = append(cols, winSQL)
+			}
 		case *SelectBuilder:
 			cols = append(cols, col.(*SelectBuilder).GetColumns()...)
 		case AliasExpr:
@@ -722,29 +1517,28 @@ func (b *SelectBuilder) Compose(builders ...*SelectBuilder) *SelectBuilder {
 		b.columns = append(b.columns, other.columns...)
 
 		// Merge joins
-		b.joinClauses = append(b.joinClauses, other.joinClauses...)
+		b.joins = append(b.joins, other.joins...)
 
 		// Merge where conditions
-		if other.whereClause.err != nil {
-			b.whereClause.err = other.whereClause.err
-		} else {
-			b.whereClause.whereParam = append(b.whereClause.whereParam, other.whereClause.whereParam...)
-			b.whereClause.whereRaw = append(b.whereClause.whereRaw, other.whereClause.whereRaw...)
-			b.whereClause.whereArgs = append(b.whereClause.whereArgs, other.whereClause.whereArgs...)
-		}
+		b.whereClause.merge(other.whereClause)
 
 		// Merge group by
 		b.groupBy = append(b.groupBy, other.groupBy...)
 		b.groupByRaw = append(b.groupByRaw, other.groupByRaw...)
+		b.groupByArgs = append(b.groupByArgs, other.groupByArgs...)
 
 		// Merge having
-		b.havingParam = append(b.havingParam, other.havingParam...)
+		havings := other.havingParam
+		if other.havingOr {
+			havings = append([]string{"(" + havings[0] + ")"}, havings[1:]...)
+		}
+		b.havingParam = append(b.havingParam, havings...)
 		b.havingRaw = append(b.havingRaw, other.havingRaw...)
 		b.havingArgs = append(b.havingArgs, other.havingArgs...)
+		b.havingSubqueries = append(b.havingSubqueries, other.havingSubqueries...)
 
 		// Merge order by
 		b.orderBy = append(b.orderBy, other.orderBy...)
-		b.orderByRaw = append(b.orderByRaw, other.orderByRaw...)
 
 		// Use the most restrictive limit/offset
 		if other.limitSet && (!b.limitSet || other.limit < b.limit) {
@@ -756,18 +1550,122 @@ func (b *SelectBuilder) Compose(builders ...*SelectBuilder) *SelectBuilder {
 			b.offset = other.offset
 		}
 
+		// Keep the first keyset pagination
+		if b.seek == nil {
+			b.seek = other.seek
+		}
+
+		// Merge semi- and anti-joins
+		b.filterJoins = append(b.filterJoins, other.filterJoins...)
+
+		// Merge optimizer hints
+		b.hints = append(b.hints, other.hints...)
+
+		// Merge custom clauses
+		b.clauses = append(b.clauses, other.clauses...)
+
+		// Merge traced call sites
+		for clause, sites := range other.callSites {
+			if b.callSites == nil {
+				b.callSites = make(map[string][]string)
+			}
+			b.callSites[clause] = append(b.callSites[clause], sites...)
+		}
+
+		// Use the most restrictive execution time limit
+		if other.maxExecTime > 0 && (b.maxExecTime == 0 || other.maxExecTime < b.maxExecTime) {
+			b.maxExecTime = other.maxExecTime
+		}
+
 		// Preserve distinct if any builder has it
 		if other.distinct {
 			b.distinct = true
 		}
+
+		// Preserve read-only if any builder has it
+		if other.readOnly {
+			b.readOnly = true
+		}
 	}
 
 	return b
 }
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// ComposeNew is like Compose but returns a new builder, leaving b and builders unchanged.
+func (b *SelectBuilder) ComposeNew(builders ...*SelectBuilder) *SelectBuilder {
+	c := b.Clone()
+	for _, other := range builders {
+		c.Compose(other.Clone())
+	}
+	return c
+}
+
+// ComposeStrict is like ComposeNew, but the new builder fails to build if the builders
+// conflict: if they select from different tables, use different dialects, or, among those
+// that select columns, differ in DISTINCT. Builders without a table or dialect, such as
+// condition-only fragments, take those of the others.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id").From("users").ComposeStrict(isActive, isAdult)
+//	sql, args, err := q.Build() // err reports a conflict
+func (b *SelectBuilder) ComposeStrict(builders ...*SelectBuilder) *SelectBuilder {
+	c := b.ComposeNew(builders...)
+	if c.whereClause.err != nil {
+		return c
+	}
+	var selecting *SelectBuilder // the first builder that selects columns
+	if len(b.columns) > 0 {
+		selecting = b
+	}
+	for i, other := range builders {
+		if other == nil {
+			continue
+		}
+		if err := other.tableClauseInterface.err; err != nil {
+			c.whereClause.err = err
+			return c
+		}
+		switch {
+		case other.tableClauseInterface.table != nil && c.tableClauseInterface.table != nil &&
+			(!reflect.DeepEqual(other.tableClauseInterface.table, c.tableClauseInterface.table) || !sameTables(other.fromTables, c.fromTables)):
+			c.whereClause.err = fmt.Errorf("ComposeStrict: builder %d selects from different tables", i+1)
+		case other.dialect != nil && c.dialect != nil && other.dialect != c.dialect:
+			c.whereClause.err = fmt.Errorf("ComposeStrict: builder %d uses a different dialect", i+1)
+		case len(other.columns) > 0 && selecting != nil && other.distinct != selecting.distinct:
+			c.whereClause.err = fmt.Errorf("ComposeStrict: builder %d differs in DISTINCT", i+1)
+		}
+		if c.whereClause.err != nil {
+			return c
+		}
+		if c.tableClauseInterface.table == nil && other.tableClauseInterface.table != nil {
+			c.tableClauseInterface.table = cloneExpr(other.tableClauseInterface.table)
+			c.fromTables = slices.Clone(other.fromTables)
+		}
+		if c.dialect == nil {
+			c.dialect = other.dialect
+		}
+		if selecting == nil && len(other.columns) > 0 {
+			selecting = other
+		}
+	}
+	return c
+}
+
+// sameTables reports whether two lists of additional FROM tables are equal.
+func sameTables(a, b []interface{}) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
+
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *SelectBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the query and writes its SQL to w, returning the args. See BuildTo.
+func (b *SelectBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
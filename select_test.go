@@ -4,11 +4,13 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sprylic/sqltk/raw"
 	"github.com/sprylic/sqltk/sqldialect"
 
 	"github.com/sprylic/sqltk/mysqlfunc"
+	"github.com/sprylic/sqltk/sqlfunc"
 )
 
 func TestSelectBuilder(t *testing.T) {
@@ -155,7 +157,7 @@ func TestSelectBuilder_RawWhere(t *testing.T) {
 		q := Select("id").From("users").Where(NewCond().Equal("active", true).
 			And(NewCond().Raw("age > 18")))
 		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
-		wantSQL := "SELECT id FROM users WHERE active = ? AND age > 18"
+		wantSQL := "SELECT id FROM users WHERE active = ? AND (age > 18)"
 		wantArgs := []interface{}{true}
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -737,6 +739,89 @@ func TestSelectBuilder_Compose(t *testing.T) {
 	})
 }
 
+func TestSelectBuilder_ComposeNewAndStrict(t *testing.T) {
+	isActive := Select().WhereEqual("active", true)
+
+	t.Run("compose new leaves builders unchanged", func(t *testing.T) {
+		q1 := Select("id").From("users")
+		q2 := Select("name").From("users").WhereEqual("verified", true)
+
+		q := q1.ComposeNew(q2, isActive)
+		q.WhereEqual("admin", false)
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "SELECT id, name FROM users WHERE verified = ? AND active = ? AND admin = ?"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if want := []interface{}{true, true, false}; !reflect.DeepEqual(args, want) {
+			t.Errorf("got args %v, want %v", args, want)
+		}
+		if sql, _, _ := q1.WithDialect(sqldialect.NoQuoteIdent()).Build(); sql != "SELECT id FROM users" {
+			t.Errorf("receiver changed: %q", sql)
+		}
+		if sql, _, _ := q2.WithDialect(sqldialect.NoQuoteIdent()).Build(); sql != "SELECT name FROM users WHERE verified = ?" {
+			t.Errorf("argument changed: %q", sql)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "fragments",
+			builder: Select("id").From("users").WithDialect(sqldialect.NoQuoteIdent()).ComposeStrict(isActive, Select("name").From("users")),
+			wantSQL: "SELECT id, name FROM users WHERE active = ?",
+		},
+		{
+			name:    "table and dialect taken from others",
+			builder: Select("id").ComposeStrict(Select().From("users").WithDialect(sqldialect.NoQuoteIdent())),
+			wantSQL: "SELECT id FROM users",
+		},
+		{
+			name:    "different tables",
+			builder: Select("id").From("users").ComposeStrict(Select("name").From("posts")),
+			wantErr: true,
+		},
+		{
+			name:    "different extra tables",
+			builder: Select("id").From("users", "roles").ComposeStrict(Select("name").From("users")),
+			wantErr: true,
+		},
+		{
+			name:    "different dialects",
+			builder: Select("id").WithDialect(sqldialect.MySQL()).ComposeStrict(isActive.Clone().WithDialect(sqldialect.Postgres())),
+			wantErr: true,
+		},
+		{
+			name:    "different distinct",
+			builder: Select("id").From("users").ComposeStrict(Select("name").From("users").Distinct()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}
+
 func TestSelectBuilder_Dialect(t *testing.T) {
 	t.Run("no quote ident dialect", func(t *testing.T) {
 		q := Select("id", "name").From("users").Where(NewCond().Equal("id", 1).
@@ -828,6 +913,182 @@ func TestSelectBuilder_FluentJoinTypes(t *testing.T) {
 	})
 }
 
+func TestSelectBuilder_JoinConditions(t *testing.T) {
+	t.Run("multi-column join with constant filter", func(t *testing.T) {
+		q := Select("u.id").From("users u").
+			Join("orders o").OnEqual("o.user_id", "u.id").
+			And(NewStringCondition("o.status = ?", "paid")).End().
+			WhereEqual("u.active", true)
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT u.id FROM users u JOIN orders o ON o.user_id = u.id AND o.status = ? WHERE u.active = ?"
+		wantArgs := []interface{}{"paid", true}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("on cond with condition builder", func(t *testing.T) {
+		q := Select("u.id").From("users u").
+			LeftJoin("orders o").OnCond(NewCond().Raw("o.user_id = u.id").Equal("o.region", "eu"))
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT u.id FROM users u LEFT JOIN orders o ON (o.user_id = u.id) AND o.region = ?"
+		wantArgs := []interface{}{"eu"}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("or on join", func(t *testing.T) {
+		q := Select("a.id").From("a").
+			Join("b").OnEqual("b.a_id", "a.id").Or(NewStringCondition("b.shared = ?", true)).End()
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT a.id FROM a JOIN b ON (b.a_id = a.id) OR (b.shared = ?)"
+		wantArgs := []interface{}{true}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("postgres placeholders number join before where", func(t *testing.T) {
+		q := Select("users.id").From("users").WithDialect(sqldialect.Postgres()).
+			Where(NewStringCondition("users.active = ?", true)).
+			Join("orders").OnEqual("orders.user_id", "users.id").And(NewStringCondition("orders.total > ?", 100)).End()
+		sql, args, err := q.Build()
+		wantSQL := `SELECT "users"."id" FROM "users" JOIN "orders" ON orders.user_id = users.id AND orders.total > $1 WHERE users.active = $2`
+		wantArgs := []interface{}{100, true}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("missing on condition", func(t *testing.T) {
+		q := Select("u.id").From("users u").Join("orders o").End()
+		if _, _, err := q.Build(); err == nil {
+			t.Error("expected error for join without ON condition")
+		}
+	})
+
+	t.Run("nil on condition", func(t *testing.T) {
+		q := Select("u.id").From("users u").Join("orders o").OnCond(nil)
+		if _, _, err := q.Build(); err == nil {
+			t.Error("expected error for nil ON condition")
+		}
+	})
+}
+
+func TestSelectBuilder_CrossJoinAndUsing(t *testing.T) {
+	t.Run("cross join", func(t *testing.T) {
+		q := Select("u.id", "r.code").From("users u").CrossJoin("regions r")
+		sql, _, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT u.id, r.code FROM users u CROSS JOIN regions r"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("cross join subquery keeps args", func(t *testing.T) {
+		sub := Select("code").From("regions").WhereEqual("active", true)
+		q := Select("u.id").From("users u").CrossJoin(Alias(sub, "r")).WhereEqual("u.id", 7)
+		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT u.id FROM users u CROSS JOIN (SELECT code FROM regions WHERE active = ?) AS r WHERE u.id = ?"
+		wantArgs := []interface{}{true, 7}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("join using", func(t *testing.T) {
+		q := Select("id").From("users").WithDialect(sqldialect.MySQL()).
+			Join("accounts").Using("tenant_id", "user_id")
+		sql, _, err := q.Build()
+		wantSQL := "SELECT `id` FROM `users` JOIN `accounts` USING (`tenant_id`, `user_id`)"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("left join using", func(t *testing.T) {
+		q := Select("id").From("users").LeftJoin("profiles").Using("user_id")
+		sql, _, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT id FROM users LEFT JOIN profiles USING (user_id)"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("dialect set after join", func(t *testing.T) {
+		q := Select("u.id").From("users u").
+			Join("accounts a").OnEqual("a.user_id", "u.id").And(NewStringCondition("a.kind = ?", "paid")).End().
+			LeftJoin("profiles").Using("user_id").
+			WhereEqual("u.active", true).
+			WithDialect(sqldialect.Postgres())
+		sql, args, err := q.Build()
+		wantSQL := `SELECT "u"."id" FROM "users u" JOIN "accounts a" ON a.user_id = u.id AND a.kind = $1 LEFT JOIN "profiles" USING ("user_id") WHERE u.active = $2`
+		wantArgs := []interface{}{"paid", true}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("got args %v, want %v", args, wantArgs)
+		}
+	})
+
+	t.Run("using without columns", func(t *testing.T) {
+		if _, _, err := Select("id").From("users").Join("accounts").Using().Build(); err == nil {
+			t.Error("expected error for USING without columns")
+		}
+	})
+
+	t.Run("using combined with on", func(t *testing.T) {
+		q := Select("id").From("users").Join("accounts").OnEqual("a", "b").Using("c")
+		if _, _, err := q.Build(); err == nil {
+			t.Error("expected error for USING combined with ON")
+		}
+	})
+}
+
 func TestSelectBuilder_GetColumns(t *testing.T) {
 	t.Run("basic string columns", func(t *testing.T) {
 		q := Select("id", "name", "email").From("users")
@@ -901,7 +1162,7 @@ func TestSelectBuilder_ComplexQueries(t *testing.T) {
 
 		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
 		wantSQL := "SELECT u.id, u.name, p.title FROM users u JOIN (SELECT id, name FROM users WHERE id IN (" +
-			"(SELECT user_id FROM posts WHERE created_at > ?))" +
+			"SELECT user_id FROM posts WHERE created_at > ?)" +
 			") AS active_users ON active_users.id = u.id LEFT JOIN posts p ON p.user_id = u.id"
 		wantArgs := []interface{}{"2023-01-01"}
 		if err != nil {
@@ -986,7 +1247,7 @@ func TestSelectBuilder_ComplexQueries(t *testing.T) {
 
 		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
 		wantSQL := "SELECT u.id, u.name FROM users u WHERE u.active = ? AND EXISTS (SELECT 1 FROM orders o " +
-			"WHERE o.user_id = u.id AND o.amount > ? AND o.created_at > DATE_SUB(NOW(), INTERVAL 30 DAY))"
+			"WHERE o.user_id = u.id AND o.amount > ? AND (o.created_at > DATE_SUB(NOW(), INTERVAL 30 DAY)))"
 		wantArgs := []interface{}{true, 1000}
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -1093,8 +1354,8 @@ func TestSelectBuilder_ComplexQueries(t *testing.T) {
 			Where(NewCond().In("id", sub))
 
 		sql, args, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
-		wantSQL := "SELECT id, name, email FROM users WHERE active = ? AND id IN ((SELECT user_id FROM orders " +
-			"WHERE amount > ? AND status = ? GROUP BY user_id HAVING COUNT(*) > ?))"
+		wantSQL := "SELECT id, name, email FROM users WHERE active = ? AND id IN (SELECT user_id FROM orders " +
+			"WHERE amount > ? AND status = ? GROUP BY user_id HAVING COUNT(*) > ?)"
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -1306,3 +1567,735 @@ func TestSelectBuilder_ConvenienceWhereMethods(t *testing.T) {
 		}
 	})
 }
+
+func TestSelectWindowFunctions(t *testing.T) {
+	t.Run("aliased row number", func(t *testing.T) {
+		q := Select("id", Alias(sqlfunc.RowNumber().Over().PartitionBy("user_id").OrderBy("created_at DESC"), "rn")).From("orders")
+		sql, args, err := q.WithDialect(sqldialect.MySQL()).Build()
+		wantSQL := "SELECT `id`, ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC) AS `rn` FROM `orders`"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+		if len(args) != 0 {
+			t.Errorf("got args %v, want none", args)
+		}
+	})
+
+	t.Run("unaliased aggregate window", func(t *testing.T) {
+		q := Select(mysqlfunc.Sum("amount").Over().PartitionBy("user_id")).From("orders")
+		sql, _, err := q.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		wantSQL := "SELECT SUM(amount) OVER (PARTITION BY user_id) FROM orders"
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sql != wantSQL {
+			t.Errorf("got SQL %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("invalid frame is reported", func(t *testing.T) {
+		q := Select(Alias(sqlfunc.RowNumber().Over().Frame("/* x */"), "rn")).From("orders")
+		if _, _, err := q.Build(); err == nil {
+			t.Error("expected error for unsafe frame")
+		}
+	})
+}
+
+func TestSelectLocking(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		dialect sqldialect.Dialect
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "for update",
+			builder: Select("id").From("jobs").WhereEqual("status", "queued").Limit(1).ForUpdate(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `id` FROM `jobs` WHERE status = ? LIMIT 1 FOR UPDATE",
+		},
+		{
+			name:    "for update skip locked",
+			builder: Select("id").From("jobs").WhereEqual("status", "queued").Limit(10).ForUpdate().SkipLocked(),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "jobs" WHERE status = $1 LIMIT 10 FOR UPDATE SKIP LOCKED`,
+		},
+		{
+			name:    "for share nowait",
+			builder: Select("id").From("accounts").ForShare().NoWait(),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantSQL: "SELECT id FROM accounts FOR SHARE NOWAIT",
+		},
+		{
+			name:    "legacy mysql share mode",
+			builder: Select("id").From("accounts").LockInShareMode(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `id` FROM `accounts` LOCK IN SHARE MODE",
+		},
+		{
+			name:    "nowait without lock",
+			builder: Select("id").From("accounts").NoWait(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "skip locked with legacy share mode",
+			builder: Select("id").From("accounts").LockInShareMode().SkipLocked(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "mysql for share skip locked",
+			builder: Select("id").From("accounts").ForShare().SkipLocked(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `id` FROM `accounts` FOR SHARE SKIP LOCKED",
+		},
+		{
+			name:    "postgres for share nowait",
+			builder: Select("id").From("accounts").ForShare().NoWait(),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "accounts" FOR SHARE NOWAIT`,
+		},
+		{
+			name:    "postgres legacy share mode",
+			builder: Select("id").From("accounts").LockInShareMode(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "postgres legacy share mode strip",
+			builder: Select("id").From("accounts").LockInShareMode().OnUnsupported(UnsupportedStrip),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "accounts" FOR SHARE`,
+		},
+		{
+			name:    "oracle for update nowait",
+			builder: Select("id").From("accounts").ForUpdate().NoWait(),
+			dialect: sqldialect.Oracle(),
+			wantSQL: `SELECT "id" FROM "accounts" FOR UPDATE NOWAIT`,
+		},
+		{
+			name:    "oracle for update skip locked",
+			builder: Select("id").From("accounts").ForUpdate().SkipLocked(),
+			dialect: sqldialect.Oracle(),
+			wantSQL: `SELECT "id" FROM "accounts" FOR UPDATE SKIP LOCKED`,
+		},
+		{
+			name:    "oracle for share",
+			builder: Select("id").From("accounts").ForShare(),
+			dialect: sqldialect.Oracle(),
+			wantErr: true,
+		},
+		{
+			name:    "oracle for share nowait",
+			builder: Select("id").From("accounts").ForShare().NoWait(),
+			dialect: sqldialect.Oracle(),
+			wantErr: true,
+		},
+		{
+			name:    "oracle legacy share mode",
+			builder: Select("id").From("accounts").LockInShareMode(),
+			dialect: sqldialect.Oracle(),
+			wantErr: true,
+		},
+		{
+			name:    "oracle for share strip",
+			builder: Select("id").From("accounts").ForShare().SkipLocked().OnUnsupported(UnsupportedStrip),
+			dialect: sqldialect.Oracle(),
+			wantSQL: `SELECT "id" FROM "accounts"`,
+		},
+		{
+			name:    "duckdb for update skip locked",
+			builder: Select("id").From("accounts").ForUpdate().SkipLocked(),
+			dialect: sqldialect.DuckDB(),
+			wantErr: true,
+		},
+		{
+			name:    "bigquery for share nowait",
+			builder: Select("id").From("accounts").ForShare().NoWait(),
+			dialect: sqldialect.BigQuery(),
+			wantErr: true,
+		},
+		{
+			name:    "clickhouse legacy share mode",
+			builder: Select("id").From("accounts").LockInShareMode(),
+			dialect: sqldialect.ClickHouse(),
+			wantErr: true,
+		},
+	}
+
+	saved := warningHandler
+	SetWarningHandler(nil)
+	defer SetWarningHandler(saved)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestSelectMaxExecutionTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		dialect sqldialect.Dialect
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "mysql hint",
+			builder: Select("id").From("users").MaxExecutionTime(2 * time.Second),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT /*+ MAX_EXECUTION_TIME(2000) */ `id` FROM `users`",
+		},
+		{
+			name:    "mysql hint before distinct, rounded up",
+			builder: Select("name").Distinct().From("users").MaxExecutionTime(1500 * time.Microsecond),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT /*+ MAX_EXECUTION_TIME(2) */ DISTINCT `name` FROM `users`",
+		},
+		{
+			name:    "postgres leaves it to the runner",
+			builder: Select("id").From("users").MaxExecutionTime(time.Second),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "users"`,
+		},
+		{
+			name:    "negative duration",
+			builder: Select("id").From("users").MaxExecutionTime(-time.Second),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestSelectOrderByStructured(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		dialect sqldialect.Dialect
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "asc and desc",
+			builder: Select("id").From("users").OrderByDesc("u.created_at").OrderByAsc("id"),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "users" ORDER BY "u"."created_at" DESC, "id" ASC`,
+		},
+		{
+			name:    "nulls last",
+			builder: Select("id").From("users").OrderByExpr("last_login", Desc, NullsLast),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `SELECT "id" FROM "users" ORDER BY "last_login" DESC NULLS LAST`,
+		},
+		{
+			name:    "nulls first emulated on mysql",
+			builder: Select("id").From("users").OrderByExpr("last_login", Asc, NullsFirst),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `id` FROM `users` ORDER BY `last_login` IS NULL DESC, `last_login` ASC",
+		},
+		{
+			name:    "function expression",
+			builder: Select("team").From("scores").GroupBy("team").OrderByExpr(sqlfunc.SqlFunc("COUNT(*)"), Desc, NullsDefault),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `team` FROM `scores` GROUP BY `team` ORDER BY COUNT(*) DESC",
+		},
+		{
+			name:    "string expression is not quoted",
+			builder: Select("team").From("scores").GroupBy("team").OrderBy("COUNT(*) DESC"),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `team` FROM `scores` GROUP BY `team` ORDER BY COUNT(*) DESC",
+		},
+		{
+			name:    "raw keeps its position",
+			builder: Select("id").From("users").OrderBy(raw.Raw("FIELD(status, 'a', 'b')")).OrderByAsc("id"),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "SELECT `id` FROM `users` ORDER BY FIELD(status, 'a', 'b'), `id` ASC",
+		},
+		{
+			name:    "expression passed as column",
+			builder: Select("id").From("users").OrderByExpr("COUNT(*)", Desc, NullsDefault),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestSelectAnalyticsDialects(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "duckdb",
+			builder:  Select("id", "name").From("events").WhereEqual("kind", "click").WhereEqual("day", 3).Limit(10).Offset(5),
+			dialect:  sqldialect.DuckDB(),
+			wantSQL:  `SELECT "id", "name" FROM "events" WHERE kind = $1 AND day = $2 LIMIT 10 OFFSET 5`,
+			wantArgs: []interface{}{"click", 3},
+		},
+		{
+			name:     "bigquery",
+			builder:  Select("e.id").From("events").WhereEqual("kind", "click").OrderByExpr("e.ts", Desc, NullsLast).Limit(10).Offset(5),
+			dialect:  sqldialect.BigQuery(),
+			wantSQL:  "SELECT `e`.`id` FROM `events` WHERE kind = ? ORDER BY `e`.`ts` DESC NULLS LAST LIMIT 10 OFFSET 5",
+			wantArgs: []interface{}{"click"},
+		},
+		{
+			name:    "bigquery offset without limit",
+			builder: Select("id").From("events").Offset(5),
+			dialect: sqldialect.BigQuery(),
+			wantErr: true,
+		},
+		{
+			name:    "duckdb locking",
+			builder: Select("id").From("events").ForUpdate(),
+			dialect: sqldialect.DuckDB(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("bigquery string escaping", func(t *testing.T) {
+		if got, want := sqldialect.BigQuery().QuoteString(`it's a \ test`), `'it\'s a \\ test'`; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
+// limitStyleDialect is a dialect that limits rows in the given style.
+type limitStyleDialect struct {
+	sqldialect.Dialect
+	style sqldialect.LimitStyle
+}
+
+func (d limitStyleDialect) LimitStyle() sqldialect.LimitStyle { return d.style }
+
+func TestSelectLimitStyles(t *testing.T) {
+	sqlServer := limitStyleDialect{sqldialect.NoQuoteIdent(), sqldialect.OffsetFetchNext}
+	oracle := limitStyleDialect{sqldialect.NoQuoteIdent(), sqldialect.FetchFirst}
+
+	tests := []struct {
+		name    string
+		builder Builder
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "mysql limit and offset",
+			builder: Select("id").From("users").OrderBy("id").Limit(10).Offset(20).WithDialect(sqldialect.MySQL()),
+			wantSQL: "SELECT `id` FROM `users` ORDER BY `id` LIMIT 20, 10",
+		},
+		{
+			name:    "mysql limit",
+			builder: Select("id").From("users").Limit(10).WithDialect(sqldialect.MySQL()),
+			wantSQL: "SELECT `id` FROM `users` LIMIT 10",
+		},
+		{
+			name:    "mysql offset",
+			builder: Select("id").From("users").Offset(20).WithDialect(sqldialect.MySQL()),
+			wantSQL: "SELECT `id` FROM `users` LIMIT 20, 18446744073709551615",
+		},
+		{
+			name:    "sql server",
+			builder: Select("id").From("users").OrderBy("id").Limit(10).Offset(20).WithDialect(sqlServer),
+			wantSQL: "SELECT id FROM users ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+		{
+			name:    "sql server limit",
+			builder: Select("id").From("users").OrderBy("id").Limit(10).WithDialect(sqlServer),
+			wantSQL: "SELECT id FROM users ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+		},
+		{
+			name:    "sql server without order by",
+			builder: Select("id").From("users").Limit(10).WithDialect(sqlServer),
+			wantErr: true,
+		},
+		{
+			name:    "oracle limit",
+			builder: Select("id").From("users").Limit(10).WithDialect(oracle),
+			wantSQL: "SELECT id FROM users FETCH FIRST 10 ROWS ONLY",
+		},
+		{
+			name:    "oracle offset",
+			builder: Select("id").From("users").OrderBy("id").Offset(20).WithDialect(oracle),
+			wantSQL: "SELECT id FROM users ORDER BY id OFFSET 20 ROWS",
+		},
+		{
+			name: "union",
+			builder: Select("id").From("a").Union(Select("id").From("b")).
+				OrderBy("id").Limit(5).Offset(5).WithDialect(oracle),
+			wantSQL: "SELECT id FROM a UNION SELECT id FROM b ORDER BY id OFFSET 5 ROWS FETCH FIRST 5 ROWS ONLY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestSelectOracle(t *testing.T) {
+	oracle := sqldialect.Oracle()
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "placeholders, quoting, and fetch first",
+			builder:  Select("id", "name").From("users").WhereEqual("status", "active").WhereEqual("role", "admin").OrderBy("id").Limit(10).Offset(20).WithDialect(oracle),
+			wantSQL:  `SELECT "id", "name" FROM "users" WHERE status = :1 AND role = :2 ORDER BY "id" OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY`,
+			wantArgs: []interface{}{"active", "admin"},
+		},
+		{
+			name:     "tableless",
+			builder:  Select(raw.Raw("SYSDATE"), Expr("? + 1", 41)).WithDialect(oracle),
+			wantSQL:  `SELECT SYSDATE, :1 + 1 FROM DUAL`,
+			wantArgs: []interface{}{41},
+		},
+		{
+			name:     "exists",
+			builder:  Select("id").From("users").WhereEqual("id", 7).ToExists().WithDialect(oracle),
+			wantSQL:  `SELECT CASE WHEN EXISTS(SELECT "id" FROM "users" WHERE id = :1) THEN 1 ELSE 0 END FROM DUAL`,
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "count of a distinct query",
+			builder:  Select("id").From("users").Distinct().ToCount().WithDialect(oracle),
+			wantSQL:  `SELECT COUNT(*) FROM (SELECT DISTINCT "id" FROM "users") t`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "table aliases",
+			builder: Select("u.id").From(Alias("users", "u")).
+				Join(Alias(Select("user_id").From("orders"), "o")).On("o.user_id", "u.id").
+				Where(NewCond().NullSafeNotIn().NotInQuery("u.id", Select("user_id").From("bans"))).
+				WithDialect(oracle),
+			wantSQL:  `SELECT "u"."id" FROM "users" u JOIN (SELECT "user_id" FROM "orders") o ON o.user_id = u.id WHERE NOT EXISTS (SELECT 1 FROM (SELECT "user_id" FROM "bans") "not_in" WHERE "not_in"."user_id" = "u"."id")`,
+			wantArgs: []interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, _, err := Select("id").WithDialect(sqldialect.Postgres()).Build(); err == nil {
+		t.Error("expected error for a SELECT without a table on Postgres")
+	}
+}
+
+func TestSelectClickHouse(t *testing.T) {
+	ch := sqldialect.ClickHouse()
+	sql, args, err := Select("e.id", "odd`name").From("events").
+		Where(NewStringCondition("kind = "+ch.QuoteString(`it's \`))).WhereEqual("user_id", 7).
+		Limit(10).Offset(20).WithDialect(ch).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantSQL := "SELECT `e`.`id`, `odd\\`name` FROM `events` WHERE kind = 'it\\'s \\\\' AND user_id = ? LIMIT 10 OFFSET 20"
+	if sql != wantSQL {
+		t.Errorf("got SQL %q, want %q", sql, wantSQL)
+	}
+	if !reflect.DeepEqual(args, []interface{}{7}) {
+		t.Errorf("got args %v, want [7]", args)
+	}
+
+	if _, _, err := Select("id").From("events").ForUpdate().WithDialect(ch).Build(); err == nil {
+		t.Error("expected error for row locking on ClickHouse")
+	}
+}
+
+func TestSelectArgsOrder(t *testing.T) {
+	paid := func() *SelectBuilder {
+		return Select("user_id").From("orders").WhereEqual("status", "paid")
+	}
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "join subquery and where",
+			builder: Select("u.id").From("users u").
+				WhereEqual("u.active", true).
+				Join(Alias(paid(), "o")).OnCond(NewStringCondition("o.user_id = u.id AND o.kind = ?", "web")),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `u`.`id` FROM `users u` JOIN (SELECT `user_id` FROM `orders` WHERE status = ?) AS o ON o.user_id = u.id AND o.kind = ? WHERE u.active = ?",
+			wantArgs: []interface{}{"paid", "web", true},
+		},
+		{
+			name: "numbered across subqueries",
+			builder: Select("u.id", Alias(Select(raw.Raw("COUNT(*)")).From("logins").WhereEqual("ok", false), "failed")).
+				From(Alias(Select("id").From("users").WhereEqual("tenant_id", 7), "u")).
+				Join(Alias(paid(), "o")).On("o.user_id", "u.id").
+				WhereGreaterThan("u.id", 100),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "u"."id", (SELECT COUNT(*) FROM "logins" WHERE ok = $1) AS "failed" FROM (SELECT "id" FROM "users" WHERE tenant_id = $2) AS u JOIN (SELECT "user_id" FROM "orders" WHERE status = $3) AS o ON o.user_id = u.id WHERE u.id > $4`,
+			wantArgs: []interface{}{false, 7, "paid", 100},
+		},
+		{
+			name: "composed joins",
+			builder: Select("u.id").From("users u").WhereEqual("u.active", true).
+				Compose(Select().Join(Alias(paid(), "o")).On("o.user_id", "u.id")),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "u"."id" FROM "users u" JOIN (SELECT "user_id" FROM "orders" WHERE status = $1) AS o ON o.user_id = u.id WHERE u.active = $2`,
+			wantArgs: []interface{}{"paid", true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectFromTables(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "tables in From",
+			builder: Select("u.name", "o.total").From("users u", "orders o").
+				Where(NewStringCondition("o.user_id = u.id")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT u.name, o.total FROM users u, orders o WHERE o.user_id = u.id",
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "AddFrom with subquery and join",
+			builder: Select("u.id").From("users").
+				AddFrom(Alias(Select("user_id").From("orders").WhereEqual("status", "paid"), "o")).
+				Join("payments p").On("p.order_id", "o.id").
+				Where(NewStringCondition("o.user_id = users.id AND users.tenant_id = ?", 7)),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "u"."id" FROM "users", (SELECT "user_id" FROM "orders" WHERE status = $1) AS o JOIN "payments p" ON p.order_id = o.id WHERE o.user_id = users.id AND users.tenant_id = $2`,
+			wantArgs: []interface{}{"paid", 7},
+		},
+		{
+			name:     "AddFrom without From",
+			builder:  Select("id").AddFrom("users").AddFrom(raw.Raw("generate_series(1, 3) AS n")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users, generate_series(1, 3) AS n",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "duplicate alias",
+			builder: Select("a.id").From("users a", "accounts a"),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+		{
+			name:    "bad table",
+			builder: Select("id").From("users").AddFrom(42),
+			dialect: sqldialect.NoQuoteIdent(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("clone", func(t *testing.T) {
+		base := Select("id").From("users u", "orders o")
+		c := base.Clone().AddFrom("payments p")
+		sql, _, err := base.WithDialect(sqldialect.NoQuoteIdent()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "SELECT id FROM users u, orders o"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if len(c.fromTables) != 2 {
+			t.Errorf("got %d further tables in clone, want 2", len(c.fromTables))
+		}
+	})
+}
+
+func TestSelectBuilder_AllErrors(t *testing.T) {
+	q := Select("id", 42).From("users").
+		Join(123).On("a", "b").
+		Where(nil).
+		Having(nil).
+		GroupBy(3.5)
+	sql, args, err := q.Build()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if sql != "" || args != nil {
+		t.Errorf("got SQL %q and args %v with the error, want none", sql, args)
+	}
+	for _, want := range []string{
+		"Select: column must be",
+		"join: table must be",
+		"Where: condition must not be nil",
+		"Having: condition must not be nil",
+		"GroupBy: expr must be",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not report %q", err, want)
+		}
+	}
+
+	t.Run("must build", func(t *testing.T) {
+		sql, args := Select("id").From("users").WhereEqual("id", 1).WithDialect(sqldialect.NoQuoteIdent()).MustBuild()
+		if want := "SELECT id FROM users WHERE id = ?"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if want := []interface{}{1}; !reflect.DeepEqual(args, want) {
+			t.Errorf("got args %v, want %v", args, want)
+		}
+	})
+
+	t.Run("must build panics", func(t *testing.T) {
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok {
+				t.Fatalf("got panic %v, want an error", r)
+			}
+			if want := "sqltk: MustBuild: SELECT from users: Where: condition must not be nil"; err.Error() != want {
+				t.Errorf("got panic %q, want %q", err, want)
+			}
+		}()
+		Select("id").From("users").Where(nil).MustBuild()
+	})
+}
@@ -0,0 +1,138 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// JoinKey pairs a column of the main query with a column of a semi- or anti-joined table.
+type JoinKey struct {
+	Left  string // column of the main query, e.g. "u.id"
+	Right string // column of the joined table, e.g. "o.user_id"
+}
+
+// filterJoin is a semi- or anti-join added with SemiJoin or AntiJoin. It only filters rows of
+// the main query and is rendered per dialect at Build time.
+type filterJoin struct {
+	anti  bool
+	table interface{} // string, or AliasExpr with a string table
+	keys  []JoinKey
+}
+
+// WhereNotInSubquery adds a WHERE clause for column NOT IN (subquery).
+// NOT IN matches no rows at all if the subquery returns a NULL; prefer AntiJoin unless the
+// subquery column is NOT NULL.
+func (b *SelectBuilder) WhereNotInSubquery(column string, subquery *SelectBuilder) *SelectBuilder {
+	cond := NewCond()
+	if b.dialect != nil {
+		cond.WithDialect(b.dialect)
+	}
+	return b.Where(cond.RowNotIn([]string{column}, subquery))
+}
+
+// SemiJoin keeps only rows of the query that have at least one matching row in table, without
+// duplicating rows the way a JOIN would. table is a table name or sqltk.Alias("table", "alias").
+// It renders as EXISTS (SELECT 1 ...), or on MySQL with a single key as an IN subquery, which
+// older MySQL versions optimize better.
+//
+// Example usage:
+//
+//	Select("u.id").From(sqltk.Alias("users", "u")).SemiJoin(sqltk.Alias("orders", "o"), sqltk.JoinKey{Left: "u.id", Right: "o.user_id"})
+func (b *SelectBuilder) SemiJoin(table interface{}, keys ...JoinKey) *SelectBuilder {
+	return b.addFilterJoin(false, table, keys)
+}
+
+// AntiJoin keeps only rows of the query that have no matching row in table.
+// table is a table name or sqltk.Alias("table", "alias"). It renders as NOT EXISTS (SELECT 1 ...),
+// or on MySQL as LEFT JOIN ... WHERE <first right key> IS NULL.
+func (b *SelectBuilder) AntiJoin(table interface{}, keys ...JoinKey) *SelectBuilder {
+	return b.addFilterJoin(true, table, keys)
+}
+
+func (b *SelectBuilder) addFilterJoin(anti bool, table interface{}, keys []JoinKey) *SelectBuilder {
+	if b.whereClause.err != nil {
+		return b
+	}
+	name := "SemiJoin"
+	if anti {
+		name = "AntiJoin"
+	}
+	switch t := table.(type) {
+	case string:
+		if t == "" {
+			b.whereClause.err = fmt.Errorf("%s: table must be set", name)
+			return b
+		}
+	case Identifier:
+		if err := t.validate(); err != nil {
+			b.whereClause.err = fmt.Errorf("%s: %w", name, err)
+			return b
+		}
+	case AliasExpr:
+		if !isTableName(t) || t.Expr == "" || t.Alias == "" {
+			b.whereClause.err = fmt.Errorf("%s: alias must name a table", name)
+			return b
+		}
+	default:
+		b.whereClause.err = fmt.Errorf("%s: table must be string or sq.AliasExpr (got %T)", name, table)
+		return b
+	}
+	if len(keys) == 0 {
+		b.whereClause.err = fmt.Errorf("%s: at least one key is required", name)
+		return b
+	}
+	for _, k := range keys {
+		if k.Left == "" || k.Right == "" {
+			b.whereClause.err = errors.New(name + ": keys must name both columns")
+			return b
+		}
+	}
+	b.filterJoins = append(b.filterJoins, filterJoin{anti: anti, table: table, keys: append([]JoinKey{}, keys...)})
+	// Depending on the dialect, a filter join renders as a WHERE condition or a LEFT JOIN.
+	b.whereClause.recordCall()
+	b.recordCall("JOIN")
+	return b
+}
+
+// usesLeftJoin reports whether the filter join is rendered as LEFT JOIN ... IS NULL.
+func (f filterJoin) usesLeftJoin(dialect sqldialect.Dialect) bool {
+	return f.anti && baseDialect(dialect) == sqldialect.MySQL()
+}
+
+func (f filterJoin) tableSQL(dialect sqldialect.Dialect) string {
+	if a, ok := f.table.(AliasExpr); ok {
+		return quoteTable(dialect, a.Expr) + asTableAlias(dialect, a.Alias)
+	}
+	return quoteTable(dialect, f.table)
+}
+
+func (f filterJoin) keysSQL(dialect sqldialect.Dialect) string {
+	parts := make([]string, len(f.keys))
+	for i, k := range f.keys {
+		parts[i] = quoteQualifiedIdent(dialect, k.Right) + " = " + quoteQualifiedIdent(dialect, k.Left)
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// joinSQL renders the LEFT JOIN of an anti-join that uses one.
+func (f filterJoin) joinSQL(dialect sqldialect.Dialect) string {
+	return "LEFT JOIN " + f.tableSQL(dialect) + " ON " + f.keysSQL(dialect)
+}
+
+// condition renders the WHERE condition of the filter join.
+func (f filterJoin) condition(dialect sqldialect.Dialect) string {
+	switch {
+	case f.usesLeftJoin(dialect):
+		return quoteQualifiedIdent(dialect, f.keys[0].Right) + " IS NULL"
+	case f.anti:
+		return "NOT EXISTS (SELECT 1 FROM " + f.tableSQL(dialect) + " WHERE " + f.keysSQL(dialect) + ")"
+	case len(f.keys) == 1 && baseDialect(dialect) == sqldialect.MySQL():
+		return quoteQualifiedIdent(dialect, f.keys[0].Left) + " IN (SELECT " + quoteQualifiedIdent(dialect, f.keys[0].Right) +
+			" FROM " + f.tableSQL(dialect) + ")"
+	default:
+		return "EXISTS (SELECT 1 FROM " + f.tableSQL(dialect) + " WHERE " + f.keysSQL(dialect) + ")"
+	}
+}
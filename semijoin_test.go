@@ -0,0 +1,94 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectSemiAndAntiJoins(t *testing.T) {
+	users := func() *SelectBuilder {
+		return Select("u.id").From(Alias("users", "u")).WhereEqual("u.active", true)
+	}
+	byUser := JoinKey{Left: "u.id", Right: "o.user_id"}
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "anti join on postgres uses not exists",
+			builder:  users().AntiJoin(Alias("orders", "o"), byUser),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "u"."id" FROM "users" AS u WHERE u.active = $1 AND NOT EXISTS (SELECT 1 FROM "orders" AS o WHERE "o"."user_id" = "u"."id")`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "anti join on mysql uses left join",
+			builder:  users().AntiJoin(Alias("orders", "o"), byUser, JoinKey{Left: "u.tenant_id", Right: "o.tenant_id"}),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `u`.`id` FROM `users` AS u LEFT JOIN `orders` AS o ON `o`.`user_id` = `u`.`id` AND `o`.`tenant_id` = `u`.`tenant_id` WHERE u.active = ? AND `o`.`user_id` IS NULL",
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "semi join on postgres uses exists",
+			builder:  users().SemiJoin(Alias("orders", "o"), byUser),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT "u"."id" FROM "users" AS u WHERE u.active = $1 AND EXISTS (SELECT 1 FROM "orders" AS o WHERE "o"."user_id" = "u"."id")`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "single key semi join on mysql uses in",
+			builder:  users().SemiJoin("orders", JoinKey{Left: "u.id", Right: "orders.user_id"}),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT `u`.`id` FROM `users` AS u WHERE u.active = ? AND `u`.`id` IN (SELECT `orders`.`user_id` FROM `orders`)",
+			wantArgs: []interface{}{true},
+		},
+		{
+			name: "not in subquery",
+			builder: Select("id").From("users").
+				WhereNotInSubquery("id", Select("user_id").From("bans").WhereEqual("kind", "spam")),
+			dialect:  sqldialect.NoQuoteIdent(),
+			wantSQL:  "SELECT id FROM users WHERE id NOT IN (SELECT user_id FROM bans WHERE kind = ?)",
+			wantArgs: []interface{}{"spam"},
+		},
+		{
+			name:    "no keys",
+			builder: users().AntiJoin("orders"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "subquery table",
+			builder: users().SemiJoin(Select("id").From("orders"), byUser),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
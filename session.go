@@ -0,0 +1,80 @@
+package sqltk
+
+import "github.com/sprylic/sqltk/sqldialect"
+
+// Session creates builders that use its dialect, so that code and tests using different
+// dialects do not depend on the global dialect set with SetDialect. A Session cannot be
+// changed after New, and is safe for concurrent use.
+type Session struct {
+	dialect sqldialect.Dialect
+}
+
+// New returns a Session whose builders use dialect, or the global dialect at the time of the
+// call if dialect is nil.
+//
+// Example usage:
+//
+//	pg := sqltk.New(sqldialect.Postgres())
+//	q := pg.Select("id").From("users").Where(pg.NewCond().Equal("status", "active"))
+//	// SELECT "id" FROM "users" WHERE "status" = $1
+func New(dialect sqldialect.Dialect) *Session {
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	return &Session{dialect: dialect}
+}
+
+// Dialect returns the dialect of the session.
+func (s *Session) Dialect() sqldialect.Dialect {
+	return s.dialect
+}
+
+// Select is like Select, with the dialect of the session.
+func (s *Session) Select(columns ...interface{}) *SelectBuilder {
+	return Select(columns...).WithDialect(s.dialect)
+}
+
+// Insert is like Insert, with the dialect of the session.
+func (s *Session) Insert(table string) *InsertBuilder {
+	return Insert(table).WithDialect(s.dialect)
+}
+
+// InsertStruct is like InsertStruct, with the dialect of the session.
+func (s *Session) InsertStruct(table string, v interface{}) *InsertBuilder {
+	return InsertStruct(table, v).WithDialect(s.dialect)
+}
+
+// InsertStructs is like InsertStructs, with the dialect of the session.
+func (s *Session) InsertStructs(table string, rows interface{}) *InsertBuilder {
+	return InsertStructs(table, rows).WithDialect(s.dialect)
+}
+
+// Replace is like Replace, with the dialect of the session.
+func (s *Session) Replace(table string) *InsertBuilder {
+	return Replace(table).WithDialect(s.dialect)
+}
+
+// Update is like Update, with the dialect of the session.
+func (s *Session) Update(table string) *UpdateBuilder {
+	return Update(table).WithDialect(s.dialect)
+}
+
+// Delete is like Delete, with the dialect of the session.
+func (s *Session) Delete(table string) *DeleteBuilder {
+	return Delete(table).WithDialect(s.dialect)
+}
+
+// Merge is like Merge, with the dialect of the session.
+func (s *Session) Merge(target string) *MergeBuilder {
+	return Merge(target).WithDialect(s.dialect)
+}
+
+// Copy is like Copy, with the dialect of the session.
+func (s *Session) Copy(table string) *CopyBuilder {
+	return Copy(table).WithDialect(s.dialect)
+}
+
+// NewCond is like NewCond, with the dialect of the session.
+func (s *Session) NewCond() *ConditionBuilder {
+	return NewCond().WithDialect(s.dialect)
+}
@@ -0,0 +1,116 @@
+package sqltk
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSession(t *testing.T) {
+	pg := New(sqldialect.Postgres())
+	my := New(sqldialect.MySQL())
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "select",
+			builder:  pg.Select("id").From("users").Where(pg.NewCond().Equal("status", "active")).WhereEqual("role", "admin"),
+			wantSQL:  `SELECT "id" FROM "users" WHERE "status" = $1 AND role = $2`,
+			wantArgs: []interface{}{"active", "admin"},
+		},
+		{
+			name:     "insert",
+			builder:  my.Insert("users").Columns("name").Values("Ann"),
+			wantSQL:  "INSERT INTO `users` (`name`) VALUES (?)",
+			wantArgs: []interface{}{"Ann"},
+		},
+		{
+			name:     "update",
+			builder:  pg.Update("users").Set("name", "Ann").WhereEqual("id", 7),
+			wantSQL:  `UPDATE "users" SET name = $1 WHERE id = $2`,
+			wantArgs: []interface{}{"Ann", 7},
+		},
+		{
+			name:     "delete",
+			builder:  pg.Delete("users").WhereEqual("id", 7),
+			wantSQL:  `DELETE FROM "users" WHERE id = $1`,
+			wantArgs: []interface{}{7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("concurrent sessions", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(s *Session, want string) {
+				defer wg.Done()
+				sql, _, err := s.Select("id").From("users").Build()
+				if err != nil || sql != want {
+					t.Errorf("got %q, %v, want %q", sql, err, want)
+				}
+			}([]*Session{pg, my}[i%2], []string{`SELECT "id" FROM "users"`, "SELECT `id` FROM `users`"}[i%2])
+		}
+		wg.Wait()
+	})
+
+	if New(nil).Dialect() != sqldialect.GetDialect() {
+		t.Error("New(nil) should use the global dialect")
+	}
+}
+
+func TestStrictDialect(t *testing.T) {
+	pg := New(sqldialect.Strict(sqldialect.Postgres()))
+	sortColumn := `name"; DROP TABLE users; --`
+
+	sql, args, err := pg.Select("id").From("users").Where(pg.NewCond().Equal("status", "active")).Limit(10).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `SELECT "id" FROM "users" WHERE "status" = $1 LIMIT 10`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"active"}) {
+		t.Errorf("got args %v", args)
+	}
+
+	for name, b := range map[string]Builder{
+		"select":   pg.Select("id").From(sortColumn),
+		"subquery": pg.Select("id").From("users").Where(pg.NewCond().InQuery("id", pg.Select("id").From(sortColumn))),
+		"insert":   pg.Insert("users").Columns(sortColumn).Values(1),
+		"update":   pg.Update(sortColumn).Set("name", "Ann"),
+		"delete":   pg.Delete(sortColumn),
+		"cond":     pg.NewCond().Equal(sortColumn, 1),
+		"union":    pg.Select("id").From("users").Union(pg.Select("id").From(sortColumn)).WithDialect(pg.Dialect()),
+	} {
+		if _, _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "invalid identifier") {
+			t.Errorf("%s: got error %v, want an invalid identifier", name, err)
+		}
+	}
+
+	if sql, _, err := Select("id").From(sortColumn).WithDialect(sqldialect.Postgres()).Build(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	} else if want := `SELECT "id" FROM "name""; DROP TABLE users; --"`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+}
@@ -1,45 +1,146 @@
 package sqldebug
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/sprylic/sqltk/internal/sqllex"
+	"github.com/sprylic/sqltk/sqldialect"
 )
 
+// debugLabel prefixes an UnsafeSqlString when it is printed, so that logged debug SQL is not
+// mistaken for a statement that can be run.
+const debugLabel = "/* sqltk debug SQL, do not execute */ "
+
+// UnsafeSqlString is SQL with its arguments written in as literals, for debugging and logging
+// only. The literals are a best effort, and the SQL must not be executed: run the SQL of Build
+// with its args instead. Printed with %s or %v, it is labelled as debug SQL; GetUnsafeString
+// returns it without the label.
 type UnsafeSqlString string
 
+// GetUnsafeString returns the SQL without the debug label.
 func (s UnsafeSqlString) GetUnsafeString() string {
 	return string(s)
 }
 
+// String returns the SQL labelled as debug SQL.
+func (s UnsafeSqlString) String() string {
+	return debugLabel + string(s)
+}
+
 // InterpolateSQL interpolates arguments into a SQL query for debugging/logging only.
 // DO NOT use the result for execution (not safe against SQL injection).
+// Strings are written as standard SQL literals, and ?, $n and :n placeholders are replaced.
+// Use InterpolateSQLDialect for SQL built with a known dialect.
 func InterpolateSQL(query string, args []interface{}) UnsafeSqlString {
+	return interpolate(sqldialect.NoQuoteIdent(), query, args, true)
+}
+
+// InterpolateSQLDialect interpolates arguments into a SQL query built with dialect, or the
+// global dialect if dialect is nil, for debugging/logging only. Strings are quoted and escaped
+// as dialect quotes them, and only the placeholders of dialect are replaced, so a ? operator
+// in SQL for Postgres is left alone. Placeholders inside quoted strings, quoted identifiers
+// and comments are not replaced. nil is written as NULL, bools as TRUE and FALSE, []byte as
+// a hex literal, time.Time as a quoted timestamp, and a driver.Valuer as its value.
+// DO NOT use the result for execution (not safe against SQL injection).
+func InterpolateSQLDialect(dialect sqldialect.Dialect, query string, args []interface{}) UnsafeSqlString {
+	if dialect == nil {
+		dialect = sqldialect.GetDialect()
+	}
+	return interpolate(dialect, query, args, false)
+}
+
+// interpolate replaces the placeholders of query with args. If anyStyle is set, it replaces
+// ?, $n and :n placeholders, whatever the placeholders of dialect.
+func interpolate(dialect sqldialect.Dialect, query string, args []interface{}, anyStyle bool) UnsafeSqlString {
 	if len(args) == 0 {
 		return UnsafeSqlString(query)
 	}
+	lx := sqllex.For(dialect)
+	if anyStyle {
+		lx.Dialect = nil
+	}
 
-	// Simple interpolation - replace ? with values
-	result := query
-	argIndex := 0
-
-	for i := 0; i < len(result) && argIndex < len(args); i++ {
-		if result[i] == '?' {
-			arg := args[argIndex]
-			var argStr string
-
-			switch v := arg.(type) {
-			case string:
-				argStr = "'" + strings.ReplaceAll(v, "'", "''") + "'"
-			case nil:
-				argStr = "NULL"
-			default:
-				argStr = fmt.Sprintf("%v", v)
-			}
-
-			result = result[:i] + argStr + result[i+1:]
-			argIndex++
+	var sb strings.Builder
+	next, last := 0, 0
+	for _, t := range lx.Tokens(query) {
+		if t.Kind != sqllex.Param {
+			continue
+		}
+		n := t.ParamNumber()
+		if n == 0 {
+			next++
+			n = next
 		}
+		if n < 1 || n > len(args) {
+			continue
+		}
+		sb.WriteString(query[last:t.Pos])
+		sb.WriteString(literal(dialect, args[n-1]))
+		last = t.Pos + len(t.Text)
 	}
+	sb.WriteString(query[last:])
+	return UnsafeSqlString(sb.String())
+}
 
-	return UnsafeSqlString(result)
+// literal returns v as a SQL literal of dialect.
+func literal(dialect sqldialect.Dialect, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return dialect.QuoteString(v)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		switch sqldialect.Unwrap(dialect) {
+		case sqldialect.Postgres(), sqldialect.DuckDB():
+			return `'\x` + hex.EncodeToString(v) + `'`
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case time.Time:
+		return dialect.QuoteString(v.Format("2006-01-02 15:04:05.999999999Z07:00"))
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL"
+		}
+		value, err := v.Value()
+		if err != nil {
+			return "/* " + strings.ReplaceAll(err.Error(), "*/", "* /") + " */ NULL"
+		}
+		return literal(dialect, value)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return literal(dialect, rv.Elem().Interface())
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return literal(dialect, rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	case reflect.String:
+		return dialect.QuoteString(rv.String())
+	}
+	return dialect.QuoteString(fmt.Sprint(v))
 }
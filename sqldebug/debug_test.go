@@ -0,0 +1,88 @@
+package sqldebug
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+type status string
+
+func TestInterpolateSQLDialect(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	name := "O'Brien"
+	tests := []struct {
+		name    string
+		dialect sqldialect.Dialect
+		sql     string
+		args    []interface{}
+		want    string
+	}{
+		{
+			name:    "mysql",
+			dialect: sqldialect.MySQL(),
+			sql:     "SELECT * FROM `t?` WHERE a = ? AND b = ? AND c = ? AND d = ? AND e = ? AND f = ? -- ?",
+			args:    []interface{}{"it's", nil, true, []byte{0xca, 0xfe}, at, status("new")},
+			want:    "SELECT * FROM `t?` WHERE a = 'it''s' AND b = NULL AND c = TRUE AND d = X'cafe' AND e = '2026-01-02 03:04:05Z' AND f = 'new' -- ?",
+		},
+		{
+			name:    "postgres",
+			dialect: sqldialect.Postgres(),
+			sql:     `SELECT * FROM "t" WHERE data ? 'k' AND a = $2 AND b = $1 AND c = '$1'`,
+			args:    []interface{}{[]byte{1}, &name},
+			want:    `SELECT * FROM "t" WHERE data ? 'k' AND a = 'O''Brien' AND b = '\x01' AND c = '$1'`,
+		},
+		{
+			name:    "bigquery",
+			dialect: sqldialect.BigQuery(),
+			sql:     `SELECT * FROM t WHERE a = ? AND b = 'x\'?' AND c = ?`,
+			args:    []interface{}{`a\'b`, 1.5},
+			want:    `SELECT * FROM t WHERE a = 'a\\\'b' AND b = 'x\'?' AND c = 1.5`,
+		},
+		{
+			name:    "oracle",
+			dialect: sqldialect.Oracle(),
+			sql:     "SELECT * FROM t WHERE a = :1 AND b = :2",
+			args:    []interface{}{(*string)(nil), false},
+			want:    "SELECT * FROM t WHERE a = NULL AND b = FALSE",
+		},
+		{
+			name:    "postgres dollar-quoted string",
+			dialect: sqldialect.Postgres(),
+			sql:     "SELECT $fn$ $1 $fn$, 'C:\\' AS dir, $1",
+			args:    []interface{}{7},
+			want:    "SELECT $fn$ $1 $fn$, 'C:\\' AS dir, 7",
+		},
+		{
+			name:    "mysql backslash",
+			dialect: sqldialect.MySQL(),
+			sql:     "SELECT * FROM `a\\` WHERE a = ? AND b = 'x\\'?' AND c = ?",
+			args:    []interface{}{`C:\' OR 1=1 -- `, 2},
+			want:    "SELECT * FROM `a\\` WHERE a = 'C:\\\\'' OR 1=1 -- ' AND b = 'x\\'?' AND c = 2",
+		},
+		{
+			name:    "string with a placeholder",
+			dialect: sqldialect.MySQL(),
+			sql:     "UPDATE t SET a = ?, b = ?",
+			args:    []interface{}{"?", 2},
+			want:    "UPDATE t SET a = '?', b = 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InterpolateSQLDialect(tt.dialect, tt.sql, tt.args).GetUnsafeString()
+			if got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := InterpolateSQL("a = $1 AND b = $2", []interface{}{1, "x"}).GetUnsafeString(), "a = 1 AND b = 'x'"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprint(InterpolateSQL("SELECT 1", nil)), "/* sqltk debug SQL, do not execute */ SELECT 1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -13,6 +13,104 @@ type Dialect interface {
 	QuoteString(s string) string
 }
 
+// LimitStyle is the syntax a dialect limits the rows of a query with.
+type LimitStyle int
+
+const (
+	// LimitOffset is LIMIT count OFFSET offset, as in Postgres, SQLite, and DuckDB. It is the
+	// style of dialects that do not implement LimitStyler.
+	LimitOffset LimitStyle = iota
+	// LimitComma is LIMIT offset, count, as in MySQL. An offset without a limit is written
+	// with the largest possible count.
+	LimitComma
+	// OffsetFetchNext is OFFSET offset ROWS FETCH NEXT count ROWS ONLY, as in SQL Server,
+	// which requires ORDER BY and an OFFSET, written as OFFSET 0 ROWS if none is set.
+	OffsetFetchNext
+	// FetchFirst is OFFSET offset ROWS FETCH FIRST count ROWS ONLY, as in Oracle 12c and
+	// later, with either part left out if it is not set.
+	FetchFirst
+)
+
+// LimitStyler is implemented by dialects that limit rows other than with LIMIT and OFFSET.
+type LimitStyler interface {
+	LimitStyle() LimitStyle
+}
+
+// LimitStyleOf returns the LimitStyle of d: that reported by its LimitStyle method, or
+// LimitOffset.
+func LimitStyleOf(d Dialect) LimitStyle {
+	if s, ok := Unwrap(d).(LimitStyler); ok {
+		return s.LimitStyle()
+	}
+	return LimitOffset
+}
+
+// DefaultMaxPlaceholders is the number of placeholders a statement may have in dialects that
+// do not implement PlaceholderLimiter: 65535, the limit of MySQL and of the Postgres protocol.
+const DefaultMaxPlaceholders = 65535
+
+// PlaceholderLimiter is implemented by dialects that allow a number of placeholders in a
+// statement other than DefaultMaxPlaceholders, e.g. 32766 for SQLite.
+type PlaceholderLimiter interface {
+	MaxPlaceholders() int
+}
+
+// MaxPlaceholdersOf returns the number of placeholders a statement may have in d: that
+// reported by its MaxPlaceholders method, or DefaultMaxPlaceholders.
+func MaxPlaceholdersOf(d Dialect) int {
+	if l, ok := Unwrap(d).(PlaceholderLimiter); ok {
+		return l.MaxPlaceholders()
+	}
+	return DefaultMaxPlaceholders
+}
+
+// ReturningSupporter is implemented by dialects that accept a RETURNING clause on some
+// statements only, or on none. statement is "INSERT", "UPDATE", or "DELETE". A custom dialect
+// for SQLite 3.35 or later accepts RETURNING everywhere; one for MariaDB accepts it on INSERT
+// and DELETE only.
+type ReturningSupporter interface {
+	SupportsReturning(statement string) bool
+}
+
+// SupportsReturning reports whether d accepts a RETURNING clause on statement: as reported by
+// its SupportsReturning method, or true.
+func SupportsReturning(d Dialect, statement string) bool {
+	if r, ok := Unwrap(d).(ReturningSupporter); ok {
+		return r.SupportsReturning(statement)
+	}
+	return true
+}
+
+// DualRequirer is implemented by dialects in which every SELECT must have a FROM clause, such
+// as Oracle, where a SELECT without a table reads from the one-row table DUAL.
+type DualRequirer interface {
+	RequiresDual() bool
+}
+
+// RequiresDual reports whether d writes FROM DUAL in a SELECT without a table: as reported
+// by its RequiresDual method, or false.
+func RequiresDual(d Dialect) bool {
+	if r, ok := Unwrap(d).(DualRequirer); ok {
+		return r.RequiresDual()
+	}
+	return false
+}
+
+// TableAliasAsSupporter is implemented by dialects that reject AS between a table and its
+// alias, such as Oracle, which reads FROM users u but not FROM users AS u.
+type TableAliasAsSupporter interface {
+	SupportsTableAliasAs() bool
+}
+
+// SupportsTableAliasAs reports whether d accepts AS before a table alias: as reported by its
+// SupportsTableAliasAs method, or true.
+func SupportsTableAliasAs(d Dialect) bool {
+	if s, ok := Unwrap(d).(TableAliasAsSupporter); ok {
+		return s.SupportsTableAliasAs()
+	}
+	return true
+}
+
 // standardDialect uses ? for all placeholders and no identifier quoting (NoQuotes dialect).
 type standardDialect struct{}
 
@@ -22,26 +120,103 @@ func (standardDialect) QuoteString(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
 
-// mySQLDialect uses ? for all placeholders and backticks for identifier quoting.
+// mySQLDialect uses ? for all placeholders and backticks for identifier quoting, doubling
+// backticks in identifiers. Strings double quotes and escape backslashes, which MySQL reads as
+// escapes by default. Rows are limited with LIMIT offset, count.
 type mySQLDialect struct{}
 
-func (mySQLDialect) Placeholder(n int) string       { return "?" }
-func (mySQLDialect) QuoteIdent(ident string) string { return "`" + ident + "`" }
-func (mySQLDialect) QuoteString(s string) string    { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+func (mySQLDialect) Placeholder(n int) string { return "?" }
+func (mySQLDialect) QuoteIdent(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
+func (mySQLDialect) QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+func (mySQLDialect) LimitStyle() LimitStyle        { return LimitComma }
+func (mySQLDialect) SupportsReturning(string) bool { return false }
 
-// postgresDialect uses $n for placeholders and double quotes for identifier quoting.
+// postgresDialect uses $n for placeholders and double quotes for identifier quoting, doubling
+// double quotes in identifiers.
 type postgresDialect struct{}
 
-func (postgresDialect) Placeholder(n int) string       { return "$" + fmt.Sprint(n) }
-func (postgresDialect) QuoteIdent(ident string) string { return "\"" + ident + "\"" }
+func (postgresDialect) Placeholder(n int) string { return "$" + fmt.Sprint(n) }
+func (postgresDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.ReplaceAll(ident, "\"", "\"\"") + "\""
+}
 func (postgresDialect) QuoteString(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
 
+// duckDBDialect uses $n for placeholders and double quotes for identifier quoting, doubling
+// double quotes in identifiers.
+type duckDBDialect struct{}
+
+func (duckDBDialect) Placeholder(n int) string { return "$" + fmt.Sprint(n) }
+func (duckDBDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.ReplaceAll(ident, "\"", "\"\"") + "\""
+}
+func (duckDBDialect) QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// bigQueryDialect uses ? for placeholders and backticks for identifier quoting.
+// Identifiers and string literals use backslash escapes.
+type bigQueryDialect struct{}
+
+func (bigQueryDialect) Placeholder(n int) string { return "?" }
+func (bigQueryDialect) QuoteIdent(ident string) string {
+	ident = strings.ReplaceAll(ident, `\`, `\\`)
+	return "`" + strings.ReplaceAll(ident, "`", "\\`") + "`"
+}
+func (bigQueryDialect) QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+func (bigQueryDialect) SupportsReturning(string) bool { return false }
+
+// oracleDialect uses :n for placeholders and double quotes for identifier quoting, doubling
+// double quotes in identifiers.
+// Rows are limited with OFFSET n ROWS FETCH FIRST n ROWS ONLY, a SELECT without a table reads
+// from DUAL, RETURNING is not supported, as Oracle only has RETURNING INTO, and table aliases
+// are written without AS.
+type oracleDialect struct{}
+
+func (oracleDialect) Placeholder(n int) string { return ":" + fmt.Sprint(n) }
+func (oracleDialect) QuoteIdent(ident string) string {
+	return "\"" + strings.ReplaceAll(ident, "\"", "\"\"") + "\""
+}
+func (oracleDialect) QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+func (oracleDialect) LimitStyle() LimitStyle        { return FetchFirst }
+func (oracleDialect) RequiresDual() bool            { return true }
+func (oracleDialect) SupportsReturning(string) bool { return false }
+func (oracleDialect) SupportsTableAliasAs() bool    { return false }
+
+// clickHouseDialect uses ? for placeholders and backticks for identifier quoting, escaping
+// backslashes and backticks in identifiers and string literals with a backslash.
+type clickHouseDialect struct{}
+
+func (clickHouseDialect) Placeholder(n int) string { return "?" }
+func (clickHouseDialect) QuoteIdent(ident string) string {
+	ident = strings.ReplaceAll(ident, `\`, `\\`)
+	return "`" + strings.ReplaceAll(ident, "`", "\\`") + "`"
+}
+func (clickHouseDialect) QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+func (clickHouseDialect) SupportsReturning(string) bool { return false }
+
 var (
-	standardDialectInstance = standardDialect{}
-	mySQLDialectInstance    = mySQLDialect{}
-	postgresDialectInstance = postgresDialect{}
+	standardDialectInstance   = standardDialect{}
+	mySQLDialectInstance      = mySQLDialect{}
+	postgresDialectInstance   = postgresDialect{}
+	duckDBDialectInstance     = duckDBDialect{}
+	bigQueryDialectInstance   = bigQueryDialect{}
+	oracleDialectInstance     = oracleDialect{}
+	clickHouseDialectInstance = clickHouseDialect{}
 
 	dialectMu     sync.RWMutex
 	globalDialect Dialect = &mySQLDialectInstance
@@ -56,7 +231,23 @@ func MySQL() Dialect { return &mySQLDialectInstance }
 // Postgres returns the Postgres SQL dialect.
 func Postgres() Dialect { return &postgresDialectInstance }
 
+// DuckDB returns the DuckDB SQL dialect.
+func DuckDB() Dialect { return &duckDBDialectInstance }
+
+// BigQuery returns the BigQuery (GoogleSQL) dialect.
+func BigQuery() Dialect { return &bigQueryDialectInstance }
+
+// Oracle returns the Oracle dialect, for Oracle Database 12c and later.
+func Oracle() Dialect { return &oracleDialectInstance }
+
+// ClickHouse returns the ClickHouse dialect.
+func ClickHouse() Dialect { return &clickHouseDialectInstance }
+
 // SetDialect sets the global SQL dialect for all builders.
+//
+// Deprecated: The global dialect is shared by all goroutines and tests of a program, so
+// changing it affects builders elsewhere. Create builders with sqltk.New(d), or set the
+// dialect of each builder with WithDialect.
 func SetDialect(d Dialect) {
 	dialectMu.Lock()
 	defer dialectMu.Unlock()
@@ -0,0 +1,77 @@
+package sqldialect
+
+import "strings"
+
+// SplitQualified splits a qualified name such as "analytics.events" into its parts. A part in
+// double quotes or backticks may contain dots, with the quote character doubled to include it,
+// as in `"my.schema".events`; its quotes are removed. Spaces around unquoted parts are trimmed.
+// It reports false if a part is empty or a quote is not closed.
+func SplitQualified(name string) ([]string, bool) {
+	var parts []string
+	for i := 0; ; {
+		for i < len(name) && name[i] == ' ' {
+			i++
+		}
+		var part string
+		if i < len(name) && (name[i] == '"' || name[i] == '`') {
+			q := name[i]
+			var sb strings.Builder
+			closed := false
+			for i++; i < len(name); i++ {
+				if name[i] != q {
+					sb.WriteByte(name[i])
+					continue
+				}
+				if i+1 < len(name) && name[i+1] == q {
+					sb.WriteByte(q)
+					i++
+					continue
+				}
+				closed = true
+				i++
+				break
+			}
+			if !closed {
+				return nil, false
+			}
+			part = sb.String()
+			for i < len(name) && name[i] == ' ' {
+				i++
+			}
+		} else {
+			end := strings.IndexByte(name[i:], '.')
+			if end < 0 {
+				end = len(name) - i
+			}
+			part = strings.TrimSpace(name[i : i+end])
+			if strings.ContainsAny(part, "\"`") {
+				return nil, false
+			}
+			i += end
+		}
+		if part == "" {
+			return nil, false
+		}
+		parts = append(parts, part)
+		if i == len(name) {
+			return parts, true
+		}
+		if name[i] != '.' {
+			return nil, false
+		}
+		i++
+	}
+}
+
+// QuoteQualified quotes each part of a qualified name such as "analytics.events" or
+// "e.user_id" with d, see SplitQualified, and quotes name as a whole if it cannot be split.
+func QuoteQualified(d Dialect, name string) string {
+	parts, ok := SplitQualified(name)
+	if !ok {
+		return d.QuoteIdent(name)
+	}
+	for i, part := range parts {
+		parts[i] = d.QuoteIdent(part)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,39 @@
+package sqldialect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitQualified(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"events", []string{"events"}},
+		{"analytics.events", []string{"analytics", "events"}},
+		{"warehouse . analytics . events", []string{"warehouse", "analytics", "events"}},
+		{`"my.schema".events`, []string{"my.schema", "events"}},
+		{"`my.db`.`odd``name`", []string{"my.db", "odd`name"}},
+		{`"say ""hi""".t`, []string{`say "hi"`, "t"}},
+		{"events e", []string{"events e"}},
+		{"", nil},
+		{"a..b", nil},
+		{`"open.t`, nil},
+		{`"a"b.t`, nil},
+		{`a"b.t`, nil},
+	}
+	for _, tt := range tests {
+		got, ok := SplitQualified(tt.name)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitQualified(%q) = %q, %v, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+
+	if got, want := QuoteQualified(Postgres(), `"my.schema".events`), `"my.schema"."events"`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if got, want := QuoteQualified(MySQL(), `a"b.t`), "`a\"b.t`"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
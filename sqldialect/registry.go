@@ -0,0 +1,82 @@
+package sqldialect
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Dialect{
+		"noquote":    NoQuoteIdent(),
+		"mysql":      MySQL(),
+		"postgres":   Postgres(),
+		"duckdb":     DuckDB(),
+		"bigquery":   BigQuery(),
+		"oracle":     Oracle(),
+		"clickhouse": ClickHouse(),
+	}
+)
+
+// RegisterDialect registers d under name, so that it can be looked up with DialectByName, e.g.
+// from a configuration file. Names are case-insensitive, and it is an error if name is taken.
+// The dialects of this package are registered as "noquote", "mysql", "postgres", "duckdb",
+// "bigquery", "oracle", and "clickhouse".
+//
+// Example usage:
+//
+//	func init() {
+//		sqldialect.MustRegisterDialect("cockroachdb", cockroachDialect{})
+//	}
+func RegisterDialect(name string, d Dialect) error {
+	if name == "" {
+		return errors.New("RegisterDialect: name must not be empty")
+	}
+	if d == nil {
+		return fmt.Errorf("RegisterDialect: dialect %q is nil", name)
+	}
+	key := strings.ToLower(name)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[key]; ok {
+		return fmt.Errorf("RegisterDialect: dialect %q is already registered", name)
+	}
+	registry[key] = d
+	return nil
+}
+
+// MustRegisterDialect is like RegisterDialect but panics on error, for registering dialects
+// in package initialization.
+func MustRegisterDialect(name string, d Dialect) {
+	if err := RegisterDialect(name, d); err != nil {
+		panic(err)
+	}
+}
+
+// DialectByName returns the dialect registered under name with RegisterDialect, or an error
+// if there is none.
+func DialectByName(name string) (Dialect, error) {
+	registryMu.RLock()
+	d, ok := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("DialectByName: unknown dialect %q", name)
+	}
+	return d, nil
+}
+
+// DialectNames returns the names of the registered dialects, in lower case and sorted.
+func DialectNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
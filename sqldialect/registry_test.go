@@ -0,0 +1,43 @@
+package sqldialect
+
+import (
+	"slices"
+	"testing"
+)
+
+// tidbDialect is a custom dialect as a third-party package would register it.
+type tidbDialect struct{ mySQLDialect }
+
+func TestRegisterDialect(t *testing.T) {
+	if err := RegisterDialect("TiDB", tidbDialect{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := DialectByName("tidb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := d.(tidbDialect); !ok {
+		t.Errorf("got %T, want tidbDialect", d)
+	}
+	if d, err := DialectByName("Postgres"); err != nil || d != Postgres() {
+		t.Errorf("got %v, %v for a built-in dialect", d, err)
+	}
+	if !slices.Contains(DialectNames(), "tidb") {
+		t.Errorf("got names %v, want tidb among them", DialectNames())
+	}
+
+	for name, d := range map[string]Dialect{
+		"":      MySQL(),
+		"tidb":  tidbDialect{},
+		"MYSQL": MySQL(),
+		"nil":   nil,
+	} {
+		if err := RegisterDialect(name, d); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+	if _, err := DialectByName("cockroachdb"); err == nil {
+		t.Error("expected error for an unknown dialect")
+	}
+}
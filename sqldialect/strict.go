@@ -0,0 +1,111 @@
+package sqldialect
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strict returns d with strict identifier validation, as a defense in depth against injection
+// through identifiers taken from user input, such as a column to sort by. The dialects of this
+// package escape quote characters in identifiers, but with Strict, builders return an error
+// from Build for an identifier that ValidateIdent rejects instead of quoting it.
+//
+// The returned dialect renders as d does. Compare Unwrap(d) with the dialects of this package
+// to tell which database a dialect is for.
+//
+// Example usage:
+//
+//	sqltk.Select("id").From("users").OrderBy(sortColumn).WithDialect(sqldialect.Strict(sqldialect.Postgres()))
+func Strict(d Dialect) Dialect {
+	if IsStrict(d) {
+		return d
+	}
+	return strictDialect{d}
+}
+
+// strictDialect is a dialect returned by Strict. QuoteIdent writes an identifier that
+// ValidateIdent rejects between NUL bytes, which CheckIdents finds, and which makes the
+// statement invalid if it is run without being checked.
+type strictDialect struct {
+	Dialect
+}
+
+func (s strictDialect) QuoteIdent(ident string) string {
+	if ValidateIdent(ident) != nil {
+		return "\x00" + strconv.Quote(ident) + "\x00"
+	}
+	return s.Dialect.QuoteIdent(ident)
+}
+
+func (s strictDialect) Unwrap() Dialect { return s.Dialect }
+
+// Unwrap returns the dialect d wraps, such as the dialect passed to Strict, or d itself. Dialects
+// that wrap another implement an Unwrap() Dialect method.
+func Unwrap(d Dialect) Dialect {
+	for {
+		w, ok := d.(interface{ Unwrap() Dialect })
+		if !ok {
+			return d
+		}
+		d = w.Unwrap()
+	}
+}
+
+// IsStrict reports whether d, or a dialect it wraps, was returned by Strict.
+func IsStrict(d Dialect) bool {
+	for {
+		if _, ok := d.(strictDialect); ok {
+			return true
+		}
+		w, ok := d.(interface{ Unwrap() Dialect })
+		if !ok {
+			return false
+		}
+		d = w.Unwrap()
+	}
+}
+
+// ValidateIdent returns an error if ident is empty, is not valid UTF-8, or contains a control
+// character such as a newline, a quote character, a backslash, a semicolon, or a comment.
+func ValidateIdent(ident string) error {
+	if ident == "" {
+		return errors.New("identifier must not be empty")
+	}
+	if !utf8.ValidString(ident) {
+		return errors.New("identifier is not valid UTF-8")
+	}
+	if strings.ContainsFunc(ident, unicode.IsControl) {
+		return errors.New("identifier contains a control character")
+	}
+	for _, s := range []string{`"`, "`", "'", `\`, ";", "--", "/*", "*/"} {
+		if strings.Contains(ident, s) {
+			return fmt.Errorf("identifier contains %q", s)
+		}
+	}
+	return nil
+}
+
+// CheckIdents returns an error if d is a Strict dialect that rejected an identifier of sql,
+// which was built with it. Builders call it before returning the SQL of Build.
+func CheckIdents(d Dialect, sql string) error {
+	if !IsStrict(d) {
+		return nil
+	}
+	start := strings.IndexByte(sql, 0)
+	if start < 0 {
+		return nil
+	}
+	end := strings.IndexByte(sql[start+1:], 0)
+	if end < 0 {
+		return errors.New("invalid identifier")
+	}
+	ident, err := strconv.Unquote(sql[start+1 : start+1+end])
+	if err != nil {
+		return errors.New("invalid identifier")
+	}
+	return fmt.Errorf("invalid identifier %q: %w", ident, ValidateIdent(ident))
+}
@@ -0,0 +1,57 @@
+package sqldialect
+
+import "testing"
+
+func TestQuoteIdentEscaping(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		ident   string
+		want    string
+	}{
+		{MySQL(), "odd`name", "`odd``name`"},
+		{Postgres(), `odd"name`, `"odd""name"`},
+		{DuckDB(), `odd"name`, `"odd""name"`},
+		{Oracle(), `odd"name`, `"odd""name"`},
+		{BigQuery(), "odd`na\\me", "`odd\\`na\\\\me`"},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.QuoteIdent(tt.ident); got != tt.want {
+			t.Errorf("%T: got %s, want %s", tt.dialect, got, tt.want)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	for _, ident := range []string{"users", "user name", "public.users", "größe"} {
+		if err := ValidateIdent(ident); err != nil {
+			t.Errorf("%q: unexpected error: %v", ident, err)
+		}
+	}
+	for _, ident := range []string{"", "a\nb", "a\x00b", `a"b`, "a`b", "a'b", `a\b`, "a;b", "a--b", "a/*b", "a*/b", "\xff"} {
+		if err := ValidateIdent(ident); err == nil {
+			t.Errorf("%q: expected error", ident)
+		}
+	}
+
+	d := Strict(Postgres())
+	if Unwrap(d) != Postgres() || Unwrap(Postgres()) != Postgres() {
+		t.Error("Unwrap should return the wrapped dialect")
+	}
+	if !IsStrict(d) || IsStrict(Postgres()) || Strict(d) != d {
+		t.Error("IsStrict should report dialects returned by Strict")
+	}
+	if got := d.QuoteIdent("users"); got != `"users"` {
+		t.Errorf("got %s, want %q", got, `"users"`)
+	}
+
+	sql := "SELECT * FROM " + d.QuoteIdent(`users"; DROP TABLE users; --`)
+	if err := CheckIdents(d, sql); err == nil {
+		t.Error("expected error for an invalid identifier")
+	}
+	if err := CheckIdents(Postgres(), sql); err != nil {
+		t.Errorf("unexpected error for a dialect that is not strict: %v", err)
+	}
+	if err := CheckIdents(d, `SELECT * FROM "users"`); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
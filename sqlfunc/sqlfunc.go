@@ -1,6 +1,7 @@
 package sqlfunc
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -8,12 +9,40 @@ import (
 
 type SqlFunc string
 
+// invalidMarker delimits a validation error carried by a SqlFunc in place of SQL.
+const invalidMarker = "\x00"
+
+// Invalid returns a SqlFunc recording err instead of SQL. Helpers return it for unsafe input
+// rather than panicking, and builders report the error from Build wherever the function is
+// used, as a column, expression or bound value. The error is still found when the result is
+// nested inside another function.
+func Invalid(err error) SqlFunc {
+	return SqlFunc(invalidMarker + err.Error() + invalidMarker)
+}
+
+// Err returns the error recorded by Invalid, or nil if the function is valid.
+func (f SqlFunc) Err() error {
+	s := string(f)
+	start := strings.Index(s, invalidMarker)
+	if start < 0 {
+		return nil
+	}
+	msg := s[start+len(invalidMarker):]
+	if end := strings.Index(msg, invalidMarker); end >= 0 {
+		msg = msg[:end]
+	}
+	return errors.New(msg)
+}
+
 // ValidateSqlFuncInput checks if input is safe for SQL function generation
 func ValidateSqlFuncInput(input interface{}) error {
 	switch v := input.(type) {
 	case string:
 		return validatePattern(v)
 	case SqlFunc:
+		if err := v.Err(); err != nil {
+			return err
+		}
 		return validatePattern(string(v))
 	default:
 		// Other types (numbers, etc.) are generally safe
@@ -0,0 +1,181 @@
+package sqlfunc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// Window Functions
+
+// RowNumber returns ROW_NUMBER(), the number of the row within its partition, starting at 1.
+func RowNumber() SqlFunc {
+	return SqlFunc("ROW_NUMBER()")
+}
+
+// Rank returns RANK(), the rank of the row within its partition, with gaps after ties.
+func Rank() SqlFunc {
+	return SqlFunc("RANK()")
+}
+
+// DenseRank returns DENSE_RANK(), the rank of the row within its partition, without gaps.
+func DenseRank() SqlFunc {
+	return SqlFunc("DENSE_RANK()")
+}
+
+// PercentRank returns PERCENT_RANK(), the relative rank of the row, from 0 to 1.
+func PercentRank() SqlFunc {
+	return SqlFunc("PERCENT_RANK()")
+}
+
+// CumeDist returns CUME_DIST(), the fraction of rows of the partition ordered before or with
+// the row.
+func CumeDist() SqlFunc {
+	return SqlFunc("CUME_DIST()")
+}
+
+// NTile returns NTILE(buckets), which divides the partition into buckets groups and numbers
+// them from 1.
+func NTile(buckets int) SqlFunc {
+	return SqlFunc(fmt.Sprintf("NTILE(%d)", buckets))
+}
+
+// Lag returns LAG(expr, offset), the value of expr offset rows before the row.
+func Lag(expr interface{}, offset int) SqlFunc {
+	if err := ValidateSqlFuncInput(expr); err != nil {
+		return Invalid(fmt.Errorf("Lag: %w", err))
+	}
+	return SqlFunc(fmt.Sprintf("LAG(%v, %d)", expr, offset))
+}
+
+// Lead returns LEAD(expr, offset), the value of expr offset rows after the row.
+func Lead(expr interface{}, offset int) SqlFunc {
+	if err := ValidateSqlFuncInput(expr); err != nil {
+		return Invalid(fmt.Errorf("Lead: %w", err))
+	}
+	return SqlFunc(fmt.Sprintf("LEAD(%v, %d)", expr, offset))
+}
+
+// FirstValue returns FIRST_VALUE(expr), the value of expr for the first row of the frame.
+func FirstValue(expr interface{}) SqlFunc {
+	if err := ValidateSqlFuncInput(expr); err != nil {
+		return Invalid(fmt.Errorf("FirstValue: %w", err))
+	}
+	return SqlFunc(fmt.Sprintf("FIRST_VALUE(%v)", expr))
+}
+
+// LastValue returns LAST_VALUE(expr), the value of expr for the last row of the frame.
+func LastValue(expr interface{}) SqlFunc {
+	if err := ValidateSqlFuncInput(expr); err != nil {
+		return Invalid(fmt.Errorf("LastValue: %w", err))
+	}
+	return SqlFunc(fmt.Sprintf("LAST_VALUE(%v)", expr))
+}
+
+// WindowExpr is a function call with an OVER (...) clause.
+// Column names are quoted with the dialect of the builder the expression is used in.
+type WindowExpr struct {
+	fn          SqlFunc
+	partitionBy []string
+	orderBy     []string
+	frame       string
+	err         error
+}
+
+// Over starts an OVER (...) clause for the function, turning it into a window expression.
+//
+// Example usage:
+//
+//	sqltk.Select(sqltk.Alias(sqlfunc.RowNumber().Over().PartitionBy("user_id").OrderBy("created_at DESC"), "rn"))
+//	sqltk.Select(sqltk.Alias(mysqlfunc.Sum("amount").Over().PartitionBy("user_id"), "total"))
+func (f SqlFunc) Over() *WindowExpr {
+	return &WindowExpr{fn: f}
+}
+
+// PartitionBy adds PARTITION BY columns.
+func (w *WindowExpr) PartitionBy(columns ...string) *WindowExpr {
+	w.partitionBy = append(w.partitionBy, columns...)
+	return w
+}
+
+// OrderBy adds ORDER BY expressions, each a column optionally followed by ASC or DESC and then
+// by NULLS FIRST or NULLS LAST (e.g. "created_at DESC NULLS LAST").
+func (w *WindowExpr) OrderBy(exprs ...string) *WindowExpr {
+	for _, expr := range exprs {
+		if _, _, err := splitOrderBy(expr); err != nil {
+			w.err = fmt.Errorf("OrderBy: %w", err)
+			return w
+		}
+	}
+	w.orderBy = append(w.orderBy, exprs...)
+	return w
+}
+
+// splitOrderBy splits an ORDER BY expression of OrderBy into its column and its direction,
+// which is upper-cased.
+func splitOrderBy(expr string) (string, string, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return "", "", fmt.Errorf("column is required")
+	}
+	words := fields[1:]
+	if len(words) > 0 && (strings.EqualFold(words[0], "ASC") || strings.EqualFold(words[0], "DESC")) {
+		words = words[1:]
+	}
+	if len(words) == 2 && strings.EqualFold(words[0], "NULLS") && (strings.EqualFold(words[1], "FIRST") || strings.EqualFold(words[1], "LAST")) {
+		words = nil
+	}
+	if len(words) > 0 {
+		return "", "", fmt.Errorf("invalid direction %q: must be ASC or DESC, optionally followed by NULLS FIRST or NULLS LAST", strings.Join(fields[1:], " "))
+	}
+	return fields[0], strings.ToUpper(strings.Join(fields[1:], " ")), nil
+}
+
+// Frame sets the frame clause (e.g. "ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW").
+func (w *WindowExpr) Frame(frame string) *WindowExpr {
+	if err := validatePattern(frame); err != nil {
+		w.err = fmt.Errorf("Frame: %w", err)
+		return w
+	}
+	w.frame = frame
+	return w
+}
+
+// SQL renders the window expression using the given dialect for identifier quoting.
+func (w *WindowExpr) SQL(dialect sqldialect.Dialect) (string, error) {
+	if w.err != nil {
+		return "", w.err
+	}
+	if w.fn == "" {
+		return "", fmt.Errorf("window: function is required")
+	}
+	if err := w.fn.Err(); err != nil {
+		return "", err
+	}
+
+	var clauses []string
+	if len(w.partitionBy) > 0 {
+		cols := make([]string, len(w.partitionBy))
+		for i, col := range w.partitionBy {
+			cols[i] = sqldialect.QuoteQualified(dialect, strings.TrimSpace(col))
+		}
+		clauses = append(clauses, "PARTITION BY "+strings.Join(cols, ", "))
+	}
+	if len(w.orderBy) > 0 {
+		exprs := make([]string, len(w.orderBy))
+		for i, o := range w.orderBy {
+			col, dir, _ := splitOrderBy(o) // checked by OrderBy
+			exprs[i] = sqldialect.QuoteQualified(dialect, col)
+			if dir != "" {
+				exprs[i] += " " + dir
+			}
+		}
+		clauses = append(clauses, "ORDER BY "+strings.Join(exprs, ", "))
+	}
+	if w.frame != "" {
+		clauses = append(clauses, w.frame)
+	}
+
+	return string(w.fn) + " OVER (" + strings.Join(clauses, " ") + ")", nil
+}
@@ -0,0 +1,42 @@
+package sqlfunc
+
+import (
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestWindowExpr(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    *WindowExpr
+		dialect sqldialect.Dialect
+		want    string
+		wantErr bool
+	}{
+		{"empty over", RowNumber().Over(), sqldialect.MySQL(), "ROW_NUMBER() OVER ()", false},
+		{"partition and order", RowNumber().Over().PartitionBy("user_id").OrderBy("created_at DESC"), sqldialect.MySQL(), "ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC)", false},
+		{"qualified columns", Rank().Over().PartitionBy("o.user_id", "o.status").OrderBy("o.total"), sqldialect.Postgres(), `RANK() OVER (PARTITION BY "o"."user_id", "o"."status" ORDER BY "o"."total")`, false},
+		{"aggregate with frame", SqlFunc("SUM(amount)").Over().OrderBy("id").Frame("ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"), sqldialect.NoQuoteIdent(), "SUM(amount) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)", false},
+		{"lag", Lag("price", 1).Over().OrderBy("day"), sqldialect.NoQuoteIdent(), "LAG(price, 1) OVER (ORDER BY day)", false},
+		{"ntile", NTile(4).Over().OrderBy("score DESC"), sqldialect.NoQuoteIdent(), "NTILE(4) OVER (ORDER BY score DESC)", false},
+		{"nulls last", RowNumber().Over().OrderBy("e.created_at desc nulls last", "id NULLS FIRST"), sqldialect.Postgres(), `ROW_NUMBER() OVER (ORDER BY "e"."created_at" DESC NULLS LAST, "id" NULLS FIRST)`, false},
+		{"invalid direction", RowNumber().Over().OrderBy("id; DROP TABLE users"), sqldialect.NoQuoteIdent(), "", true},
+		{"nulls without position", RowNumber().Over().OrderBy("id DESC NULLS"), sqldialect.NoQuoteIdent(), "", true},
+		{"empty order", RowNumber().Over().OrderBy(" "), sqldialect.NoQuoteIdent(), "", true},
+		{"unsafe frame", RowNumber().Over().Frame("ROWS 1; DROP TABLE users --"), sqldialect.NoQuoteIdent(), "", true},
+		{"missing function", SqlFunc("").Over(), sqldialect.NoQuoteIdent(), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.expr.SQL(tt.dialect)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
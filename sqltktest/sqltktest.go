@@ -0,0 +1,165 @@
+// Package sqltktest provides test helpers for comparing the SQL and arguments built by sqltk builders.
+package sqltktest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/internal/sqllex"
+)
+
+// TB is the subset of testing.TB used by the helpers in this package.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertBuild builds b and reports a test error if the build fails or the SQL or arguments
+// differ from the expected ones. Differences are reported as a token-level SQL diff and a
+// table of arguments; see Diff.
+//
+// Example usage:
+//
+//	sqltktest.AssertBuild(t, q, "SELECT id FROM users WHERE status = ?", "active")
+func AssertBuild(t TB, b sqltk.Builder, wantSQL string, wantArgs ...interface{}) {
+	t.Helper()
+	gotSQL, gotArgs, err := b.Build()
+	if err != nil {
+		t.Errorf("unexpected build error: %v", err)
+		return
+	}
+	if diff := Diff(gotSQL, wantSQL, gotArgs, wantArgs); diff != "" {
+		t.Errorf("built query differs (-want +got):\n%s", diff)
+	}
+}
+
+// Diff compares built SQL and arguments with expected ones and returns a readable report,
+// or "" if they are equal. A nil and an empty argument list are equal.
+//
+// SQL differences are shown inline on the tokens that differ, with -[want]- and +{got}+
+// markers. Arguments are shown in a table with the differing rows marked by "!".
+func Diff(gotSQL, wantSQL string, gotArgs, wantArgs []interface{}) string {
+	var sb strings.Builder
+	if gotSQL != wantSQL {
+		wantTokens, gotTokens := tokenize(wantSQL), tokenize(gotSQL)
+		if slices.Equal(wantTokens, gotTokens) {
+			fmt.Fprintf(&sb, "SQL differs only in whitespace:\n  want: %q\n  got:  %q\n", wantSQL, gotSQL)
+		} else {
+			sb.WriteString("SQL:\n  ")
+			sb.WriteString(diffTokens(wantTokens, gotTokens))
+			sb.WriteString("\n")
+		}
+	}
+	if !argsEqual(gotArgs, wantArgs) {
+		sb.WriteString("args:\n")
+		sb.WriteString(argsTable(gotArgs, wantArgs))
+	}
+	return sb.String()
+}
+
+// tokenize splits SQL into tokens with sqllex, keeping qualified names such as u.id whole.
+// Whitespace is dropped, so queries that differ only in spacing tokenize the same way.
+func tokenize(sql string) []string {
+	var tokens []string
+	name, dot := false, false // the last token ends with a name, or with the "." after one
+	for _, t := range (sqllex.Lexer{}).Tokens(sql) {
+		isName := t.Kind == sqllex.Word || t.Kind == sqllex.Quoted
+		switch {
+		case dot && !t.Space && (isName || t.IsPunct("*")):
+			tokens[len(tokens)-1] += t.Text
+			name, dot = true, false
+		case name && !t.Space && t.IsPunct("."):
+			tokens[len(tokens)-1] += t.Text
+			name, dot = false, true
+		default:
+			tokens = append(tokens, t.Text)
+			name, dot = isName, false
+		}
+	}
+	return tokens
+}
+
+// diffTokens renders the tokens of got with the changes from want marked inline.
+func diffTokens(want, got []string) string {
+	// Longest common subsequence table over the token lists.
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out, removed, added []string
+	flush := func() {
+		if len(removed) > 0 {
+			out = append(out, "-["+strings.Join(removed, " ")+"]-")
+			removed = nil
+		}
+		if len(added) > 0 {
+			out = append(out, "+{"+strings.Join(added, " ")+"}+")
+			added = nil
+		}
+	}
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			flush()
+			out = append(out, want[i])
+			i++
+			j++
+		case j < len(got) && (i == len(want) || lcs[i][j+1] >= lcs[i+1][j]):
+			added = append(added, got[j])
+			j++
+		default:
+			removed = append(removed, want[i])
+			i++
+		}
+	}
+	flush()
+	return strings.Join(out, " ")
+}
+
+func argsEqual(got, want []interface{}) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+// argsTable renders got and want arguments side by side, one row per position.
+func argsTable(got, want []interface{}) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  \t#\twant\tgot")
+	for i := 0; i < max(len(got), len(want)); i++ {
+		wantCell, gotCell := "-", "-"
+		var wantVal, gotVal interface{}
+		if i < len(want) {
+			wantVal = want[i]
+			wantCell = fmt.Sprintf("%#v", wantVal)
+		}
+		if i < len(got) {
+			gotVal = got[i]
+			gotCell = fmt.Sprintf("%#v", gotVal)
+		}
+		mark := " "
+		if i >= len(want) || i >= len(got) || !reflect.DeepEqual(wantVal, gotVal) {
+			mark = "!"
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%s\t%s\n", mark, i+1, wantCell, gotCell)
+	}
+	w.Flush()
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package sqltktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+type fakeTB struct {
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		gotSQL   string
+		wantSQL  string
+		gotArgs  []interface{}
+		wantArgs []interface{}
+		want     string
+	}{
+		{
+			name:    "equal",
+			gotSQL:  "SELECT id FROM users WHERE id = ?",
+			wantSQL: "SELECT id FROM users WHERE id = ?",
+			gotArgs: []interface{}{1}, wantArgs: []interface{}{1},
+			want: "",
+		},
+		{
+			name:    "nil and empty args are equal",
+			gotSQL:  "SELECT 1",
+			wantSQL: "SELECT 1",
+			gotArgs: []interface{}{},
+			want:    "",
+		},
+		{
+			name:    "changed token",
+			gotSQL:  "SELECT id FROM users WHERE state = ? ORDER BY id",
+			wantSQL: "SELECT id FROM users WHERE status = ? ORDER BY id",
+			want:    "SQL:\n  SELECT id FROM users WHERE -[status]- +{state}+ = ? ORDER BY id\n",
+		},
+		{
+			name:    "added and removed tokens",
+			gotSQL:  "SELECT id, name FROM users",
+			wantSQL: "SELECT id FROM users LIMIT 10",
+			want:    "SQL:\n  SELECT id +{, name}+ FROM users -[LIMIT 10]-\n",
+		},
+		{
+			name:    "quoted tokens",
+			gotSQL:  "SELECT `id` FROM `users`",
+			wantSQL: `SELECT "id" FROM "users"`,
+			want:    "SQL:\n  SELECT -[\"id\"]- +{`id`}+ FROM -[\"users\"]- +{`users`}+\n",
+		},
+		{
+			name:    "qualified names and strings",
+			gotSQL:  "SELECT u.id FROM users u WHERE u.name = 'a b' AND n = $1",
+			wantSQL: "SELECT u.id FROM users u WHERE u.name = 'a c' AND n = $2",
+			want:    "SQL:\n  SELECT u.id FROM users u WHERE u.name = -['a c']- +{'a b'}+ AND n = -[$2]- +{$1}+\n",
+		},
+		{
+			name:    "whitespace only",
+			gotSQL:  "SELECT id  FROM users",
+			wantSQL: "SELECT id FROM users",
+			want:    "SQL differs only in whitespace:\n  want: \"SELECT id FROM users\"\n  got:  \"SELECT id  FROM users\"\n",
+		},
+		{
+			name:     "args table",
+			gotSQL:   "SELECT 1",
+			wantSQL:  "SELECT 1",
+			gotArgs:  []interface{}{"active", 7},
+			wantArgs: []interface{}{"active", "7", true},
+			want: "args:\n" +
+				"     #  want      got\n" +
+				"     1  \"active\"  \"active\"\n" +
+				"  !  2  \"7\"       7\n" +
+				"  !  3  true      -\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.gotSQL, tt.wantSQL, tt.gotArgs, tt.wantArgs)
+			if got != tt.want {
+				t.Errorf("got diff:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertBuild(t *testing.T) {
+	q := sqltk.Select("id").From("users").WhereEqual("status", "active").WithDialect(sqldialect.NoQuoteIdent())
+
+	tb := &fakeTB{}
+	AssertBuild(tb, q, "SELECT id FROM users WHERE status = ?", "active")
+	if len(tb.errors) != 0 {
+		t.Errorf("got errors %v, want none", tb.errors)
+	}
+
+	tb = &fakeTB{}
+	AssertBuild(tb, q, "SELECT id FROM users WHERE status = ?", "inactive")
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], `"inactive"`) {
+		t.Errorf("got errors %v, want one args diff", tb.errors)
+	}
+
+	tb = &fakeTB{}
+	AssertBuild(tb, sqltk.Select("id"), "SELECT id")
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "build error") {
+		t.Errorf("got errors %v, want one build error", tb.errors)
+	}
+}
@@ -0,0 +1,257 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// structField is a field of a struct mapped to a column, see SelectT and InsertStruct.
+type structField struct {
+	column    string
+	index     []int // index path of the field
+	auto      bool  // set by the database, tag option auto
+	omitEmpty bool  // tag option omitempty
+}
+
+// structFields returns the fields of a struct type mapped to columns, with their index paths
+// prefixed with index. The column of a field is its db tag, or the field name in snake_case;
+// fields tagged db:"-" and unexported fields are skipped, and the fields of embedded structs
+// are included.
+func structFields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag, tagged := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		path := append(index[:len(index):len(index)], i)
+		if f.Anonymous && !tagged {
+			t := f.Type
+			if t.Kind() == reflect.Pointer {
+				// Scanning would need to allocate the embedded struct.
+				continue
+			}
+			if t.Kind() == reflect.Struct {
+				fields = append(fields, structFields(t, path)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = snakeCase(f.Name)
+		}
+		sf := structField{column: name, index: path}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "auto":
+				sf.auto = true
+			case "omitempty":
+				sf.omitEmpty = true
+			}
+		}
+		fields = append(fields, sf)
+	}
+	return fields
+}
+
+// snakeCase returns a Go name in snake_case, e.g. user_id for UserID.
+func snakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// structValues returns the struct values of v, a struct, a pointer to one, or a slice of
+// either, and their type. It is an error if v holds no struct, or a nil pointer.
+func structValues(v interface{}) (reflect.Type, []reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, nil, errors.New("value must not be nil")
+	}
+	var values []reflect.Value
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i))
+		}
+	} else {
+		values = []reflect.Value{rv}
+	}
+
+	var typ reflect.Type
+	for i, value := range values {
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, nil, fmt.Errorf("value %d is nil", i+1)
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("%s is not a struct", value.Type())
+		}
+		if typ == nil {
+			typ = value.Type()
+		} else if value.Type() != typ {
+			return nil, nil, fmt.Errorf("values of types %s and %s cannot be mixed", typ, value.Type())
+		}
+		values[i] = value
+	}
+	return typ, values, nil
+}
+
+// InsertStruct starts an INSERT of v, a struct or a pointer to one, into table. The columns
+// are those of SelectT; the tag option auto leaves out a column the database sets, such as a
+// serial ID, and omitempty leaves out a column whose field has the zero value, so that the
+// column default applies.
+//
+// Example usage:
+//
+//	type User struct {
+//		ID        int64     `db:"id,auto"`
+//		Email     string    `db:"email"`
+//		CreatedAt time.Time `db:"created_at,omitempty"`
+//	}
+//
+//	q := sqltk.InsertStruct("users", &User{Email: "a@example.com"})
+//	// INSERT INTO users (email) VALUES (?)
+func InsertStruct(table string, v interface{}) *InsertBuilder {
+	b := Insert(table)
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		b.err = fmt.Errorf("InsertStruct: got a %T, use InsertStructs", v)
+		return b
+	}
+	typ, values, err := structValues(v)
+	if err != nil {
+		b.err = fmt.Errorf("InsertStruct: %w", err)
+		return b
+	}
+	return b.structRows("InsertStruct", typ, values)
+}
+
+// InsertStructs starts an INSERT of the elements of rows, a slice of structs or of pointers to
+// structs, into table, one row each; see InsertStruct. A column tagged omitempty is left out
+// only if it is zero in every row. Use BuildBatches for many rows.
+func InsertStructs(table string, rows interface{}) *InsertBuilder {
+	b := Insert(table)
+	if rv := reflect.ValueOf(rows); rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		b.err = fmt.Errorf("InsertStructs: rows must be a slice (got %T)", rows)
+		return b
+	}
+	typ, values, err := structValues(rows)
+	if err == nil && len(values) == 0 {
+		err = errors.New("rows must not be empty")
+	}
+	if err != nil {
+		b.err = fmt.Errorf("InsertStructs: %w", err)
+		return b
+	}
+	return b.structRows("InsertStructs", typ, values)
+}
+
+// structRows sets the columns and rows of the insert from struct values of type typ.
+func (b *InsertBuilder) structRows(op string, typ reflect.Type, values []reflect.Value) *InsertBuilder {
+	var fields []structField
+	for _, f := range structFields(typ, nil) {
+		if f.auto {
+			continue
+		}
+		if f.omitEmpty {
+			empty := true
+			for _, v := range values {
+				if !v.FieldByIndex(f.index).IsZero() {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		b.err = fmt.Errorf("%s: %s has no columns to insert", op, typ)
+		return b
+	}
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+	b.Columns(columns...)
+	for _, v := range values {
+		row := make([]interface{}, len(fields))
+		for i, f := range fields {
+			row[i] = v.FieldByIndex(f.index).Interface()
+		}
+		b.Values(row...)
+	}
+	return b
+}
+
+// StructOption configures how the fields of a struct are mapped to columns, see SetStruct.
+type StructOption func(*structOptions)
+
+type structOptions struct {
+	onlyNonZero bool
+}
+
+// OnlyNonZero leaves out the fields with the zero value, as for a PATCH that changes only the
+// fields it was given. Use a pointer field to set a column to the zero value of its type.
+func OnlyNonZero() StructOption {
+	return func(o *structOptions) {
+		o.onlyNonZero = true
+	}
+}
+
+// SetStruct adds a SET clause for each field of v, a struct or a pointer to one, with the
+// columns of InsertStruct: fields tagged auto are left out, as are zero fields tagged omitempty,
+// or all zero fields with OnlyNonZero.
+//
+// Example usage:
+//
+//	type UserPatch struct {
+//		Email *string `db:"email"`
+//		Name  *string `db:"name"`
+//	}
+//
+//	q := sqltk.Update("users").SetStruct(patch, sqltk.OnlyNonZero()).WhereEqual("id", id)
+//	// UPDATE users SET email = ? WHERE id = ?, if only Email is set
+func (b *UpdateBuilder) SetStruct(v interface{}, opts ...StructOption) *UpdateBuilder {
+	var o structOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		b.whereClause.addErr(fmt.Errorf("SetStruct: %T is not a struct", v))
+		return b
+	}
+	typ, values, err := structValues(v)
+	if err != nil {
+		b.whereClause.addErr(fmt.Errorf("SetStruct: %w", err))
+		return b
+	}
+	for _, f := range structFields(typ, nil) {
+		value := values[0].FieldByIndex(f.index)
+		if f.auto || (f.omitEmpty || o.onlyNonZero) && value.IsZero() {
+			continue
+		}
+		b.Set(f.column, value.Interface())
+	}
+	return b
+}
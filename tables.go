@@ -0,0 +1,151 @@
+package sqltk
+
+import "strings"
+
+// subqueryCondition is implemented by conditions that embed subqueries, so that the builders
+// they are added to can report the tables of the subqueries.
+type subqueryCondition interface {
+	subqueryBuilders() []*SelectBuilder
+}
+
+func (c *ConditionBuilder) subqueryBuilders() []*SelectBuilder {
+	return c.subqueries
+}
+
+// condSubqueries returns the subqueries embedded in cond, if it records them.
+func condSubqueries(cond Condition) []*SelectBuilder {
+	if sc, ok := cond.(subqueryCondition); ok {
+		return sc.subqueryBuilders()
+	}
+	return nil
+}
+
+// tableSet collects table names in the order they are first seen, ignoring case.
+type tableSet struct {
+	names []string
+	seen  map[string]bool
+}
+
+// add adds the table name of a string table reference such as "users u".
+func (ts *tableSet) add(table string) {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return
+	}
+	name := fields[0]
+	if ts.seen == nil {
+		ts.seen = make(map[string]bool)
+	}
+	if key := strings.ToLower(name); !ts.seen[key] {
+		ts.seen[key] = true
+		ts.names = append(ts.names, name)
+	}
+}
+
+// addExpr adds the tables of a table or column expression.
+func (ts *tableSet) addExpr(expr interface{}) {
+	switch e := expr.(type) {
+	case string:
+		ts.add(e)
+	case Identifier:
+		ts.add(e.String())
+	case *SelectBuilder:
+		ts.addSelect(e)
+	case AliasExpr:
+		ts.addExpr(e.Expr)
+	case derivedTable:
+		ts.addSelect(e.query)
+	case topNTable:
+		ts.addSelect(e.query)
+	case existsExpr:
+		ts.addSelect(e.query)
+	}
+}
+
+// addSelect adds the tables of a SELECT and its subqueries.
+func (ts *tableSet) addSelect(b *SelectBuilder) {
+	if b == nil {
+		return
+	}
+	ts.addExpr(b.tableClauseInterface.table)
+	for _, t := range b.fromTables {
+		ts.addExpr(t)
+	}
+	for _, j := range b.joins {
+		ts.addExpr(j.table)
+	}
+	for _, f := range b.filterJoins {
+		ts.addExpr(f.table)
+	}
+	for _, col := range b.columns {
+		switch col.(type) {
+		case *SelectBuilder, AliasExpr, existsExpr:
+			ts.addExpr(col)
+		}
+	}
+	ts.addSubqueries(b.whereClause.subqueries)
+	ts.addSubqueries(b.havingSubqueries)
+}
+
+func (ts *tableSet) addSubqueries(subqueries []*SelectBuilder) {
+	for _, sub := range subqueries {
+		ts.addSelect(sub)
+	}
+}
+
+// GetTables returns every table the query references, in FROM, JOINs, semi- and anti-joins,
+// and subqueries in columns, FROM, JOINs, and conditions built with ConditionBuilder or AsExists,
+// in the order they first appear, without aliases or duplicates. Names are returned as given,
+// e.g. "public.users". Tables inside raw SQL, such as raw.Raw tables or NewStringCondition
+// conditions, cannot be seen and are not returned.
+//
+// Example usage:
+//
+//	q := Select("u.id").From("users u").Join("orders o").On("o.user_id", "u.id").
+//		Where(NewCond().InQuery("u.id", Select("user_id").From("admins")))
+//	q.GetTables() // ["users", "orders", "admins"]
+func (b *SelectBuilder) GetTables() []string {
+	var ts tableSet
+	ts.addSelect(b)
+	return ts.names
+}
+
+// GetTables returns the tables the compound query references. See SelectBuilder.GetTables.
+func (b *CompoundBuilder) GetTables() []string {
+	var ts tableSet
+	for _, p := range b.parts {
+		ts.addSelect(p.query)
+	}
+	return ts.names
+}
+
+// GetTables returns the table the INSERT writes to. See SelectBuilder.GetTables.
+func (b *InsertBuilder) GetTables() []string {
+	var ts tableSet
+	ts.add(b.table)
+	return ts.names
+}
+
+// GetTables returns the table the UPDATE writes to, followed by the joined and FROM tables and
+// the tables of subqueries in its conditions. See SelectBuilder.GetTables.
+func (b *UpdateBuilder) GetTables() []string {
+	var ts tableSet
+	ts.add(b.table)
+	for _, j := range b.joins {
+		ts.add(j.table)
+	}
+	for _, t := range b.from {
+		ts.add(t)
+	}
+	ts.addSubqueries(b.whereClause.subqueries)
+	return ts.names
+}
+
+// GetTables returns the table the DELETE removes rows from, followed by the tables of
+// subqueries in its conditions. See SelectBuilder.GetTables.
+func (b *DeleteBuilder) GetTables() []string {
+	var ts tableSet
+	ts.add(b.table)
+	ts.addSubqueries(b.whereClause.subqueries)
+	return ts.names
+}
@@ -0,0 +1,89 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+)
+
+func TestGetTables(t *testing.T) {
+	admins := func() *SelectBuilder { return Select("user_id").From("admins") }
+
+	tests := []struct {
+		name    string
+		builder interface{ GetTables() []string }
+		want    []string
+	}{
+		{
+			name:    "from and joins",
+			builder: Select("u.id").From("users u", "accounts a").Join("orders o").On("o.user_id", "u.id").LeftJoin(Alias("payments", "p")).On("p.order_id", "o.id"),
+			want:    []string{"users", "accounts", "orders", "payments"},
+		},
+		{
+			name: "subqueries",
+			builder: Select("u.id", Alias(Select(raw.Raw("COUNT(*)")).From("logins"), "n")).
+				From(Alias(Select("id").From("public.users"), "u")).
+				Join(Alias(Select("user_id").From("orders"), "o")).On("o.user_id", "u.id").
+				Where(NewCond().InQuery("u.id", admins())).
+				WhereExists(Select("1").From("sessions")).
+				Having(NewCond().NotIn("u.id", Select("id").From("banned"))),
+			want: []string{"public.users", "orders", "logins", "admins", "sessions", "banned"},
+		},
+		{
+			name: "combined and grouped conditions",
+			builder: Select("id").From("users").
+				Where(NewCond().Equal("a", 1).Or(NewCond().InQuery("id", admins()))).
+				WhereGroup(func(c *ConditionBuilder) { c.RowIn([]string{"id"}, Select("id").From("owners")) }).
+				OrWhere(Select("1").From("tokens").AsExists()),
+			want: []string{"users", "admins", "owners", "tokens"},
+		},
+		{
+			name:    "semi-join, derived queries, and duplicates",
+			builder: Select("id").From("users").SemiJoin(Alias("orders", "o"), JoinKey{Left: "users.id", Right: "o.user_id"}).Join("USERS x").On("x.id", "users.id").ToCount(),
+			want:    []string{"users", "orders"},
+		},
+		{
+			name:    "raw tables are not seen",
+			builder: Select("id").From(raw.Raw("generate_series(1, 3) AS n")).Where(NewStringCondition("id IN (SELECT id FROM hidden)")),
+			want:    nil,
+		},
+		{
+			name:    "compound",
+			builder: Select("id").From("users").Union(Select("id").From("admins")),
+			want:    []string{"users", "admins"},
+		},
+		{
+			name:    "insert",
+			builder: Insert("users").Columns("id").Values(1),
+			want:    []string{"users"},
+		},
+		{
+			name:    "update",
+			builder: Update("users").Set("active", false).Where(NewCond().InQuery("id", admins())),
+			want:    []string{"users", "admins"},
+		},
+		{
+			name:    "delete",
+			builder: Delete("users").WhereNotExists(Select("1").From("orders")),
+			want:    []string{"users", "orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.builder.GetTables(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got tables %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("subquery changed after use", func(t *testing.T) {
+		sub := admins()
+		q := Select("id").From("users").Where(NewCond().InQuery("id", sub))
+		sub.From("owners")
+		if got, want := q.GetTables(), []string{"users", "admins"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got tables %q, want %q", got, want)
+		}
+	})
+}
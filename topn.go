@@ -0,0 +1,155 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// topNRankColumn is the ROW_NUMBER() column added to the inner query of TopNPerGroup.
+const topNRankColumn = "sqltk_rn"
+
+// topNTable is the FROM table of a query built by TopNPerGroup.
+type topNTable struct {
+	query     *SelectBuilder
+	n         int
+	partition []string
+	order     orderTerm
+}
+
+// TopNPerGroup returns a new builder selecting at most n rows of this query for each distinct
+// value of partitionCols, taking the first rows by orderExpr. orderExpr is an ORDER BY term
+// as accepted by OrderBy, e.g. "created_at DESC".
+//
+// On Postgres the rows are taken with a LATERAL subquery per group:
+//
+//	SELECT t.* FROM (SELECT DISTINCT partition FROM ...) AS g
+//	CROSS JOIN LATERAL (query AND partition = g.partition ORDER BY orderExpr LIMIT n) AS t
+//
+// Other dialects number the rows with ROW_NUMBER() and keep the first n:
+//
+//	SELECT t.* FROM (SELECT ..., ROW_NUMBER() OVER (PARTITION BY partition ORDER BY orderExpr) AS sqltk_rn
+//	FROM ...) AS t WHERE sqltk_rn <= n
+//
+// The sqltk_rn column is part of the result on those dialects. ORDER BY, LIMIT, OFFSET, and
+// locking are dropped from this query; set them on the returned builder instead. Like ToCount,
+// the inner query is rendered with the dialect of the new builder.
+//
+// Example usage:
+//
+//	q := sqltk.Select("id", "user_id", "total").From("orders").WhereEqual("status", "paid")
+//	latest := q.TopNPerGroup(3, []string{"user_id"}, "created_at DESC")
+func (b *SelectBuilder) TopNPerGroup(n int, partitionCols []string, orderExpr interface{}) *SelectBuilder {
+	out := &SelectBuilder{
+		columns:     []interface{}{raw.Raw("t.*")},
+		maxExecTime: b.maxExecTime,
+		dialect:     b.dialect,
+	}
+	if n <= 0 {
+		out.whereClause.err = errors.New("TopNPerGroup: n must be positive")
+		return out
+	}
+	if len(partitionCols) == 0 {
+		out.whereClause.err = errors.New("TopNPerGroup: at least one partition column is required")
+		return out
+	}
+	for _, col := range partitionCols {
+		if !isPlainIdent(col) {
+			out.whereClause.err = errors.New("TopNPerGroup: partition columns must be column names")
+			return out
+		}
+	}
+	order, err := newOrderTerm(orderExpr)
+	if err != nil {
+		out.whereClause.err = fmt.Errorf("TopNPerGroup: %w", err)
+		return out
+	}
+
+	inner := *b
+	inner.orderBy = nil
+	inner.limitSet, inner.limit = false, 0
+	inner.offsetSet, inner.offset = false, 0
+	inner.lock, inner.lockOption = "", ""
+	inner.maxExecTime = 0
+	inner.seek = nil
+
+	out.tableClauseInterface.table = topNTable{
+		query:     &inner,
+		n:         n,
+		partition: append([]string(nil), partitionCols...),
+		order:     order,
+	}
+	return out
+}
+
+// lateral reports whether the table is rendered as a LATERAL join for the dialect.
+func (t topNTable) lateral(dialect sqldialect.Dialect) bool {
+	return baseDialect(dialect) == sqldialect.Postgres()
+}
+
+// build renders the FROM table with ? placeholders, like buildDerived.
+func (t topNTable) build(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if !t.lateral(dialect) {
+		ranked := *t.query
+		cols := ranked.columns
+		if len(cols) == 0 {
+			cols = []interface{}{raw.Raw("*")}
+		}
+		partition := make([]string, len(t.partition))
+		for i, col := range t.partition {
+			partition[i] = quoteQualifiedIdent(dialect, col)
+		}
+		orderBys, orderArgs, err := buildOrderBys(dialect, []orderTerm{t.order})
+		if err != nil {
+			return "", nil, err
+		}
+		rank := "ROW_NUMBER() OVER (PARTITION BY " + strings.Join(partition, ", ") +
+			" ORDER BY " + strings.Join(orderBys, ", ") + ") AS " + topNRankColumn
+		ranked.columns = append(cols[:len(cols):len(cols)], Expr(rank, orderArgs...))
+
+		sql, args, err := buildDerived(&ranked, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + sql + ")" + asTableAlias(dialect, "t"), args, nil
+	}
+
+	groups := *t.query
+	groups.columns = make([]interface{}, len(t.partition))
+	for i, col := range t.partition {
+		groups.columns[i] = col
+	}
+	groups.distinct = true
+	groupsSQL, groupsArgs, err := buildDerived(&groups, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	perGroup := *t.query
+	perGroup.whereClause = t.query.whereClause.clone()
+	for _, col := range t.partition {
+		name := col[strings.LastIndex(col, ".")+1:]
+		perGroup.whereClause.whereParam = append(perGroup.whereClause.whereParam,
+			quoteQualifiedIdent(dialect, col)+" = g."+dialect.QuoteIdent(name))
+	}
+	perGroup.orderBy = []orderTerm{t.order}
+	perGroup.limitSet, perGroup.limit = true, t.n
+	perGroupSQL, perGroupArgs, err := buildDerived(&perGroup, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql := "(" + groupsSQL + ")" + asTableAlias(dialect, "g") + " CROSS JOIN LATERAL (" + perGroupSQL + ")" + asTableAlias(dialect, "t")
+	return sql, append(groupsArgs, perGroupArgs...), nil
+}
+
+// condition renders the WHERE condition that keeps the first n rows, if the dialect needs one.
+func (t topNTable) condition(dialect sqldialect.Dialect) string {
+	if t.lateral(dialect) {
+		return ""
+	}
+	return topNRankColumn + " <= " + intToString(t.n)
+}
@@ -0,0 +1,84 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestSelectTopNPerGroup(t *testing.T) {
+	orders := func() *SelectBuilder {
+		return Select("id", "user_id", "total").From("orders").WhereEqual("status", "paid").OrderByDesc("id").Limit(100)
+	}
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "row number on mysql",
+			builder:  orders().TopNPerGroup(3, []string{"user_id"}, "created_at DESC"),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "SELECT t.* FROM (SELECT `id`, `user_id`, `total`, ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC) AS sqltk_rn FROM `orders` WHERE status = ?) AS t WHERE sqltk_rn <= 3",
+			wantArgs: []interface{}{"paid"},
+		},
+		{
+			name:     "lateral on postgres",
+			builder:  orders().TopNPerGroup(3, []string{"user_id"}, "created_at DESC").WhereGreaterThan("total", 10),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `SELECT t.* FROM (SELECT DISTINCT "user_id" FROM "orders" WHERE status = $1) AS g CROSS JOIN LATERAL (SELECT "id", "user_id", "total" FROM "orders" WHERE status = $2 AND "user_id" = g."user_id" ORDER BY "created_at" DESC LIMIT 3) AS t WHERE total > $3`,
+			wantArgs: []interface{}{"paid", "paid", 10},
+		},
+		{
+			name:     "select star with several partition columns",
+			builder:  Select().From("events").TopNPerGroup(1, []string{"tenant_id", "kind"}, "at").OrderBy("tenant_id"),
+			dialect:  sqldialect.DuckDB(),
+			wantSQL:  `SELECT t.* FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY "tenant_id", "kind" ORDER BY "at") AS sqltk_rn FROM "events") AS t WHERE sqltk_rn <= 1 ORDER BY "tenant_id"`,
+			wantArgs: []interface{}{},
+		},
+		{
+			name:    "n must be positive",
+			builder: orders().TopNPerGroup(0, []string{"user_id"}, "created_at"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "partition columns required",
+			builder: orders().TopNPerGroup(1, nil, "created_at"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "partition column must be a name",
+			builder: orders().TopNPerGroup(1, []string{"LOWER(email)"}, "created_at"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
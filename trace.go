@@ -0,0 +1,176 @@
+package sqltk
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+// traceCallSites is set by SetTraceCallSites.
+var traceCallSites atomic.Bool
+
+// SetTraceCallSites enables or disables recording the call site of builder calls, which
+// BuildTrace reports for each clause. Recording uses runtime.Callers on every builder call,
+// so enable it only while debugging. Calls made while it is disabled are not recorded.
+func SetTraceCallSites(enabled bool) {
+	traceCallSites.Store(enabled)
+}
+
+// Trace is the result of BuildTrace: the built query and the clauses it is made of.
+type Trace struct {
+	SQL   string
+	Args  []interface{}
+	Spans []TraceSpan
+}
+
+// TraceSpan is the part of a built query produced by one clause.
+type TraceSpan struct {
+	Clause string // e.g. "SELECT", "JOIN", "WHERE", or "CLAUSE" for custom clauses
+	Start  int    // byte offset of the span in Trace.SQL
+	End    int
+	SQL    string
+	// CallSites are the file:line locations of the builder calls that contributed to the
+	// clause, if call-site tracing was enabled with SetTraceCallSites.
+	CallSites []string
+}
+
+// String renders the trace as a table with one clause per line.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, s := range t.Spans {
+		fmt.Fprintf(w, "%s\t[%d:%d]\t%s\t%s\n", s.Clause, s.Start, s.End, s.SQL, strings.Join(s.CallSites, ", "))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// BuildTrace builds the query like Build and also returns the SQL span produced by each clause,
+// with the call sites of the builder calls behind it when SetTraceCallSites is enabled.
+//
+// Example usage:
+//
+//	sqltk.SetTraceCallSites(true)
+//	q := sqltk.Select("id").From("users").WhereEqual("status", "active")
+//	trace, err := q.BuildTrace()
+//	fmt.Print(trace)
+//	// SELECT  [0:11]   SELECT `id`       main.go:12
+//	// FROM    [12:24]  FROM `users`      main.go:12
+//	// WHERE   [25:43]  WHERE status = ?  main.go:12
+func (b *SelectBuilder) BuildTrace() (*Trace, error) {
+	tr := &buildTracer{}
+	sql, args, err := b.build(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &Trace{SQL: sql, Args: args}
+	for i, m := range tr.marks {
+		end := len(sql)
+		if i+1 < len(tr.marks) {
+			end = tr.marks[i+1].start
+		}
+		start := m.start
+		for start < end && sql[start] == ' ' {
+			start++
+		}
+		if start == end {
+			continue
+		}
+		var sites []string
+		if m.clause == "WHERE" {
+			sites = b.whereClause.callSites
+		} else {
+			sites = b.callSites[m.clause]
+		}
+		trace.Spans = append(trace.Spans, TraceSpan{
+			Clause:    m.clause,
+			Start:     start,
+			End:       end,
+			SQL:       sql[start:end],
+			CallSites: append([]string(nil), sites...),
+		})
+	}
+	return trace, nil
+}
+
+// buildTracer records where each clause starts while a query is built.
+type buildTracer struct {
+	marks []traceMark
+}
+
+type traceMark struct {
+	clause string
+	start  int
+}
+
+// mark records that clause starts at offset start. It is a no-op on a nil tracer.
+func (t *buildTracer) mark(clause string, start int) {
+	if t == nil {
+		return
+	}
+	t.marks = append(t.marks, traceMark{clause: clause, start: start})
+}
+
+// number numbers the placeholders of sql for dialect, as numberPlaceholders does with numbers,
+// moving the recorded marks to match. It also works on a nil tracer.
+func (t *buildTracer) number(dialect sqldialect.Dialect, sql string, numbers []int) string {
+	placeholderIdx := 1
+	if t == nil || len(t.marks) == 0 {
+		return numberPlaceholders(dialect, sql, &placeholderIdx, numbers)
+	}
+	var sb strings.Builder
+	sb.WriteString(numberPlaceholders(dialect, sql[:t.marks[0].start], &placeholderIdx, numbers))
+	for i := range t.marks {
+		end := len(sql)
+		if i+1 < len(t.marks) {
+			end = t.marks[i+1].start
+		}
+		segment := sql[t.marks[i].start:end]
+		t.marks[i].start = sb.Len()
+		sb.WriteString(numberPlaceholders(dialect, segment, &placeholderIdx, numbers))
+	}
+	return sb.String()
+}
+
+// recordCall records the call site of a builder call contributing to clause, if enabled.
+func (b *SelectBuilder) recordCall(clause string) {
+	if site := callSite(); site != "" {
+		if b.callSites == nil {
+			b.callSites = make(map[string][]string)
+		}
+		b.callSites[clause] = append(b.callSites[clause], site)
+	}
+}
+
+// recordCall records the call site of a WHERE call, if enabled.
+func (w *whereClause) recordCall() {
+	if site := callSite(); site != "" {
+		w.callSites = append(w.callSites, site)
+	}
+}
+
+// callSite returns the file:line of the first caller outside this package, or "" if
+// call-site tracing is disabled. Test files of this package count as callers.
+func callSite() string {
+	if !traceCallSites.Load() {
+		return ""
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/sprylic/sqltk.") || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package sqltk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+type traceSpanWant struct {
+	clause string
+	sql    string
+}
+
+func TestBuildTrace(t *testing.T) {
+	tests := []struct {
+		name      string
+		builder   *SelectBuilder
+		wantSpans []traceSpanWant
+		wantErr   bool
+	}{
+		{
+			name: "all clauses",
+			builder: Select("u.id", "COUNT(*) AS n").WithDialect(sqldialect.Postgres()).From("users u").
+				Join("orders o").On("o.user_id", "u.id").
+				WhereEqual("u.status", "active").
+				GroupBy("u.id").
+				Having(NewStringCondition("COUNT(*) > ?", 1)).
+				OrderByDesc("n").
+				Limit(10).Offset(20).
+				ForUpdate(),
+			wantSpans: []traceSpanWant{
+				{"SELECT", `SELECT "u"."id", COUNT(*) AS "n"`},
+				{"FROM", `FROM "users u"`},
+				{"JOIN", `JOIN "orders o" ON o.user_id = u.id`},
+				{"WHERE", `WHERE u.status = $1`},
+				{"GROUP BY", `GROUP BY "u"."id"`},
+				{"HAVING", `HAVING COUNT(*) > $2`},
+				{"ORDER BY", `ORDER BY "n" DESC`},
+				{"LIMIT", `LIMIT 10`},
+				{"OFFSET", `OFFSET 20`},
+				{"LOCK", `FOR UPDATE`},
+			},
+		},
+		{
+			name: "custom clause",
+			builder: Select("id").WithDialect(sqldialect.Postgres()).From("events").
+				AddClause(RawClause(AfterFrom, "PREWHERE day = ?", "2024-01-01")).
+				WhereEqual("kind", "click"),
+			wantSpans: []traceSpanWant{
+				{"SELECT", `SELECT "id"`},
+				{"FROM", `FROM "events"`},
+				{"CLAUSE", `PREWHERE day = $1`},
+				{"WHERE", `WHERE kind = $2`},
+			},
+		},
+		{
+			name:    "build error",
+			builder: Select("id").From("users").Where(nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace, err := tt.builder.BuildTrace()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wantSQL, wantArgs, _ := tt.builder.Build()
+			if trace.SQL != wantSQL {
+				t.Errorf("got SQL %q, want %q", trace.SQL, wantSQL)
+			}
+			if !reflect.DeepEqual(trace.Args, wantArgs) {
+				t.Errorf("got args %#v, want %#v", trace.Args, wantArgs)
+			}
+			var got []traceSpanWant
+			for _, s := range trace.Spans {
+				if trace.SQL[s.Start:s.End] != s.SQL {
+					t.Errorf("span %s: SQL %q does not match offsets [%d:%d]", s.Clause, s.SQL, s.Start, s.End)
+				}
+				got = append(got, traceSpanWant{s.Clause, s.SQL})
+			}
+			if !reflect.DeepEqual(got, tt.wantSpans) {
+				t.Errorf("got spans %v, want %v", got, tt.wantSpans)
+			}
+		})
+	}
+}
+
+func TestBuildTraceCallSites(t *testing.T) {
+	SetTraceCallSites(true)
+	defer SetTraceCallSites(false)
+
+	q := Select("id").From("users")
+	q.WhereEqual("status", "active")
+	q.OrderByAsc("id")
+
+	trace, err := q.WithDialect(sqldialect.MySQL()).BuildTrace()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sites := make(map[string][]string)
+	for _, s := range trace.Spans {
+		sites[s.Clause] = s.CallSites
+	}
+	for _, clause := range []string{"SELECT", "FROM", "WHERE", "ORDER BY"} {
+		if len(sites[clause]) != 1 || !strings.HasPrefix(sites[clause][0], "trace_test.go:") {
+			t.Errorf("got call sites %v for %s, want one in trace_test.go", sites[clause], clause)
+		}
+	}
+	if sites["WHERE"][0] == sites["SELECT"][0] {
+		t.Errorf("WHERE and SELECT share call site %s", sites["WHERE"][0])
+	}
+	if out := trace.String(); !strings.Contains(out, "WHERE") || !strings.Contains(out, "trace_test.go:") {
+		t.Errorf("unexpected String output:\n%s", out)
+	}
+
+	// Calls made while tracing is disabled are not recorded.
+	SetTraceCallSites(false)
+	trace, err = Select("id").From("users").BuildTrace()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range trace.Spans {
+		if len(s.CallSites) != 0 {
+			t.Errorf("got call sites %v for %s with tracing disabled", s.CallSites, s.Clause)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// TypedSelect is a SELECT whose columns are derived from the fields of the struct type T, so
+// that the query and the values it is scanned into cannot drift apart. The column of a field is
+// its db tag, which may be table-qualified, or the field name in snake_case; fields tagged
+// db:"-" and unexported fields are skipped, and the fields of embedded structs are included.
+// The tag options of InsertStruct do not apply; all fields are selected.
+//
+// The embedded SelectBuilder adds the rest of the query. Its methods return the *SelectBuilder,
+// so keep the *TypedSelect to call BuildScan.
+//
+// Example usage:
+//
+//	type User struct {
+//		ID    int64  `db:"id"`
+//		Email string `db:"email"`
+//		Admin bool   // admin
+//	}
+//
+//	q := sqltk.SelectT[User]()
+//	q.From("users").WhereEqual("active", true)
+//	sql, args, scan, err := q.BuildScan()
+//	rows, err := db.Query(sql, args...)
+//	for rows.Next() {
+//		var u User
+//		err = rows.Scan(scan(&u)...)
+//	}
+type TypedSelect[T any] struct {
+	*SelectBuilder
+	fields [][]int // field index paths of the columns
+}
+
+// SelectT starts a SELECT of the columns of T. It is an error, reported by Build, if T is not a
+// struct or has no columns.
+func SelectT[T any]() *TypedSelect[T] {
+	q := &TypedSelect[T]{SelectBuilder: Select()}
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		q.whereClause.addErr(fmt.Errorf("SelectT: %s is not a struct", typ))
+		return q
+	}
+	fields := structFields(typ, nil)
+	if len(fields) == 0 {
+		q.whereClause.addErr(fmt.Errorf("SelectT: %s has no columns", typ))
+		return q
+	}
+	for _, f := range fields {
+		q.columns = append(q.columns, f.column)
+		q.fields = append(q.fields, f.index)
+	}
+	return q
+}
+
+// BuildScan builds the query like Build, and returns a function giving the scan destinations
+// of its columns in a value of T, for rows.Scan. It is an error if the columns were changed
+// after SelectT, e.g. with AddField, since they would no longer match the fields of T.
+func (q *TypedSelect[T]) BuildScan() (string, []interface{}, func(*T) []interface{}, error) {
+	sql, args, err := q.Build()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(q.columns) != len(q.fields) {
+		return "", nil, nil, errors.New("BuildScan: the columns no longer match the fields of the struct")
+	}
+	fields := q.fields
+	scan := func(dest *T) []interface{} {
+		v := reflect.ValueOf(dest).Elem()
+		targets := make([]interface{}, len(fields))
+		for i, path := range fields {
+			targets[i] = v.FieldByIndex(path).Addr().Interface()
+		}
+		return targets
+	}
+	return sql, args, scan, nil
+}
@@ -0,0 +1,66 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+type typedBase struct {
+	CreatedAt string
+}
+
+type typedUser struct {
+	typedBase
+	ID       int64  `db:"users.id"`
+	Email    string `db:"email"`
+	APIToken string
+	Password string `db:"-"`
+	note     string
+}
+
+func TestSelectT(t *testing.T) {
+	q := SelectT[typedUser]()
+	q.From("users").WhereEqual("active", true).WithDialect(sqldialect.Postgres())
+	sql, args, scan, err := q.BuildScan()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `SELECT "created_at", "users"."id", "email", "api_token" FROM "users" WHERE active = $1`; sql != want {
+		t.Errorf("got SQL %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{true}) {
+		t.Errorf("got args %v", args)
+	}
+
+	var u typedUser
+	targets := scan(&u)
+	if len(targets) != 4 {
+		t.Fatalf("got %d scan targets, want 4", len(targets))
+	}
+	*targets[0].(*string) = "2024-01-01"
+	*targets[1].(*int64) = 7
+	*targets[2].(*string) = "a@example.com"
+	*targets[3].(*string) = "tok"
+	want := typedUser{typedBase: typedBase{CreatedAt: "2024-01-01"}, ID: 7, Email: "a@example.com", APIToken: "tok"}
+	if u != want {
+		t.Errorf("got %+v, want %+v", u, want)
+	}
+
+	t.Run("changed columns", func(t *testing.T) {
+		q := SelectT[typedUser]()
+		q.From("users").AddField("name")
+		if _, _, _, err := q.BuildScan(); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		q := SelectT[int]()
+		q.From("users")
+		if _, _, _, err := q.BuildScan(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+// UnsupportedMode controls what Build does with a clause the dialect does not support, such as
+// FULL JOIN on MySQL or RETURNING on MySQL. See OnUnsupported.
+type UnsupportedMode int
+
+const (
+	// UnsupportedError makes Build return an error. It is the default.
+	UnsupportedError UnsupportedMode = iota
+	// UnsupportedStrip leaves the clause out, or replaces it with the nearest supported form,
+	// and reports a warning to the handler set with SetWarningHandler.
+	UnsupportedStrip
+	// UnsupportedEmulate rewrites the query to get the same result where that is feasible,
+	// and makes Build return an error otherwise.
+	UnsupportedEmulate
+)
+
+var (
+	warningMu      sync.RWMutex
+	warningHandler = func(warning string) { log.Print("sqltk: " + warning) }
+)
+
+// SetWarningHandler sets the function that receives warnings, such as clauses left out by
+// UnsupportedStrip. By default warnings are written with the log package. A nil fn discards them.
+func SetWarningHandler(fn func(warning string)) {
+	warningMu.Lock()
+	defer warningMu.Unlock()
+	warningHandler = fn
+}
+
+// warn reports a warning to the handler set with SetWarningHandler.
+func warn(warning string) {
+	warningMu.RLock()
+	fn := warningHandler
+	warningMu.RUnlock()
+	if fn != nil {
+		fn(warning)
+	}
+}
+
+// strip reports whether an unsupported clause is to be left out, named by feature. It returns
+// an error unless m is UnsupportedStrip; callers emulate the clause before calling strip.
+func (m UnsupportedMode) strip(feature, replacement string) (bool, error) {
+	if m != UnsupportedStrip {
+		return false, errors.New(feature + " is not supported by this dialect")
+	}
+	if replacement == "" {
+		warn(feature + " is not supported by this dialect and was left out")
+	} else {
+		warn(feature + " is not supported by this dialect and was built as " + replacement)
+	}
+	return true, nil
+}
+
+// returningClause renders the RETURNING clause of statement, which is "INSERT", "UPDATE", or
+// "DELETE", see sqldialect.SupportsReturning. Plain column names are quoted; other strings,
+// such as "*" and expressions, are written as-is.
+func returningClause(dialect sqldialect.Dialect, mode UnsupportedMode, statement string, columns []interface{}) (string, error) {
+	if len(columns) == 0 {
+		return "", nil
+	}
+	if !sqldialect.SupportsReturning(baseDialect(dialect), statement) {
+		_, err := mode.strip("RETURNING", "")
+		return "", err
+	}
+	cols := make([]string, len(columns))
+	for i, col := range columns {
+		switch c := col.(type) {
+		case string:
+			if isPlainIdent(c) {
+				cols[i] = quoteQualifiedIdent(dialect, c)
+			} else {
+				cols[i] = c
+			}
+		case raw.Raw:
+			cols[i] = string(c)
+		case sqlfunc.SqlFunc:
+			if err := c.Err(); err != nil {
+				return "", fmt.Errorf("Returning: %w", err)
+			}
+			cols[i] = string(c)
+		}
+	}
+	return " RETURNING " + strings.Join(cols, ", "), nil
+}
+
+// checkReturning checks the columns passed to Returning.
+func checkReturning(columns []interface{}) error {
+	for _, col := range columns {
+		switch c := col.(type) {
+		case string:
+			if c == "" {
+				return errors.New("Returning: column must not be empty")
+			}
+		case raw.Raw, sqlfunc.SqlFunc:
+		default:
+			return fmt.Errorf("Returning: column must be string, sq.Raw, or sqlfunc.SqlFunc (got %T)", col)
+		}
+	}
+	return nil
+}
+
+// rawColumns returns columns as raw.Raw, for the Returning of the Postgres builders, which
+// writes them as-is.
+func rawColumns(columns []string) []interface{} {
+	cols := make([]interface{}, len(columns))
+	for i, col := range columns {
+		cols[i] = raw.Raw(col)
+	}
+	return cols
+}
+
+// emulateFullJoin builds a query with a FULL JOIN, which MySQL lacks, as the UNION of the
+// query with a LEFT JOIN and with a RIGHT JOIN.
+func (b *SelectBuilder) emulateFullJoin(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	full := -1
+	for i, j := range b.joins {
+		if j.joinType == "FULL JOIN" {
+			if full >= 0 {
+				return "", nil, errors.New("FULL JOIN: only one FULL JOIN can be emulated")
+			}
+			full = i
+		}
+	}
+	if len(b.groupBy) > 0 || len(b.groupByRaw) > 0 || len(b.havingParam) > 0 || len(b.havingRaw) > 0 ||
+		len(b.orderBy) > 0 || b.limitSet || b.offsetSet || b.lock != "" || len(b.clauses) > 0 || b.seek != nil {
+		return "", nil, errors.New("FULL JOIN: can only be emulated in queries without GROUP BY, HAVING, ORDER BY, LIMIT, OFFSET, locking, or custom clauses")
+	}
+	left, right := b.Clone(), b.Clone()
+	left.joins[full].joinType = "LEFT JOIN"
+	right.joins[full].joinType = "RIGHT JOIN"
+	return left.Union(right).WithDialect(dialect).Build()
+}
+
+// OnUnsupported sets what Build does with clauses the dialect does not support: return an
+// error (the default), leave them out with a warning, or emulate them. See UnsupportedMode.
+// On MySQL, a FULL JOIN is emulated as the UNION of the query with a LEFT and a RIGHT JOIN,
+// which removes duplicate rows, and is stripped to a LEFT JOIN. Locking clauses and index
+// hints are stripped.
+//
+// Example usage:
+//
+//	Select("u.id", "o.id").From("users u").FullJoin("orders o").On("o.user_id", "u.id").
+//		OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.MySQL())
+//	// SELECT ... LEFT JOIN `orders o` ... UNION SELECT ... RIGHT JOIN `orders o` ...
+func (b *SelectBuilder) OnUnsupported(mode UnsupportedMode) *SelectBuilder {
+	b.unsupported = mode
+	return b
+}
+
+// OnUnsupported sets what Build does with clauses the dialect does not support. On MySQL,
+// ON CONFLICT DO NOTHING is emulated with ON DUPLICATE KEY UPDATE, which matches a conflict on
+// any unique key; it is stripped on other dialects, as is RETURNING. See UnsupportedMode.
+func (b *InsertBuilder) OnUnsupported(mode UnsupportedMode) *InsertBuilder {
+	b.unsupported = mode
+	return b
+}
+
+// OnUnsupported sets what Build does with clauses the dialect does not support, such as
+// RETURNING on MySQL, which can be stripped. See UnsupportedMode.
+func (b *UpdateBuilder) OnUnsupported(mode UnsupportedMode) *UpdateBuilder {
+	b.unsupported = mode
+	return b
+}
+
+// OnUnsupported sets what Build does with clauses the dialect does not support, such as
+// RETURNING on MySQL, which can be stripped. See UnsupportedMode.
+func (b *DeleteBuilder) OnUnsupported(mode UnsupportedMode) *DeleteBuilder {
+	b.unsupported = mode
+	return b
+}
@@ -0,0 +1,216 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+)
+
+func TestOnUnsupported(t *testing.T) {
+	var warnings []string
+	saved := warningHandler
+	SetWarningHandler(func(w string) { warnings = append(warnings, w) })
+	defer SetWarningHandler(saved)
+
+	fullJoin := func() *SelectBuilder {
+		return Select("u.id", "o.id").From("users u").FullJoin("orders o").On("o.user_id", "u.id").WhereEqual("u.active", true)
+	}
+	returning := func(mode UnsupportedMode) *PostgresDeleteBuilder {
+		del := Delete("users").WhereEqual("id", 1).OnUnsupported(mode).WithDialect(sqldialect.MySQL())
+		return &PostgresDeleteBuilder{DeleteBuilder: del, returning: []string{"id"}}
+	}
+
+	tests := []struct {
+		name         string
+		builder      Builder
+		wantSQL      string
+		wantArgs     []interface{}
+		wantErr      bool
+		wantWarnings int
+	}{
+		{
+			name:    "full join error",
+			builder: fullJoin().WithDialect(sqldialect.MySQL()),
+			wantErr: true,
+		},
+		{
+			name:         "full join strip",
+			builder:      fullJoin().OnUnsupported(UnsupportedStrip).WithDialect(sqldialect.MySQL()),
+			wantSQL:      "SELECT `u`.`id`, `o`.`id` FROM `users u` LEFT JOIN `orders o` ON o.user_id = u.id WHERE u.active = ?",
+			wantArgs:     []interface{}{true},
+			wantWarnings: 1,
+		},
+		{
+			name:     "full join emulate",
+			builder:  fullJoin().OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.MySQL()),
+			wantSQL:  "SELECT `u`.`id`, `o`.`id` FROM `users u` LEFT JOIN `orders o` ON o.user_id = u.id WHERE u.active = ? UNION SELECT `u`.`id`, `o`.`id` FROM `users u` RIGHT JOIN `orders o` ON o.user_id = u.id WHERE u.active = ?",
+			wantArgs: []interface{}{true, true},
+		},
+		{
+			name:    "full join emulate with limit",
+			builder: fullJoin().Limit(10).OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.MySQL()),
+			wantErr: true,
+		},
+		{
+			name:     "full join supported",
+			builder:  fullJoin().OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `SELECT "u"."id", "o"."id" FROM "users u" FULL JOIN "orders o" ON o.user_id = u.id WHERE u.active = $1`,
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:    "returning error",
+			builder: returning(UnsupportedError),
+			wantErr: true,
+		},
+		{
+			name:         "returning strip",
+			builder:      returning(UnsupportedStrip),
+			wantSQL:      "DELETE FROM `users` WHERE id = ?",
+			wantArgs:     []interface{}{1},
+			wantWarnings: 1,
+		},
+		{
+			name:    "returning emulate",
+			builder: returning(UnsupportedEmulate),
+			wantErr: true,
+		},
+		{
+			name:     "on conflict emulate",
+			builder:  Insert("users").Columns("email").Values("a@example.com").OnConflict("email").DoNothing().OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.MySQL()),
+			wantSQL:  "INSERT INTO `users` (`email`) VALUES (?) ON DUPLICATE KEY UPDATE `email` = `email`",
+			wantArgs: []interface{}{"a@example.com"},
+		},
+		{
+			name:    "on conflict emulate with partial index",
+			builder: Insert("users").Columns("email").Values("a@example.com").OnConflict("email").OnConflictWhere(NewStringCondition("deleted_at IS NULL")).DoNothing().OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.MySQL()),
+			wantErr: true,
+		},
+		{
+			name:         "on conflict strip",
+			builder:      Insert("users").Columns("email").Values("a@example.com").OnConflict("email").DoNothing().OnUnsupported(UnsupportedStrip).WithDialect(sqldialect.BigQuery()),
+			wantSQL:      "INSERT INTO `users` (`email`) VALUES (?)",
+			wantArgs:     []interface{}{"a@example.com"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "lock and index hint strip",
+			builder:      Select("id").From("users").UseIndex("idx_users").ForUpdate().OnUnsupported(UnsupportedStrip).WithDialect(sqldialect.DuckDB()),
+			wantSQL:      `SELECT "id" FROM "users"`,
+			wantArgs:     []interface{}{},
+			wantWarnings: 2,
+		},
+		{
+			name:    "lock emulate",
+			builder: Select("id").From("users").ForUpdate().OnUnsupported(UnsupportedEmulate).WithDialect(sqldialect.DuckDB()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings = nil
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("got warnings %q, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// mariaDBDialect is a MySQL dialect with RETURNING on INSERT and DELETE, as in MariaDB.
+type mariaDBDialect struct {
+	sqldialect.Dialect
+}
+
+func (mariaDBDialect) SupportsReturning(statement string) bool { return statement != "UPDATE" }
+
+func TestReturning(t *testing.T) {
+	mariaDB := mariaDBDialect{sqldialect.MySQL()}
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "insert",
+			builder:  Insert("users").Columns("email").Values("a@example.com").Returning("id", "users.created_at").WithDialect(sqldialect.Postgres()),
+			wantSQL:  `INSERT INTO "users" ("email") VALUES ($1) RETURNING "id", "users"."created_at"`,
+			wantArgs: []interface{}{"a@example.com"},
+		},
+		{
+			name: "insert after on conflict",
+			builder: Insert("users").Columns("email").Values("a@example.com").OnConflict("email").DoNothing().
+				Returning("*").WithDialect(sqldialect.DuckDB()),
+			wantSQL:  `INSERT INTO "users" ("email") VALUES ($1) ON CONFLICT ("email") DO NOTHING RETURNING *`,
+			wantArgs: []interface{}{"a@example.com"},
+		},
+		{
+			name:     "update",
+			builder:  Update("users").Set("name", "Al").WhereEqual("id", 1).Returning("id", raw.Raw("now() AS at")).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `UPDATE "users" SET name = $1 WHERE id = $2 RETURNING "id", now() AS at`,
+			wantArgs: []interface{}{"Al", 1},
+		},
+		{
+			name:     "delete on mariadb",
+			builder:  Delete("users").WhereEqual("id", 1).Returning("id").WithDialect(mariaDB),
+			wantSQL:  "DELETE FROM `users` WHERE id = ? RETURNING `id`",
+			wantArgs: []interface{}{1},
+		},
+		{
+			name:    "update on mariadb",
+			builder: Update("users").Set("name", "Al").Returning("id").WithDialect(mariaDB),
+			wantErr: true,
+		},
+		{
+			name:    "insert on mysql",
+			builder: Insert("users").Columns("email").Values("a@example.com").Returning("id").WithDialect(sqldialect.MySQL()),
+			wantErr: true,
+		},
+		{
+			name:    "bad column",
+			builder: Delete("users").Returning(42).WithDialect(sqldialect.Postgres()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package sqltk
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sprylic/sqltk/raw"
 	"github.com/sprylic/sqltk/sqldebug"
@@ -16,7 +20,21 @@ type UpdateBuilder struct {
 	sets    []string
 	setArgs []interface{}
 	whereClause
-	dialect sqldialect.Dialect // per-builder dialect, if set
+	dialect     sqldialect.Dialect // per-builder dialect, if set
+	schema      *Schema            // schema to check against, see WithSchema
+	unsupported UnsupportedMode    // see OnUnsupported
+	reuseArgs   bool               // see ReuseArgs
+	returning   []interface{}      // see Returning
+	with        withClause         // see With
+	joins       []updateJoin       // see Join
+	from        []string           // see From
+}
+
+// updateJoin is a table joined to an UPDATE, see UpdateBuilder.Join.
+type updateJoin struct {
+	table  string
+	on     string        // ON condition, with ? placeholders
+	onArgs []interface{} // args of on
 }
 
 // Update creates a new UpdateBuilder for the given table.
@@ -39,6 +57,10 @@ func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
 	if b.whereClause.err != nil {
 		return b
 	}
+	if err := exprErr(value); err != nil {
+		b.whereClause.addErr(fmt.Errorf("Set %s: %w", column, err))
+		return b
+	}
 	b.sets = append(b.sets, column+" = ?")
 	b.setArgs = append(b.setArgs, value)
 	return b
@@ -53,12 +75,122 @@ func (b *UpdateBuilder) SetRaw(expr string) *UpdateBuilder {
 	return b
 }
 
+// SetExpr adds a SET clause assigning the expression expr, whose ? placeholders are bound to
+// args, as for `updated_at = NOW()` or `total = total + ?`. Build returns an error if the
+// number of placeholders, counted with the dialect of the statement, and args differ.
+func (b *UpdateBuilder) SetExpr(column, expr string, args ...interface{}) *UpdateBuilder {
+	return b.Set(column, checkedExpr{SQLExpr: Expr(expr, args...), op: "SetExpr"})
+}
+
+// Increment adds a SET clause adding by to the current value of column, which is atomic in
+// the database unlike reading the value and setting its sum.
+func (b *UpdateBuilder) Increment(column string, by interface{}) *UpdateBuilder {
+	return b.SetExpr(column, column+" + ?", by)
+}
+
+// Decrement adds a SET clause subtracting by from the current value of column; see Increment.
+func (b *UpdateBuilder) Decrement(column string, by interface{}) *UpdateBuilder {
+	return b.SetExpr(column, column+" - ?", by)
+}
+
+// SetMap adds a SET clause for each column of values, in column order so that the query is
+// the same on every build.
+func (b *UpdateBuilder) SetMap(values map[string]interface{}) *UpdateBuilder {
+	columns := make([]string, 0, len(values))
+	for col := range values {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	for _, col := range columns {
+		b.Set(col, values[col])
+	}
+	return b
+}
+
+// Join adds a table joined on the condition on, whose columns the SET and WHERE clauses can
+// use to update rows from another table. MySQL builds it as UPDATE ... JOIN ... ON ... SET;
+// other dialects as UPDATE ... SET ... FROM ... WHERE, with on ANDed to the WHERE clause and
+// the alias of the updated table removed from the SET columns.
+//
+// Example usage:
+//
+//	sqltk.Update("orders").Join("users", sqltk.NewStringCondition("users.id = orders.user_id")).
+//		SetExpr("orders.email", "users.email").WhereEqual("users.active", true)
+//	// MySQL:    UPDATE `orders` JOIN `users` ON users.id = orders.user_id SET orders.email = users.email WHERE users.active = ?
+//	// Postgres: UPDATE "orders" SET email = users.email FROM "users" WHERE users.id = orders.user_id AND users.active = $1
+func (b *UpdateBuilder) Join(table string, on Condition) *UpdateBuilder {
+	if table == "" {
+		b.whereClause.addErr(errors.New("Join: table must be set"))
+		return b
+	}
+	if on == nil {
+		b.whereClause.addErr(errors.New("Join: condition must not be nil"))
+		return b
+	}
+	sql, args, err := on.BuildCondition()
+	if err != nil {
+		b.whereClause.addErr(fmt.Errorf("Join: condition error: %w", err))
+		return b
+	}
+	if sql == "" {
+		b.whereClause.addErr(errors.New("Join: condition must not be empty"))
+		return b
+	}
+	b.joins = append(b.joins, updateJoin{table: table, on: sql, onArgs: args})
+	return b
+}
+
+// From adds tables whose columns the SET and WHERE clauses can use, matched to the updated
+// rows by WHERE conditions. Postgres and most other dialects build them as UPDATE ... SET ...
+// FROM; MySQL, which has no FROM, lists them after the updated table.
+func (b *UpdateBuilder) From(tables ...string) *UpdateBuilder {
+	for _, t := range tables {
+		if t == "" {
+			b.whereClause.addErr(errors.New("From: table must be set"))
+			return b
+		}
+	}
+	b.from = append(b.from, tables...)
+	return b
+}
+
 // Where adds a WHERE clause. Accepts a Condition.
 func (b *UpdateBuilder) Where(cond Condition, args ...interface{}) *UpdateBuilder {
 	b.whereClause.Where(cond, args...)
 	return b
 }
 
+// OrWhere combines the WHERE conditions added so far with cond using OR.
+func (b *UpdateBuilder) OrWhere(cond Condition) *UpdateBuilder {
+	b.whereClause.OrWhere(cond)
+	return b
+}
+
+// WhereGroup adds the conditions built by fn as a single parenthesized WHERE condition.
+func (b *UpdateBuilder) WhereGroup(fn func(*ConditionBuilder)) *UpdateBuilder {
+	b.whereClause.WhereGroup(b.dialect, fn)
+	return b
+}
+
+// WhereIf adds a WHERE clause only if cond is true.
+func (b *UpdateBuilder) WhereIf(cond bool, condition Condition) *UpdateBuilder {
+	if cond {
+		b.whereClause.Where(condition)
+	}
+	return b
+}
+
+// ApplyIf calls fn with the builder only if cond is true.
+func (b *UpdateBuilder) ApplyIf(cond bool, fn func(*UpdateBuilder) *UpdateBuilder) *UpdateBuilder {
+	if !cond || fn == nil {
+		return b
+	}
+	if next := fn(b); next != nil {
+		return next
+	}
+	return b
+}
+
 // WhereEqual adds a WHERE clause for equality (column = value).
 func (b *UpdateBuilder) WhereEqual(column string, value interface{}) *UpdateBuilder {
 	b.Where(NewStringCondition(column+" = ?", value))
@@ -131,6 +263,33 @@ func (b *UpdateBuilder) WhereNotIn(column string, values ...interface{}) *Update
 	return b
 }
 
+// WhereInSlice adds a WHERE clause for IN condition with one placeholder per element of a typed
+// slice, e.g. []int64 or []string.
+func (b *UpdateBuilder) WhereInSlice(column string, slice interface{}) *UpdateBuilder {
+	b.Where(NewCond().InSlice(column, slice))
+	return b
+}
+
+// WhereNotInSlice adds a WHERE clause for NOT IN condition with one placeholder per element of a typed slice.
+func (b *UpdateBuilder) WhereNotInSlice(column string, slice interface{}) *UpdateBuilder {
+	b.Where(NewCond().NotInSlice(column, slice))
+	return b
+}
+
+// WhereInEnum adds a WHERE clause for IN condition with the requested values, which must all be
+// in allowed. See ConditionBuilder.InEnum.
+func (b *UpdateBuilder) WhereInEnum(column string, allowed []string, requested []string) *UpdateBuilder {
+	b.Where(NewCond().InEnum(column, allowed, requested))
+	return b
+}
+
+// WhereDateRange adds a WHERE clause for the half-open time range of the bounds that are set.
+// See ConditionBuilder.DateRange.
+func (b *UpdateBuilder) WhereDateRange(column string, from, to *time.Time) *UpdateBuilder {
+	b.Where(NewCond().DateRange(column, from, to))
+	return b
+}
+
 // WhereBetween adds a WHERE clause for BETWEEN condition (column BETWEEN min AND max).
 func (b *UpdateBuilder) WhereBetween(column string, min, max interface{}) *UpdateBuilder {
 	b.Where(NewCond().Between(column, min, max))
@@ -161,12 +320,42 @@ func (b *UpdateBuilder) WhereColsEqual(column1, column2 string) *UpdateBuilder {
 	return b
 }
 
+// Returning adds columns to a RETURNING clause, which returns them from the updated rows.
+// A plain column name is quoted for the dialect; other strings, such as "*", and raw.Raw or
+// sqlfunc.SqlFunc expressions are written as-is. Dialects without RETURNING for UPDATE, such as
+// MySQL, are handled as set with OnUnsupported; see sqldialect.SupportsReturning.
+func (b *UpdateBuilder) Returning(cols ...interface{}) *UpdateBuilder {
+	if err := checkReturning(cols); err != nil {
+		b.whereClause.addErr(err)
+		return b
+	}
+	b.returning = append(b.returning, cols...)
+	return b
+}
+
+// With adds a common table expression named name, which the statement reads like a table. See
+// InsertBuilder.With.
+func (b *UpdateBuilder) With(name string, body Builder) *UpdateBuilder {
+	if err := b.with.add(name, body); err != nil {
+		b.whereClause.addErr(err)
+	}
+	return b
+}
+
 // WithDialect sets the dialect for this builder instance.
 func (b *UpdateBuilder) WithDialect(d sqldialect.Dialect) *UpdateBuilder {
 	b.dialect = d
 	return b
 }
 
+// GetDialect returns the dialect the builder renders with: its own, or the global dialect if none is set.
+func (b *UpdateBuilder) GetDialect() sqldialect.Dialect {
+	if b.dialect == nil {
+		return sqldialect.GetDialect()
+	}
+	return b.dialect
+}
+
 // Build builds the SQL UPDATE query and returns the query string, arguments, and error if any.
 func (b *UpdateBuilder) Build() (string, []interface{}, error) {
 	if b.tableClauseString.err != nil {
@@ -181,38 +370,122 @@ func (b *UpdateBuilder) Build() (string, []interface{}, error) {
 	if len(b.sets) == 0 {
 		return "", nil, errors.New("Update: at least one SET clause must be set")
 	}
+	if b.schema != nil {
+		if err := b.schema.checkUpdate(b); err != nil {
+			return "", nil, err
+		}
+	}
 
 	dialect := b.dialect
 	if dialect == nil {
 		dialect = sqldialect.GetDialect()
 	}
-	placeholderIdx := 1
+	out := dialect
+	dialect = renderDialect(dialect)
 
+	withSQL, args, err := b.with.build(dialect)
+	if err != nil {
+		return "", nil, err
+	}
 	var sb strings.Builder
-	args := append([]interface{}{}, b.setArgs...)
+	mysql := baseDialect(dialect) == sqldialect.MySQL()
 
+	sb.WriteString(withSQL)
 	sb.WriteString("UPDATE ")
-	sb.WriteString(dialect.QuoteIdent(b.tableClauseString.table))
+	sb.WriteString(quoteQualifiedIdent(dialect, b.tableClauseString.table))
+	where := b.whereClause
+	if mysql {
+		for _, j := range b.joins {
+			sb.WriteString(" JOIN ")
+			sb.WriteString(quoteQualifiedIdent(dialect, j.table))
+			onSQL, onArgs, err := bindArgs(dialect, j.on, j.onArgs)
+			if err != nil {
+				return "", nil, fmt.Errorf("join: %w", err)
+			}
+			sb.WriteString(" ON ")
+			sb.WriteString(onSQL)
+			args = append(args, onArgs...)
+		}
+		for _, t := range b.from {
+			sb.WriteString(", ")
+			sb.WriteString(quoteQualifiedIdent(dialect, t))
+		}
+	} else if len(b.joins) > 0 {
+		// The target table cannot be referenced in a JOIN of the FROM clause, so the ON
+		// conditions come first in the WHERE clause.
+		where.whereParam, where.whereArgs = nil, nil
+		for _, j := range b.joins {
+			where.whereParam = append(where.whereParam, j.on)
+			where.whereArgs = append(where.whereArgs, j.onArgs...)
+		}
+		for i, param := range b.whereParam {
+			if i == 0 && b.whereOr {
+				param = "(" + param + ")"
+			}
+			where.whereParam = append(where.whereParam, param)
+		}
+		where.whereArgs = append(where.whereArgs, b.whereArgs...)
+		where.whereOr = false
+	}
 	sb.WriteString(" SET ")
 
-	setSQL := strings.Join(b.sets, ", ")
-	if dialect.Placeholder(0) != "?" {
-		for strings.Contains(setSQL, "?") && dialect.Placeholder(0) != "?" {
-			setSQL = strings.Replace(setSQL, "?", dialect.Placeholder(placeholderIdx), 1)
-			placeholderIdx++
+	sets := b.sets
+	if !mysql && (len(b.joins) > 0 || len(b.from) > 0) {
+		// SET columns cannot be qualified with the updated table alongside FROM.
+		prefix := tableAlias(b.tableClauseString.table) + "."
+		sets = make([]string, len(b.sets))
+		for i, set := range b.sets {
+			sets[i] = strings.TrimPrefix(set, prefix)
 		}
 	}
-
+	setSQL, setArgs, err := bindArgs(dialect, strings.Join(sets, ", "), b.setArgs)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, setArgs...)
 	sb.WriteString(setSQL)
 
-	whereSQL, whereArgs := b.buildWhereSQL(dialect, &placeholderIdx)
+	if !mysql && (len(b.joins) > 0 || len(b.from) > 0) {
+		sb.WriteString(" FROM ")
+		for i, j := range b.joins {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(quoteQualifiedIdent(dialect, j.table))
+		}
+		for i, t := range b.from {
+			if i > 0 || len(b.joins) > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(quoteQualifiedIdent(dialect, t))
+		}
+	}
+
+	whereSQL, whereArgs, err := where.buildWhereSQL(dialect)
+	if err != nil {
+		return "", nil, err
+	}
 	if whereSQL != "" {
 		sb.WriteString(" WHERE ")
 		sb.WriteString(whereSQL)
 		args = append(args, whereArgs...)
 	}
 
-	return sb.String(), args, nil
+	returning, err := returningClause(dialect, b.unsupported, "UPDATE", b.returning)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(returning)
+
+	if err := sqldialect.CheckIdents(dialect, sb.String()); err != nil {
+		return "", nil, err
+	}
+	if err := argsErr(args); err != nil {
+		return "", nil, err
+	}
+	numbers, args := reuseNumbers(out, sb.String(), args, b.reuseArgs)
+	placeholderIdx := 1
+	return numberPlaceholders(out, sb.String(), &placeholderIdx, numbers), args, nil
 }
 
 // PostgresUpdateBuilder extends UpdateBuilder with RETURNING support for Postgres.
@@ -234,23 +507,30 @@ func (b *PostgresUpdateBuilder) Returning(cols ...string) *PostgresUpdateBuilder
 
 // Build builds the SQL UPDATE query with RETURNING (if set) and returns the query string, arguments, and error if any.
 func (b *PostgresUpdateBuilder) Build() (string, []interface{}, error) {
-	sql, args, err := b.UpdateBuilder.Build()
-	if err != nil {
-		return sql, args, err
-	}
-	if len(b.returning) > 0 {
-		sql += " RETURNING " + strings.Join(b.returning, ", ")
-	}
-	return sql, args, nil
+	c := *b.UpdateBuilder
+	c.returning = append(c.returning[:len(c.returning):len(c.returning)], rawColumns(b.returning)...)
+	return c.Build()
+}
+
+// BuildTo builds the statement with RETURNING (if set) and writes its SQL to w, returning the
+// args. See BuildTo.
+func (b *PostgresUpdateBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
 
 // Example usage:
 //   pq := sq.NewPostgresUpdate("users").Set("name", "Alice").Where("id = ?", 1).Returning("id")
 //   sql, args, err := pq.Build()
 
-// DebugSQL returns the SQL with arguments interpolated for debugging/logging only.
+// DebugSQL returns the SQL with arguments interpolated for debugging/logging only, labelled
+// as debug SQL.
 // DO NOT use the result for execution (not safe against SQL injection).
 func (b *UpdateBuilder) DebugSQL() string {
 	sql, args, _ := b.Build()
-	return sqldebug.InterpolateSQL(sql, args).GetUnsafeString()
+	return sqldebug.InterpolateSQLDialect(b.dialect, sql, args).String()
+}
+
+// BuildTo builds the statement and writes its SQL to w, returning the args. See BuildTo.
+func (b *UpdateBuilder) BuildTo(w io.Writer) ([]interface{}, error) {
+	return BuildTo(w, b)
 }
@@ -105,7 +105,7 @@ func TestUpdateBuilder(t *testing.T) {
 
 		q := Update("users").Set("name", "Alice").Where(cond)
 		sql, args, err := q.Build()
-		wantSQL := "UPDATE users SET name = ? WHERE (active = ?) OR (vip = ?) AND age > ?"
+		wantSQL := "UPDATE users SET name = ? WHERE ((active = ?) OR (vip = ?)) AND age > ?"
 		wantArgs := []interface{}{"Alice", true, true, 16}
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -190,3 +190,173 @@ func TestPostgresUpdateBuilder_Returning(t *testing.T) {
 		t.Errorf("got args %v, want %v", args, wantArgs)
 	}
 }
+
+func TestUpdateBuilder_SetStruct(t *testing.T) {
+	email := "a@example.com"
+	empty := ""
+	type patch struct {
+		ID    int64   `db:"id,auto"`
+		Email *string `db:"email"`
+		Name  *string `db:"name"`
+		Plan  string  `db:"plan,omitempty"`
+		Age   int
+	}
+
+	tests := []struct {
+		name     string
+		builder  *UpdateBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "all fields",
+			builder:  Update("users").SetStruct(patch{ID: 1, Email: &email}).WhereEqual("id", 1),
+			wantSQL:  "UPDATE users SET email = ?, name = ?, age = ? WHERE id = ?",
+			wantArgs: []interface{}{&email, (*string)(nil), 0, 1},
+		},
+		{
+			name:     "only non-zero",
+			builder:  Update("users").SetStruct(&patch{Name: &empty, Plan: "pro"}, OnlyNonZero()).WhereEqual("id", 1),
+			wantSQL:  "UPDATE users SET name = ?, plan = ? WHERE id = ?",
+			wantArgs: []interface{}{&empty, "pro", 1},
+		},
+		{
+			name:     "map",
+			builder:  Update("users").SetMap(map[string]interface{}{"name": "Bob", "age": 31, "email": nil}).WhereEqual("id", 1),
+			wantSQL:  "UPDATE users SET age = ?, email = ?, name = ? WHERE id = ?",
+			wantArgs: []interface{}{31, nil, "Bob", 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for name, b := range map[string]*UpdateBuilder{
+			"nil":          Update("users").SetStruct(nil),
+			"not a struct": Update("users").SetStruct("x"),
+			"slice":        Update("users").SetStruct([]patch{{}}),
+			"nothing set":  Update("users").SetStruct(patch{}, OnlyNonZero()),
+		} {
+			if _, _, err := b.Build(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+}
+
+func TestUpdateBuilder_SetExpr(t *testing.T) {
+	q := Update("counters").
+		Increment("hits", 1).
+		Decrement("credits", 2).
+		SetExpr("updated_at", "NOW()").
+		SetExpr("label", "CONCAT(label, ?, '?')", "-x").
+		WhereEqual("id", 7).
+		WithDialect(sqldialect.Postgres())
+	sql, args, err := q.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantSQL := `UPDATE "counters" SET hits = hits + $1, credits = credits - $2, updated_at = NOW(), label = CONCAT(label, $3, '?') WHERE id = $4`
+	wantArgs := []interface{}{1, 2, "-x", 7}
+	if sql != wantSQL {
+		t.Errorf("got SQL %q, want %q", sql, wantSQL)
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("got args %v, want %v", args, wantArgs)
+	}
+
+	if _, _, err := Update("counters").SetExpr("hits", "hits + ?").Build(); err == nil {
+		t.Error("expected error for a placeholder without an arg")
+	}
+
+	t.Run("placeholders of the statement dialect", func(t *testing.T) {
+		sql, args, err := Update("notes").SetExpr("body", `CONCAT(body, 'it\'', ?)`, "s").WithDialect(sqldialect.MySQL()).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "UPDATE `notes` SET body = CONCAT(body, 'it\\'', ?)"; sql != want {
+			t.Errorf("got SQL %q, want %q", sql, want)
+		}
+		if !reflect.DeepEqual(args, []interface{}{"s"}) {
+			t.Errorf("got args %v", args)
+		}
+	})
+}
+
+func TestUpdateBuilder_Join(t *testing.T) {
+	newQuery := func() *UpdateBuilder {
+		return Update("orders").
+			Join("users", NewStringCondition("users.id = orders.user_id AND users.region = ?", "eu")).
+			SetExpr("orders.email", "users.email").
+			Set("orders.status", "synced").
+			Where(NewStringCondition("users.active = ?", true)).
+			OrWhere(NewStringCondition("users.vip = ?", true)).
+			Where(NewStringCondition("orders.total > ?", 10))
+	}
+	tests := []struct {
+		name     string
+		builder  *UpdateBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "mysql join",
+			builder:  newQuery().WithDialect(sqldialect.MySQL()),
+			wantSQL:  "UPDATE `orders` JOIN `users` ON users.id = orders.user_id AND users.region = ? SET orders.email = users.email, orders.status = ? WHERE ((users.active = ?) OR (users.vip = ?)) AND orders.total > ?",
+			wantArgs: []interface{}{"eu", "synced", true, true, 10},
+		},
+		{
+			name:     "postgres join",
+			builder:  newQuery().WithDialect(sqldialect.Postgres()),
+			wantSQL:  `UPDATE "orders" SET email = users.email, status = $1 FROM "users" WHERE users.id = orders.user_id AND users.region = $2 AND ((users.active = $3) OR (users.vip = $4)) AND orders.total > $5`,
+			wantArgs: []interface{}{"synced", "eu", true, true, 10},
+		},
+		{
+			name: "postgres from",
+			builder: Update("accounts").Set("balance", 0).From("closures").
+				Where(NewStringCondition("closures.account_id = accounts.id")).WithDialect(sqldialect.Postgres()),
+			wantSQL:  `UPDATE "accounts" SET balance = $1 FROM "closures" WHERE closures.account_id = accounts.id`,
+			wantArgs: []interface{}{0},
+		},
+		{
+			name: "mysql from",
+			builder: Update("accounts").Set("balance", 0).From("closures").
+				Where(NewStringCondition("closures.account_id = accounts.id")).WithDialect(sqldialect.MySQL()),
+			wantSQL:  "UPDATE `accounts`, `closures` SET balance = ? WHERE closures.account_id = accounts.id",
+			wantArgs: []interface{}{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	if got, want := newQuery().GetTables(), []string{"orders", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tables %v, want %v", got, want)
+	}
+	if _, _, err := Update("orders").Set("a", 1).Join("users", nil).Build(); err == nil {
+		t.Error("expected error for a nil join condition")
+	}
+}
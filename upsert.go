@@ -0,0 +1,383 @@
+package sqltk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+// onConflict is the ON CONFLICT clause of an InsertBuilder.
+type onConflict struct {
+	targets   []interface{} // columns and index expressions of the unique index
+	where     string        // predicate of a partial unique index
+	whereArgs []interface{}
+	doNothing bool
+	updates   []conflictUpdate // see DoUpdateSet
+}
+
+// conflictUpdate is an assignment of DoUpdateSet.
+type conflictUpdate struct {
+	column string
+	value  interface{}
+}
+
+// Excluded is the value the row that could not be inserted has for a column, for use with
+// DoUpdateSet. It is built as EXCLUDED.column on Postgres and DuckDB, and VALUES(column) on
+// MySQL.
+type Excluded string
+
+// OnConflict adds an ON CONFLICT clause whose target is the unique index on targets (Postgres
+// and DuckDB). A string target is a column; a raw.Raw or sqlfunc.SqlFunc target is an index
+// expression, rendered as-is, e.g. raw.Raw("lower(email)"). Expressions other than function
+// calls must be parenthesized, as in Postgres. With no targets, any conflict matches. Use
+// OnConflictWhere for a partial unique index, and finish with an action, DoNothing or
+// DoUpdateSet.
+//
+// On MySQL, DoUpdateSet is built as ON DUPLICATE KEY UPDATE, which applies to a conflict on
+// any unique index, so the targets are not used there. Other dialects, and DoNothing on MySQL,
+// are handled as set with OnUnsupported.
+//
+// Example usage:
+//
+//	Insert("users").Columns("tenant_id", "email").Values(1, "a@example.com").
+//		OnConflict("tenant_id", raw.Raw("lower(email)")).
+//		OnConflictWhere(NewStringCondition("deleted_at IS NULL")).
+//		DoNothing()
+//	// ... ON CONFLICT (tenant_id, lower(email)) WHERE deleted_at IS NULL DO NOTHING
+func (b *InsertBuilder) OnConflict(targets ...interface{}) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, t := range targets {
+		switch t := t.(type) {
+		case string:
+			if t == "" {
+				b.err = errors.New("OnConflict: target must not be empty")
+				return b
+			}
+		case raw.Raw, sqlfunc.SqlFunc:
+		default:
+			b.err = fmt.Errorf("OnConflict: target must be string, sq.Raw, or sqlfunc.SqlFunc (got %T)", t)
+			return b
+		}
+	}
+	if b.idemKey != nil {
+		b.err = errors.New("OnConflict: cannot be combined with IdempotencyKey")
+		return b
+	}
+	b.conflict = &onConflict{targets: append([]interface{}{}, targets...)}
+	return b
+}
+
+// OnConflictWhere sets the predicate of the partial unique index targeted by OnConflict,
+// e.g. NewStringCondition("deleted_at IS NULL").
+func (b *InsertBuilder) OnConflictWhere(cond Condition) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.conflict == nil {
+		b.err = errors.New("OnConflictWhere: OnConflict must be called first")
+		return b
+	}
+	if cond == nil {
+		b.err = errors.New("OnConflictWhere: condition must not be nil")
+		return b
+	}
+	sql, args, err := cond.BuildCondition()
+	if err != nil {
+		b.err = fmt.Errorf("OnConflictWhere: condition error: %w", err)
+		return b
+	}
+	if b.conflict.where != "" {
+		sql = b.conflict.where + " AND " + sql
+	}
+	b.conflict.where = sql
+	b.conflict.whereArgs = append(b.conflict.whereArgs, args...)
+	return b
+}
+
+// DoNothing sets the action of the ON CONFLICT clause to DO NOTHING, skipping conflicting rows.
+func (b *InsertBuilder) DoNothing() *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.conflict == nil {
+		b.err = errors.New("DoNothing: OnConflict must be called first")
+		return b
+	}
+	if len(b.conflict.updates) > 0 {
+		b.err = errors.New("DoNothing: cannot be combined with DoUpdateSet")
+		return b
+	}
+	b.conflict.doNothing = true
+	return b
+}
+
+// DoUpdateSet adds an assignment to the DO UPDATE SET action of the ON CONFLICT clause, which
+// updates the conflicting row instead of inserting. value is bound as an argument, unless it is
+// an Excluded column, a raw.Raw or sqlfunc.SqlFunc expression written as-is, or an Expr.
+//
+// Example usage:
+//
+//	Insert("users").Columns("email", "name").Values("a@example.com", "Al").
+//		OnConflict("email").
+//		DoUpdateSet("name", Excluded("name")).
+//		DoUpdateSet("updated_at", raw.Raw("NOW()"))
+//	// Postgres: ... ON CONFLICT ("email") DO UPDATE SET "name" = EXCLUDED."name", "updated_at" = NOW()
+//	// MySQL:    ... ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `updated_at` = NOW()
+func (b *InsertBuilder) DoUpdateSet(column string, value interface{}) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	switch {
+	case b.conflict == nil:
+		b.err = errors.New("DoUpdateSet: OnConflict must be called first")
+	case b.conflict.doNothing:
+		b.err = errors.New("DoUpdateSet: cannot be combined with DoNothing")
+	case column == "":
+		b.err = errors.New("DoUpdateSet: column must not be empty")
+	default:
+		b.conflict.updates = append(b.conflict.updates, conflictUpdate{column: column, value: value})
+	}
+	return b
+}
+
+// idempotencyKey is the key column and value of an insert, see IdempotencyKey.
+type idempotencyKey struct {
+	column string
+	key    interface{}
+}
+
+// IdempotencyKey makes the insert an exactly-once write: it adds column with value key to the
+// row and skips the row with ON CONFLICT (column) DO NOTHING if a row with the same key exists,
+// so a retried webhook or job does not write twice (Postgres and DuckDB). column must have a
+// unique constraint, and the insert must have exactly one row. With RETURNING, a duplicate
+// returns no rows; otherwise its affected row count is 0.
+//
+// Example usage:
+//
+//	q := NewPostgresInsert("payments")
+//	q.Columns("order_id", "amount").Values(42, 999).IdempotencyKey("event_id", event.ID)
+//	q.Returning("id")
+//	// INSERT INTO "payments" ("order_id", "amount", "event_id") VALUES ($1, $2, $3)
+//	//   ON CONFLICT ("event_id") DO NOTHING RETURNING id
+func (b *InsertBuilder) IdempotencyKey(column string, key interface{}) *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	switch {
+	case column == "":
+		b.err = errors.New("IdempotencyKey: column must not be empty")
+	case key == nil:
+		b.err = errors.New("IdempotencyKey: key must not be nil")
+	case b.conflict != nil:
+		b.err = errors.New("IdempotencyKey: cannot be combined with OnConflict")
+	default:
+		b.idemKey = &idempotencyKey{column: column, key: key}
+		b.conflict = &onConflict{targets: []interface{}{column}, doNothing: true}
+	}
+	return b
+}
+
+// Replace starts a REPLACE INTO statement for the given table, which deletes the rows that
+// conflict with an inserted row on a unique key before inserting it (MySQL, and INSERT OR
+// REPLACE on DuckDB). Other dialects are handled as set with OnUnsupported, where
+// UnsupportedStrip builds a plain INSERT.
+//
+// Example usage:
+//
+//	Replace("settings").Columns("user_id", "theme").Values(1, "dark")
+//	// REPLACE INTO `settings` (`user_id`, `theme`) VALUES (?, ?)
+func Replace(table string) *InsertBuilder {
+	return &InsertBuilder{table: table, replace: true}
+}
+
+// Ignore makes the insert skip rows that would fail, such as those with a duplicate key:
+// INSERT IGNORE on MySQL, and INSERT OR IGNORE on DuckDB. Other dialects are handled as set
+// with OnUnsupported; on Postgres, UnsupportedEmulate builds ON CONFLICT DO NOTHING, which skips
+// conflicting rows but not, as MySQL does, rows with invalid values.
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.replace {
+		b.err = errors.New("Ignore: cannot be combined with Replace")
+		return b
+	}
+	b.ignore = true
+	return b
+}
+
+// insertVerb returns the start of the statement up to the table, for Replace and Ignore, and
+// whether Ignore is emulated with ON CONFLICT DO NOTHING.
+func (b *InsertBuilder) insertVerb(dialect sqldialect.Dialect) (string, bool, error) {
+	base := baseDialect(dialect)
+	switch {
+	case b.replace:
+		if b.conflict != nil || b.idemKey != nil {
+			return "", false, errors.New("Replace: cannot be combined with OnConflict")
+		}
+		switch base {
+		case sqldialect.MySQL():
+			return "REPLACE INTO ", false, nil
+		case sqldialect.DuckDB():
+			return "INSERT OR REPLACE INTO ", false, nil
+		}
+		if _, err := b.unsupported.strip("REPLACE", "INSERT"); err != nil {
+			return "", false, fmt.Errorf("Replace: %w", err)
+		}
+	case b.ignore:
+		switch base {
+		case sqldialect.MySQL():
+			return "INSERT IGNORE INTO ", false, nil
+		case sqldialect.DuckDB():
+			if b.conflict != nil {
+				return "", false, errors.New("Ignore: cannot be combined with OnConflict on DuckDB")
+			}
+			return "INSERT OR IGNORE INTO ", false, nil
+		case sqldialect.Postgres():
+			if b.unsupported == UnsupportedEmulate && b.conflict == nil {
+				return "INSERT INTO ", true, nil
+			}
+		}
+		if _, err := b.unsupported.strip("INSERT IGNORE", "INSERT"); err != nil {
+			return "", false, fmt.Errorf("Ignore: %w", err)
+		}
+	}
+	return "INSERT INTO ", false, nil
+}
+
+// buildConflict renders the ON CONFLICT clause with ? placeholders. On dialects without ON
+// CONFLICT, it is emulated or left out as set with OnUnsupported. columns are the inserted
+// columns.
+func (b *InsertBuilder) buildConflict(dialect sqldialect.Dialect, columns []string) (string, []interface{}, error) {
+	base := baseDialect(dialect)
+	if base == sqldialect.MySQL() && len(b.conflict.updates) > 0 {
+		if b.conflict.where != "" {
+			return "", nil, errors.New("OnConflictWhere: MySQL does not support a partial index predicate")
+		}
+		sets, args, err := b.conflict.buildUpdates(dialect, true)
+		if err != nil {
+			return "", nil, err
+		}
+		return " ON DUPLICATE KEY UPDATE " + sets, args, nil
+	}
+	sql, args, err := b.conflict.build(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	if base == sqldialect.Postgres() || base == sqldialect.DuckDB() {
+		return sql, args, nil
+	}
+	if b.unsupported == UnsupportedEmulate && base == sqldialect.MySQL() && b.conflict.where == "" && len(columns) > 0 {
+		// Assigning a column to itself leaves a conflicting row unchanged.
+		col := dialect.QuoteIdent(columns[0])
+		return " ON DUPLICATE KEY UPDATE " + col + " = " + col, nil, nil
+	}
+	if _, err := b.unsupported.strip("ON CONFLICT", ""); err != nil {
+		return "", nil, fmt.Errorf("OnConflict: %w", err)
+	}
+	return "", nil, nil
+}
+
+// build renders the ON CONFLICT clause with ? placeholders.
+func (c *onConflict) build(dialect sqldialect.Dialect) (string, []interface{}, error) {
+	if len(c.targets) == 0 && c.where != "" {
+		return "", nil, errors.New("OnConflict: a partial index predicate requires targets")
+	}
+	if !c.doNothing && len(c.updates) == 0 {
+		return "", nil, errors.New("OnConflict: an action is required, see DoNothing and DoUpdateSet")
+	}
+	if len(c.updates) > 0 && len(c.targets) == 0 {
+		return "", nil, errors.New("OnConflict: DoUpdateSet requires targets")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" ON CONFLICT")
+	if len(c.targets) > 0 {
+		targets := make([]string, len(c.targets))
+		for i, t := range c.targets {
+			switch t := t.(type) {
+			case string:
+				targets[i] = dialect.QuoteIdent(t)
+			case raw.Raw:
+				targets[i] = string(t)
+			case sqlfunc.SqlFunc:
+				if err := t.Err(); err != nil {
+					return "", nil, err
+				}
+				targets[i] = string(t)
+			}
+		}
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(targets, ", "))
+		sb.WriteString(")")
+	}
+	var args []interface{}
+	if c.where != "" {
+		var where string
+		var err error
+		if where, args, err = bindArgs(dialect, c.where, c.whereArgs); err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(where)
+	}
+	if c.doNothing {
+		sb.WriteString(" DO NOTHING")
+		return sb.String(), args, nil
+	}
+	sets, setArgs, err := c.buildUpdates(dialect, false)
+	if err != nil {
+		return "", nil, err
+	}
+	sb.WriteString(" DO UPDATE SET ")
+	sb.WriteString(sets)
+	return sb.String(), append(args, setArgs...), nil
+}
+
+// buildUpdates renders the assignments of DoUpdateSet with ? placeholders, referring to
+// Excluded columns as MySQL does if mysql is set.
+func (c *onConflict) buildUpdates(dialect sqldialect.Dialect, mysql bool) (string, []interface{}, error) {
+	var args []interface{}
+	sets := make([]string, len(c.updates))
+	for i, u := range c.updates {
+		var value string
+		if v, ok := u.value.(Excluded); ok {
+			if mysql {
+				value = "VALUES(" + dialect.QuoteIdent(string(v)) + ")"
+			} else {
+				value = "EXCLUDED." + dialect.QuoteIdent(string(v))
+			}
+		} else {
+			var valueArgs []interface{}
+			var err error
+			value, valueArgs, err = buildValue(dialect, u.value)
+			if err != nil {
+				return "", nil, fmt.Errorf("DoUpdateSet: %w", err)
+			}
+			args = append(args, valueArgs...)
+		}
+		sets[i] = dialect.QuoteIdent(u.column) + " = " + value
+	}
+	return strings.Join(sets, ", "), args, nil
+}
+
+// buildValue renders a value assigned to a column with ? placeholders: raw.Raw and
+// sqlfunc.SqlFunc as-is, a Binder as its SQL, and other values as a placeholder.
+func buildValue(dialect sqldialect.Dialect, value interface{}) (string, []interface{}, error) {
+	switch v := value.(type) {
+	case raw.Raw:
+		return string(v), nil, nil
+	case sqlfunc.SqlFunc:
+		return string(v), nil, v.Err()
+	case Binder:
+		return bindExpr(dialect, v)
+	default:
+		return "?", []interface{}{v}, nil
+	}
+}
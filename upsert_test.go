@@ -0,0 +1,303 @@
+package sqltk
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sprylic/sqltk/raw"
+	"github.com/sprylic/sqltk/sqldialect"
+	"github.com/sprylic/sqltk/sqlfunc"
+)
+
+func TestInsertOnConflict(t *testing.T) {
+	insert := func() *InsertBuilder {
+		return Insert("users").Columns("tenant_id", "email").Values(1, "a@example.com")
+	}
+
+	tests := []struct {
+		name     string
+		builder  Builder
+		dialect  sqldialect.Dialect
+		wantSQL  string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "column target",
+			builder:  insert().OnConflict("email").DoNothing(),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT ("email") DO NOTHING`,
+			wantArgs: []interface{}{1, "a@example.com"},
+		},
+		{
+			name: "composite target with expression and partial index",
+			builder: insert().
+				OnConflict("tenant_id", raw.Raw("lower(email)")).
+				OnConflictWhere(NewStringCondition("deleted_at IS NULL")).
+				OnConflictWhere(NewStringCondition("kind = ?", "member")).
+				DoNothing(),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT ("tenant_id", lower(email)) WHERE deleted_at IS NULL AND kind = $3 DO NOTHING`,
+			wantArgs: []interface{}{1, "a@example.com", "member"},
+		},
+		{
+			name:     "function target",
+			builder:  insert().OnConflict(sqlfunc.SqlFunc("lower(email)")).DoNothing(),
+			dialect:  sqldialect.DuckDB(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT (lower(email)) DO NOTHING`,
+			wantArgs: []interface{}{1, "a@example.com"},
+		},
+		{
+			name:     "no target",
+			builder:  insert().OnConflict().DoNothing(),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			wantArgs: []interface{}{1, "a@example.com"},
+		},
+		{
+			name:     "with returning",
+			builder:  &PostgresInsertBuilder{InsertBuilder: insert().OnConflict("email").DoNothing(), returning: []string{"id"}},
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT ("email") DO NOTHING RETURNING id`,
+			wantArgs: []interface{}{1, "a@example.com"},
+		},
+		{
+			name:     "idempotency key",
+			builder:  insert().IdempotencyKey("event_id", "evt_123"),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email", "event_id") VALUES ($1, $2, $3) ON CONFLICT ("event_id") DO NOTHING`,
+			wantArgs: []interface{}{1, "a@example.com", "evt_123"},
+		},
+		{
+			name:     "idempotency key with returning",
+			builder:  &PostgresInsertBuilder{InsertBuilder: Insert("users").IdempotencyKey("event_id", 7).Columns("email").Values("a@example.com"), returning: []string{"id"}},
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("email", "event_id") VALUES ($1, $2) ON CONFLICT ("event_id") DO NOTHING RETURNING id`,
+			wantArgs: []interface{}{"a@example.com", 7},
+		},
+		{
+			name:    "idempotency key with several rows",
+			builder: insert().Values(2, "b@example.com").IdempotencyKey("event_id", "evt_123"),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "idempotency key column already inserted",
+			builder: insert().IdempotencyKey("email", "evt_123"),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "idempotency key with on conflict",
+			builder: insert().OnConflict("email").DoNothing().IdempotencyKey("event_id", "evt_123"),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "nil idempotency key",
+			builder: insert().IdempotencyKey("event_id", nil),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "predicate without target",
+			builder: insert().OnConflict().OnConflictWhere(NewStringCondition("deleted_at IS NULL")).DoNothing(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "missing action",
+			builder: insert().OnConflict("email"),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "bad target",
+			builder: insert().OnConflict(42).DoNothing(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "where before on conflict",
+			builder: insert().OnConflictWhere(NewStringCondition("deleted_at IS NULL")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported dialect",
+			builder: insert().OnConflict("email").DoNothing(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name: "do update",
+			builder: insert().OnConflict("email").
+				DoUpdateSet("tenant_id", Excluded("tenant_id")).
+				DoUpdateSet("updated_at", raw.Raw("NOW()")).
+				DoUpdateSet("visits", Expr("visits + ?", 1)).
+				DoUpdateSet("note", "dup"),
+			dialect:  sqldialect.Postgres(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT ("email") DO UPDATE SET "tenant_id" = EXCLUDED."tenant_id", "updated_at" = NOW(), "visits" = visits + $3, "note" = $4`,
+			wantArgs: []interface{}{1, "a@example.com", 1, "dup"},
+		},
+		{
+			name: "do update with partial index",
+			builder: insert().OnConflict("email").OnConflictWhere(NewStringCondition("deleted_at IS NULL")).
+				DoUpdateSet("tenant_id", Excluded("tenant_id")),
+			dialect:  sqldialect.DuckDB(),
+			wantSQL:  `INSERT INTO "users" ("tenant_id", "email") VALUES ($1, $2) ON CONFLICT ("email") WHERE deleted_at IS NULL DO UPDATE SET "tenant_id" = EXCLUDED."tenant_id"`,
+			wantArgs: []interface{}{1, "a@example.com"},
+		},
+		{
+			name:     "on duplicate key update",
+			builder:  insert().OnConflict("email").DoUpdateSet("tenant_id", Excluded("tenant_id")).DoUpdateSet("note", "dup"),
+			dialect:  sqldialect.MySQL(),
+			wantSQL:  "INSERT INTO `users` (`tenant_id`, `email`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `tenant_id` = VALUES(`tenant_id`), `note` = ?",
+			wantArgs: []interface{}{1, "a@example.com", "dup"},
+		},
+		{
+			name:    "do update without target",
+			builder: insert().OnConflict().DoUpdateSet("tenant_id", Excluded("tenant_id")),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "do update with partial index on mysql",
+			builder: insert().OnConflict("email").OnConflictWhere(NewStringCondition("deleted_at IS NULL")).DoUpdateSet("note", "dup"),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "do update and do nothing",
+			builder: insert().OnConflict("email").DoUpdateSet("note", "dup").DoNothing(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "do update on unsupported dialect",
+			builder: insert().OnConflict("email").DoUpdateSet("note", "dup"),
+			dialect: sqldialect.BigQuery(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.builder.(interface {
+				WithDialect(sqldialect.Dialect) *InsertBuilder
+			}).WithDialect(tt.dialect)
+			sql, args, err := tt.builder.Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestInsertIgnoreReplace(t *testing.T) {
+	insert := func() *InsertBuilder {
+		return Insert("users").Columns("id", "email").Values(1, "a@example.com")
+	}
+	replace := func() *InsertBuilder {
+		return Replace("users").Columns("id", "email").Values(1, "a@example.com")
+	}
+
+	tests := []struct {
+		name    string
+		builder *InsertBuilder
+		dialect sqldialect.Dialect
+		wantSQL string
+		wantErr bool
+	}{
+		{
+			name:    "ignore on mysql",
+			builder: insert().Ignore(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "INSERT IGNORE INTO `users` (`id`, `email`) VALUES (?, ?)",
+		},
+		{
+			name:    "ignore on duckdb",
+			builder: insert().Ignore(),
+			dialect: sqldialect.DuckDB(),
+			wantSQL: `INSERT OR IGNORE INTO "users" ("id", "email") VALUES ($1, $2)`,
+		},
+		{
+			name:    "ignore emulated on postgres",
+			builder: insert().Ignore().OnUnsupported(UnsupportedEmulate),
+			dialect: sqldialect.Postgres(),
+			wantSQL: `INSERT INTO "users" ("id", "email") VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		},
+		{
+			name:    "ignore stripped on bigquery",
+			builder: insert().Ignore().OnUnsupported(UnsupportedStrip),
+			dialect: sqldialect.BigQuery(),
+			wantSQL: "INSERT INTO `users` (`id`, `email`) VALUES (?, ?)",
+		},
+		{
+			name:    "ignore on postgres",
+			builder: insert().Ignore(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "replace on mysql",
+			builder: replace(),
+			dialect: sqldialect.MySQL(),
+			wantSQL: "REPLACE INTO `users` (`id`, `email`) VALUES (?, ?)",
+		},
+		{
+			name:    "replace on duckdb",
+			builder: replace(),
+			dialect: sqldialect.DuckDB(),
+			wantSQL: `INSERT OR REPLACE INTO "users" ("id", "email") VALUES ($1, $2)`,
+		},
+		{
+			name:    "replace on postgres",
+			builder: replace(),
+			dialect: sqldialect.Postgres(),
+			wantErr: true,
+		},
+		{
+			name:    "replace with on conflict",
+			builder: replace().OnConflict("id").DoNothing(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+		{
+			name:    "replace and ignore",
+			builder: replace().Ignore(),
+			dialect: sqldialect.MySQL(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _, err := tt.builder.WithDialect(tt.dialect).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("got SQL %q, want %q", sql, tt.wantSQL)
+			}
+		})
+	}
+}